@@ -0,0 +1,6 @@
+package appconstant
+
+const (
+	AppointmentStatusBooked    = "booked"
+	AppointmentStatusCancelled = "cancelled"
+)
@@ -0,0 +1,35 @@
+package appconstant
+
+const (
+	AppointmentReminderEmailSubject = "Upcoming Consultation"
+
+	AppointmentReminderEmailTemplate = `
+		<!DOCTYPE html>
+
+		<html>
+
+		<head>
+			<title>UPCOMING CONSULTATION</title>
+			<style>
+                .email-container {
+                    border: 1px solid #ccc;
+                    border-radius: 5px;
+                    padding: 20px;
+                }
+			</style>
+		</head>
+
+		<body>
+            <div class="email-container">
+                <h2>Your consultation is coming up</h2>
+                <p>Hi {{.Name}},</p>
+                <p>This is a reminder of your consultation with <strong>{{.CounterpartName}}</strong>.</p>
+                <p>Starts at: <strong>{{.ScheduledAt}}</strong></p>
+                <p>The chat room opens at the booked time. Please be ready a few minutes before it starts.</p>
+                <p>Best regards,<br>MaxHealth Team</p>
+            </div>
+		</body>
+
+		</html>
+    `
+)
@@ -0,0 +1,20 @@
+package appconstant
+
+const (
+	ChatEventMessage = "message"
+	ChatEventTyping  = "typing"
+	ChatEventRead    = "read"
+	ChatEventClosed  = "closed"
+
+	ChatEventStarted           = "started"
+	ChatEventExpiring          = "expiring"
+	ChatEventExtensionRequest  = "extension_requested"
+	ChatEventExtensionUnpaid   = "extension_waiting_for_payment"
+	ChatEventExtensionAccepted = "extension_accepted"
+	ChatEventExtensionRejected = "extension_rejected"
+
+	AccessTokenProtocol = "access_token"
+
+	InProcessChatBroker = "memory"
+	PostgresChatBroker  = "postgres"
+)
@@ -7,4 +7,6 @@ const (
 	DrugPicturesUrl       = "drugs/"
 	ChatAttachmentUrl     = "chat_attachments/"
 	OrderPaymentProofsUrl = "order_payment_proofs/"
+
+	ConsultationPaymentProofsUrl = "consultation_payment_proofs/"
 )
@@ -0,0 +1,9 @@
+package appconstant
+
+const (
+	ConsultationPaymentWaitingForPayment      = "waiting_for_payment"
+	ConsultationPaymentWaitingForConfirmation = "waiting_for_confirmation"
+	ConsultationPaymentPaid                   = "paid"
+	ConsultationPaymentCancelled              = "cancelled"
+	ConsultationPaymentRefunded               = "refunded"
+)
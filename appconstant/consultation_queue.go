@@ -0,0 +1,30 @@
+package appconstant
+
+import "time"
+
+const (
+	ChatRoomDeclineReasonDeclined = "declined"
+	ChatRoomDeclineReasonExpired  = "expired"
+)
+
+const (
+	ConsultationQueueWaitingForPayment = "waiting_for_payment"
+	ConsultationQueueScheduled         = "scheduled"
+	ConsultationQueueQueued            = "queued"
+	ConsultationQueueInConsultation    = "in_consultation"
+	ConsultationQueueClosed            = "closed"
+)
+
+const (
+	ConsultationDefaultDuration     = 30 * time.Minute
+	ConsultationExpiryWarning       = 5 * time.Minute
+	ConsultationHistorySampleLength = 20
+)
+
+const (
+	ChatRoomExtensionPending           = "pending"
+	ChatRoomExtensionWaitingForPayment = "waiting_for_payment"
+	ChatRoomExtensionAccepted          = "accepted"
+	ChatRoomExtensionRejected          = "rejected"
+	ChatRoomExtensionExpired           = "expired"
+)
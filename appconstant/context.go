@@ -4,6 +4,7 @@ const (
 	RequestId = "request-id"
 	AccountId = "account-id"
 	Role      = "role"
+	SessionId = "session-id"
 
 	Latitude  = "lat"
 	Longitude = "long"
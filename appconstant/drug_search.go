@@ -0,0 +1,5 @@
+package appconstant
+
+import "time"
+
+const DrugSearchRefreshDelay = 5 * time.Second
@@ -20,4 +20,16 @@ const (
 	OrderPharmacyIdString   = "order_pharmacy_id"
 	PharmacyDrugIdString    = "pharmacy_drug_id"
 	DoctorIdString          = "doctor_id"
+	PharmacyHolidayIdString = "pharmacy_holiday_id"
+	SessionIdString         = "session_id"
+	AccountIdString         = "account_id"
+	SignatureString         = "signature"
+	StrategyString          = "strategy"
+	ExtensionIdString       = "extension_id"
+	SpecializationIdString  = "specialization_id"
+	ReviewIdString          = "review_id"
+	ExceptionIdString       = "exception_id"
+	AppointmentIdString     = "appointment_id"
+	StockMutationIdString   = "stock_mutation_id"
+	LowStockAlertIdString   = "low_stock_alert_id"
 )
@@ -0,0 +1,35 @@
+package appconstant
+
+const (
+	LowStockAlertEmailSubject = "Low Stock Alert"
+
+	LowStockAlertEmailTemplate = `
+		<!DOCTYPE html>
+
+		<html>
+
+		<head>
+			<title>LOW STOCK ALERT</title>
+			<style>
+                .email-container {
+                    border: 1px solid #ccc;
+                    border-radius: 5px;
+                    padding: 20px;
+                }
+			</style>
+		</head>
+
+		<body>
+            <div class="email-container">
+                <h2>Stock is running low</h2>
+                <p>Hi {{.Name}},</p>
+                <p><strong>{{.DrugName}}</strong> at <strong>{{.PharmacyName}}</strong> has dropped to its reorder threshold.</p>
+                <p>Stock left: <strong>{{.Stock}}</strong> (threshold {{.ReorderThreshold}})</p>
+                <p>Please restock it soon to avoid turning customers away.</p>
+                <p>Best regards,<br>MaxHealth Team</p>
+            </div>
+		</body>
+
+		</html>
+    `
+)
@@ -80,4 +80,55 @@ const (
 	MsgInvalidPharmacyOperational      = "invalid pharmacy operational"
 	MsgInvalidPharmacyCourier          = "invalid pharmacy courier"
 	MsgOngoingOrderExists              = "ongoing order exists"
+	MsgCheckoutAmountMismatch          = "%s mismatch, submitted %s but expected %s"
+	MsgCourierNotAvailable             = "courier is not available for this pharmacy"
+	MsgShippingRateUnavailable         = "shipping rate is currently unavailable"
+	MsgPharmacyClosed                  = "%s is currently closed"
+	MsgPharmacyClosedUntil             = "%s is currently closed and opens at %s"
+	MsgInvalidPharmacyHoliday          = "invalid pharmacy holiday"
+	MsgPharmacyHolidayNotFound         = "pharmacy holiday not found"
+	MsgRefreshTokenReused              = "refresh token has already been used, please log in again"
+	MsgSessionNotFound                 = "session not found"
+	MsgAccountSuspended                = "your account has been suspended"
+	MsgAccountAlreadySuspended         = "account is already suspended"
+	MsgAccountNotSuspended             = "account is not suspended"
+	MsgPrescriptionRequired            = "%s requires a valid prescription"
+	MsgPrescriptionOverQuantity        = "quantity of %s exceeds the remaining prescribed quantity"
+	MsgPrescriptionExpired             = "prescription has expired"
+	MsgPrescriptionUnverified          = "prescription signature is invalid"
+	MsgInvalidFulfilmentStrategy       = "strategy must be one of cheapest, fewest_shipments or fastest"
+	MsgConsultationNotPaid             = "consultation fee has not been paid"
+	MsgConsultationPaymentNotFound     = "consultation payment not found"
+	MsgInvalidConsultationPayment      = "consultation payment cannot be changed in its current status"
+	MsgChatRoomNotQueued               = "consultation request is not waiting in the queue"
+	MsgChatRoomNotStarted              = "consultation has not started yet"
+	MsgExtensionAlreadyPending         = "an extension request is already waiting for a response"
+	MsgExtensionNotFound               = "extension request not found"
+	MsgExtensionNotPending             = "extension request has already been answered"
+	MsgSpecializationNotFound          = "doctor specialization not found"
+	MsgReviewNotFound                  = "review not found"
+	MsgReviewAlreadyExists             = "consultation has already been reviewed"
+	MsgChatRoomNotReviewable           = "only finished consultations can be reviewed"
+	MsgInvalidDoctorSchedule           = "invalid doctor schedule"
+	MsgScheduleExceptionNotFound       = "schedule exception not found"
+	MsgInvalidAppointmentSlot          = "the selected time is not an available slot"
+	MsgAppointmentSlotTaken            = "the selected slot has already been booked"
+	MsgAppointmentNotFound             = "appointment not found"
+	MsgAppointmentNotCancellable       = "appointment can no longer be cancelled"
+	MsgAppointmentNotStarted           = "appointment has not started yet"
+	MsgStockMutationNotFound           = "stock mutation request not found"
+	MsgInvalidStockMutationStatus      = "stock mutation request cannot be changed in its current status"
+	MsgBatchRequired                   = "a batch number and expiry date are required when adding stock"
+	MsgBatchExpired                    = "batch has already expired"
+	MsgBatchExpiryMismatch             = "batch number is already recorded with a different expiry date"
+	MsgLowStockAlertNotFound           = "low stock alert not found"
+	MsgInvalidStockChangeDateRange     = "end-date should be greater or equal to start-date"
+	MsgInvalidSpreadsheet              = "file is not a valid spreadsheet"
+	MsgTooManySpreadsheetRows          = "file has too many rows"
+	MsgInvalidDrugImportColumns        = "file should have stock and price columns and a drug_id or drug_name column"
+	MsgEmptyDrugImport                 = "file has no rows to import"
+	MsgStockMutationOrderStarted       = "stock mutation request is needed by an order that is already being processed"
+	MsgOrderStatusChanged              = "order status has changed, please reload the order"
+	MsgPharmacyOperationalNotFound     = "pharmacy operational day not found"
+	MsgPharmacyHolidayExists           = "pharmacy already has a holiday on this date"
 )
@@ -0,0 +1,35 @@
+package appconstant
+
+const (
+	OrderExpiredEmailSubject = "Order Cancelled"
+
+	OrderExpiredEmailTemplate = `
+		<!DOCTYPE html>
+
+		<html>
+
+		<head>
+			<title>ORDER CANCELLED</title>
+			<style>
+                .email-container {
+                    border: 1px solid #ccc;
+                    border-radius: 5px;
+                    padding: 20px;
+                }
+			</style>
+		</head>
+
+		<body>
+            <div class="email-container">
+                <h2>Your order has been cancelled</h2>
+                <p>Hi {{.Name}},</p>
+                <p>We did not receive the payment for your order <strong>#{{.OrderId}}</strong> in time, so it has been cancelled automatically.</p>
+                <p>Total amount: <strong>Rp{{.TotalAmount}}</strong></p>
+                <p>The items have been returned to the pharmacies. If you still need them, please place a new order.</p>
+                <p>Best regards,<br>MaxHealth Team</p>
+            </div>
+		</body>
+
+		</html>
+    `
+)
@@ -0,0 +1,9 @@
+package appconstant
+
+const (
+	BatchExpiryDateFormat    = "2006-01-02"
+	DefaultExpiringBatchDays = 30
+
+	BatchReceivedDescription   = "batch %s received"
+	BatchWrittenOffDescription = "batch %s expired, written off"
+)
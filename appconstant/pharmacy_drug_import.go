@@ -0,0 +1,31 @@
+package appconstant
+
+const (
+	PharmacyDrugImportMaxFileSize = 5000000
+	PharmacyDrugImportMaxRows     = 5000
+	PharmacyDrugImportMinPrice    = 500
+	PharmacyDrugImportDescription = "imported by manager"
+
+	PharmacyDrugImportActionCreate = "create"
+	PharmacyDrugImportActionUpdate = "update"
+
+	PharmacyDrugImportColumnDrugId      = "drug_id"
+	PharmacyDrugImportColumnDrugName    = "drug_name"
+	PharmacyDrugImportColumnStock       = "stock"
+	PharmacyDrugImportColumnPrice       = "price"
+	PharmacyDrugImportColumnBatchNumber = "batch_number"
+	PharmacyDrugImportColumnExpiryDate  = "expiry_date"
+)
+
+const (
+	PharmacyDrugImportDrugRequired      = "drug_id or drug_name is required"
+	PharmacyDrugImportInvalidDrugId     = "drug_id should be a positive number"
+	PharmacyDrugImportDrugNotFound      = "drug not found"
+	PharmacyDrugImportDrugNameMismatch  = "drug_name does not match the drug of drug_id"
+	PharmacyDrugImportAmbiguousDrugName = "drug_name matches more than one drug, use drug_id instead"
+	PharmacyDrugImportDuplicateDrug     = "drug is already listed on row %d"
+	PharmacyDrugImportInvalidStock      = "stock should be a number greater or equal to 0"
+	PharmacyDrugImportInvalidPrice      = "price should be a number greater or equal to 500"
+	PharmacyDrugImportIncompleteBatch   = "batch_number and expiry_date should be filled together"
+	PharmacyDrugImportInvalidExpiryDate = "expiry_date should be in yyyy-mm-dd format"
+)
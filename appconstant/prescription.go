@@ -0,0 +1,15 @@
+package appconstant
+
+const PrescriptionDefaultValidDays = 30
+
+const (
+	PrescriptionDocumentContentType = "application/pdf"
+	PrescriptionDocumentFileName    = "prescription-%d.pdf"
+	PrescriptionVerificationUrl     = "%s/prescriptions/%d/verification?signature=%s"
+)
+
+const (
+	FulfilmentStrategyCheapest        = "cheapest"
+	FulfilmentStrategyFewestShipments = "fewest_shipments"
+	FulfilmentStrategyFastest         = "fastest"
+)
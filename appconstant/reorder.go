@@ -0,0 +1,6 @@
+package appconstant
+
+const (
+	DefaultReorderSalesWindowDays = 30
+	DefaultReorderCoverDays       = 14
+)
@@ -0,0 +1,6 @@
+package appconstant
+
+const (
+	RajaOngkirShippingRateProvider = "rajaongkir"
+	LocalShippingRateProvider      = "local"
+)
@@ -0,0 +1,15 @@
+package appconstant
+
+const (
+	StockChangeTypeSale             = "sale"
+	StockChangeTypeMutation         = "mutation"
+	StockChangeTypeManualAdjustment = "manual_adjustment"
+	StockChangeTypeCancellation     = "cancellation"
+
+	StockChangeSignPositive = "positive"
+	StockChangeSignNegative = "negative"
+
+	StockChangeDateFormat        = "2006-01-02"
+	StockChangeExportContentType = "text/csv"
+	StockChangeExportFileName    = "stock-changes.csv"
+)
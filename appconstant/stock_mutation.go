@@ -0,0 +1,17 @@
+package appconstant
+
+const (
+	StockMutationPending  = "pending"
+	StockMutationApproved = "approved"
+	StockMutationRejected = "rejected"
+	StockMutationShipped  = "shipped"
+	StockMutationReceived = "received"
+	// Cancelled requests were raised for an order that has been cancelled.
+	StockMutationCancelled = "cancelled"
+
+	StockMutationIncoming = "incoming"
+	StockMutationOutgoing = "outgoing"
+
+	StockMutationRequestedDescription  = "stock mutation requested"
+	StockMutationTransitionDescription = "stock mutation #%d %s"
+)
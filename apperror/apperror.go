@@ -6,7 +6,9 @@ import (
 	"net/http"
 	"runtime/debug"
 	"strings"
+	"time"
 
+	"github.com/shopspring/decimal"
 	"github.com/sidiqPratomo/max-health-backend/appconstant"
 )
 
@@ -399,3 +401,250 @@ func OngoingOrderExistsError() *AppError {
 	err := errors.New(appconstant.MsgOngoingOrderExists)
 	return NewAppError(http.StatusForbidden, err, appconstant.MsgOngoingOrderExists)
 }
+
+func CheckoutAmountMismatchError(field string, submitted, expected decimal.Decimal) *AppError {
+	err := fmt.Errorf(appconstant.MsgCheckoutAmountMismatch, field, submitted.String(), expected.String())
+	return NewAppError(http.StatusUnprocessableEntity, err, err.Error())
+}
+
+func CourierNotAvailableError() *AppError {
+	err := errors.New(appconstant.MsgCourierNotAvailable)
+	return NewAppError(http.StatusBadRequest, err, appconstant.MsgCourierNotAvailable)
+}
+
+func ShippingRateUnavailableError(err error) *AppError {
+	return NewAppError(http.StatusBadGateway, err, appconstant.MsgShippingRateUnavailable)
+}
+
+func PharmacyClosedError(pharmacyName string, opensAt *time.Time) *AppError {
+	err := fmt.Errorf(appconstant.MsgPharmacyClosed, pharmacyName)
+	if opensAt != nil {
+		err = fmt.Errorf(appconstant.MsgPharmacyClosedUntil, pharmacyName, opensAt.Format(time.RFC3339))
+	}
+	return NewAppError(http.StatusUnprocessableEntity, err, err.Error())
+}
+
+func InvalidPharmacyHolidayError() *AppError {
+	err := errors.New(appconstant.MsgInvalidPharmacyHoliday)
+	return NewAppError(http.StatusBadRequest, err, appconstant.MsgInvalidPharmacyHoliday)
+}
+
+func PharmacyHolidayNotFoundError() *AppError {
+	err := errors.New(appconstant.MsgPharmacyHolidayNotFound)
+	return NewAppError(http.StatusNotFound, err, appconstant.MsgPharmacyHolidayNotFound)
+}
+
+func RefreshTokenReusedError() *AppError {
+	err := errors.New(appconstant.MsgRefreshTokenReused)
+	return NewAppError(http.StatusUnauthorized, err, appconstant.MsgRefreshTokenReused)
+}
+
+func SessionNotFoundError() *AppError {
+	err := errors.New(appconstant.MsgSessionNotFound)
+	return NewAppError(http.StatusNotFound, err, appconstant.MsgSessionNotFound)
+}
+
+func AccountNotFoundByIdError() *AppError {
+	err := errors.New(appconstant.MsgAccountNotFound)
+	return NewAppError(http.StatusNotFound, err, appconstant.MsgAccountNotFound)
+}
+
+func AccountSuspendedError() *AppError {
+	err := errors.New(appconstant.MsgAccountSuspended)
+	return NewAppError(http.StatusForbidden, err, appconstant.MsgAccountSuspended)
+}
+
+func AccountAlreadySuspendedError() *AppError {
+	err := errors.New(appconstant.MsgAccountAlreadySuspended)
+	return NewAppError(http.StatusBadRequest, err, appconstant.MsgAccountAlreadySuspended)
+}
+
+func AccountNotSuspendedError() *AppError {
+	err := errors.New(appconstant.MsgAccountNotSuspended)
+	return NewAppError(http.StatusBadRequest, err, appconstant.MsgAccountNotSuspended)
+}
+
+func PrescriptionRequiredError(drugName string) *AppError {
+	err := fmt.Errorf(appconstant.MsgPrescriptionRequired, drugName)
+	return NewAppError(http.StatusForbidden, err, err.Error())
+}
+
+func PrescriptionQuantityExceededError(drugName string) *AppError {
+	err := fmt.Errorf(appconstant.MsgPrescriptionOverQuantity, drugName)
+	return NewAppError(http.StatusUnprocessableEntity, err, err.Error())
+}
+
+func PrescriptionExpiredError() *AppError {
+	err := errors.New(appconstant.MsgPrescriptionExpired)
+	return NewAppError(http.StatusBadRequest, err, appconstant.MsgPrescriptionExpired)
+}
+
+func PrescriptionSignatureInvalidError() *AppError {
+	err := errors.New(appconstant.MsgPrescriptionUnverified)
+	return NewAppError(http.StatusBadRequest, err, appconstant.MsgPrescriptionUnverified)
+}
+
+func InvalidFulfilmentStrategyError() *AppError {
+	err := errors.New(appconstant.MsgInvalidFulfilmentStrategy)
+	return NewAppError(http.StatusBadRequest, err, appconstant.MsgInvalidFulfilmentStrategy)
+}
+
+func ConsultationNotPaidError() *AppError {
+	err := errors.New(appconstant.MsgConsultationNotPaid)
+	return NewAppError(http.StatusForbidden, err, appconstant.MsgConsultationNotPaid)
+}
+
+func ConsultationPaymentNotFoundError() *AppError {
+	err := errors.New(appconstant.MsgConsultationPaymentNotFound)
+	return NewAppError(http.StatusNotFound, err, appconstant.MsgConsultationPaymentNotFound)
+}
+
+func InvalidConsultationPaymentStatusError() *AppError {
+	err := errors.New(appconstant.MsgInvalidConsultationPayment)
+	return NewAppError(http.StatusBadRequest, err, appconstant.MsgInvalidConsultationPayment)
+}
+
+func ChatRoomNotQueuedError() *AppError {
+	err := errors.New(appconstant.MsgChatRoomNotQueued)
+	return NewAppError(http.StatusBadRequest, err, appconstant.MsgChatRoomNotQueued)
+}
+
+func ChatRoomNotStartedError() *AppError {
+	err := errors.New(appconstant.MsgChatRoomNotStarted)
+	return NewAppError(http.StatusBadRequest, err, appconstant.MsgChatRoomNotStarted)
+}
+
+func ExtensionAlreadyPendingError() *AppError {
+	err := errors.New(appconstant.MsgExtensionAlreadyPending)
+	return NewAppError(http.StatusBadRequest, err, appconstant.MsgExtensionAlreadyPending)
+}
+
+func ExtensionNotFoundError() *AppError {
+	err := errors.New(appconstant.MsgExtensionNotFound)
+	return NewAppError(http.StatusNotFound, err, appconstant.MsgExtensionNotFound)
+}
+
+func ExtensionNotPendingError() *AppError {
+	err := errors.New(appconstant.MsgExtensionNotPending)
+	return NewAppError(http.StatusBadRequest, err, appconstant.MsgExtensionNotPending)
+}
+
+func SpecializationNotFoundError() *AppError {
+	err := errors.New(appconstant.MsgSpecializationNotFound)
+	return NewAppError(http.StatusNotFound, err, appconstant.MsgSpecializationNotFound)
+}
+
+func ReviewNotFoundError() *AppError {
+	err := errors.New(appconstant.MsgReviewNotFound)
+	return NewAppError(http.StatusNotFound, err, appconstant.MsgReviewNotFound)
+}
+
+func ReviewAlreadyExistsError() *AppError {
+	err := errors.New(appconstant.MsgReviewAlreadyExists)
+	return NewAppError(http.StatusBadRequest, err, appconstant.MsgReviewAlreadyExists)
+}
+
+func ChatRoomNotReviewableError() *AppError {
+	err := errors.New(appconstant.MsgChatRoomNotReviewable)
+	return NewAppError(http.StatusBadRequest, err, appconstant.MsgChatRoomNotReviewable)
+}
+
+func InvalidDoctorScheduleError() *AppError {
+	err := errors.New(appconstant.MsgInvalidDoctorSchedule)
+	return NewAppError(http.StatusBadRequest, err, appconstant.MsgInvalidDoctorSchedule)
+}
+
+func ScheduleExceptionNotFoundError() *AppError {
+	err := errors.New(appconstant.MsgScheduleExceptionNotFound)
+	return NewAppError(http.StatusNotFound, err, appconstant.MsgScheduleExceptionNotFound)
+}
+
+func InvalidAppointmentSlotError() *AppError {
+	err := errors.New(appconstant.MsgInvalidAppointmentSlot)
+	return NewAppError(http.StatusBadRequest, err, appconstant.MsgInvalidAppointmentSlot)
+}
+
+func AppointmentSlotTakenError() *AppError {
+	err := errors.New(appconstant.MsgAppointmentSlotTaken)
+	return NewAppError(http.StatusConflict, err, appconstant.MsgAppointmentSlotTaken)
+}
+
+func AppointmentNotFoundError() *AppError {
+	err := errors.New(appconstant.MsgAppointmentNotFound)
+	return NewAppError(http.StatusNotFound, err, appconstant.MsgAppointmentNotFound)
+}
+
+func AppointmentNotCancellableError() *AppError {
+	err := errors.New(appconstant.MsgAppointmentNotCancellable)
+	return NewAppError(http.StatusBadRequest, err, appconstant.MsgAppointmentNotCancellable)
+}
+
+func AppointmentNotStartedError() *AppError {
+	err := errors.New(appconstant.MsgAppointmentNotStarted)
+	return NewAppError(http.StatusBadRequest, err, appconstant.MsgAppointmentNotStarted)
+}
+
+func StockMutationNotFoundError() *AppError {
+	err := errors.New(appconstant.MsgStockMutationNotFound)
+	return NewAppError(http.StatusNotFound, err, appconstant.MsgStockMutationNotFound)
+}
+
+func InvalidStockMutationStatusError() *AppError {
+	err := errors.New(appconstant.MsgInvalidStockMutationStatus)
+	return NewAppError(http.StatusBadRequest, err, appconstant.MsgInvalidStockMutationStatus)
+}
+
+func BatchRequiredError() *AppError {
+	err := errors.New(appconstant.MsgBatchRequired)
+	return NewAppError(http.StatusBadRequest, err, appconstant.MsgBatchRequired)
+}
+
+func BatchExpiredError() *AppError {
+	err := errors.New(appconstant.MsgBatchExpired)
+	return NewAppError(http.StatusBadRequest, err, appconstant.MsgBatchExpired)
+}
+
+func BatchExpiryMismatchError() *AppError {
+	err := errors.New(appconstant.MsgBatchExpiryMismatch)
+	return NewAppError(http.StatusBadRequest, err, appconstant.MsgBatchExpiryMismatch)
+}
+
+func LowStockAlertNotFoundError() *AppError {
+	err := errors.New(appconstant.MsgLowStockAlertNotFound)
+	return NewAppError(http.StatusNotFound, err, appconstant.MsgLowStockAlertNotFound)
+}
+
+func InvalidStockChangeDateRangeError() *AppError {
+	err := errors.New(appconstant.MsgInvalidStockChangeDateRange)
+	return NewAppError(http.StatusBadRequest, err, appconstant.MsgInvalidStockChangeDateRange)
+}
+
+func InvalidDrugImportColumnsError() *AppError {
+	err := errors.New(appconstant.MsgInvalidDrugImportColumns)
+	return NewAppError(http.StatusBadRequest, err, appconstant.MsgInvalidDrugImportColumns)
+}
+
+func EmptyDrugImportError() *AppError {
+	err := errors.New(appconstant.MsgEmptyDrugImport)
+	return NewAppError(http.StatusBadRequest, err, appconstant.MsgEmptyDrugImport)
+}
+
+func StockMutationOrderStartedError() *AppError {
+	err := errors.New(appconstant.MsgStockMutationOrderStarted)
+	return NewAppError(http.StatusConflict, err, appconstant.MsgStockMutationOrderStarted)
+}
+
+func OrderStatusChangedError() *AppError {
+	err := errors.New(appconstant.MsgOrderStatusChanged)
+	return NewAppError(http.StatusConflict, err, appconstant.MsgOrderStatusChanged)
+}
+
+func PharmacyOperationalNotFoundError() *AppError {
+	err := errors.New(appconstant.MsgPharmacyOperationalNotFound)
+	return NewAppError(http.StatusNotFound, err, appconstant.MsgPharmacyOperationalNotFound)
+}
+
+func PharmacyHolidayExistsError() *AppError {
+	err := errors.New(appconstant.MsgPharmacyHolidayExists)
+	return NewAppError(http.StatusConflict, err, appconstant.MsgPharmacyHolidayExists)
+}
@@ -9,22 +9,38 @@ import (
 )
 
 type Config struct {
-	Port                string
-	FEPort              string
-	DbUrl               string
-	Issuer              string
-	SendEmailIdentity   string
-	SendEmailUsername   string
-	SendEmailPassword   string
-	SendEmailHost       string
-	SendEmailPort       string
-	VerifSecret         string
-	AccessSecret        string
-	RefreshSecret       string
-	ResetPasswordSecret string
-	RajaOngkirApiKey    string
-	HashCost            int
-	GracefulPeriod      int
+	Port                       string
+	FEPort                     string
+	FEOrigin                   string
+	DbUrl                      string
+	Issuer                     string
+	SendEmailIdentity          string
+	SendEmailUsername          string
+	SendEmailPassword          string
+	SendEmailHost              string
+	SendEmailPort              string
+	VerifSecret                string
+	AccessSecret               string
+	RefreshSecret              string
+	ResetPasswordSecret        string
+	PrescriptionSecret         string
+	ApiBaseUrl                 string
+	RajaOngkirApiKey           string
+	ChatBroker                 string
+	ShippingRateProvider       string
+	RequireMigrations          bool
+	HashCost                   int
+	GracefulPeriod             int
+	OrderExpiryInterval        int
+	ConsultationPaymentTimeout int
+	ConsultationJoinTimeout    int
+	ConsultationExpiryInterval int
+	AppointmentReminderLead    int
+	AppointmentInterval        int
+	BatchWriteOffInterval      int
+	LowStockAlertInterval      int
+	ShippingRateCacheTtl       int
+	TokenVersionCacheTtl       int
 }
 
 func Init(log *logrus.Logger) *Config {
@@ -47,22 +63,133 @@ func Init(log *logrus.Logger) *Config {
 		}).Fatal("error loading .env file")
 	}
 
+	orderExpiryInterval, err := strconv.Atoi(os.Getenv("ORDER_EXPIRY_INTERVAL"))
+	if err != nil {
+		log.WithFields(logrus.Fields{
+			"error": "ORDER_EXPIRY_INTERVAL must be integer",
+		}).Fatal("error loading .env file")
+	}
+
+	consultationPaymentTimeout, err := strconv.Atoi(os.Getenv("CONSULTATION_PAYMENT_TIMEOUT"))
+	if err != nil {
+		log.WithFields(logrus.Fields{
+			"error": "CONSULTATION_PAYMENT_TIMEOUT must be integer",
+		}).Fatal("error loading .env file")
+	}
+
+	consultationJoinTimeout, err := strconv.Atoi(os.Getenv("CONSULTATION_JOIN_TIMEOUT"))
+	if err != nil {
+		log.WithFields(logrus.Fields{
+			"error": "CONSULTATION_JOIN_TIMEOUT must be integer",
+		}).Fatal("error loading .env file")
+	}
+
+	consultationExpiryInterval, err := strconv.Atoi(os.Getenv("CONSULTATION_EXPIRY_INTERVAL"))
+	if err != nil {
+		log.WithFields(logrus.Fields{
+			"error": "CONSULTATION_EXPIRY_INTERVAL must be integer",
+		}).Fatal("error loading .env file")
+	}
+
+	appointmentReminderLead, err := strconv.Atoi(os.Getenv("APPOINTMENT_REMINDER_LEAD"))
+	if err != nil {
+		log.WithFields(logrus.Fields{
+			"error": "APPOINTMENT_REMINDER_LEAD must be integer",
+		}).Fatal("error loading .env file")
+	}
+
+	appointmentInterval, err := strconv.Atoi(os.Getenv("APPOINTMENT_SCHEDULER_INTERVAL"))
+	if err != nil {
+		log.WithFields(logrus.Fields{
+			"error": "APPOINTMENT_SCHEDULER_INTERVAL must be integer",
+		}).Fatal("error loading .env file")
+	}
+
+	batchWriteOffInterval, err := strconv.Atoi(os.Getenv("BATCH_WRITE_OFF_INTERVAL"))
+	if err != nil {
+		log.WithFields(logrus.Fields{
+			"error": "BATCH_WRITE_OFF_INTERVAL must be integer",
+		}).Fatal("error loading .env file")
+	}
+
+	lowStockAlertInterval, err := strconv.Atoi(os.Getenv("LOW_STOCK_ALERT_INTERVAL"))
+	if err != nil {
+		log.WithFields(logrus.Fields{
+			"error": "LOW_STOCK_ALERT_INTERVAL must be integer",
+		}).Fatal("error loading .env file")
+	}
+
+	shippingRateCacheTtl, err := strconv.Atoi(os.Getenv("SHIPPING_RATE_CACHE_TTL"))
+	if err != nil {
+		log.WithFields(logrus.Fields{
+			"error": "SHIPPING_RATE_CACHE_TTL must be integer",
+		}).Fatal("error loading .env file")
+	}
+
+	tokenVersionCacheTtl, err := strconv.Atoi(os.Getenv("TOKEN_VERSION_CACHE_TTL"))
+	if err != nil {
+		log.WithFields(logrus.Fields{
+			"error": "TOKEN_VERSION_CACHE_TTL must be integer",
+		}).Fatal("error loading .env file")
+	}
+
+	prescriptionSecret := os.Getenv("PRESCRIPTION_SECRET_KEY")
+	if prescriptionSecret == "" {
+		log.WithFields(logrus.Fields{
+			"error": "PRESCRIPTION_SECRET_KEY must not be empty",
+		}).Fatal("error loading .env file")
+	}
+
+	return &Config{
+		Port:                       os.Getenv("BE_PORT"),
+		FEPort:                     os.Getenv("FE_PORT"),
+		FEOrigin:                   os.Getenv("FE_ORIGIN"),
+		DbUrl:                      os.Getenv("DATABASE_URL"),
+		Issuer:                     os.Getenv("ISSUER"),
+		SendEmailIdentity:          os.Getenv("SEND_EMAIL_IDENTITY"),
+		SendEmailUsername:          os.Getenv("SEND_EMAIL_USERNAME"),
+		SendEmailPassword:          os.Getenv("SEND_EMAIL_PASSWORD"),
+		SendEmailHost:              os.Getenv("SEND_EMAIL_HOST"),
+		SendEmailPort:              os.Getenv("SEND_EMAIL_PORT"),
+		VerifSecret:                os.Getenv("VERIFICATION_CODE_SECRET_KEY"),
+		AccessSecret:               os.Getenv("ACCESS_TOKEN_SECRET_KEY"),
+		RefreshSecret:              os.Getenv("REFRESH_TOKEN_SECRET_KEY"),
+		ResetPasswordSecret:        os.Getenv("RESET_PASSWORD_SECRET_KEY"),
+		PrescriptionSecret:         prescriptionSecret,
+		ApiBaseUrl:                 os.Getenv("API_BASE_URL"),
+		RajaOngkirApiKey:           os.Getenv("RAJA_ONGKIR_API_KEY"),
+		ChatBroker:                 os.Getenv("CHAT_BROKER"),
+		ShippingRateProvider:       os.Getenv("SHIPPING_RATE_PROVIDER"),
+		RequireMigrations:          os.Getenv("REQUIRE_MIGRATIONS") == "true",
+		HashCost:                   hashCost,
+		GracefulPeriod:             gracefulPeriod,
+		OrderExpiryInterval:        orderExpiryInterval,
+		ConsultationPaymentTimeout: consultationPaymentTimeout,
+		ConsultationJoinTimeout:    consultationJoinTimeout,
+		ConsultationExpiryInterval: consultationExpiryInterval,
+		AppointmentReminderLead:    appointmentReminderLead,
+		AppointmentInterval:        appointmentInterval,
+		BatchWriteOffInterval:      batchWriteOffInterval,
+		LowStockAlertInterval:      lowStockAlertInterval,
+		ShippingRateCacheTtl:       shippingRateCacheTtl,
+		TokenVersionCacheTtl:       tokenVersionCacheTtl,
+	}
+}
+
+// InitDatabase only loads the database settings, so tools such as migrate can
+// run without the rest of the server environment. A missing .env file is fine
+// when DATABASE_URL is already set in the environment.
+func InitDatabase(log *logrus.Logger) *Config {
+	_ = godotenv.Load()
+
+	dbUrl := os.Getenv("DATABASE_URL")
+	if dbUrl == "" {
+		log.WithFields(logrus.Fields{
+			"error": "DATABASE_URL must not be empty",
+		}).Fatal("error loading database config")
+	}
+
 	return &Config{
-		Port:                os.Getenv("BE_PORT"),
-		FEPort:              os.Getenv("FE_PORT"),
-		DbUrl:               os.Getenv("DATABASE_URL"),
-		Issuer:              os.Getenv("ISSUER"),
-		SendEmailIdentity:   os.Getenv("SEND_EMAIL_IDENTITY"),
-		SendEmailUsername:   os.Getenv("SEND_EMAIL_USERNAME"),
-		SendEmailPassword:   os.Getenv("SEND_EMAIL_PASSWORD"),
-		SendEmailHost:       os.Getenv("SEND_EMAIL_HOST"),
-		SendEmailPort:       os.Getenv("SEND_EMAIL_PORT"),
-		VerifSecret:         os.Getenv("VERIFICATION_CODE_SECRET_KEY"),
-		AccessSecret:        os.Getenv("ACCESS_TOKEN_SECRET_KEY"),
-		RefreshSecret:       os.Getenv("REFRESH_TOKEN_SECRET_KEY"),
-		ResetPasswordSecret: os.Getenv("RESET_PASSWORD_SECRET_KEY"),
-		RajaOngkirApiKey:    os.Getenv("RAJA_ONGKIR_API_KEY"),
-		HashCost:            hashCost,
-		GracefulPeriod:      gracefulPeriod,
+		DbUrl: dbUrl,
 	}
 }
@@ -2,7 +2,7 @@ package database
 
 const (
 	FindAccountByEmailQuery = `
-		SELECT a.account_id, a.email, a.password, a.role_id, r.role_name, a.account_name, a.profile_picture, a.verified_at
+		SELECT a.account_id, a.email, a.password, a.role_id, r.role_name, a.account_name, a.profile_picture, a.verified_at, a.token_version, a.suspended_at
 		FROM accounts a
 		JOIN roles r ON a.role_id =  r.role_id
 		WHERE a.email ILIKE $1
@@ -69,4 +69,49 @@ const (
 		SET deleted_at = NOW(), updated_at = NOW()
 		WHERE account_id = $1
 	`
+
+	FindOneAccountTokenVersionByIdQuery = `
+		SELECT token_version
+		FROM accounts
+		WHERE account_id = $1
+		AND deleted_at IS NULL
+		AND suspended_at IS NULL
+	`
+
+	IncrementOneAccountTokenVersionByIdQuery = `
+		UPDATE accounts
+		SET token_version = token_version + 1, updated_at = NOW()
+		WHERE account_id = $1
+	`
+
+	FindAllAccountsQuery = `
+		SELECT a.account_id, a.email, a.account_name, a.role_id, r.role_name, a.profile_picture, a.verified_at, a.suspended_at, a.created_at, COUNT(*) OVER()
+		FROM accounts a
+		JOIN roles r ON a.role_id = r.role_id
+		WHERE a.deleted_at IS NULL
+	`
+
+	FindOneAccountDetailByIdQuery = `
+		SELECT a.account_id, a.email, a.account_name, a.role_id, r.role_name, a.profile_picture, a.verified_at, a.suspended_at, a.created_at
+		FROM accounts a
+		JOIN roles r ON a.role_id = r.role_id
+		WHERE a.account_id = $1
+		AND a.deleted_at IS NULL
+	`
+
+	SuspendOneAccountByIdQuery = `
+		UPDATE accounts
+		SET suspended_at = NOW(), updated_at = NOW()
+		WHERE account_id = $1
+		AND suspended_at IS NULL
+		AND deleted_at IS NULL
+	`
+
+	UnsuspendOneAccountByIdQuery = `
+		UPDATE accounts
+		SET suspended_at = NULL, updated_at = NOW()
+		WHERE account_id = $1
+		AND suspended_at IS NOT NULL
+		AND deleted_at IS NULL
+	`
 )
@@ -0,0 +1,45 @@
+package database
+
+const (
+	CreateOneAccountSessionQuery = `
+		INSERT INTO account_sessions (account_id, user_agent, ip_address)
+		VALUES ($1, $2, $3)
+		RETURNING account_session_id
+	`
+
+	FindAllActiveAccountSessionsByAccountIdQuery = `
+		SELECT s.account_session_id, s.account_id, s.user_agent, s.ip_address, s.last_seen_at, s.created_at
+		FROM account_sessions s
+		WHERE s.account_id = $1
+		AND s.revoked_at IS NULL
+		AND EXISTS (
+			SELECT 1
+			FROM refresh_tokens rt
+			WHERE rt.account_session_id = s.account_session_id
+			AND rt.used_at IS NULL
+			AND rt.expired_at > NOW()
+		)
+		ORDER BY s.last_seen_at DESC
+	`
+
+	UpdateLastSeenOneAccountSessionQuery = `
+		UPDATE account_sessions
+		SET user_agent = $1, ip_address = $2, last_seen_at = NOW(), updated_at = NOW()
+		WHERE account_session_id = $3
+	`
+
+	RevokeOneAccountSessionQuery = `
+		UPDATE account_sessions
+		SET revoked_at = NOW(), updated_at = NOW()
+		WHERE account_session_id = $1
+		AND account_id = $2
+		AND revoked_at IS NULL
+	`
+
+	RevokeAllAccountSessionsByAccountIdQuery = `
+		UPDATE account_sessions
+		SET revoked_at = NOW(), updated_at = NOW()
+		WHERE account_id = $1
+		AND revoked_at IS NULL
+	`
+)
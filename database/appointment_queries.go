@@ -0,0 +1,95 @@
+package database
+
+const (
+	appointmentColumns = `
+		SELECT ap.appointment_id, ap.chat_room_id, ap.user_account_id, ua.account_name, ua.email, ap.doctor_account_id, da.account_name, da.email,
+			ap.scheduled_at, ap.end_at, ap.appointment_status, ap.reminder_sent_at, ap.cancelled_at, ap.created_at
+	`
+
+	appointmentJoins = `
+		FROM appointments ap
+		JOIN accounts ua ON ua.account_id = ap.user_account_id
+		JOIN accounts da ON da.account_id = ap.doctor_account_id
+	`
+
+	LockDoctorAppointmentsQuery = `
+		SELECT doctor_id
+		FROM doctors
+		WHERE account_id = $1
+		FOR UPDATE
+	`
+
+	CountOverlappingAppointmentsQuery = `
+		SELECT COUNT(*)
+		FROM appointments
+		WHERE doctor_account_id = $1
+		AND appointment_status = 'booked'
+		AND scheduled_at < $3::TIMESTAMPTZ
+		AND end_at > $2::TIMESTAMPTZ
+		AND deleted_at IS NULL
+	`
+
+	FindAllBookedAppointmentsBetweenQuery = appointmentColumns + appointmentJoins + `
+		WHERE ap.doctor_account_id = $1
+		AND ap.appointment_status = 'booked'
+		AND ap.scheduled_at < $3::TIMESTAMPTZ
+		AND ap.end_at > $2::TIMESTAMPTZ
+		AND ap.deleted_at IS NULL
+		ORDER BY ap.scheduled_at
+	`
+
+	CreateOneAppointmentQuery = `
+		INSERT INTO appointments (chat_room_id, user_account_id, doctor_account_id, scheduled_at, end_at)
+		VALUES ($1, $2, $3, $4::TIMESTAMPTZ, $5::TIMESTAMPTZ)
+		RETURNING appointment_id, appointment_status, created_at
+	`
+
+	FindOneAppointmentByIdQuery = appointmentColumns + appointmentJoins + `
+		WHERE ap.appointment_id = $1
+		AND ap.deleted_at IS NULL
+	`
+
+	FindOneAppointmentByIdForUpdateQuery = FindOneAppointmentByIdQuery + `
+		FOR UPDATE OF ap
+	`
+
+	FindAllAppointmentsQuery = appointmentColumns + `, COUNT(*) OVER()
+	` + appointmentJoins + `
+		WHERE ap.deleted_at IS NULL
+	`
+
+	CancelOneAppointmentQuery = `
+		UPDATE appointments
+		SET appointment_status = 'cancelled', cancelled_at = NOW(), updated_at = NOW()
+		WHERE appointment_id = $1
+		AND appointment_status = 'booked'
+		AND deleted_at IS NULL
+	`
+
+	CancelOneAppointmentByChatRoomIdQuery = `
+		UPDATE appointments
+		SET appointment_status = 'cancelled', cancelled_at = NOW(), updated_at = NOW()
+		WHERE chat_room_id = $1
+		AND appointment_status = 'booked'
+		AND deleted_at IS NULL
+	`
+
+	FindAllAppointmentsDueForReminderForUpdateQuery = appointmentColumns + appointmentJoins + `
+		JOIN chat_rooms cr ON cr.chat_room_id = ap.chat_room_id
+		WHERE ap.appointment_status = 'booked'
+		AND ap.reminder_sent_at IS NULL
+		AND ap.scheduled_at > NOW()
+		AND ap.scheduled_at <= NOW() + make_interval(secs => $1)
+		AND cr.expired_at IS NULL
+		AND ap.deleted_at IS NULL
+		ORDER BY ap.scheduled_at
+		LIMIT $2
+		FOR UPDATE OF ap SKIP LOCKED
+	`
+
+	MarkAppointmentReminderSentQuery = `
+		UPDATE appointments
+		SET reminder_sent_at = NOW(), updated_at = NOW()
+		WHERE appointment_id = $1
+	`
+)
@@ -60,8 +60,8 @@ const (
 	`
 
 	PostOneCartQuery = `
-		INSERT INTO cart_items (user_id, pharmacy_drug_id,quantity)
-		VALUES($1, $2, $3)
+		INSERT INTO cart_items (user_id, pharmacy_drug_id, quantity, prescription_drug_id)
+		VALUES($1, $2, $3, $4)
 		RETURNING cart_item_id
 	`
 
@@ -76,7 +76,7 @@ const (
 	`
 
 	GetAllCartQuery = `
-		Select ci.cart_item_id, ci.user_id, ci.pharmacy_drug_id, ci.quantity, pd.pharmacy_id, pd.drug_id, pd.price, pd.stock, d.drug_name, d.image, p.pharmacy_name
+		Select ci.cart_item_id, ci.user_id, ci.pharmacy_drug_id, ci.quantity, pd.pharmacy_id, pd.drug_id, pd.price, pd.stock, d.drug_name, d.image, p.pharmacy_name, pharmacy_is_open(p.pharmacy_id, NOW())
 		from cart_items ci
 		join pharmacy_drugs pd
 		on ci.pharmacy_drug_id = pd.pharmacy_drug_id
@@ -99,7 +99,7 @@ const (
 	`
 
 	GetAllDetailedCartItems = `
-		SELECT ci.cart_item_id, d.drug_id, d.drug_name, pd.pharmacy_drug_id, pd.price, d.unit_in_pack, ci.quantity
+		SELECT ci.cart_item_id, d.drug_id, d.drug_name, pd.pharmacy_drug_id, pd.price, d.unit_in_pack, ci.quantity, d.is_prescription_required, ci.prescription_drug_id
 		FROM cart_items ci
 		JOIN pharmacy_drugs pd
 		ON pd.pharmacy_drug_id = ci.pharmacy_drug_id
@@ -119,4 +119,16 @@ const (
 		SET updated_at = NOW(),
 		deleted_at = NOW()
 	`
+
+	GetAllCartDataByIds = `
+		SELECT ci.cart_item_id, ci.user_id, ci.pharmacy_drug_id, ci.quantity, pd.pharmacy_id, pd.drug_id, pd.price, pd.stock, d.drug_name, d.image, p.pharmacy_name
+		FROM cart_items ci
+		JOIN pharmacy_drugs pd
+		ON ci.pharmacy_drug_id = pd.pharmacy_drug_id
+		JOIN drugs d
+		ON pd.drug_id = d.drug_id
+		JOIN pharmacies p
+		ON pd.pharmacy_id = p.pharmacy_id
+		WHERE ci.deleted_at IS NULL AND pd.deleted_at IS NULL
+	`
 )
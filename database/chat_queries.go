@@ -14,4 +14,20 @@ const (
 		WHERE chat_room_id = $1
 		AND c.deleted_at IS NULL
 	`
+
+	FindOneChatByIdQuery = `
+		SELECT c.chat_id, c.chat_room_id, c.sender_account_id, c.chat_message, c.attachment_format, c.attachment_url, c.prescription_id, p.redeemed_at, c.created_at
+		FROM chats c
+		LEFT JOIN prescriptions p ON p.prescription_id = c.prescription_id
+		WHERE c.chat_id = $1
+		AND c.deleted_at IS NULL
+	`
+
+	ListenChatEventsQuery = `
+		LISTEN chat_events
+	`
+
+	NotifyChatEventQuery = `
+		SELECT pg_notify('chat_events', $1)
+	`
 )
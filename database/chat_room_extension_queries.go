@@ -0,0 +1,32 @@
+package database
+
+const (
+	chatRoomExtensionSelect = `
+		SELECT chat_room_extension_id, chat_room_id, requested_by_account_id, minutes, fee, extension_status, responded_at, created_at
+		FROM chat_room_extensions
+	`
+
+	CreateOneChatRoomExtensionQuery = `
+		INSERT INTO chat_room_extensions (chat_room_id, requested_by_account_id, minutes, fee)
+		VALUES ($1, $2, $3, $4)
+		RETURNING chat_room_extension_id, extension_status, created_at
+	`
+
+	FindOneChatRoomExtensionByIdForUpdateQuery = chatRoomExtensionSelect + `
+		WHERE chat_room_extension_id = $1
+		FOR UPDATE
+	`
+
+	FindOnePendingChatRoomExtensionQuery = chatRoomExtensionSelect + `
+		WHERE chat_room_id = $1
+		AND extension_status IN ('pending', 'waiting_for_payment')
+	`
+
+	UpdateChatRoomExtensionStatusOneQuery = `
+		UPDATE chat_room_extensions
+		SET extension_status = $2, responded_at = COALESCE(responded_at, NOW()), updated_at = NOW()
+		WHERE chat_room_extension_id = $1
+		AND extension_status = $3
+		RETURNING responded_at
+	`
+)
@@ -2,18 +2,29 @@ package database
 
 const (
 	CreateOneRoomQuery = `
-		INSERT INTO chat_rooms (user_account_id, doctor_account_id)
-		VALUES ($1, $2)
+		INSERT INTO chat_rooms (user_account_id, doctor_account_id, allow_fallback, scheduled_at)
+		VALUES ($1, $2, $3, $4::TIMESTAMPTZ)
 		RETURNING chat_room_id
 	`
 
 	StartChatQuery = `
 		UPDATE chat_rooms
-		SET expired_at = NOW() + INTERVAL '30 minutes'
+		SET expired_at = NOW() + make_interval(mins => $3), started_at = NOW(), updated_at = NOW()
 		WHERE chat_room_id = $1
 		AND doctor_account_id = $2
 		AND expired_at IS NULL
 		AND deleted_at IS NULL
+		RETURNING expired_at
+	`
+
+	ExtendChatRoomQuery = `
+		UPDATE chat_rooms
+		SET expired_at = expired_at + make_interval(mins => $2), updated_at = NOW()
+		WHERE chat_room_id = $1
+		AND started_at IS NOT NULL
+		AND expired_at > NOW()
+		AND deleted_at IS NULL
+		RETURNING expired_at
 	`
 
 	FindActiveChatRoomQuery = `
@@ -26,7 +37,7 @@ const (
 	`
 
 	FindChatRoomByIdQuery = `
-		SELECT user_account_id, doctor_account_id, expired_at
+		SELECT user_account_id, doctor_account_id, expired_at, allow_fallback, queued_at, assigned_at, started_at, scheduled_at
 		FROM chat_rooms
 		WHERE chat_room_id = $1 
 		AND deleted_at IS NULL
@@ -62,16 +73,18 @@ const (
 			CASE 
 				WHEN c.chat_message IS NULL THEN cr.created_at
 				ELSE c.created_at
-			END
+			END,
+			ROW_NUMBER() OVER (ORDER BY cr.queued_at, cr.chat_room_id)
 		FROM chat_rooms cr 
 		JOIN accounts a  ON a.account_id  = cr.user_account_id
+		JOIN consultation_payments cp ON cp.chat_room_id = cr.chat_room_id AND cp.chat_room_extension_id IS NULL AND cp.payment_status = 'paid' AND cp.deleted_at IS NULL
 		LEFT JOIN chats c ON c.chat_id = 
 			(SELECT c2.chat_id
 			FROM chats c2
 			WHERE c2.chat_room_id = cr.chat_room_id 
 			ORDER BY created_at DESC limit 1)
-		WHERE cr.deleted_at IS NULL AND expired_at IS NULL AND doctor_account_id = $1
-		ORDER BY created_at DESC
+		WHERE cr.deleted_at IS NULL AND cr.expired_at IS NULL AND cr.queued_at <= NOW() AND cr.doctor_account_id = $1
+		ORDER BY cr.queued_at, cr.chat_room_id
 	`
 
 	CloseChatRoomQuery = `
@@ -79,4 +92,56 @@ const (
 		SET expired_at = NOW(), updated_at = NOW()
 		WHERE chat_room_id = $1
 	`
+
+	EnqueueChatRoomQuery = `
+		UPDATE chat_rooms
+		SET queued_at = GREATEST(NOW(), scheduled_at), assigned_at = GREATEST(NOW(), scheduled_at), updated_at = NOW()
+		WHERE chat_room_id = $1
+		AND queued_at IS NULL
+		AND expired_at IS NULL
+		AND deleted_at IS NULL
+	`
+
+	ReassignChatRoomQuery = `
+		UPDATE chat_rooms
+		SET doctor_account_id = $2, assigned_at = NOW(), updated_at = NOW()
+		WHERE chat_room_id = $1
+		AND expired_at IS NULL
+		AND deleted_at IS NULL
+	`
+
+	CreateOneChatRoomDeclineQuery = `
+		INSERT INTO chat_room_declines (chat_room_id, doctor_account_id, reason)
+		VALUES ($1, $2, $3)
+	`
+
+	GetChatRoomQueuePositionQuery = `
+		SELECT q.position, q.queue_length
+		FROM (
+			SELECT cr.chat_room_id,
+				ROW_NUMBER() OVER (ORDER BY cr.queued_at, cr.chat_room_id) AS position,
+				COUNT(*) OVER () AS queue_length
+			FROM chat_rooms cr
+			WHERE cr.doctor_account_id = $2
+			AND cr.queued_at <= NOW()
+			AND cr.expired_at IS NULL
+			AND cr.deleted_at IS NULL
+		) q
+		WHERE q.chat_room_id = $1
+	`
+
+	GetDoctorConsultationHistoryQuery = `
+		SELECT COUNT(*),
+			COALESCE(EXTRACT(EPOCH FROM AVG(h.started_at - h.assigned_at)), 0),
+			COALESCE(EXTRACT(EPOCH FROM AVG(h.expired_at - h.started_at) FILTER (WHERE h.expired_at <= NOW())), 0)
+		FROM (
+			SELECT cr.assigned_at, cr.started_at, cr.expired_at
+			FROM chat_rooms cr
+			WHERE cr.doctor_account_id = $1
+			AND cr.started_at IS NOT NULL
+			AND cr.deleted_at IS NULL
+			ORDER BY cr.started_at DESC
+			LIMIT $2
+		) h
+	`
 )
@@ -0,0 +1,137 @@
+package database
+
+const (
+	consultationPaymentSelect = `
+		SELECT cp.consultation_payment_id, cp.chat_room_id, cr.user_account_id, ua.account_name, cr.doctor_account_id, da.account_name,
+			cp.chat_room_extension_id, cp.amount, cp.payment_status, cp.payment_proof, cp.confirmed_at, cp.cancelled_at, cp.refunded_at, cp.created_at
+		FROM consultation_payments cp
+		JOIN chat_rooms cr ON cr.chat_room_id = cp.chat_room_id
+		JOIN accounts ua ON ua.account_id = cr.user_account_id
+		JOIN accounts da ON da.account_id = cr.doctor_account_id
+	`
+
+	CreateOneConsultationPaymentQuery = `
+		INSERT INTO consultation_payments (chat_room_id, chat_room_extension_id, amount, payment_status, confirmed_at)
+		VALUES ($1, $2, $3, $4::VARCHAR, CASE WHEN $4::VARCHAR = 'paid' THEN NOW() END)
+		RETURNING consultation_payment_id
+	`
+
+	FindOneConsultationPaymentByChatRoomIdQuery = consultationPaymentSelect + `
+		WHERE cp.chat_room_id = $1
+		AND cp.chat_room_extension_id IS NULL
+		AND cp.deleted_at IS NULL
+	`
+
+	FindOneConsultationPaymentByChatRoomIdForUpdateQuery = FindOneConsultationPaymentByChatRoomIdQuery + `
+		FOR UPDATE OF cp
+	`
+
+	FindAllConsultationPaymentsQuery = `
+		SELECT cp.consultation_payment_id, cp.chat_room_id, cr.user_account_id, ua.account_name, cr.doctor_account_id, da.account_name,
+			cp.chat_room_extension_id, cp.amount, cp.payment_status, cp.payment_proof, cp.confirmed_at, cp.cancelled_at, cp.refunded_at, cp.created_at, COUNT(*) OVER()
+		FROM consultation_payments cp
+		JOIN chat_rooms cr ON cr.chat_room_id = cp.chat_room_id
+		JOIN accounts ua ON ua.account_id = cr.user_account_id
+		JOIN accounts da ON da.account_id = cr.doctor_account_id
+		WHERE cp.deleted_at IS NULL
+	`
+
+	FindAllExpiredConsultationPaymentsForUpdateQuery = consultationPaymentSelect + `
+		WHERE cp.deleted_at IS NULL
+		AND cp.chat_room_extension_id IS NULL
+		AND cr.deleted_at IS NULL
+		AND cr.expired_at IS NULL
+		AND (
+			(cp.payment_status = 'waiting_for_payment' AND cp.updated_at < NOW() - make_interval(secs => $1))
+			OR (cp.payment_status = 'paid' AND cr.assigned_at < NOW() - make_interval(secs => $2))
+		)
+		ORDER BY cp.created_at
+		LIMIT $3
+		FOR UPDATE OF cp SKIP LOCKED
+	`
+
+	FindOneExtensionPaymentByChatRoomExtensionIdQuery = consultationPaymentSelect + `
+		WHERE cp.chat_room_extension_id = $1
+		AND cp.deleted_at IS NULL
+	`
+
+	FindOneExtensionPaymentByChatRoomExtensionIdForUpdateQuery = FindOneExtensionPaymentByChatRoomExtensionIdQuery + `
+		FOR UPDATE OF cp
+	`
+
+	// The fee of an extension is paid through its own payment, every query
+	// keyed by chat room only touches the payment of the consultation itself.
+	consultationPaymentByChatRoomId = `
+		WHERE chat_room_id = $1
+		AND chat_room_extension_id IS NULL
+		AND deleted_at IS NULL
+	`
+
+	consultationPaymentByChatRoomExtensionId = `
+		WHERE chat_room_extension_id = $1
+		AND deleted_at IS NULL
+	`
+
+	updateConsultationPaymentProof = `
+		UPDATE consultation_payments
+		SET payment_proof = $2, payment_status = 'waiting_for_confirmation', updated_at = NOW()
+	`
+
+	UpdateConsultationPaymentProofOneQuery = updateConsultationPaymentProof + consultationPaymentByChatRoomId + `
+		AND payment_status = 'waiting_for_payment'
+	`
+
+	UpdateExtensionPaymentProofOneQuery = updateConsultationPaymentProof + consultationPaymentByChatRoomExtensionId + `
+		AND payment_status = 'waiting_for_payment'
+	`
+
+	confirmConsultationPayment = `
+		UPDATE consultation_payments
+		SET payment_status = 'paid', confirmed_at = NOW(), updated_at = NOW()
+	`
+
+	ConfirmConsultationPaymentOneQuery = confirmConsultationPayment + consultationPaymentByChatRoomId + `
+		AND payment_status = 'waiting_for_confirmation'
+	`
+
+	ConfirmExtensionPaymentOneQuery = confirmConsultationPayment + consultationPaymentByChatRoomExtensionId + `
+		AND payment_status = 'waiting_for_confirmation'
+	`
+
+	rejectConsultationPayment = `
+		UPDATE consultation_payments
+		SET payment_proof = '', payment_status = 'waiting_for_payment', updated_at = NOW()
+	`
+
+	RejectConsultationPaymentOneQuery = rejectConsultationPayment + consultationPaymentByChatRoomId + `
+		AND payment_status = 'waiting_for_confirmation'
+	`
+
+	RejectExtensionPaymentOneQuery = rejectConsultationPayment + consultationPaymentByChatRoomExtensionId + `
+		AND payment_status = 'waiting_for_confirmation'
+	`
+
+	cancelConsultationPayment = `
+		UPDATE consultation_payments
+		SET payment_status = CASE
+				WHEN payment_status IN ('waiting_for_confirmation', 'paid') AND amount > 0 THEN 'refunded'
+				ELSE 'cancelled'
+			END,
+			refunded_at = CASE
+				WHEN payment_status IN ('waiting_for_confirmation', 'paid') AND amount > 0 THEN NOW()
+			END,
+			cancelled_at = CASE
+				WHEN payment_status IN ('waiting_for_confirmation', 'paid') AND amount > 0 THEN NULL
+				ELSE NOW()
+			END,
+			updated_at = NOW()
+	`
+
+	CancelConsultationPaymentOneQuery = cancelConsultationPayment + consultationPaymentByChatRoomId + `
+		AND payment_status IN ('waiting_for_payment', 'waiting_for_confirmation', 'paid')
+	`
+
+	CancelExtensionPaymentOneQuery = cancelConsultationPayment + consultationPaymentByChatRoomExtensionId + `
+		AND payment_status IN ('waiting_for_payment', 'waiting_for_confirmation', 'paid')
+	`
+)
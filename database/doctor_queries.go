@@ -11,6 +11,7 @@ const (
 		UPDATE doctors 
 		SET fee_per_patient = $1,
 		experience = $2,
+		consultation_duration_minutes = COALESCE($4, consultation_duration_minutes),
 		updated_at = NOW()
 		WHERE account_id = $3
 	`
@@ -22,7 +23,8 @@ const (
 	`
 
 	FindDoctorByAccountIdQuery = `
-		SELECT d.doctor_id, d.experience, d.specialization_id, ds.specialization_name, d.fee_per_patient, d.certificate
+		SELECT d.doctor_id, d.experience, d.specialization_id, ds.specialization_name, d.fee_per_patient, d.certificate,
+			COALESCE(d.consultation_duration_minutes, ds.consultation_duration_minutes), d.rating, d.review_count
 		FROM doctors d
 		JOIN accounts a
 		ON d.account_id = a.account_id
@@ -34,7 +36,8 @@ const (
 	`
 
 	FindDoctorByDoctorIdQuery = `
-		SELECT d.doctor_id, a.email, a.account_name, a.profile_picture, d.experience, d.specialization_id, ds.specialization_name, d.fee_per_patient
+		SELECT d.doctor_id, a.email, a.account_name, a.profile_picture, d.experience, d.specialization_id, ds.specialization_name, d.fee_per_patient,
+			COALESCE(d.consultation_duration_minutes, ds.consultation_duration_minutes), d.rating, d.review_count
 		FROM doctors d
 		JOIN accounts a
 		ON d.account_id = a.account_id
@@ -58,4 +61,44 @@ const (
 		WHERE d.account_id = $1 
 		AND deleted_at IS NULL
 	`
+
+	FindFallbackDoctorAccountIdQuery = `
+		SELECT d.account_id
+		FROM doctors d
+		JOIN accounts a ON a.account_id = d.account_id
+		WHERE d.specialization_id = (
+			SELECT d2.specialization_id
+			FROM doctors d2
+			WHERE d2.account_id = $2
+		)
+		AND d.account_id <> $2
+		AND d.is_online = TRUE
+		AND d.fee_per_patient <= $4
+		AND a.verified_at IS NOT NULL
+		AND a.suspended_at IS NULL
+		AND (d.deleted_at IS NULL AND a.deleted_at IS NULL)
+		AND NOT EXISTS (
+			SELECT 1
+			FROM chat_room_declines crd
+			WHERE crd.chat_room_id = $1
+			AND crd.doctor_account_id = d.account_id
+		)
+		AND NOT EXISTS (
+			SELECT 1
+			FROM chat_rooms cr
+			WHERE cr.user_account_id = $3
+			AND cr.doctor_account_id = d.account_id
+			AND (cr.expired_at > NOW() OR cr.expired_at IS NULL)
+			AND cr.deleted_at IS NULL
+		)
+		ORDER BY (
+			SELECT COUNT(*)
+			FROM chat_rooms cr
+			WHERE cr.doctor_account_id = d.account_id
+			AND cr.queued_at <= NOW()
+			AND cr.expired_at IS NULL
+			AND cr.deleted_at IS NULL
+		), d.experience DESC, d.doctor_id
+		LIMIT 1
+	`
 )
@@ -0,0 +1,69 @@
+package database
+
+const (
+	doctorReviewColumns = `
+		SELECT dr.doctor_review_id, dr.chat_room_id, dr.user_account_id, ua.account_name, dr.doctor_account_id, da.account_name,
+			dr.rating, dr.review, dr.reply, dr.replied_at, dr.hidden_at, dr.hidden_reason, dr.created_at
+	`
+
+	doctorReviewJoins = `
+		FROM doctor_reviews dr
+		JOIN accounts ua ON ua.account_id = dr.user_account_id
+		JOIN accounts da ON da.account_id = dr.doctor_account_id
+	`
+
+	CreateOneDoctorReviewQuery = `
+		INSERT INTO doctor_reviews (chat_room_id, user_account_id, doctor_account_id, rating, review)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING doctor_review_id, created_at
+	`
+
+	FindOneDoctorReviewByIdQuery = doctorReviewColumns + doctorReviewJoins + `
+		WHERE dr.doctor_review_id = $1
+		AND dr.deleted_at IS NULL
+	`
+
+	FindOneDoctorReviewByChatRoomIdQuery = doctorReviewColumns + doctorReviewJoins + `
+		WHERE dr.chat_room_id = $1
+		AND dr.deleted_at IS NULL
+	`
+
+	FindAllDoctorReviewsQuery = doctorReviewColumns + `, COUNT(*) OVER()
+	` + doctorReviewJoins + `
+		WHERE dr.deleted_at IS NULL
+	`
+
+	UpdateDoctorReviewReplyOneQuery = `
+		UPDATE doctor_reviews
+		SET reply = $2, replied_at = NOW(), updated_at = NOW()
+		WHERE doctor_review_id = $1
+		AND deleted_at IS NULL
+	`
+
+	UpdateDoctorReviewVisibilityOneQuery = `
+		UPDATE doctor_reviews
+		SET hidden_at = CASE WHEN $2::BOOLEAN THEN NOW() END, hidden_reason = $3, updated_at = NOW()
+		WHERE doctor_review_id = $1
+		AND deleted_at IS NULL
+	`
+
+	LockDoctorRatingQuery = `
+		SELECT doctor_id
+		FROM doctors
+		WHERE account_id = $1
+		FOR UPDATE
+	`
+
+	RefreshDoctorRatingQuery = `
+		UPDATE doctors d
+		SET rating = COALESCE(s.rating, 0), review_count = s.review_count, updated_at = NOW()
+		FROM (
+			SELECT ROUND(AVG(rating), 2) AS rating, COUNT(*) AS review_count
+			FROM doctor_reviews
+			WHERE doctor_account_id = $1
+			AND hidden_at IS NULL
+			AND deleted_at IS NULL
+		) s
+		WHERE d.account_id = $1
+	`
+)
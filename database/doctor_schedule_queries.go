@@ -0,0 +1,91 @@
+package database
+
+const (
+	FindDoctorScheduleSettingsByAccountIdQuery = `
+		SELECT d.timezone, d.use_schedule_availability
+		FROM doctors d
+		WHERE d.account_id = $1
+		AND d.deleted_at IS NULL
+	`
+
+	FindDoctorScheduleSettingsByDoctorIdQuery = `
+		SELECT d.account_id, d.timezone, d.use_schedule_availability
+		FROM doctors d
+		WHERE d.doctor_id = $1
+		AND d.deleted_at IS NULL
+	`
+
+	FindAllDoctorSchedulesByAccountIdQuery = `
+		SELECT doctor_schedule_id, doctor_account_id, schedule_day,
+		TO_CHAR(start_hour, 'HH24:MI'), TO_CHAR(end_hour, 'HH24:MI')
+		FROM doctor_schedules
+		WHERE doctor_account_id = $1
+		AND deleted_at IS NULL
+		ORDER BY CASE schedule_day
+			WHEN 'Monday' THEN 1
+			WHEN 'Tuesday' THEN 2
+			WHEN 'Wednesday' THEN 3
+			WHEN 'Thursday' THEN 4
+			WHEN 'Friday' THEN 5
+			WHEN 'Saturday' THEN 6
+			ELSE 7
+		END, start_hour
+	`
+
+	FindAllUpcomingDoctorScheduleExceptionsByAccountIdQuery = `
+		SELECT doctor_schedule_exception_id, doctor_account_id, exception_date, is_available,
+		TO_CHAR(start_hour, 'HH24:MI'), TO_CHAR(end_hour, 'HH24:MI'), description
+		FROM doctor_schedule_exceptions
+		WHERE doctor_account_id = $1
+		AND exception_date >= $2
+		AND deleted_at IS NULL
+		ORDER BY exception_date
+	`
+
+	UpdateDoctorScheduleSettingsQuery = `
+		UPDATE doctors
+		SET timezone = $2, use_schedule_availability = $3, updated_at = NOW()
+		WHERE account_id = $1
+		AND deleted_at IS NULL
+	`
+
+	DeleteAllDoctorSchedulesByAccountIdQuery = `
+		UPDATE doctor_schedules
+		SET deleted_at = NOW(), updated_at = NOW()
+		WHERE doctor_account_id = $1
+		AND deleted_at IS NULL
+	`
+
+	CreateOneDoctorScheduleQuery = `
+		INSERT INTO doctor_schedules (doctor_account_id, schedule_day, start_hour, end_hour)
+		VALUES ($1, $2, $3::TIME, $4::TIME)
+	`
+
+	CreateOneDoctorScheduleExceptionQuery = `
+		INSERT INTO doctor_schedule_exceptions (doctor_account_id, exception_date, is_available, start_hour, end_hour, description)
+		VALUES ($1, $2, $3, $4::TIME, $5::TIME, $6)
+		ON CONFLICT (doctor_account_id, exception_date) WHERE deleted_at IS NULL
+		DO UPDATE SET is_available = EXCLUDED.is_available,
+		start_hour = EXCLUDED.start_hour,
+		end_hour = EXCLUDED.end_hour,
+		description = EXCLUDED.description,
+		updated_at = NOW()
+		RETURNING doctor_schedule_exception_id
+	`
+
+	DeleteOneDoctorScheduleExceptionQuery = `
+		UPDATE doctor_schedule_exceptions
+		SET deleted_at = NOW(), updated_at = NOW()
+		WHERE doctor_schedule_exception_id = $1
+		AND doctor_account_id = $2
+		AND deleted_at IS NULL
+	`
+
+	SyncDoctorScheduleAvailabilityQuery = `
+		UPDATE doctors
+		SET is_online = doctor_is_available(account_id, NOW()), updated_at = NOW()
+		WHERE use_schedule_availability = TRUE
+		AND is_online IS DISTINCT FROM doctor_is_available(account_id, NOW())
+		AND deleted_at IS NULL
+	`
+)
@@ -2,8 +2,15 @@ package database
 
 const (
 	GetAllDoctorSpecializationQuery = `
-		SELECT specialization_id, specialization_name
+		SELECT specialization_id, specialization_name, consultation_duration_minutes
 		FROM doctor_specializations
 		WHERE deleted_at IS NULL
 	`
+
+	UpdateDoctorSpecializationConsultationDurationQuery = `
+		UPDATE doctor_specializations
+		SET consultation_duration_minutes = $2
+		WHERE specialization_id = $1
+		AND deleted_at IS NULL
+	`
 )
\ No newline at end of file
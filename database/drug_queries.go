@@ -190,4 +190,11 @@ const (
 		LIMIT $2
 		OFFSET $3
 	`
+
+	GetDrugsByIdsOrNamesQuery = `
+		SELECT d.drug_id, d.drug_name
+		FROM drugs d
+		WHERE (d.drug_id = ANY($1) OR LOWER(d.drug_name) = ANY($2))
+		AND d.deleted_at IS NULL
+	`
 )
@@ -0,0 +1,55 @@
+package database
+
+const (
+	DrugSearchRankColumn = `
+		ts_rank(d.search_vector, tsq) + GREATEST(word_similarity(st.term, d.drug_name), word_similarity(st.term, COALESCE(d.generic_name, ''))) AS search_rank
+	`
+
+	DrugNoSearchRankColumn = `
+		0 AS search_rank
+	`
+
+	DrugSearchTermJoin = `
+		CROSS JOIN websearch_to_tsquery('indonesian', st.term) AS tsq
+	`
+
+	DrugSearchCondition = `
+		AND (d.search_vector @@ tsq
+			OR st.term <% d.drug_name
+			OR st.term <% COALESCE(d.generic_name, '')
+			OR d.drug_name ILIKE '%' || st.term || '%')
+	`
+
+	SuggestDrugsQuery = `
+		SELECT d.drug_id, d.drug_name, COALESCE(d.generic_name, '')
+		FROM drugs d
+		WHERE d.deleted_at IS NULL
+			AND d.is_active
+			AND (d.drug_name ILIKE $1::TEXT || '%'
+				OR d.generic_name ILIKE $1::TEXT || '%'
+				OR $1::TEXT <% d.drug_name
+				OR $1::TEXT <% COALESCE(d.generic_name, ''))
+		ORDER BY
+			(d.drug_name ILIKE $1::TEXT || '%') DESC,
+			GREATEST(word_similarity($1::TEXT, d.drug_name), word_similarity($1::TEXT, COALESCE(d.generic_name, ''))) DESC,
+			d.drug_name ASC
+		LIMIT $2
+	`
+
+	FindDrugSearchCorrectionsQuery = `
+		SELECT w.word, COALESCE(t.term, w.word)
+		FROM unnest($1::TEXT[]) WITH ORDINALITY AS w(word, position)
+		LEFT JOIN LATERAL (
+			SELECT dst.term
+			FROM drug_search_terms dst
+			WHERE dst.term % w.word
+			ORDER BY similarity(dst.term, w.word) DESC, dst.frequency DESC
+			LIMIT 1
+		) t ON NOT EXISTS (SELECT 1 FROM drug_search_terms WHERE term = w.word)
+		ORDER BY w.position
+	`
+
+	RefreshDrugSearchTermsQuery = `
+		REFRESH MATERIALIZED VIEW CONCURRENTLY drug_search_terms
+	`
+)
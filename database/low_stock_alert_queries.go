@@ -0,0 +1,42 @@
+package database
+
+const (
+	lowStockAlertColumns = `
+		SELECT la.low_stock_alert_id, la.pharmacy_manager_id, a.account_name, a.email, la.pharmacy_drug_id, pd.pharmacy_id, p.pharmacy_name,
+			pd.drug_id, d.drug_name, la.stock_change_id, la.stock, la.reorder_threshold, la.read_at, la.created_at
+	`
+
+	lowStockAlertJoins = `
+		FROM low_stock_alerts la
+		JOIN pharmacy_managers pm ON pm.pharmacy_manager_id = la.pharmacy_manager_id
+		JOIN accounts a ON a.account_id = pm.account_id
+		JOIN pharmacy_drugs pd ON pd.pharmacy_drug_id = la.pharmacy_drug_id
+		JOIN pharmacies p ON p.pharmacy_id = pd.pharmacy_id
+		JOIN drugs d ON d.drug_id = pd.drug_id
+	`
+
+	CreateLowStockAlertQuery = `
+		INSERT INTO low_stock_alerts (pharmacy_manager_id, pharmacy_drug_id, stock_change_id, stock, reorder_threshold)
+		SELECT p.pharmacy_manager_id, pd.pharmacy_drug_id, $2, $3, $4
+		FROM pharmacy_drugs pd
+		JOIN pharmacies p ON p.pharmacy_id = pd.pharmacy_id
+		WHERE pd.pharmacy_drug_id = $1
+		ON CONFLICT (stock_change_id) DO NOTHING
+		RETURNING low_stock_alert_id
+	`
+
+	FindAllLowStockAlertsByIdsQuery = lowStockAlertColumns + lowStockAlertJoins + `
+		WHERE la.low_stock_alert_id = ANY($1)
+		ORDER BY la.low_stock_alert_id
+	`
+
+	FindAllLowStockAlertsQuery = lowStockAlertColumns + `, COUNT(*) OVER()` + lowStockAlertJoins + `
+		WHERE la.pharmacy_manager_id = $1
+	`
+
+	MarkLowStockAlertReadQuery = `
+		UPDATE low_stock_alerts
+		SET read_at = COALESCE(read_at, NOW()), updated_at = NOW()
+		WHERE low_stock_alert_id = $1 AND pharmacy_manager_id = $2
+	`
+)
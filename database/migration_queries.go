@@ -0,0 +1,44 @@
+package database
+
+const (
+	CreateSchemaMigrationsTable = `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			name VARCHAR NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)
+	`
+
+	LockSchemaMigrations = `
+		SELECT pg_advisory_xact_lock(hashtext('schema_migrations'))
+	`
+
+	FindAllAppliedMigrations = `
+		SELECT version, name, applied_at
+		FROM schema_migrations
+		ORDER BY version
+	`
+
+	FindAppliedMigrationByVersion = `
+		SELECT EXISTS (
+			SELECT 1
+			FROM schema_migrations
+			WHERE version = $1
+		)
+	`
+
+	FindLatestAppliedMigrationVersion = `
+		SELECT COALESCE(MAX(version), 0)
+		FROM schema_migrations
+	`
+
+	CreateOneAppliedMigration = `
+		INSERT INTO schema_migrations(version, name)
+		VALUES ($1, $2)
+	`
+
+	DeleteOneAppliedMigration = `
+		DELETE FROM schema_migrations
+		WHERE version = $1
+	`
+)
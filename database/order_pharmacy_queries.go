@@ -76,6 +76,10 @@ const (
 		AND o.deleted_at IS NULL
 	`
 
+	FindAllOrderPharmaciesByOrderIdForUpdate = FindAllOrderPharmaciesByOrderId + `
+		FOR UPDATE OF op
+	`
+
 	FindAllOngoingOrderPharmacyIdsByPharmacyId = `
 		SELECT op.order_pharmacy_id
 		FROM order_pharmacies op
@@ -92,6 +96,15 @@ const (
 		WHERE order_id = $2
 	`
 
+	UpdateStatusBulkOrderPharmaciesByOrderIdAndStatusId = `
+		UPDATE order_pharmacies
+		SET order_status_id = $1,
+		updated_at = NOW()
+		WHERE order_id = $2
+		AND order_status_id = $3
+		AND deleted_at IS NULL
+	`
+
 	FindOrderPharmacyByOrderPharmacyId = `
 		SELECT op.order_pharmacy_id, o.user_id, op.order_id, op.order_status_id, op.pharmacy_courier_id, op.subtotal_amount, 
 			op.delivery_fee
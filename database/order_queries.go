@@ -2,7 +2,7 @@ package database
 
 const (
 	FindAllOrders = `
-		SELECT DISTINCT o.order_id, o.created_at, count(o.order_id) AS total_item_count
+		SELECT DISTINCT o.order_id, o.created_at, count(*) OVER() AS total_item_count
 		FROM orders o
 		JOIN order_pharmacies op on op.order_id = o.order_id
 		WHERE o.deleted_at IS NULL
@@ -61,7 +61,7 @@ const (
 	`
 
 	CreateOrderItems = `
-		INSERT INTO order_items(order_pharmacy_id, drug_id, drug_name, pharmacy_drug_id, drug_price, drug_unit, quantity)
+		INSERT INTO order_items(order_pharmacy_id, drug_id, drug_name, pharmacy_drug_id, drug_price, drug_unit, quantity, prescription_drug_id)
 		VALUES
 	`
 
@@ -77,4 +77,23 @@ const (
 		FROM orders
 		WHERE order_id = $1 AND deleted_at IS NULL
 	`
+
+	FindExpiredUnpaidOrdersForUpdate = `
+		SELECT o.order_id, o.total_amount, o.created_at, a.email, a.account_name
+		FROM orders o
+		JOIN users u ON u.user_id = o.user_id
+		JOIN accounts a ON a.account_id = u.account_id
+		WHERE o.deleted_at IS NULL
+		AND o.expired_at <= NOW()
+		AND EXISTS (
+			SELECT 1
+			FROM order_pharmacies op
+			WHERE op.order_id = o.order_id
+			AND op.order_status_id = 1
+			AND op.deleted_at IS NULL
+		)
+		ORDER BY o.expired_at
+		LIMIT $1
+		FOR UPDATE OF o SKIP LOCKED
+	`
 )
@@ -0,0 +1,115 @@
+package database
+
+const (
+	pharmacyDrugBatchColumns = `
+		SELECT b.pharmacy_drug_batch_id, b.pharmacy_drug_id, b.batch_number, b.expiry_date, b.stock, b.written_off_at, b.created_at
+	`
+
+	FindAllPharmacyDrugBatchesByPharmacyDrugIdQuery = pharmacyDrugBatchColumns + `
+		FROM pharmacy_drug_batches b
+		WHERE b.pharmacy_drug_id = $1 AND b.deleted_at IS NULL
+		ORDER BY b.expiry_date ASC NULLS LAST, b.pharmacy_drug_batch_id ASC
+	`
+
+	FindAllAvailablePharmacyDrugBatchesForUpdateQuery = pharmacyDrugBatchColumns + `
+		FROM pharmacy_drug_batches b
+		WHERE b.pharmacy_drug_id = $1 AND b.deleted_at IS NULL AND b.stock > 0
+			AND (b.expiry_date IS NULL OR b.expiry_date >= CURRENT_DATE)
+		ORDER BY b.expiry_date ASC NULLS LAST, b.pharmacy_drug_batch_id ASC
+		FOR UPDATE
+	`
+
+	FindAllExpiredPharmacyDrugBatchesQuery = pharmacyDrugBatchColumns + `
+		FROM pharmacy_drug_batches b
+		WHERE b.deleted_at IS NULL AND b.stock > 0 AND b.expiry_date < CURRENT_DATE
+		ORDER BY b.expiry_date ASC, b.pharmacy_drug_batch_id ASC
+		LIMIT $1
+	`
+
+	FindAllExpiringPharmacyDrugBatchesQuery = pharmacyDrugBatchColumns + `, pd.pharmacy_id, p.pharmacy_name, pd.drug_id, d.drug_name, COUNT(*) OVER()
+		FROM pharmacy_drug_batches b
+		JOIN pharmacy_drugs pd ON pd.pharmacy_drug_id = b.pharmacy_drug_id
+		JOIN pharmacies p ON p.pharmacy_id = pd.pharmacy_id
+		JOIN drugs d ON d.drug_id = pd.drug_id
+		WHERE b.deleted_at IS NULL AND pd.deleted_at IS NULL AND b.stock > 0
+			AND b.expiry_date <= CURRENT_DATE + $2::INT
+			AND p.pharmacy_manager_id = $1
+	`
+
+	UpsertPharmacyDrugBatchStockQuery = `
+		INSERT INTO pharmacy_drug_batches (pharmacy_drug_id, batch_number, expiry_date, stock)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (pharmacy_drug_id, batch_number) WHERE deleted_at IS NULL
+		DO UPDATE SET stock = pharmacy_drug_batches.stock + EXCLUDED.stock, updated_at = NOW()
+		RETURNING pharmacy_drug_batch_id, expiry_date
+	`
+
+	DeductPharmacyDrugBatchStockQuery = `
+		UPDATE pharmacy_drug_batches
+		SET stock = stock - $2, updated_at = NOW()
+		WHERE pharmacy_drug_batch_id = $1
+	`
+
+	WriteOffPharmacyDrugBatchOneQuery = `
+		WITH expired AS (
+			SELECT pharmacy_drug_batch_id, stock
+			FROM pharmacy_drug_batches
+			WHERE pharmacy_drug_batch_id = $1 AND stock > 0 AND expiry_date < CURRENT_DATE
+			FOR UPDATE
+		)
+		UPDATE pharmacy_drug_batches b
+		SET stock = 0, written_off_at = NOW(), updated_at = NOW()
+		FROM expired e
+		WHERE b.pharmacy_drug_batch_id = e.pharmacy_drug_batch_id
+		RETURNING e.stock
+	`
+
+	CreatePharmacyDrugBatchAllocations = `
+		INSERT INTO pharmacy_drug_batch_allocations (pharmacy_drug_id, pharmacy_drug_batch_id, order_pharmacy_id, stock_mutation_request_id, quantity)
+		VALUES
+	`
+
+	FindAllPendingPharmacyDrugBatchAllocationsForUpdateQuery = `
+		SELECT pharmacy_drug_batch_allocation_id, pharmacy_drug_id, order_pharmacy_id, stock_mutation_request_id, quantity
+		FROM pharmacy_drug_batch_allocations
+		WHERE pharmacy_drug_id = $1 AND pharmacy_drug_batch_id IS NULL AND released_at IS NULL AND quantity > 0
+		ORDER BY created_at ASC, pharmacy_drug_batch_allocation_id ASC
+		FOR UPDATE
+	`
+
+	ReducePendingPharmacyDrugBatchAllocationOneQuery = `
+		UPDATE pharmacy_drug_batch_allocations
+		SET quantity = quantity - $2, updated_at = NOW()
+		WHERE pharmacy_drug_batch_allocation_id = $1
+	`
+
+	releasePharmacyDrugBatchAllocations = `
+		WITH released AS (
+			UPDATE pharmacy_drug_batch_allocations
+			SET released_at = NOW(), updated_at = NOW()
+			WHERE released_at IS NULL AND order_pharmacy_id IN (
+	`
+
+	restoreReleasedPharmacyDrugBatches = `
+			)
+			RETURNING pharmacy_drug_batch_id, quantity
+		), restored AS (
+			SELECT pharmacy_drug_batch_id, SUM(quantity) AS quantity
+			FROM released
+			WHERE pharmacy_drug_batch_id IS NOT NULL
+			GROUP BY pharmacy_drug_batch_id
+		)
+		UPDATE pharmacy_drug_batches b
+		SET stock = b.stock + r.quantity, updated_at = NOW()
+		FROM restored r
+		WHERE r.pharmacy_drug_batch_id = b.pharmacy_drug_batch_id
+	`
+
+	ReleasePharmacyDrugBatchAllocationsByOrderPharmacyIdQuery = releasePharmacyDrugBatchAllocations + `
+		$1
+	` + restoreReleasedPharmacyDrugBatches
+
+	ReleasePharmacyDrugBatchAllocationsByOrderIdQuery = releasePharmacyDrugBatchAllocations + `
+		SELECT order_pharmacy_id FROM order_pharmacies WHERE order_id = $1
+	` + restoreReleasedPharmacyDrugBatches
+)
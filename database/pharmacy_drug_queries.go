@@ -19,6 +19,7 @@ const (
 		WHERE d.drug_id = $1 
 			AND pd.deleted_at IS NULL
 			AND ST_DistanceSphere((ST_SetSRID(ST_MakePoint($2, $3), 4326)), p.geom) <= 25000
+			AND pharmacy_is_open(p.pharmacy_id, NOW())
 		ORDER BY ST_DistanceSphere((ST_SetSRID(ST_MakePoint($2, $3), 4326)), p.geom) ASC
 		LIMIT $4
 		OFFSET $5
@@ -29,17 +30,24 @@ const (
 			SELECT pharmacy_id, CAST((ST_DistanceSphere((ST_SetSRID(ST_MakePoint($1, $2), 4326)), pharmacies.geom)) AS NUMERIC) AS distance
 			FROM pharmacies
 			WHERE deleted_at IS NULL AND (ST_DistanceSphere((ST_SetSRID(ST_MakePoint($1, $2), 4326)), pharmacies.geom)) <= 25000
+				AND pharmacy_is_open(pharmacy_id, NOW())
 	`
 
 	GetDrugListQuery = `
 		drug_list AS(
-			SELECT pd.pharmacy_drug_id, pd.drug_id, d.drug_name, pd.price, d.image, ip.distance, d.is_prescription_required
+			SELECT pd.pharmacy_drug_id, pd.drug_id, d.drug_name, pd.price, d.image, ip.distance, d.is_prescription_required,
+	`
+
+	GetDrugListFromQuery = `
 			FROM pharmacy_drugs pd 
 			JOIN in_range_pharmacy ip 
 			ON pd.pharmacy_id = ip.pharmacy_id
 			JOIN drugs d
 			ON pd.drug_id = d.drug_id
-			WHERE pd.deleted_at IS NULL AND d.deleted_at IS NULL AND pd.stock > 0 AND d.is_active AND d.drug_name ILIKE 
+	`
+
+	GetDrugListConditionQuery = `
+			WHERE pd.deleted_at IS NULL AND d.deleted_at IS NULL AND pd.stock > 0 AND d.is_active
 	`
 
 	GetPriceRangeQuery = `
@@ -48,7 +56,7 @@ const (
 			FROM drug_list dl
 			GROUP BY dl.drug_id),
 		closest_drug AS(
-			SELECT dl.pharmacy_drug_id, dl.drug_id, dl.drug_name, dl.price, dl.image, dl.is_prescription_required 
+			SELECT dl.pharmacy_drug_id, dl.drug_id, dl.drug_name, dl.price, dl.image, dl.is_prescription_required, dl.search_rank
 			FROM drug_list dl
 			JOIN
 				(SELECT drug_id, MIN(distance) AS min_distance
@@ -69,8 +77,10 @@ const (
 	`
 
 	GetPharmacyDrugById = `
-		select pharmacy_drug_id, pharmacy_id, drug_id, price, stock 
-		from pharmacy_drugs where pharmacy_drug_id = $1 and deleted_at is null
+		select pd.pharmacy_drug_id, pd.pharmacy_id, pd.drug_id, pd.price, pd.stock, d.drug_name, d.is_prescription_required
+		from pharmacy_drugs pd
+		join drugs d on d.drug_id = pd.drug_id
+		where pd.pharmacy_drug_id = $1 and pd.deleted_at is null
 	`
 
 	GetPharmacyDrugsByCartForUpdate = `
@@ -127,10 +137,57 @@ const (
 			AND d.deleted_at IS NULL
 			AND ST_DistanceSphere(ua.geom, p.geom) <= 25000
 			AND pd.stock > 0
+			AND pharmacy_is_open(p.pharmacy_id, NOW())
 		ORDER BY ST_DistanceSphere(ua.geom, p.geom) ASC
 		LIMIT 1
 	`
 
+	GetAllNearestAvailablePharmacyDrugsByDrugIdsQuery = `
+		SELECT
+			c.pharmacy_id,
+			c.pharmacy_name,
+			c.address,
+			c.distance,
+			c.pharmacy_drug_id,
+			c.drug_id,
+			c.drug_name,
+			c.manufacture,
+			c.image,
+			c.weight,
+			c.selling_unit,
+			c.unit_in_pack,
+			c.price
+		FROM (
+			SELECT
+				p.pharmacy_id,
+				p.pharmacy_name,
+				p.address,
+				ST_DistanceSphere(ua.geom, p.geom) AS distance,
+				pd.pharmacy_drug_id,
+				d.drug_id,
+				d.drug_name,
+				d.manufacture,
+				d.image,
+				d.weight,
+				d.selling_unit,
+				d.unit_in_pack,
+				pd.price,
+				ROW_NUMBER() OVER (PARTITION BY d.drug_id ORDER BY ST_DistanceSphere(ua.geom, p.geom) ASC) AS candidate_rank
+			FROM pharmacy_drugs pd
+			JOIN pharmacies p ON p.pharmacy_id = pd.pharmacy_id
+			JOIN user_addresses ua ON ua.user_address_id = $2
+			JOIN drugs d ON d.drug_id = pd.drug_id
+			WHERE d.drug_id = ANY($1)
+				AND pd.deleted_at IS NULL
+				AND d.deleted_at IS NULL
+				AND ST_DistanceSphere(ua.geom, p.geom) <= 25000
+				AND pd.stock > 0
+				AND pharmacy_is_open(p.pharmacy_id, NOW())
+		) c
+		WHERE c.candidate_rank <= $3
+		ORDER BY c.drug_id, c.distance ASC
+	`
+
 	GetPharmacyDrugsByOrderPharmacyId = `
 		WITH order_drugs AS (
 			SELECT oi.quantity, pd.pharmacy_drug_id, pd.stock 
@@ -164,6 +221,52 @@ const (
 		where pharmacy_drug_id = $1;
 	`
 
+	UpdatePharmacyDrugReorderThreshold = `
+		UPDATE pharmacy_drugs
+		SET reorder_threshold = $2, updated_at = NOW()
+		WHERE pharmacy_drug_id = $1 AND deleted_at IS NULL
+	`
+
+	// Sales velocity is the quantity sold per day over the window, the
+	// suggestion covers that many days on top of the reorder threshold.
+	FindAllReorderSuggestionsQuery = `
+		WITH sales AS (
+			SELECT oi.pharmacy_drug_id, SUM(oi.quantity) AS sold_quantity
+			FROM order_items oi
+			JOIN order_pharmacies op ON op.order_pharmacy_id = oi.order_pharmacy_id
+			WHERE op.order_status_id != $3 AND op.deleted_at IS NULL
+				AND op.created_at >= NOW() - make_interval(days => $2::INT)
+			GROUP BY oi.pharmacy_drug_id
+		), suggestions AS (
+			SELECT pd.pharmacy_drug_id, pd.pharmacy_id, p.pharmacy_name, pd.drug_id, d.drug_name, pd.stock, pd.reorder_threshold,
+				COALESCE(s.sold_quantity, 0)::INT AS sold_quantity,
+				COALESCE(s.sold_quantity, 0)::NUMERIC / $2::INT AS daily_sales
+			FROM pharmacy_drugs pd
+			JOIN pharmacies p ON p.pharmacy_id = pd.pharmacy_id
+			JOIN drugs d ON d.drug_id = pd.drug_id
+			LEFT JOIN sales s ON s.pharmacy_drug_id = pd.pharmacy_drug_id
+			WHERE p.pharmacy_manager_id = $1 AND pd.deleted_at IS NULL AND p.deleted_at IS NULL AND d.deleted_at IS NULL
+	`
+
+	FindAllReorderSuggestionsSelectQuery = `
+		), suggested AS (
+			SELECT *, GREATEST(CEIL(daily_sales * $4::INT) + COALESCE(reorder_threshold, 0) - stock, 0)::INT AS suggested_quantity
+			FROM suggestions
+		)
+		SELECT pharmacy_drug_id, pharmacy_id, pharmacy_name, drug_id, drug_name, stock, reorder_threshold, sold_quantity,
+			ROUND(daily_sales, 2)::FLOAT8, suggested_quantity, COUNT(*) OVER()
+		FROM suggested
+		WHERE suggested_quantity > 0
+		ORDER BY CASE WHEN daily_sales > 0 THEN stock / daily_sales END ASC NULLS LAST, suggested_quantity DESC, pharmacy_drug_id ASC
+	`
+
+	UpdatePharmacyDrugStockByAmount = `
+		UPDATE pharmacy_drugs
+		SET stock = stock + $2, updated_at = NOW()
+		WHERE pharmacy_drug_id = $1
+		RETURNING stock
+	`
+
 	DeletePharmacyDrug = `
 		update pharmacy_drugs set updated_at=now(), deleted_at= now()
 		where pharmacy_drug_id = $1;
@@ -172,6 +275,7 @@ const (
 	AddPharmacyDrug = `
 		insert into pharmacy_drugs (pharmacy_id, drug_id, stock, price)
 		VALUES ($1, $2, $3, $4)
+		RETURNING pharmacy_drug_id
 	`
 
 	GetPossibleStockMutation = `
@@ -193,8 +297,7 @@ const (
 		AND pd.stock > 0
 	`
 
-	GetPharmacyDrugByIdForUpdate = `
-		select pharmacy_drug_id, pharmacy_id, drug_id, price, stock 
-		from pharmacy_drugs where pharmacy_drug_id = $1 and deleted_at is null for update
+	GetPharmacyDrugByIdForUpdate = GetPharmacyDrugById + `
+		for update of pd
 	`
 )
@@ -0,0 +1,27 @@
+package database
+
+const (
+	FindAllUpcomingPharmacyHolidaysByPharmacyId = `
+		SELECT pharmacy_holiday_id, pharmacy_id, holiday_date, is_open,
+		TO_CHAR(open_hour, 'HH24:MI'), TO_CHAR(close_hour, 'HH24:MI'), description
+		FROM pharmacy_holidays
+		WHERE pharmacy_id = $1
+		AND holiday_date >= $2
+		AND deleted_at IS NULL
+		ORDER BY holiday_date
+	`
+
+	CreateOnePharmacyHoliday = `
+		INSERT INTO pharmacy_holidays(pharmacy_id, holiday_date, is_open, open_hour, close_hour, description)
+		VALUES ($1, $2, $3, $4::TIME, $5::TIME, $6)
+		RETURNING pharmacy_holiday_id
+	`
+
+	DeleteOnePharmacyHoliday = `
+		UPDATE pharmacy_holidays
+		SET deleted_at = NOW(), updated_at = NOW()
+		WHERE pharmacy_holiday_id = $1
+		AND pharmacy_id = $2
+		AND deleted_at IS NULL
+	`
+)
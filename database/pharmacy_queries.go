@@ -11,7 +11,7 @@ const (
 	`
 
 	FindOnePharmacyById = `
-		SELECT pharmacy_id, pharmacy_name, pharmacy_manager_id
+		SELECT pharmacy_id, pharmacy_name, pharmacy_manager_id, timezone
 		FROM pharmacies
 		WHERE pharmacy_id = $1
 		AND deleted_at IS NULL
@@ -61,6 +61,35 @@ const (
 		WHERE pharmacy_operational_id = $5
 	`
 
+	FindAllPharmacyOperationalsByPharmacyId = `
+		SELECT pharmacy_operational_id, pharmacy_id, operational_day,
+		COALESCE(TO_CHAR(open_hour::TIME, 'HH24:MI'), '00:00'),
+		COALESCE(TO_CHAR(close_hour::TIME, 'HH24:MI'), '00:00'),
+		is_open AND open_hour IS NOT NULL AND close_hour IS NOT NULL
+		FROM pharmacy_operationals
+		WHERE pharmacy_id = $1
+		AND deleted_at IS NULL
+		ORDER BY pharmacy_operational_id
+	`
+
+	UpdateOnePharmacyOperationalByPharmacyIdAndDay = `
+		UPDATE pharmacy_operationals
+		SET open_hour = $1,
+		close_hour = $2,
+		is_open = $3,
+		updated_at = NOW()
+		WHERE pharmacy_id = $4
+		AND operational_day = $5
+		AND deleted_at IS NULL
+	`
+
+	UpdatePharmacyTimezone = `
+		UPDATE pharmacies
+		SET timezone = $1,
+		updated_at = NOW()
+		WHERE pharmacy_id = $2
+	`
+
 	DeleteBulkPharmacyOperationalByPharmacyId = `
 		UPDATE pharmacy_operationals
 		SET deleted_at = NOW(), updated_at = NOW()
@@ -2,13 +2,13 @@ package database
 
 const (
 	CreateOnePrescriptionQuery = `
-		INSERT INTO prescriptions (user_account_id, doctor_account_id)
-		VALUES ($1, $2)
+		INSERT INTO prescriptions (user_account_id, doctor_account_id, refill_count, valid_until)
+		VALUES ($1, $2, $3, NOW() + make_interval(days => $4))
 		RETURNING prescription_id
 	`
 
 	GetPrescriptionByIdQuery = `
-		SELECT user_account_id, doctor_account_id, redeemed_at, ordered_at
+		SELECT user_account_id, doctor_account_id, redeemed_at, ordered_at, refill_count, refills_used, valid_until, COALESCE(valid_until <= NOW(), FALSE)
 		FROM prescriptions
 		WHERE prescription_id = $1
 		AND deleted_at IS NULL
@@ -20,8 +20,21 @@ const (
 		WHERE prescription_id = $1 AND deleted_at IS NULL
 	`
 
+	GetPrescriptionDocumentByIdQuery = `
+		SELECT p.user_account_id, a1.account_name, p.doctor_account_id, a2.account_name, COALESCE(ds.specialization_name, ''),
+			p.refill_count, p.valid_until, COALESCE(p.valid_until <= NOW(), FALSE), p.created_at
+		FROM prescriptions p
+		JOIN accounts a1 ON a1.account_id = p.user_account_id
+		JOIN accounts a2 ON a2.account_id = p.doctor_account_id
+		LEFT JOIN doctors d ON d.account_id = p.doctor_account_id
+		LEFT JOIN doctor_specializations ds ON ds.specialization_id = d.specialization_id
+		WHERE p.prescription_id = $1
+		AND p.deleted_at IS NULL
+	`
+
 	GetPrescriptionListByUserAccountIdQuery = `
-		SELECT p.prescription_id, p.user_account_id, a1.account_name, p.doctor_account_id, a2.account_name, p.redeemed_at, p.ordered_at, p.created_at
+		SELECT p.prescription_id, p.user_account_id, a1.account_name, p.doctor_account_id, a2.account_name, p.redeemed_at, p.ordered_at, p.created_at,
+			p.refill_count, p.refills_used, p.valid_until, COALESCE(p.valid_until <= NOW(), FALSE)
 		FROM prescriptions p
 		JOIN accounts a1 ON a1.account_id = p.user_account_id
 		JOIN accounts a2 ON a2.account_id = p.doctor_account_id
@@ -37,9 +50,54 @@ const (
 		WHERE p.user_account_id = $1 AND p.deleted_at IS NULL
 	`
 
+	RefillOnePrescriptionIfExhaustedQuery = `
+		WITH refilled AS (
+			UPDATE prescriptions
+			SET refills_used = refills_used + 1, updated_at = NOW()
+			WHERE prescription_id = $1
+			AND ordered_at IS NULL
+			AND refills_used < refill_count
+			AND NOT EXISTS (
+				SELECT 1
+				FROM prescription_drugs
+				WHERE prescription_id = $1
+				AND remaining_quantity > 0
+			)
+			RETURNING prescription_id
+		)
+		UPDATE prescription_drugs pd
+		SET remaining_quantity = pd.quantity
+		FROM refilled r
+		WHERE pd.prescription_id = r.prescription_id
+	`
+
+	SetPrescriptionOrderedAtNowIfExhaustedQuery = `
+		UPDATE prescriptions
+		SET ordered_at = NOW(), updated_at = NOW()
+		WHERE prescription_id = $1
+		AND ordered_at IS NULL
+		AND NOT EXISTS (
+			SELECT 1
+			FROM prescription_drugs
+			WHERE prescription_id = $1
+			AND remaining_quantity > 0
+		)
+	`
+
 	SetPrescriptionOrderedAtNowQuery = `
 		UPDATE prescriptions
 		SET ordered_at = NOW(), updated_at = NOW()
 		WHERE prescription_id = $1
 	`
+
+	FindAllPrescriptionRedemptionsQuery = `
+		SELECT o.order_id, op.order_pharmacy_id, op.order_status_id, oi.order_item_id, oi.prescription_drug_id, oi.drug_id, oi.drug_name, oi.quantity, oi.created_at
+		FROM order_items oi
+		JOIN prescription_drugs pd ON pd.prescription_drug_id = oi.prescription_drug_id
+		JOIN order_pharmacies op ON op.order_pharmacy_id = oi.order_pharmacy_id
+		JOIN orders o ON o.order_id = op.order_id
+		WHERE pd.prescription_id = $1
+		AND oi.deleted_at IS NULL
+		ORDER BY oi.created_at DESC, oi.order_item_id ASC
+	`
 )
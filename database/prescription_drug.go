@@ -2,14 +2,115 @@ package database
 
 const (
 	PostOnePrescriptionDrugQuery = `
-		INSERT INTO prescription_drugs (prescription_id, drug_id, quantity, note)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO prescription_drugs (prescription_id, drug_id, quantity, remaining_quantity, note)
+		VALUES ($1, $2, $3, $3, $4)
 	`
 
 	GetAllPrescriptionDrugQuery = `
-		SELECT pd.prescription_drug_id, d.drug_id, d.drug_name, d.image, d.is_active, pd.quantity, pd.note
+		SELECT pd.prescription_drug_id, d.drug_id, d.drug_name, d.image, d.is_active, pd.quantity, pd.remaining_quantity, pd.note
 		FROM prescription_drugs pd 
 		JOIN drugs d ON d.drug_id = pd.drug_id
 		WHERE pd.prescription_id = $1
+		ORDER BY pd.prescription_drug_id
 	`
+
+	FindOneRedeemablePrescriptionDrugQuery = `
+		SELECT pd.prescription_drug_id, pd.prescription_id, pd.drug_id, d.drug_name, pd.quantity, pd.remaining_quantity
+		FROM prescription_drugs pd
+		JOIN prescriptions p ON p.prescription_id = pd.prescription_id
+		JOIN drugs d ON d.drug_id = pd.drug_id
+		WHERE pd.prescription_id = $1
+		AND pd.drug_id = $2
+		AND p.user_account_id = $3
+		AND p.ordered_at IS NULL
+		AND p.deleted_at IS NULL
+		AND (p.valid_until IS NULL OR p.valid_until > NOW())
+	`
+
+	FindOnePrescriptionDrugByCartItemIdQuery = `
+		SELECT pd.prescription_drug_id, pd.prescription_id, pd.drug_id, d.drug_name, pd.quantity, pd.remaining_quantity
+		FROM cart_items ci
+		JOIN prescription_drugs pd ON pd.prescription_drug_id = ci.prescription_drug_id
+		JOIN drugs d ON d.drug_id = pd.drug_id
+		WHERE ci.cart_item_id = $1
+		AND ci.deleted_at IS NULL
+	`
+
+	FindAllRedeemablePrescriptionDrugsForUpdateQuery = `
+		SELECT pd.prescription_drug_id, pd.prescription_id, pd.drug_id, d.drug_name, pd.quantity, pd.remaining_quantity
+		FROM prescription_drugs pd
+		JOIN prescriptions p ON p.prescription_id = pd.prescription_id
+		JOIN drugs d ON d.drug_id = pd.drug_id
+		WHERE p.user_account_id = $1
+		AND p.ordered_at IS NULL
+		AND p.deleted_at IS NULL
+		AND (p.valid_until IS NULL OR p.valid_until > NOW())
+	`
+
+	DecreaseOnePrescriptionDrugRemainingQuantityQuery = `
+		UPDATE prescription_drugs
+		SET remaining_quantity = remaining_quantity - $2
+		WHERE prescription_drug_id = $1
+		AND remaining_quantity >= $2
+	`
+
+	// A cancelled order that finished a refill cycle gives the refill back,
+	// as long as nothing has been taken from the refilled cycle yet. Otherwise
+	// the restored quantity is capped at the prescribed quantity.
+	restorePrescriptionDrugsQuery = `
+		, prescription_restores AS (
+			SELECT pd.prescription_id,
+				BOOL_OR(pd.remaining_quantity + COALESCE(ri.quantity, 0) > pd.quantity) AS crosses_refill,
+				BOOL_AND(pd.remaining_quantity + COALESCE(ri.quantity, 0) >= pd.quantity) AS is_refill_untouched
+			FROM prescription_drugs pd
+			LEFT JOIN restored_items ri ON ri.prescription_drug_id = pd.prescription_drug_id
+			WHERE pd.prescription_id IN (
+				SELECT rpd.prescription_id
+				FROM prescription_drugs rpd
+				JOIN restored_items ri ON ri.prescription_drug_id = rpd.prescription_drug_id
+			)
+			GROUP BY pd.prescription_id
+		), refills_returned AS (
+			SELECT pr.prescription_id
+			FROM prescription_restores pr
+			JOIN prescriptions p ON p.prescription_id = pr.prescription_id
+			WHERE pr.crosses_refill AND pr.is_refill_untouched AND p.refills_used > 0
+		), restored AS (
+			UPDATE prescription_drugs pd
+			SET remaining_quantity = CASE
+				WHEN pd.prescription_id IN (SELECT prescription_id FROM refills_returned)
+				THEN pd.remaining_quantity + COALESCE((SELECT ri.quantity FROM restored_items ri WHERE ri.prescription_drug_id = pd.prescription_drug_id), 0) - pd.quantity
+				ELSE LEAST(pd.quantity, pd.remaining_quantity + COALESCE((SELECT ri.quantity FROM restored_items ri WHERE ri.prescription_drug_id = pd.prescription_drug_id), 0))
+			END
+			FROM prescription_restores pr
+			WHERE pd.prescription_id = pr.prescription_id
+			RETURNING pd.prescription_id
+		)
+		UPDATE prescriptions p
+		SET refills_used = p.refills_used - CASE WHEN p.prescription_id IN (SELECT prescription_id FROM refills_returned) THEN 1 ELSE 0 END,
+			ordered_at = NULL,
+			updated_at = NOW()
+		WHERE p.prescription_id IN (SELECT prescription_id FROM restored)
+	`
+
+	RestorePrescriptionDrugsByOrderIdQuery = `
+		WITH restored_items AS (
+			SELECT oi.prescription_drug_id, SUM(oi.quantity) AS quantity
+			FROM order_items oi
+			JOIN order_pharmacies op ON op.order_pharmacy_id = oi.order_pharmacy_id
+			WHERE op.order_id = $1
+			AND oi.prescription_drug_id IS NOT NULL
+			AND oi.deleted_at IS NULL
+			GROUP BY oi.prescription_drug_id
+		)` + restorePrescriptionDrugsQuery
+
+	RestorePrescriptionDrugsByOrderPharmacyIdQuery = `
+		WITH restored_items AS (
+			SELECT oi.prescription_drug_id, SUM(oi.quantity) AS quantity
+			FROM order_items oi
+			WHERE oi.order_pharmacy_id = $1
+			AND oi.prescription_drug_id IS NOT NULL
+			AND oi.deleted_at IS NULL
+			GROUP BY oi.prescription_drug_id
+		)` + restorePrescriptionDrugsQuery
 )
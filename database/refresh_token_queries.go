@@ -3,20 +3,29 @@ package database
 const (
 	PostOneRefreshTokenQuery = `
 		INSERT 
-		INTO refresh_tokens (account_id, refresh_token, expired_at)
-		VALUES ($1, $2, NOW() + INTERVAL '1 day')
+		INTO refresh_tokens (account_id, account_session_id, refresh_token, expired_at)
+		VALUES ($1, $2, $3, NOW() + INTERVAL '1 day')
 	`
 
 	InvalidateRefreshTokensQuery = `
 		UPDATE refresh_tokens
 		SET expired_at = NOW(), updated_at = NOW()
 		WHERE account_id = $1
+		AND expired_at > NOW()
 	`
 
-	FindOneRefreshTokenQuery = `
-		SELECT refresh_token
-		FROM refresh_tokens
-		WHERE refresh_token = $1 
-		AND expired_at > NOW()
+	FindOneRefreshTokenForUpdateQuery = `
+		SELECT rt.refresh_token_id, rt.account_id, rt.account_session_id, rt.used_at IS NOT NULL, rt.expired_at <= NOW()
+		FROM refresh_tokens rt
+		JOIN account_sessions s ON s.account_session_id = rt.account_session_id
+		WHERE rt.refresh_token = $1
+		AND s.revoked_at IS NULL
+		FOR UPDATE OF rt
+	`
+
+	UpdateUsedAtOneRefreshTokenQuery = `
+		UPDATE refresh_tokens
+		SET used_at = NOW(), updated_at = NOW()
+		WHERE refresh_token_id = $1
 	`
-)
\ No newline at end of file
+)
@@ -0,0 +1,49 @@
+package database
+
+const (
+	stockMutationRequestColumns = `
+		SELECT smr.stock_mutation_request_id, smr.pharmacy_requester_id, rp.pharmacy_name, rp.pharmacy_manager_id, rpd.pharmacy_drug_id,
+			smr.pharmacy_target_id, tp.pharmacy_name, tp.pharmacy_manager_id, tpd.pharmacy_drug_id,
+			smr.drug_id, d.drug_name, smr.stock, smr.order_id, smr.mutation_status, smr.responded_at, smr.shipped_at, smr.received_at, smr.created_at
+	`
+
+	stockMutationRequestJoins = `
+		FROM stock_mutation_requests smr
+		JOIN pharmacies rp ON rp.pharmacy_id = smr.pharmacy_requester_id
+		JOIN pharmacies tp ON tp.pharmacy_id = smr.pharmacy_target_id
+		JOIN drugs d ON d.drug_id = smr.drug_id
+		LEFT JOIN pharmacy_drugs rpd ON rpd.pharmacy_id = smr.pharmacy_requester_id AND rpd.drug_id = smr.drug_id AND rpd.deleted_at IS NULL
+		LEFT JOIN pharmacy_drugs tpd ON tpd.pharmacy_id = smr.pharmacy_target_id AND tpd.drug_id = smr.drug_id AND tpd.deleted_at IS NULL
+	`
+
+	FindOneStockMutationRequestByIdQuery = stockMutationRequestColumns + stockMutationRequestJoins + `
+		WHERE smr.stock_mutation_request_id = $1
+	`
+
+	FindOneStockMutationRequestByIdForUpdateQuery = FindOneStockMutationRequestByIdQuery + `
+		FOR UPDATE OF smr
+	`
+
+	FindAllStockMutationRequestsQuery = stockMutationRequestColumns + `, COUNT(*) OVER()
+	` + stockMutationRequestJoins + `
+		WHERE TRUE
+	`
+
+	UpdateStockMutationRequestStatusOneQuery = `
+		UPDATE stock_mutation_requests
+		SET mutation_status = $2::VARCHAR,
+			responded_at = CASE WHEN $2::VARCHAR IN ('approved', 'rejected') THEN NOW() ELSE responded_at END,
+			shipped_at = CASE WHEN $2::VARCHAR = 'shipped' THEN NOW() ELSE shipped_at END,
+			received_at = CASE WHEN $2::VARCHAR = 'received' THEN NOW() ELSE received_at END,
+			updated_at = NOW()
+		WHERE stock_mutation_request_id = $1
+	`
+
+	CancelPendingStockMutationRequestsByOrderIdQuery = `
+		UPDATE stock_mutation_requests
+		SET mutation_status = 'cancelled',
+			responded_at = NOW(),
+			updated_at = NOW()
+		WHERE order_id = $1 AND mutation_status = 'pending'
+	`
+)
@@ -2,12 +2,12 @@ package database
 
 const (
 	CreateStockChanges = `
-		INSERT INTO stock_changes (pharmacy_drug_id, final_stock, amount, description)
+		INSERT INTO stock_changes (pharmacy_drug_id, final_stock, amount, description, change_type, actor_account_id)
 		VALUES
 	`
 
 	CreateStockMutations = `
-		INSERT INTO stock_mutation_requests (pharmacy_requester_id, pharmacy_target_id, drug_id, stock, status_id)
+		INSERT INTO stock_mutation_requests (pharmacy_requester_id, pharmacy_target_id, drug_id, stock, order_id)
 		VALUES
 	`
 
@@ -51,7 +51,13 @@ const (
 	`
 
 	GetStockChanges = `
-			SELECT p.pharmacy_name, p.address, d.drug_name, d.image, sc.final_stock, sc.amount, sc.description
+			SELECT sc.stock_change_id, sc.pharmacy_drug_id, p.pharmacy_id, p.pharmacy_name, p.address, d.drug_id, d.drug_name, d.image,
+				sc.final_stock, sc.amount, sc.description, sc.change_type, sc.actor_account_id, actor.account_name, sc.created_at
+	`
+
+	GetStockChangesCount = `, COUNT(*) OVER()`
+
+	GetStockChangesFrom = `
 			FROM stock_changes sc
 			JOIN pharmacy_drugs pd 
 			ON pd.pharmacy_drug_id = sc.pharmacy_drug_id
@@ -59,6 +65,24 @@ const (
 			ON pd.pharmacy_id = p.pharmacy_id
 			JOIN drugs d
 			ON d.drug_id = pd.drug_id
+			LEFT JOIN accounts actor
+			ON actor.account_id = sc.actor_account_id
 			WHERE p.pharmacy_manager_id = $1 AND sc.deleted_at IS NULL AND pd.deleted_at IS NULL AND p.deleted_at IS NULL AND d.deleted_at IS NULL
 	`
+
+	FindAllAlertUncheckedStockChangesForUpdate = `
+		SELECT sc.stock_change_id, sc.pharmacy_drug_id, sc.final_stock, sc.amount, pd.reorder_threshold
+		FROM stock_changes sc
+		JOIN pharmacy_drugs pd ON pd.pharmacy_drug_id = sc.pharmacy_drug_id
+		WHERE sc.alert_checked_at IS NULL
+		ORDER BY sc.stock_change_id
+		LIMIT $1
+		FOR UPDATE OF sc SKIP LOCKED
+	`
+
+	MarkStockChangesAlertChecked = `
+		UPDATE stock_changes
+		SET alert_checked_at = NOW()
+		WHERE stock_change_id = ANY($1)
+	`
 )
\ No newline at end of file
@@ -0,0 +1,33 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/sidiqPratomo/max-health-backend/entity"
+)
+
+type AccountSessionResponse struct {
+	Id         int64     `json:"session_id"`
+	UserAgent  string    `json:"user_agent"`
+	IpAddress  string    `json:"ip_address"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	CreatedAt  time.Time `json:"created_at"`
+	IsCurrent  bool      `json:"is_current"`
+}
+
+func ConvertToAccountSessionResponses(accountSessions []entity.AccountSession, currentSessionId int64) []AccountSessionResponse {
+	accountSessionResponses := []AccountSessionResponse{}
+
+	for _, accountSession := range accountSessions {
+		accountSessionResponses = append(accountSessionResponses, AccountSessionResponse{
+			Id:         accountSession.Id,
+			UserAgent:  accountSession.UserAgent,
+			IpAddress:  accountSession.IpAddress,
+			LastSeenAt: accountSession.LastSeenAt,
+			CreatedAt:  accountSession.CreatedAt,
+			IsCurrent:  accountSession.Id == currentSessionId,
+		})
+	}
+
+	return accountSessionResponses
+}
@@ -0,0 +1,62 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/sidiqPratomo/max-health-backend/entity"
+)
+
+type AdminAccountQuery struct {
+	Search    string `form:"search"`
+	Role      string `form:"role" binding:"omitempty,oneof=user doctor 'pharmacy manager' admin"`
+	Verified  *bool  `form:"verified"`
+	Suspended *bool  `form:"suspended"`
+	Page      string `form:"page"`
+	Limit     string `form:"limit"`
+}
+
+type AdminAccountResponse struct {
+	Id             int64      `json:"account_id"`
+	Email          string     `json:"email"`
+	Name           string     `json:"account_name"`
+	RoleName       string     `json:"role"`
+	ProfilePicture string     `json:"profile_picture"`
+	IsVerified     bool       `json:"is_verified"`
+	VerifiedAt     *time.Time `json:"verified_at"`
+	IsSuspended    bool       `json:"is_suspended"`
+	SuspendedAt    *time.Time `json:"suspended_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type AllAdminAccountsResponse struct {
+	PageInfo entity.PageInfo        `json:"page_info"`
+	Accounts []AdminAccountResponse `json:"accounts"`
+}
+
+func ConvertToAdminAccountResponse(account entity.Account) AdminAccountResponse {
+	return AdminAccountResponse{
+		Id:             account.Id,
+		Email:          account.Email,
+		Name:           account.Name,
+		RoleName:       account.RoleName,
+		ProfilePicture: account.ProfilePicture,
+		IsVerified:     account.VerifiedAt != nil,
+		VerifiedAt:     account.VerifiedAt,
+		IsSuspended:    account.SuspendedAt != nil,
+		SuspendedAt:    account.SuspendedAt,
+		CreatedAt:      account.CreatedAt,
+	}
+}
+
+func ConvertToAllAdminAccountsResponse(accounts []entity.Account, pageInfo entity.PageInfo) AllAdminAccountsResponse {
+	accountResponses := []AdminAccountResponse{}
+
+	for _, account := range accounts {
+		accountResponses = append(accountResponses, ConvertToAdminAccountResponse(account))
+	}
+
+	return AllAdminAccountsResponse{
+		PageInfo: pageInfo,
+		Accounts: accountResponses,
+	}
+}
@@ -0,0 +1,188 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/sidiqPratomo/max-health-backend/entity"
+)
+
+type DoctorScheduleRequest struct {
+	Timezone                string                      `json:"timezone" binding:"required,timezone"`
+	UseScheduleAvailability bool                        `json:"use_schedule_availability"`
+	Slots                   []DoctorScheduleSlotRequest `json:"slots" binding:"dive"`
+}
+
+type DoctorScheduleSlotRequest struct {
+	Day       string `json:"day" binding:"required,oneof=Monday Tuesday Wednesday Thursday Friday Saturday Sunday"`
+	StartHour string `json:"start_hour" binding:"required"`
+	EndHour   string `json:"end_hour" binding:"required"`
+}
+
+type DoctorScheduleExceptionRequest struct {
+	Date        string  `json:"date" binding:"required,datetime=2006-01-02"`
+	IsAvailable bool    `json:"is_available"`
+	StartHour   *string `json:"start_hour" binding:"required_if=IsAvailable true"`
+	EndHour     *string `json:"end_hour" binding:"required_if=IsAvailable true"`
+	Description string  `json:"description"`
+}
+
+type DoctorScheduleResponse struct {
+	DoctorAccountId         int64                             `json:"doctor_account_id"`
+	Timezone                string                            `json:"timezone"`
+	UseScheduleAvailability bool                              `json:"use_schedule_availability"`
+	Slots                   []DoctorScheduleSlotResponse      `json:"slots"`
+	Exceptions              []DoctorScheduleExceptionResponse `json:"exceptions"`
+}
+
+type DoctorScheduleSlotResponse struct {
+	Id        int64  `json:"id"`
+	Day       string `json:"day"`
+	StartHour string `json:"start_hour"`
+	EndHour   string `json:"end_hour"`
+}
+
+type DoctorScheduleExceptionResponse struct {
+	Id          int64   `json:"id"`
+	Date        string  `json:"date"`
+	IsAvailable bool    `json:"is_available"`
+	StartHour   *string `json:"start_hour,omitempty"`
+	EndHour     *string `json:"end_hour,omitempty"`
+	Description string  `json:"description"`
+}
+
+type AppointmentSlotQuery struct {
+	Date string `form:"date" binding:"required,datetime=2006-01-02"`
+}
+
+type AppointmentSlotsResponse struct {
+	DoctorId        int64                     `json:"doctor_id"`
+	Date            string                    `json:"date"`
+	Timezone        string                    `json:"timezone"`
+	DurationMinutes int                       `json:"duration_minutes"`
+	Slots           []AppointmentSlotResponse `json:"slots"`
+}
+
+type AppointmentSlotResponse struct {
+	StartAt  time.Time `json:"start_at"`
+	EndAt    time.Time `json:"end_at"`
+	IsBooked bool      `json:"is_booked"`
+}
+
+type AppointmentRequest struct {
+	DoctorId int64     `json:"doctor_id" binding:"required"`
+	StartAt  time.Time `json:"start_at" binding:"required"`
+}
+
+type AppointmentQuery struct {
+	Status string `form:"status" binding:"omitempty,oneof=booked cancelled"`
+	Page   string `form:"page"`
+	Limit  string `form:"limit"`
+}
+
+type AppointmentResponse struct {
+	Id              int64      `json:"id"`
+	RoomId          int64      `json:"room_id"`
+	UserAccountId   int64      `json:"user_account_id"`
+	UserName        string     `json:"user_name"`
+	DoctorAccountId int64      `json:"doctor_account_id"`
+	DoctorName      string     `json:"doctor_name"`
+	ScheduledAt     time.Time  `json:"scheduled_at"`
+	EndAt           time.Time  `json:"end_at"`
+	Status          string     `json:"status"`
+	CancelledAt     *time.Time `json:"cancelled_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+type AllAppointmentsResponse struct {
+	PageInfo     entity.PageInfo       `json:"page_info"`
+	Appointments []AppointmentResponse `json:"appointments"`
+}
+
+func DoctorScheduleRequestToDoctorScheduleSlots(doctorScheduleRequest DoctorScheduleRequest) []entity.DoctorScheduleSlot {
+	doctorScheduleSlots := []entity.DoctorScheduleSlot{}
+
+	for _, slot := range doctorScheduleRequest.Slots {
+		doctorScheduleSlots = append(doctorScheduleSlots, entity.DoctorScheduleSlot{
+			Day:       slot.Day,
+			StartHour: slot.StartHour,
+			EndHour:   slot.EndHour,
+		})
+	}
+
+	return doctorScheduleSlots
+}
+
+func ConvertToDoctorScheduleResponse(doctorSchedule entity.DoctorSchedule) DoctorScheduleResponse {
+	doctorScheduleSlots := []DoctorScheduleSlotResponse{}
+	for _, slot := range doctorSchedule.Slots {
+		doctorScheduleSlots = append(doctorScheduleSlots, DoctorScheduleSlotResponse{
+			Id:        slot.Id,
+			Day:       slot.Day,
+			StartHour: slot.StartHour,
+			EndHour:   slot.EndHour,
+		})
+	}
+
+	doctorScheduleExceptions := []DoctorScheduleExceptionResponse{}
+	for _, exception := range doctorSchedule.Exceptions {
+		doctorScheduleExceptions = append(doctorScheduleExceptions, DoctorScheduleExceptionResponse{
+			Id:          exception.Id,
+			Date:        exception.Date.Format("2006-01-02"),
+			IsAvailable: exception.IsAvailable,
+			StartHour:   exception.StartHour,
+			EndHour:     exception.EndHour,
+			Description: exception.Description,
+		})
+	}
+
+	return DoctorScheduleResponse{
+		DoctorAccountId:         doctorSchedule.DoctorAccountId,
+		Timezone:                doctorSchedule.Timezone,
+		UseScheduleAvailability: doctorSchedule.UseScheduleAvailability,
+		Slots:                   doctorScheduleSlots,
+		Exceptions:              doctorScheduleExceptions,
+	}
+}
+
+func ConvertToAppointmentSlotResponses(appointmentSlots []entity.AppointmentSlot) []AppointmentSlotResponse {
+	appointmentSlotResponses := []AppointmentSlotResponse{}
+
+	for _, appointmentSlot := range appointmentSlots {
+		appointmentSlotResponses = append(appointmentSlotResponses, AppointmentSlotResponse{
+			StartAt:  appointmentSlot.StartAt,
+			EndAt:    appointmentSlot.EndAt,
+			IsBooked: appointmentSlot.IsBooked,
+		})
+	}
+
+	return appointmentSlotResponses
+}
+
+func ConvertToAppointmentResponse(appointment entity.Appointment) AppointmentResponse {
+	return AppointmentResponse{
+		Id:              appointment.Id,
+		RoomId:          appointment.ChatRoomId,
+		UserAccountId:   appointment.UserAccountId,
+		UserName:        appointment.UserName,
+		DoctorAccountId: appointment.DoctorAccountId,
+		DoctorName:      appointment.DoctorName,
+		ScheduledAt:     appointment.ScheduledAt,
+		EndAt:           appointment.EndAt,
+		Status:          appointment.Status,
+		CancelledAt:     appointment.CancelledAt,
+		CreatedAt:       appointment.CreatedAt,
+	}
+}
+
+func ConvertToAllAppointmentsResponse(appointments []entity.Appointment, pageInfo entity.PageInfo) AllAppointmentsResponse {
+	appointmentResponses := []AppointmentResponse{}
+
+	for _, appointment := range appointments {
+		appointmentResponses = append(appointmentResponses, ConvertToAppointmentResponse(appointment))
+	}
+
+	return AllAppointmentsResponse{
+		PageInfo:     pageInfo,
+		Appointments: appointmentResponses,
+	}
+}
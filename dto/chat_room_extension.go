@@ -0,0 +1,41 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/sidiqPratomo/max-health-backend/entity"
+)
+
+type ChatRoomExtensionRequest struct {
+	Minutes int `json:"minutes" binding:"required,gte=5,lte=60"`
+}
+
+type ChatRoomExtensionRespondRequest struct {
+	IsAccepted *bool `json:"is_accepted" binding:"required"`
+}
+
+type ChatRoomExtensionResponse struct {
+	Id                   int64           `json:"id"`
+	RoomId               int64           `json:"room_id"`
+	RequestedByAccountId int64           `json:"requested_by_account_id"`
+	Minutes              int             `json:"minutes"`
+	Fee                  decimal.Decimal `json:"fee"`
+	Status               string          `json:"status"`
+	RespondedAt          *time.Time      `json:"responded_at"`
+	CreatedAt            time.Time       `json:"created_at"`
+	ExpiredAt            *time.Time      `json:"expired_at,omitempty"`
+}
+
+func ConvertToChatRoomExtensionResponse(chatRoomExtension entity.ChatRoomExtension) ChatRoomExtensionResponse {
+	return ChatRoomExtensionResponse{
+		Id:                   chatRoomExtension.Id,
+		RoomId:               chatRoomExtension.ChatRoomId,
+		RequestedByAccountId: chatRoomExtension.RequestedByAccountId,
+		Minutes:              chatRoomExtension.Minutes,
+		Fee:                  chatRoomExtension.Fee,
+		Status:               chatRoomExtension.Status,
+		RespondedAt:          chatRoomExtension.RespondedAt,
+		CreatedAt:            chatRoomExtension.CreatedAt,
+	}
+}
@@ -0,0 +1,72 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/sidiqPratomo/max-health-backend/entity"
+)
+
+type ConsultationPaymentQuery struct {
+	Status string `form:"status" binding:"omitempty,oneof=waiting_for_payment waiting_for_confirmation paid cancelled refunded"`
+	Page   string `form:"page"`
+	Limit  string `form:"limit"`
+}
+
+type ConsultationPaymentConfirmRequest struct {
+	IsApproved *bool `json:"is_approved" binding:"required"`
+}
+
+type ConsultationPaymentResponse struct {
+	Id              int64           `json:"consultation_payment_id"`
+	RoomId          int64           `json:"room_id"`
+	UserAccountId   int64           `json:"user_account_id"`
+	UserName        string          `json:"user_name"`
+	DoctorAccountId int64           `json:"doctor_account_id"`
+	DoctorName      string          `json:"doctor_name"`
+	ExtensionId     *int64          `json:"extension_id"`
+	Amount          decimal.Decimal `json:"amount"`
+	Status          string          `json:"status"`
+	PaymentProof    string          `json:"payment_proof"`
+	ConfirmedAt     *time.Time      `json:"confirmed_at"`
+	CancelledAt     *time.Time      `json:"cancelled_at"`
+	RefundedAt      *time.Time      `json:"refunded_at"`
+	CreatedAt       time.Time       `json:"created_at"`
+}
+
+type AllConsultationPaymentsResponse struct {
+	PageInfo             entity.PageInfo               `json:"page_info"`
+	ConsultationPayments []ConsultationPaymentResponse `json:"consultation_payments"`
+}
+
+func ConvertToConsultationPaymentResponse(consultationPayment entity.ConsultationPayment) ConsultationPaymentResponse {
+	return ConsultationPaymentResponse{
+		Id:              consultationPayment.Id,
+		RoomId:          consultationPayment.ChatRoomId,
+		UserAccountId:   consultationPayment.UserAccountId,
+		UserName:        consultationPayment.UserName,
+		DoctorAccountId: consultationPayment.DoctorAccountId,
+		DoctorName:      consultationPayment.DoctorName,
+		ExtensionId:     consultationPayment.ChatRoomExtensionId,
+		Amount:          consultationPayment.Amount,
+		Status:          consultationPayment.Status,
+		PaymentProof:    consultationPayment.PaymentProof,
+		ConfirmedAt:     consultationPayment.ConfirmedAt,
+		CancelledAt:     consultationPayment.CancelledAt,
+		RefundedAt:      consultationPayment.RefundedAt,
+		CreatedAt:       consultationPayment.CreatedAt,
+	}
+}
+
+func ConvertToAllConsultationPaymentsResponse(consultationPayments []entity.ConsultationPayment, pageInfo entity.PageInfo) AllConsultationPaymentsResponse {
+	consultationPaymentResponses := []ConsultationPaymentResponse{}
+
+	for _, consultationPayment := range consultationPayments {
+		consultationPaymentResponses = append(consultationPaymentResponses, ConvertToConsultationPaymentResponse(consultationPayment))
+	}
+
+	return AllConsultationPaymentsResponse{
+		PageInfo:             pageInfo,
+		ConsultationPayments: consultationPaymentResponses,
+	}
+}
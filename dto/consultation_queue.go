@@ -0,0 +1,32 @@
+package dto
+
+import (
+	"math"
+	"time"
+
+	"github.com/sidiqPratomo/max-health-backend/entity"
+)
+
+type ConsultationQueueResponse struct {
+	RoomId               int64      `json:"room_id"`
+	DoctorAccountId      int64      `json:"doctor_account_id"`
+	Status               string     `json:"status"`
+	Position             int        `json:"position"`
+	QueueLength          int        `json:"queue_length"`
+	EstimatedWaitMinutes int        `json:"estimated_wait_minutes"`
+	AssignedAt           *time.Time `json:"assigned_at"`
+	ExpiresAt            *time.Time `json:"expires_at"`
+}
+
+func ConvertToConsultationQueueResponse(queueStatus entity.ConsultationQueueStatus) ConsultationQueueResponse {
+	return ConsultationQueueResponse{
+		RoomId:               queueStatus.RoomId,
+		DoctorAccountId:      queueStatus.DoctorAccountId,
+		Status:               queueStatus.Status,
+		Position:             queueStatus.Position,
+		QueueLength:          queueStatus.QueueLength,
+		EstimatedWaitMinutes: int(math.Ceil(queueStatus.EstimatedWait.Minutes())),
+		AssignedAt:           queueStatus.AssignedAt,
+		ExpiresAt:            queueStatus.ExpiresAt,
+	}
+}
@@ -19,28 +19,39 @@ type DoctorDto struct {
 	Experience         int             `json:"experience"`
 	Name               string          `json:"name" `
 	SpecializationName string          `json:"specialization"`
+	Rating             decimal.Decimal `json:"rating"`
+	ReviewCount        int             `json:"review_count"`
 }
 
 type UpdateDoctorDataRequest struct {
-	Name          string `json:"name" validate:"required"`
-	Password      string `json:"password"`
-	FeePerPatient string `json:"fee_per_patient" validate:"omitempty,number,gte=0"`
-	Experience    int    `json:"years_of_experience" validate:"omitempty,number,gte=0"`
+	Name                 string `json:"name" validate:"required"`
+	Password             string `json:"password"`
+	FeePerPatient        string `json:"fee_per_patient" validate:"omitempty,number,gte=0"`
+	Experience           int    `json:"years_of_experience" validate:"omitempty,number,gte=0"`
+	ConsultationDuration *int   `json:"consultation_duration_minutes" validate:"omitempty,gte=5,lte=240"`
 }
 
 type DoctorSpecialization struct {
-	Id   int64  `json:"id"`
-	Name string `json:"name"`
+	Id                   int64  `json:"id"`
+	Name                 string `json:"name"`
+	ConsultationDuration *int   `json:"consultation_duration_minutes"`
+}
+
+type UpdateDoctorSpecializationRequest struct {
+	ConsultationDuration *int `json:"consultation_duration_minutes" binding:"omitempty,gte=5,lte=240"`
 }
 
 type DoctorProfileResponse struct {
-	Email              string          `json:"email"`
-	Name               string          `json:"name"`
-	ProfilePicture     string          `json:"profile_picture"`
-	Experience         int             `json:"experience"`
-	FeePerPatient      decimal.Decimal `json:"fee_per_patient"`
-	SpecializationId   int64           `json:"specialization_id"`
-	SpecializationName string          `json:"specialization_name"`
+	Email                string          `json:"email"`
+	Name                 string          `json:"name"`
+	ProfilePicture       string          `json:"profile_picture"`
+	Experience           int             `json:"experience"`
+	FeePerPatient        decimal.Decimal `json:"fee_per_patient"`
+	SpecializationId     int64           `json:"specialization_id"`
+	SpecializationName   string          `json:"specialization_name"`
+	ConsultationDuration int             `json:"consultation_duration_minutes"`
+	Rating               decimal.Decimal `json:"rating"`
+	ReviewCount          int             `json:"review_count"`
 }
 
 type UpdateDoctorStatusRequest struct {
@@ -54,17 +65,19 @@ type GetDoctorStatusResponse struct {
 func UpdateDoctorDataRequestToDetailedDoctor(updateDoctorDataRequest UpdateDoctorDataRequest) entity.DetailedDoctor {
 	decimal, _ := decimal.NewFromString(updateDoctorDataRequest.FeePerPatient)
 	return entity.DetailedDoctor{
-		Name:          updateDoctorDataRequest.Name,
-		Password:      updateDoctorDataRequest.Password,
-		FeePerPatient: decimal,
-		Experience:    updateDoctorDataRequest.Experience,
+		Name:                 updateDoctorDataRequest.Name,
+		Password:             updateDoctorDataRequest.Password,
+		FeePerPatient:        decimal,
+		Experience:           updateDoctorDataRequest.Experience,
+		ConsultationDuration: updateDoctorDataRequest.ConsultationDuration,
 	}
 }
 
 func ConvertToDocterSpecializationDTO(doctorSpecialization entity.DoctorSpecialization) DoctorSpecialization {
 	return DoctorSpecialization{
-		Id:   doctorSpecialization.Id,
-		Name: doctorSpecialization.Name,
+		Id:                   doctorSpecialization.Id,
+		Name:                 doctorSpecialization.Name,
+		ConsultationDuration: doctorSpecialization.ConsultationDuration,
 	}
 }
 
@@ -73,8 +86,9 @@ func ConvertToDoctorSpecializationDTOList(doctorSpecializationList []entity.Doct
 
 	for _, doctorSpecialization := range doctorSpecializationList {
 		specializationListDTO = append(specializationListDTO, DoctorSpecialization{
-			Id:   doctorSpecialization.Id,
-			Name: doctorSpecialization.Name,
+			Id:                   doctorSpecialization.Id,
+			Name:                 doctorSpecialization.Name,
+			ConsultationDuration: doctorSpecialization.ConsultationDuration,
 		})
 	}
 
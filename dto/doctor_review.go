@@ -0,0 +1,79 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/sidiqPratomo/max-health-backend/entity"
+)
+
+type DoctorReviewRequest struct {
+	Rating int    `json:"rating" binding:"required,gte=1,lte=5"`
+	Review string `json:"review" binding:"max=1000"`
+}
+
+type DoctorReviewReplyRequest struct {
+	Reply string `json:"reply" binding:"required,max=1000"`
+}
+
+type DoctorReviewVisibilityRequest struct {
+	IsHidden *bool  `json:"is_hidden" binding:"required"`
+	Reason   string `json:"reason" binding:"max=255"`
+}
+
+type DoctorReviewQuery struct {
+	IsHidden string `form:"is_hidden" binding:"omitempty,oneof=true false"`
+	Page     string `form:"page"`
+	Limit    string `form:"limit"`
+}
+
+type DoctorReviewResponse struct {
+	Id              int64      `json:"id"`
+	RoomId          int64      `json:"room_id"`
+	UserAccountId   int64      `json:"user_account_id"`
+	UserName        string     `json:"user_name"`
+	DoctorAccountId int64      `json:"doctor_account_id"`
+	DoctorName      string     `json:"doctor_name"`
+	Rating          int        `json:"rating"`
+	Review          string     `json:"review"`
+	Reply           *string    `json:"reply"`
+	RepliedAt       *time.Time `json:"replied_at"`
+	HiddenAt        *time.Time `json:"hidden_at,omitempty"`
+	HiddenReason    string     `json:"hidden_reason,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+type AllDoctorReviewsResponse struct {
+	PageInfo      entity.PageInfo        `json:"page_info"`
+	DoctorReviews []DoctorReviewResponse `json:"reviews"`
+}
+
+func ConvertToDoctorReviewResponse(doctorReview entity.DoctorReview) DoctorReviewResponse {
+	return DoctorReviewResponse{
+		Id:              doctorReview.Id,
+		RoomId:          doctorReview.ChatRoomId,
+		UserAccountId:   doctorReview.UserAccountId,
+		UserName:        doctorReview.UserName,
+		DoctorAccountId: doctorReview.DoctorAccountId,
+		DoctorName:      doctorReview.DoctorName,
+		Rating:          doctorReview.Rating,
+		Review:          doctorReview.Review,
+		Reply:           doctorReview.Reply,
+		RepliedAt:       doctorReview.RepliedAt,
+		HiddenAt:        doctorReview.HiddenAt,
+		HiddenReason:    doctorReview.HiddenReason,
+		CreatedAt:       doctorReview.CreatedAt,
+	}
+}
+
+func ConvertToAllDoctorReviewsResponse(doctorReviews []entity.DoctorReview, pageInfo entity.PageInfo) AllDoctorReviewsResponse {
+	doctorReviewResponses := []DoctorReviewResponse{}
+
+	for _, doctorReview := range doctorReviews {
+		doctorReviewResponses = append(doctorReviewResponses, ConvertToDoctorReviewResponse(doctorReview))
+	}
+
+	return AllDoctorReviewsResponse{
+		PageInfo:      pageInfo,
+		DoctorReviews: doctorReviewResponses,
+	}
+}
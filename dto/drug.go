@@ -66,8 +66,25 @@ type DrugDetailResponse struct {
 }
 
 type DrugListingResponse struct {
-	Drugs    []entity.DrugListing `json:"drug_list"`
-	PageInfo entity.PageInfo      `json:"page_info"`
+	Drugs      []entity.DrugListing `json:"drug_list"`
+	PageInfo   entity.PageInfo      `json:"page_info"`
+	DidYouMean *string              `json:"did_you_mean,omitempty"`
+}
+
+type DrugSuggestQuery struct {
+	Query string `form:"q" binding:"required"`
+	Limit string `form:"limit"`
+}
+
+type DrugSuggestionResponse struct {
+	Id          int64  `json:"drug_id"`
+	Name        string `json:"drug_name"`
+	GenericName string `json:"generic_name"`
+}
+
+type DrugSuggestionsResponse struct {
+	Suggestions []DrugSuggestionResponse `json:"suggestions"`
+	DidYouMean  *string                  `json:"did_you_mean,omitempty"`
 }
 
 type DrugResponse struct {
@@ -263,3 +280,16 @@ func ConvertToMutationPharmacyDrugs(pharmacyDrugs []entity.PharmacyDrugDetail) [
 	}
 	return res
 }
+
+func ConvertToDrugSuggestionsResponse(drugSuggestions []entity.DrugSuggestion, didYouMean *string) DrugSuggestionsResponse {
+	suggestions := []DrugSuggestionResponse{}
+	for _, drugSuggestion := range drugSuggestions {
+		suggestions = append(suggestions, DrugSuggestionResponse{
+			Id:          drugSuggestion.Id,
+			Name:        drugSuggestion.Name,
+			GenericName: drugSuggestion.GenericName,
+		})
+	}
+
+	return DrugSuggestionsResponse{Suggestions: suggestions, DidYouMean: didYouMean}
+}
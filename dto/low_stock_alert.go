@@ -0,0 +1,109 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/sidiqPratomo/max-health-backend/entity"
+)
+
+type UpdateReorderThresholdRequest struct {
+	ReorderThreshold *int `json:"reorder_threshold" binding:"omitempty,min=0"`
+}
+
+type LowStockAlertQuery struct {
+	IsUnread bool   `form:"unread"`
+	Page     string `form:"page"`
+	Limit    string `form:"limit"`
+}
+
+type ReorderSuggestionQuery struct {
+	PharmacyId *int64 `form:"pharmacy-id"`
+	Days       *int   `form:"days" binding:"omitempty,min=1,max=365"`
+	CoverDays  *int   `form:"cover-days" binding:"omitempty,min=1,max=365"`
+	Page       string `form:"page"`
+	Limit      string `form:"limit"`
+}
+
+type LowStockAlertResponse struct {
+	Id               int64      `json:"id"`
+	PharmacyDrugId   int64      `json:"pharmacy_drug_id"`
+	PharmacyId       int64      `json:"pharmacy_id"`
+	PharmacyName     string     `json:"pharmacy_name"`
+	DrugId           int64      `json:"drug_id"`
+	DrugName         string     `json:"drug_name"`
+	Stock            int        `json:"stock"`
+	ReorderThreshold int        `json:"reorder_threshold"`
+	ReadAt           *time.Time `json:"read_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+type AllLowStockAlertsResponse struct {
+	PageInfo       entity.PageInfo         `json:"page_info"`
+	LowStockAlerts []LowStockAlertResponse `json:"alerts"`
+}
+
+type ReorderSuggestionResponse struct {
+	PharmacyDrugId    int64   `json:"pharmacy_drug_id"`
+	PharmacyId        int64   `json:"pharmacy_id"`
+	PharmacyName      string  `json:"pharmacy_name"`
+	DrugId            int64   `json:"drug_id"`
+	DrugName          string  `json:"drug_name"`
+	Stock             int     `json:"stock"`
+	ReorderThreshold  *int    `json:"reorder_threshold"`
+	SoldQuantity      int     `json:"sold_quantity"`
+	DailySales        float64 `json:"daily_sales"`
+	SuggestedQuantity int     `json:"suggested_quantity"`
+}
+
+type AllReorderSuggestionsResponse struct {
+	PageInfo           entity.PageInfo             `json:"page_info"`
+	ReorderSuggestions []ReorderSuggestionResponse `json:"suggestions"`
+}
+
+func ConvertToAllLowStockAlertsResponse(lowStockAlerts []entity.LowStockAlert, pageInfo entity.PageInfo) AllLowStockAlertsResponse {
+	lowStockAlertResponses := []LowStockAlertResponse{}
+
+	for _, lowStockAlert := range lowStockAlerts {
+		lowStockAlertResponses = append(lowStockAlertResponses, LowStockAlertResponse{
+			Id:               lowStockAlert.Id,
+			PharmacyDrugId:   lowStockAlert.PharmacyDrugId,
+			PharmacyId:       lowStockAlert.PharmacyId,
+			PharmacyName:     lowStockAlert.PharmacyName,
+			DrugId:           lowStockAlert.DrugId,
+			DrugName:         lowStockAlert.DrugName,
+			Stock:            lowStockAlert.Stock,
+			ReorderThreshold: lowStockAlert.ReorderThreshold,
+			ReadAt:           lowStockAlert.ReadAt,
+			CreatedAt:        lowStockAlert.CreatedAt,
+		})
+	}
+
+	return AllLowStockAlertsResponse{
+		PageInfo:       pageInfo,
+		LowStockAlerts: lowStockAlertResponses,
+	}
+}
+
+func ConvertToAllReorderSuggestionsResponse(reorderSuggestions []entity.ReorderSuggestion, pageInfo entity.PageInfo) AllReorderSuggestionsResponse {
+	reorderSuggestionResponses := []ReorderSuggestionResponse{}
+
+	for _, reorderSuggestion := range reorderSuggestions {
+		reorderSuggestionResponses = append(reorderSuggestionResponses, ReorderSuggestionResponse{
+			PharmacyDrugId:    reorderSuggestion.PharmacyDrugId,
+			PharmacyId:        reorderSuggestion.PharmacyId,
+			PharmacyName:      reorderSuggestion.PharmacyName,
+			DrugId:            reorderSuggestion.DrugId,
+			DrugName:          reorderSuggestion.DrugName,
+			Stock:             reorderSuggestion.Stock,
+			ReorderThreshold:  reorderSuggestion.ReorderThreshold,
+			SoldQuantity:      reorderSuggestion.SoldQuantity,
+			DailySales:        reorderSuggestion.DailySales,
+			SuggestedQuantity: reorderSuggestion.SuggestedQuantity,
+		})
+	}
+
+	return AllReorderSuggestionsResponse{
+		PageInfo:           pageInfo,
+		ReorderSuggestions: reorderSuggestionResponses,
+	}
+}
@@ -180,10 +180,11 @@ type PharmacyCheckoutFromPrescriptionRequest struct {
 }
 
 type OrderCheckoutRequest struct {
-	AccountId   int64
-	Address     string                    `json:"address" binding:"required"`
-	TotalAmount int                       `json:"total_amount" binding:"required"`
-	Pharmacies  []PharmacyCheckoutRequest `json:"pharmacies" binding:"required"`
+	AccountId     int64
+	UserAddressId int64                     `json:"user_address_id" binding:"required,gte=1"`
+	Address       string                    `json:"address" binding:"required"`
+	TotalAmount   int                       `json:"total_amount" binding:"required"`
+	Pharmacies    []PharmacyCheckoutRequest `json:"pharmacies" binding:"required"`
 }
 
 type PharmacyDrugQuantity struct {
@@ -201,10 +202,11 @@ type OrderChangeStatusRequest struct {
 
 func ConvertPrescriptionCheckoutRequest(request CheckoutFromPrescriptionRequest) OrderCheckoutRequest {
 	return OrderCheckoutRequest{
-		AccountId:   request.AccountId,
-		TotalAmount: request.TotalAmount,
-		Address:     request.Address,
-		Pharmacies:  ConvertPharmacyCheckoutFromPrescriptionRequestList(request.Pharmacies),
+		AccountId:     request.AccountId,
+		UserAddressId: request.UserAddressId,
+		TotalAmount:   request.TotalAmount,
+		Address:       request.Address,
+		Pharmacies:    ConvertPharmacyCheckoutFromPrescriptionRequestList(request.Pharmacies),
 	}
 }
 
@@ -1,6 +1,8 @@
 package dto
 
 import (
+	"time"
+
 	"github.com/sidiqPratomo/max-health-backend/entity"
 	"github.com/shopspring/decimal"
 )
@@ -48,6 +50,52 @@ type UpdatePharmacyCourierRequest struct {
 	IsActive  bool  `json:"is_active"`
 }
 
+type PharmacyScheduleRequest struct {
+	Timezone     string                               `json:"timezone" binding:"required,timezone"`
+	Operationals []PharmacyScheduleOperationalRequest `json:"operationals" binding:"required,len=7,dive"`
+}
+
+type PharmacyScheduleOperationalRequest struct {
+	OperationalDay string `json:"operational_day" binding:"required,oneof=Monday Tuesday Wednesday Thursday Friday Saturday Sunday"`
+	OpenHour       string `json:"open_hour" binding:"required"`
+	CloseHour      string `json:"close_hour" binding:"required"`
+	IsOpen         bool   `json:"is_open"`
+}
+
+type PharmacyHolidayRequest struct {
+	Date        string  `json:"date" binding:"required,datetime=2006-01-02"`
+	IsOpen      bool    `json:"is_open"`
+	OpenHour    *string `json:"open_hour" binding:"required_if=IsOpen true"`
+	CloseHour   *string `json:"close_hour" binding:"required_if=IsOpen true"`
+	Description string  `json:"description"`
+}
+
+type PharmacyScheduleResponse struct {
+	PharmacyId   int64                         `json:"pharmacy_id"`
+	Timezone     string                        `json:"timezone"`
+	IsOpen       bool                          `json:"is_open"`
+	OpensAt      *time.Time                    `json:"opens_at,omitempty"`
+	Operationals []PharmacyOperationalResponse `json:"operationals"`
+	Holidays     []PharmacyHolidayResponse     `json:"holidays"`
+}
+
+type PharmacyOperationalResponse struct {
+	Id             int64  `json:"id"`
+	OperationalDay string `json:"operational_day"`
+	OpenHour       string `json:"open_hour"`
+	CloseHour      string `json:"close_hour"`
+	IsOpen         bool   `json:"is_open"`
+}
+
+type PharmacyHolidayResponse struct {
+	Id          int64   `json:"id"`
+	Date        string  `json:"date"`
+	IsOpen      bool    `json:"is_open"`
+	OpenHour    *string `json:"open_hour,omitempty"`
+	CloseHour   *string `json:"close_hour,omitempty"`
+	Description string  `json:"description"`
+}
+
 type Pharmacy struct {
 	Id                      int64   `json:"id,omitempty"`
 	PharmacyManagerId       int64   `json:"manager_id,omitempty"`
@@ -63,15 +111,17 @@ type Pharmacy struct {
 }
 
 type UpdatePharmacyDrugReq struct {
-	Stock int             `json:"stock"`
-	Price decimal.Decimal `json:"price"`
+	Stock int                       `json:"stock"`
+	Price decimal.Decimal           `json:"price"`
+	Batch *PharmacyDrugBatchRequest `json:"batch"`
 }
 
 type AddPharmacyDrugReq struct {
-	PharmacyId int64           `json:"pharmacy_id"`
-	DrugId     int64           `json:"drug_id"`
-	Stock      int             `json:"stock"`
-	Price      decimal.Decimal `json:"price"`
+	PharmacyId int64                     `json:"pharmacy_id"`
+	DrugId     int64                     `json:"drug_id"`
+	Stock      int                       `json:"stock"`
+	Price      decimal.Decimal           `json:"price"`
+	Batch      *PharmacyDrugBatchRequest `json:"batch"`
 }
 
 type PostStockMutationRequest struct {
@@ -192,3 +242,52 @@ func AllUpdatePharmacyCourierRequestToAllPharmacyCouriers(pharmacyCourierRequest
 
 	return pharmacyCouriers
 }
+
+func ConvertToPharmacyScheduleResponse(schedule entity.PharmacySchedule, openingStatus entity.PharmacyOpeningStatus) PharmacyScheduleResponse {
+	pharmacyOperationals := []PharmacyOperationalResponse{}
+	for _, pharmacyOperational := range schedule.Operationals {
+		pharmacyOperationals = append(pharmacyOperationals, PharmacyOperationalResponse{
+			Id:             pharmacyOperational.Id,
+			OperationalDay: pharmacyOperational.OperationalDay,
+			OpenHour:       pharmacyOperational.OpenHour,
+			CloseHour:      pharmacyOperational.CloseHour,
+			IsOpen:         pharmacyOperational.IsOpen,
+		})
+	}
+
+	pharmacyHolidays := []PharmacyHolidayResponse{}
+	for _, pharmacyHoliday := range schedule.Holidays {
+		pharmacyHolidays = append(pharmacyHolidays, PharmacyHolidayResponse{
+			Id:          pharmacyHoliday.Id,
+			Date:        pharmacyHoliday.Date.Format("2006-01-02"),
+			IsOpen:      pharmacyHoliday.IsOpen,
+			OpenHour:    pharmacyHoliday.OpenHour,
+			CloseHour:   pharmacyHoliday.CloseHour,
+			Description: pharmacyHoliday.Description,
+		})
+	}
+
+	return PharmacyScheduleResponse{
+		PharmacyId:   schedule.PharmacyId,
+		Timezone:     schedule.Timezone,
+		IsOpen:       openingStatus.IsOpen,
+		OpensAt:      openingStatus.OpensAt,
+		Operationals: pharmacyOperationals,
+		Holidays:     pharmacyHolidays,
+	}
+}
+
+func ConvertPharmacyScheduleRequestToPharmacyOperationals(pharmacyId int64, request PharmacyScheduleRequest) []entity.PharmacyOperational {
+	pharmacyOperationals := []entity.PharmacyOperational{}
+	for _, operational := range request.Operationals {
+		pharmacyOperationals = append(pharmacyOperationals, entity.PharmacyOperational{
+			PharmacyId:     pharmacyId,
+			OperationalDay: operational.OperationalDay,
+			OpenHour:       operational.OpenHour,
+			CloseHour:      operational.CloseHour,
+			IsOpen:         operational.IsOpen,
+		})
+	}
+
+	return pharmacyOperationals
+}
@@ -0,0 +1,95 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/sidiqPratomo/max-health-backend/appconstant"
+	"github.com/sidiqPratomo/max-health-backend/entity"
+)
+
+type PharmacyDrugBatchRequest struct {
+	BatchNumber string `json:"batch_number" binding:"required"`
+	ExpiryDate  string `json:"expiry_date" binding:"required"`
+}
+
+type ReceivePharmacyDrugBatchRequest struct {
+	PharmacyDrugBatchRequest
+	Stock int `json:"stock" binding:"required,gt=0"`
+}
+
+type ExpiringBatchQuery struct {
+	Days       *int   `form:"days" binding:"omitempty,min=0"`
+	PharmacyId *int64 `form:"pharmacy-id"`
+	Page       string `form:"page"`
+	Limit      string `form:"limit"`
+}
+
+type PharmacyDrugBatchResponse struct {
+	Id           int64      `json:"id"`
+	BatchNumber  string     `json:"batch_number"`
+	ExpiryDate   *string    `json:"expiry_date"`
+	Stock        int        `json:"stock"`
+	WrittenOffAt *time.Time `json:"written_off_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+type ExpiringBatchResponse struct {
+	PharmacyDrugBatchResponse
+	PharmacyDrugId int64  `json:"pharmacy_drug_id"`
+	PharmacyId     int64  `json:"pharmacy_id"`
+	PharmacyName   string `json:"pharmacy_name"`
+	DrugId         int64  `json:"drug_id"`
+	DrugName       string `json:"drug_name"`
+}
+
+type AllExpiringBatchesResponse struct {
+	PageInfo        entity.PageInfo         `json:"page_info"`
+	ExpiringBatches []ExpiringBatchResponse `json:"batches"`
+}
+
+func ConvertToPharmacyDrugBatchResponse(pharmacyDrugBatch entity.PharmacyDrugBatch) PharmacyDrugBatchResponse {
+	var expiryDate *string
+	if pharmacyDrugBatch.ExpiryDate != nil {
+		formattedExpiryDate := pharmacyDrugBatch.ExpiryDate.Format(appconstant.BatchExpiryDateFormat)
+		expiryDate = &formattedExpiryDate
+	}
+
+	return PharmacyDrugBatchResponse{
+		Id:           pharmacyDrugBatch.Id,
+		BatchNumber:  pharmacyDrugBatch.BatchNumber,
+		ExpiryDate:   expiryDate,
+		Stock:        pharmacyDrugBatch.Stock,
+		WrittenOffAt: pharmacyDrugBatch.WrittenOffAt,
+		CreatedAt:    pharmacyDrugBatch.CreatedAt,
+	}
+}
+
+func ConvertToPharmacyDrugBatchesResponse(pharmacyDrugBatches []entity.PharmacyDrugBatch) []PharmacyDrugBatchResponse {
+	pharmacyDrugBatchResponses := []PharmacyDrugBatchResponse{}
+
+	for _, pharmacyDrugBatch := range pharmacyDrugBatches {
+		pharmacyDrugBatchResponses = append(pharmacyDrugBatchResponses, ConvertToPharmacyDrugBatchResponse(pharmacyDrugBatch))
+	}
+
+	return pharmacyDrugBatchResponses
+}
+
+func ConvertToAllExpiringBatchesResponse(expiringBatches []entity.ExpiringBatch, pageInfo entity.PageInfo) AllExpiringBatchesResponse {
+	expiringBatchResponses := []ExpiringBatchResponse{}
+
+	for _, expiringBatch := range expiringBatches {
+		expiringBatchResponses = append(expiringBatchResponses, ExpiringBatchResponse{
+			PharmacyDrugBatchResponse: ConvertToPharmacyDrugBatchResponse(expiringBatch.PharmacyDrugBatch),
+			PharmacyDrugId:            expiringBatch.PharmacyDrugId,
+			PharmacyId:                expiringBatch.PharmacyId,
+			PharmacyName:              expiringBatch.PharmacyName,
+			DrugId:                    expiringBatch.DrugId,
+			DrugName:                  expiringBatch.DrugName,
+		})
+	}
+
+	return AllExpiringBatchesResponse{
+		PageInfo:        pageInfo,
+		ExpiringBatches: expiringBatchResponses,
+	}
+}
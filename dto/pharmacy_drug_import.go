@@ -0,0 +1,71 @@
+package dto
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/sidiqPratomo/max-health-backend/entity"
+)
+
+type PharmacyDrugImportQuery struct {
+	DryRun bool `form:"dry-run"`
+}
+
+type PharmacyDrugImportRowResponse struct {
+	Row            int             `json:"row"`
+	DrugId         *int64          `json:"drug_id"`
+	DrugName       string          `json:"drug_name"`
+	PharmacyDrugId *int64          `json:"pharmacy_drug_id"`
+	Action         string          `json:"action"`
+	PreviousStock  *int            `json:"previous_stock"`
+	Stock          int             `json:"stock"`
+	Price          decimal.Decimal `json:"price"`
+	BatchNumber    string          `json:"batch_number"`
+	ExpiryDate     string          `json:"expiry_date"`
+	Errors         []string        `json:"errors"`
+}
+
+type PharmacyDrugImportResponse struct {
+	DryRun      bool                            `json:"dry_run"`
+	Applied     bool                            `json:"applied"`
+	TotalRows   int                             `json:"total_rows"`
+	ValidRows   int                             `json:"valid_rows"`
+	InvalidRows int                             `json:"invalid_rows"`
+	Rows        []PharmacyDrugImportRowResponse `json:"rows"`
+}
+
+func ConvertToPharmacyDrugImportResponse(importRows []entity.PharmacyDrugImportRow, dryRun, applied bool) PharmacyDrugImportResponse {
+	response := PharmacyDrugImportResponse{
+		DryRun:    dryRun,
+		Applied:   applied,
+		TotalRows: len(importRows),
+		Rows:      []PharmacyDrugImportRowResponse{},
+	}
+
+	for _, importRow := range importRows {
+		errors := importRow.Errors
+		if errors == nil {
+			errors = []string{}
+		}
+
+		if len(errors) == 0 {
+			response.ValidRows++
+		} else {
+			response.InvalidRows++
+		}
+
+		response.Rows = append(response.Rows, PharmacyDrugImportRowResponse{
+			Row:            importRow.RowNumber,
+			DrugId:         importRow.DrugId,
+			DrugName:       importRow.DrugName,
+			PharmacyDrugId: importRow.PharmacyDrugId,
+			Action:         importRow.Action,
+			PreviousStock:  importRow.PreviousStock,
+			Stock:          importRow.Stock,
+			Price:          importRow.Price,
+			BatchNumber:    importRow.BatchNumber,
+			ExpiryDate:     importRow.ExpiryDate,
+			Errors:         errors,
+		})
+	}
+
+	return response
+}
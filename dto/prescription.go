@@ -14,12 +14,13 @@ type PrescriptionDrugRequest struct {
 }
 
 type PrescriptionDrugResponse struct {
-	Id         int64        `json:"id"`
-	Drug       DrugResponse `json:"drug"`
-	Quantity   int          `json:"quantity"`
-	Note       string       `json:"note"`
-	RedeemedAt *string      `json:"redeemed_at,omitempty"`
-	OrderedAt  *string      `json:"ordered_at,omitempty"`
+	Id                int64        `json:"id"`
+	Drug              DrugResponse `json:"drug"`
+	Quantity          int          `json:"quantity"`
+	RemainingQuantity int          `json:"remaining_quantity"`
+	Note              string       `json:"note"`
+	RedeemedAt        *string      `json:"redeemed_at,omitempty"`
+	OrderedAt         *string      `json:"ordered_at,omitempty"`
 }
 
 type PrescriptionResponse struct {
@@ -31,6 +32,10 @@ type PrescriptionResponse struct {
 	RedeemedAt        *time.Time                 `json:"redeemed_at"`
 	OrderedAt         *time.Time                 `json:"ordered_at"`
 	CreatedAt         *time.Time                 `json:"created_at"`
+	RefillCount       int                        `json:"refill_count"`
+	RefillsUsed       int                        `json:"refills_used"`
+	ValidUntil        *time.Time                 `json:"valid_until"`
+	IsExpired         bool                       `json:"is_expired"`
 	PrescriptionDrugs []PrescriptionDrugResponse `json:"prescription_drugs"`
 }
 
@@ -42,6 +47,36 @@ type PrescriptionResponseList struct {
 	} `json:"page_info"`
 }
 
+type PrescriptionRedemptionResponse struct {
+	OrderId            int64     `json:"order_id"`
+	OrderPharmacyId    int64     `json:"order_pharmacy_id"`
+	OrderStatusId      int64     `json:"order_status_id"`
+	OrderItemId        int64     `json:"order_item_id"`
+	PrescriptionDrugId int64     `json:"prescription_drug_id"`
+	DrugId             int64     `json:"drug_id"`
+	DrugName           string    `json:"drug_name"`
+	Quantity           int       `json:"quantity"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+type PrescriptionRedemptionsResponse struct {
+	Prescription PrescriptionResponse             `json:"prescription"`
+	Redemptions  []PrescriptionRedemptionResponse `json:"redemptions"`
+}
+
+type PrescriptionVerificationResponse struct {
+	PrescriptionId       int64                      `json:"prescription_id"`
+	IsValid              bool                       `json:"is_valid"`
+	IsExpired            bool                       `json:"is_expired"`
+	DoctorName           string                     `json:"doctor_name"`
+	DoctorSpecialization string                     `json:"doctor_specialization"`
+	UserName             string                     `json:"user_name"`
+	RefillCount          int                        `json:"refill_count"`
+	IssuedAt             *time.Time                 `json:"issued_at"`
+	ValidUntil           *time.Time                 `json:"valid_until"`
+	PrescriptionDrugs    []PrescriptionDrugResponse `json:"prescription_drugs"`
+}
+
 func ConvertToPrescriptionDrugListResponse(prescriptionDrugList []entity.PrescriptionDrug) []PrescriptionDrugResponse {
 	var list []PrescriptionDrugResponse
 
@@ -54,10 +89,11 @@ func ConvertToPrescriptionDrugListResponse(prescriptionDrugList []entity.Prescri
 
 func ConvertToPrescriptionDrugResponse(prescriptionDrug entity.PrescriptionDrug) PrescriptionDrugResponse {
 	return PrescriptionDrugResponse{
-		Id:       prescriptionDrug.Id,
-		Drug:     ConvertToDrugResponse(prescriptionDrug.Drug),
-		Quantity: prescriptionDrug.Quantity,
-		Note:     prescriptionDrug.Note,
+		Id:                prescriptionDrug.Id,
+		Drug:              ConvertToDrugResponse(prescriptionDrug.Drug),
+		Quantity:          prescriptionDrug.Quantity,
+		RemainingQuantity: prescriptionDrug.RemainingQuantity,
+		Note:              prescriptionDrug.Note,
 	}
 }
 
@@ -71,6 +107,10 @@ func ConvertToPrescriptionResponse(prescription entity.Prescription) Prescriptio
 		RedeemedAt:        prescription.RedeemedAt,
 		OrderedAt:         prescription.OrderedAt,
 		CreatedAt:         prescription.CreatedAt,
+		RefillCount:       prescription.RefillCount,
+		RefillsUsed:       prescription.RefillsUsed,
+		ValidUntil:        prescription.ValidUntil,
+		IsExpired:         prescription.IsExpired,
 		PrescriptionDrugs: ConvertToPrescriptionDrugListResponse(prescription.PrescriptionDrugs),
 	}
 }
@@ -90,3 +130,41 @@ func ConvertToPrescriptionResponseList(prescriptionList []entity.Prescription, t
 		}{TotalPage: totalPage, TotalItem: totalItem},
 	}
 }
+
+func ConvertToPrescriptionRedemptionsResponse(prescription entity.Prescription, redemptions []entity.PrescriptionRedemption) PrescriptionRedemptionsResponse {
+	redemptionResponses := []PrescriptionRedemptionResponse{}
+
+	for _, redemption := range redemptions {
+		redemptionResponses = append(redemptionResponses, PrescriptionRedemptionResponse{
+			OrderId:            redemption.OrderId,
+			OrderPharmacyId:    redemption.OrderPharmacyId,
+			OrderStatusId:      redemption.OrderStatusId,
+			OrderItemId:        redemption.OrderItemId,
+			PrescriptionDrugId: redemption.PrescriptionDrugId,
+			DrugId:             redemption.DrugId,
+			DrugName:           redemption.DrugName,
+			Quantity:           redemption.Quantity,
+			CreatedAt:          redemption.CreatedAt,
+		})
+	}
+
+	return PrescriptionRedemptionsResponse{
+		Prescription: ConvertToPrescriptionResponse(prescription),
+		Redemptions:  redemptionResponses,
+	}
+}
+
+func ConvertToPrescriptionVerificationResponse(prescription entity.Prescription) PrescriptionVerificationResponse {
+	return PrescriptionVerificationResponse{
+		PrescriptionId:       *prescription.Id,
+		IsValid:              true,
+		IsExpired:            prescription.IsExpired,
+		DoctorName:           prescription.DoctorName,
+		DoctorSpecialization: prescription.DoctorSpecialization,
+		UserName:             prescription.UserName,
+		RefillCount:          prescription.RefillCount,
+		IssuedAt:             prescription.CreatedAt,
+		ValidUntil:           prescription.ValidUntil,
+		PrescriptionDrugs:    ConvertToPrescriptionDrugListResponse(prescription.PrescriptionDrugs),
+	}
+}
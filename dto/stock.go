@@ -1,15 +1,74 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/sidiqPratomo/max-health-backend/entity"
+)
+
 type StockChangeQuery struct {
-	PharmacyId *int64 `form:"pharmacy-id"`
+	PharmacyId *int64  `form:"pharmacy-id"`
+	DrugId     *int64  `form:"drug-id"`
+	ChangeType *string `form:"change-type" binding:"omitempty,oneof=sale mutation manual_adjustment cancellation"`
+	Sign       *string `form:"sign" binding:"omitempty,oneof=positive negative"`
+	StartDate  *string `form:"start-date" binding:"omitempty,datetime=2006-01-02"`
+	EndDate    *string `form:"end-date" binding:"omitempty,datetime=2006-01-02"`
+	Page       string  `form:"page"`
+	Limit      string  `form:"limit"`
 }
 
 type StockChangeResponse struct {
-	PharmacyName    string `json:"pharmacy_name"`
-	PharmacyAddress string `json:"pharmacy_address"`
-	DrugImage       string `json:"drug_url"`
-	DrugName        string `json:"drug_name"`
-	FinalStock      int    `json:"final_stock"`
-	Change          int    `json:"stock_change"`
-	Description     string `json:"description"`
+	Id              int64     `json:"id"`
+	PharmacyDrugId  int64     `json:"pharmacy_drug_id"`
+	PharmacyId      int64     `json:"pharmacy_id"`
+	PharmacyName    string    `json:"pharmacy_name"`
+	PharmacyAddress string    `json:"pharmacy_address"`
+	DrugId          int64     `json:"drug_id"`
+	DrugImage       string    `json:"drug_url"`
+	DrugName        string    `json:"drug_name"`
+	FinalStock      int       `json:"final_stock"`
+	Change          int       `json:"stock_change"`
+	Description     string    `json:"description"`
+	ChangeType      string    `json:"change_type"`
+	ActorAccountId  *int64    `json:"actor_account_id"`
+	ActorName       *string   `json:"actor_name"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type AllStockChangesResponse struct {
+	PageInfo     entity.PageInfo       `json:"page_info"`
+	StockChanges []StockChangeResponse `json:"stock_changes"`
+}
+
+func ConvertToStockChangeResponse(stockChange entity.StockChangeDetail) StockChangeResponse {
+	return StockChangeResponse{
+		Id:              stockChange.Id,
+		PharmacyDrugId:  stockChange.PharmacyDrugId,
+		PharmacyId:      stockChange.PharmacyId,
+		PharmacyName:    stockChange.PharmacyName,
+		PharmacyAddress: stockChange.PharmacyAddress,
+		DrugId:          stockChange.DrugId,
+		DrugImage:       stockChange.DrugImage,
+		DrugName:        stockChange.DrugName,
+		FinalStock:      stockChange.FinalStock,
+		Change:          stockChange.Amount,
+		Description:     stockChange.Description,
+		ChangeType:      stockChange.ChangeType,
+		ActorAccountId:  stockChange.ActorAccountId,
+		ActorName:       stockChange.ActorName,
+		CreatedAt:       stockChange.CreatedAt,
+	}
+}
+
+func ConvertToAllStockChangesResponse(stockChanges []entity.StockChangeDetail, pageInfo entity.PageInfo) AllStockChangesResponse {
+	stockChangeResponses := []StockChangeResponse{}
+
+	for _, stockChange := range stockChanges {
+		stockChangeResponses = append(stockChangeResponses, ConvertToStockChangeResponse(stockChange))
+	}
+
+	return AllStockChangesResponse{
+		PageInfo:     pageInfo,
+		StockChanges: stockChangeResponses,
+	}
 }
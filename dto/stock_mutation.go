@@ -0,0 +1,68 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/sidiqPratomo/max-health-backend/entity"
+)
+
+type StockMutationRequestQuery struct {
+	Direction string `form:"direction" binding:"required,oneof=incoming outgoing"`
+	Status    string `form:"status" binding:"omitempty,oneof=pending approved rejected shipped received cancelled"`
+	Page      string `form:"page"`
+	Limit     string `form:"limit"`
+}
+
+type StockMutationRequestResponse struct {
+	Id                    int64      `json:"id"`
+	RequesterPharmacyId   int64      `json:"requester_pharmacy_id"`
+	RequesterPharmacyName string     `json:"requester_pharmacy_name"`
+	TargetPharmacyId      int64      `json:"target_pharmacy_id"`
+	TargetPharmacyName    string     `json:"target_pharmacy_name"`
+	DrugId                int64      `json:"drug_id"`
+	DrugName              string     `json:"drug_name"`
+	Quantity              int        `json:"quantity"`
+	OrderId               *int64     `json:"order_id"`
+	Status                string     `json:"status"`
+	RespondedAt           *time.Time `json:"responded_at"`
+	ShippedAt             *time.Time `json:"shipped_at"`
+	ReceivedAt            *time.Time `json:"received_at"`
+	CreatedAt             time.Time  `json:"created_at"`
+}
+
+type AllStockMutationRequestsResponse struct {
+	PageInfo              entity.PageInfo                `json:"page_info"`
+	StockMutationRequests []StockMutationRequestResponse `json:"stock_mutations"`
+}
+
+func ConvertToStockMutationRequestResponse(stockMutationRequest entity.StockMutationRequest) StockMutationRequestResponse {
+	return StockMutationRequestResponse{
+		Id:                    stockMutationRequest.Id,
+		RequesterPharmacyId:   stockMutationRequest.PharmacyRequesterId,
+		RequesterPharmacyName: stockMutationRequest.PharmacyRequesterName,
+		TargetPharmacyId:      stockMutationRequest.PharmacyTargetId,
+		TargetPharmacyName:    stockMutationRequest.PharmacyTargetName,
+		DrugId:                stockMutationRequest.DrugId,
+		DrugName:              stockMutationRequest.DrugName,
+		Quantity:              stockMutationRequest.Stock,
+		OrderId:               stockMutationRequest.OrderId,
+		Status:                stockMutationRequest.Status,
+		RespondedAt:           stockMutationRequest.RespondedAt,
+		ShippedAt:             stockMutationRequest.ShippedAt,
+		ReceivedAt:            stockMutationRequest.ReceivedAt,
+		CreatedAt:             stockMutationRequest.CreatedAt,
+	}
+}
+
+func ConvertToAllStockMutationRequestsResponse(stockMutationRequests []entity.StockMutationRequest, pageInfo entity.PageInfo) AllStockMutationRequestsResponse {
+	stockMutationRequestResponses := []StockMutationRequestResponse{}
+
+	for _, stockMutationRequest := range stockMutationRequests {
+		stockMutationRequestResponses = append(stockMutationRequestResponses, ConvertToStockMutationRequestResponse(stockMutationRequest))
+	}
+
+	return AllStockMutationRequestsResponse{
+		PageInfo:              pageInfo,
+		StockMutationRequests: stockMutationRequestResponses,
+	}
+}
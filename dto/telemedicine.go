@@ -3,12 +3,14 @@ package dto
 import (
 	"time"
 
+	"github.com/sidiqPratomo/max-health-backend/appconstant"
 	"github.com/sidiqPratomo/max-health-backend/entity"
 	"github.com/shopspring/decimal"
 )
 
 type UserCreateRoomRequest struct {
 	DoctorAccountId int64 `json:"doctor_account_id" binding:"required,gte=1"`
+	AllowFallback   bool  `json:"allow_fallback"`
 }
 
 type DoctorJoinRoomRequest struct {
@@ -19,6 +21,25 @@ type PostOneMessageRequest struct {
 	RoomId            int64                     `json:"room_id" validate:"required,gte=1"`
 	Message           string                    `json:"message" validate:"required,min=1"`
 	PrescriptionDrugs []PrescriptionDrugRequest `json:"prescription_drugs"`
+	RefillCount       int                       `json:"refill_count" validate:"gte=0,lte=12"`
+	ValidForDays      int                       `json:"valid_for_days" validate:"gte=0,lte=365"`
+}
+
+type ChatEventRequest struct {
+	Type           string `json:"type" validate:"required,oneof=typing read"`
+	IsTyping       bool   `json:"is_typing"`
+	LastReadChatId int64  `json:"last_read_chat_id" validate:"required_if=Type read,gte=0"`
+}
+
+type ChatEvent struct {
+	Type            string                     `json:"type"`
+	RoomId          int64                      `json:"room_id"`
+	SenderAccountId int64                      `json:"sender_account_id,omitempty"`
+	Chat            *Chat                      `json:"chat,omitempty"`
+	IsTyping        *bool                      `json:"is_typing,omitempty"`
+	LastReadChatId  *int64                     `json:"last_read_chat_id,omitempty"`
+	ExpiredAt       *time.Time                 `json:"expired_at,omitempty"`
+	Extension       *ChatRoomExtensionResponse `json:"extension,omitempty"`
 }
 
 type UserCreateRoomResponse struct {
@@ -55,28 +76,49 @@ type ChatRoomPreview struct {
 	ParticipantPictureUrl string     `json:"participant_picture_url"`
 	ExpiredAt             *time.Time `json:"expired_at,omitempty"`
 	LastChat              Chat       `json:"last_chat"`
+	QueuePosition         int        `json:"queue_position,omitempty"`
 }
 
 type CheckoutFromPrescriptionRequest struct {
 	AccountId      int64
 	PrescriptionId int64                                     `json:"prescription_id" binding:"required,gte=1"`
+	UserAddressId  int64                                     `json:"user_address_id" binding:"required,gte=1"`
 	Address        string                                    `json:"address" binding:"required"`
 	TotalAmount    int                                       `json:"total_amount" binding:"required"`
 	Pharmacies     []PharmacyCheckoutFromPrescriptionRequest `json:"pharmacies" binding:"required,min=1"`
 }
 
 type PreapareForCheckoutResponse struct {
-	PharmacyDrugs []PreapareForCheckoutItemResponse `json:"pharmacy_drugs"`
+	Strategy       string                            `json:"strategy"`
+	Subtotal       decimal.Decimal                   `json:"subtotal"`
+	ShippingCost   decimal.Decimal                   `json:"shipping_cost"`
+	TotalCost      decimal.Decimal                   `json:"total_cost"`
+	ShipmentCount  int                               `json:"shipment_count"`
+	EstimatedHours *int                              `json:"estimated_hours"`
+	PharmacyDrugs  []PreapareForCheckoutItemResponse `json:"pharmacy_drugs"`
+	Alternatives   []FulfilmentPlanResponse          `json:"alternatives"`
+}
+
+type FulfilmentPlanResponse struct {
+	Subtotal       decimal.Decimal                   `json:"subtotal"`
+	ShippingCost   decimal.Decimal                   `json:"shipping_cost"`
+	TotalCost      decimal.Decimal                   `json:"total_cost"`
+	ShipmentCount  int                               `json:"shipment_count"`
+	EstimatedHours *int                              `json:"estimated_hours"`
+	PharmacyDrugs  []PreapareForCheckoutItemResponse `json:"pharmacy_drugs"`
 }
 
 type PreapareForCheckoutItemResponse struct {
-	PharmacyId      int64                     `json:"pharmacy_id"`
-	PharmacyName    string                    `json:"pharmacy_name"`
-	PharmacyAddress string                    `json:"pharmacy_address"`
-	Distance        float64                   `json:"distance"`
-	Subtotal        decimal.Decimal           `json:"subtotal"`
-	Couriers        []entity.AvailableCourier `json:"couriers"`
-	DrugQuantities  []DrugQuantity            `json:"drug_quantities"`
+	PharmacyId        int64                     `json:"pharmacy_id"`
+	PharmacyName      string                    `json:"pharmacy_name"`
+	PharmacyAddress   string                    `json:"pharmacy_address"`
+	Distance          float64                   `json:"distance"`
+	Subtotal          decimal.Decimal           `json:"subtotal"`
+	Couriers          []entity.AvailableCourier `json:"couriers"`
+	DrugQuantities    []DrugQuantity            `json:"drug_quantities"`
+	PharmacyCourierId int64                     `json:"recommended_pharmacy_courier_id"`
+	ShippingCost      decimal.Decimal           `json:"shipping_cost"`
+	Etd               string                    `json:"estimated_time_of_delivery"`
 }
 
 type DrugQuantity struct {
@@ -96,6 +138,34 @@ func ConvertToChatDTO(chat entity.Chat) Chat {
 	}
 }
 
+func ConvertToChatEventDTO(chatEvent entity.ChatEvent) ChatEvent {
+	chatEventDTO := ChatEvent{
+		Type:            chatEvent.Type,
+		RoomId:          chatEvent.RoomId,
+		SenderAccountId: chatEvent.SenderAccountId,
+	}
+
+	switch chatEvent.Type {
+	case appconstant.ChatEventMessage:
+		if chatEvent.Chat != nil {
+			chat := ConvertToChatDTO(*chatEvent.Chat)
+			chatEventDTO.Chat = &chat
+		}
+	case appconstant.ChatEventTyping:
+		chatEventDTO.IsTyping = &chatEvent.IsTyping
+	case appconstant.ChatEventRead:
+		chatEventDTO.LastReadChatId = &chatEvent.LastReadChatId
+	}
+
+	chatEventDTO.ExpiredAt = chatEvent.ExpiredAt
+	if chatEvent.Extension != nil {
+		extension := ConvertToChatRoomExtensionResponse(*chatEvent.Extension)
+		chatEventDTO.Extension = &extension
+	}
+
+	return chatEventDTO
+}
+
 func ConvertToChatListDTO(chatList []entity.Chat) []Chat {
 	var chatListDTO []Chat
 
@@ -124,6 +194,7 @@ func ConvertToChatRoomPreview(chatRoomPreview entity.ChatRoomPreview) ChatRoomPr
 		ParticipantPictureUrl: chatRoomPreview.ParticipantPictureUrl,
 		ExpiredAt:             chatRoomPreview.ExpiredAt,
 		LastChat:              ConvertToChatDTO(chatRoomPreview.LastChat),
+		QueuePosition:         chatRoomPreview.QueuePosition,
 	}
 }
 
@@ -137,8 +208,15 @@ func ConvertToChatRoomPreviewList(chatRoomPreviewList []entity.ChatRoomPreview)
 	return chatRoomPreviewListDTO
 }
 
-func ConvertToPrescriptionEntity(prescriptionDrugs []PrescriptionDrugRequest) entity.Prescription {
-	var prescription entity.Prescription
+func ConvertToPrescriptionEntity(prescriptionDrugs []PrescriptionDrugRequest, refillCount int, validForDays int) entity.Prescription {
+	prescription := entity.Prescription{
+		RefillCount:  refillCount,
+		ValidForDays: validForDays,
+	}
+
+	if prescription.ValidForDays == 0 {
+		prescription.ValidForDays = appconstant.PrescriptionDefaultValidDays
+	}
 
 	for _, prescriptionDrug := range prescriptionDrugs {
 		prescription.PrescriptionDrugs = append(prescription.PrescriptionDrugs, entity.PrescriptionDrug{
@@ -156,7 +234,7 @@ func ConvertPostMessageRequestToChatEntity(dto PostOneMessageRequest) entity.Cha
 	return entity.Chat{
 		RoomId:       dto.RoomId,
 		Message:      &dto.Message,
-		Prescription: ConvertToPrescriptionEntity(dto.PrescriptionDrugs),
+		Prescription: ConvertToPrescriptionEntity(dto.PrescriptionDrugs, dto.RefillCount, dto.ValidForDays),
 	}
 }
 
@@ -183,22 +261,56 @@ func ConverToDrugQuantityListDTO(drugQuantityList []entity.DrugQuantity) []DrugQ
 
 func ConvertPreapareForCheckoutItemToResponse(checkoutItem entity.PrepareForCheckoutItem) PreapareForCheckoutItemResponse {
 	return PreapareForCheckoutItemResponse{
-		PharmacyId:      checkoutItem.PharmacyId,
-		PharmacyName:    checkoutItem.PharmacyName,
-		PharmacyAddress: checkoutItem.PharmacyAddress,
-		Distance:        checkoutItem.Distance,
-		Subtotal:        checkoutItem.Subtotal,
-		Couriers:        checkoutItem.DeliveryOptions,
-		DrugQuantities:  ConverToDrugQuantityListDTO(checkoutItem.DrugQuantities),
+		PharmacyId:        checkoutItem.PharmacyId,
+		PharmacyName:      checkoutItem.PharmacyName,
+		PharmacyAddress:   checkoutItem.PharmacyAddress,
+		Distance:          checkoutItem.Distance,
+		Subtotal:          checkoutItem.Subtotal,
+		Couriers:          checkoutItem.DeliveryOptions,
+		DrugQuantities:    ConverToDrugQuantityListDTO(checkoutItem.DrugQuantities),
+		PharmacyCourierId: checkoutItem.PharmacyCourierId,
+		ShippingCost:      checkoutItem.ShippingCost,
+		Etd:               checkoutItem.Etd,
 	}
 }
 
-func ConvertPrepareForCheckoutToResponse(checkoutItemList []entity.PrepareForCheckoutItem) PreapareForCheckoutResponse {
-	var response PreapareForCheckoutResponse
+func ConvertToFulfilmentPlanResponse(plan entity.FulfilmentPlan) FulfilmentPlanResponse {
+	response := FulfilmentPlanResponse{
+		Subtotal:       plan.Subtotal,
+		ShippingCost:   plan.ShippingCost,
+		TotalCost:      plan.TotalCost,
+		ShipmentCount:  len(plan.Items),
+		EstimatedHours: plan.EstimatedHours,
+	}
 
-	for _, checkoutItem := range checkoutItemList {
+	for _, checkoutItem := range plan.Items {
 		response.PharmacyDrugs = append(response.PharmacyDrugs, ConvertPreapareForCheckoutItemToResponse(checkoutItem))
 	}
 
 	return response
 }
+
+// The first plan is the recommended one and is also flattened into the top
+// level, so clients that only read pharmacy_drugs keep working.
+func ConvertFulfilmentPlansToPrepareForCheckoutResponse(strategy string, plans []entity.FulfilmentPlan) PreapareForCheckoutResponse {
+	response := PreapareForCheckoutResponse{
+		Strategy:     strategy,
+		Alternatives: []FulfilmentPlanResponse{},
+	}
+
+	for i, plan := range plans {
+		planResponse := ConvertToFulfilmentPlanResponse(plan)
+		if i == 0 {
+			response.Subtotal = planResponse.Subtotal
+			response.ShippingCost = planResponse.ShippingCost
+			response.TotalCost = planResponse.TotalCost
+			response.ShipmentCount = planResponse.ShipmentCount
+			response.EstimatedHours = planResponse.EstimatedHours
+			response.PharmacyDrugs = planResponse.PharmacyDrugs
+		}
+
+		response.Alternatives = append(response.Alternatives, planResponse)
+	}
+
+	return response
+}
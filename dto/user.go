@@ -79,7 +79,8 @@ type UpdateUserAddressRequest struct {
 }
 
 type CreateOneCartRequest struct {
-	PharmacyDrugId int64 `json:"pharmacy_drug_id" binding:"required"`
+	PharmacyDrugId int64  `json:"pharmacy_drug_id" binding:"required"`
+	PrescriptionId *int64 `json:"prescription_id" binding:"omitempty,gte=1"`
 }
 
 type UpdateQtyCartRequest struct {
@@ -99,13 +100,14 @@ type CartDTO struct {
 }
 
 type PharmacyDrugsDto struct {
-	Id           int64           `json:"pharmacy_drug_id"`
-	PharmacyId   int64           `json:"pharmacy_id"`
-	Name         string          `json:"drug_name"`
-	Price        decimal.Decimal `json:"price"`
-	Image        string          `json:"image"`
-	PharmacyName string          `json:"pharmacy_name"`
-	Stock        int             `json:"stock"`
+	Id             int64           `json:"pharmacy_drug_id"`
+	PharmacyId     int64           `json:"pharmacy_id"`
+	Name           string          `json:"drug_name"`
+	Price          decimal.Decimal `json:"price"`
+	Image          string          `json:"image"`
+	PharmacyName   string          `json:"pharmacy_name"`
+	Stock          int             `json:"stock"`
+	IsPharmacyOpen bool            `json:"is_pharmacy_open"`
 }
 
 func ConvertUpdateRequestToUserAddress(request UpdateUserAddressRequest) entity.UserAddress {
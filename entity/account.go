@@ -16,6 +16,18 @@ type Account struct {
 	Name           string
 	ProfilePicture string
 	VerifiedAt     *time.Time
+	TokenVersion   int64
+	SuspendedAt    *time.Time
+	CreatedAt      time.Time
+}
+
+type AccountFilter struct {
+	Search      string
+	RoleName    string
+	IsVerified  *bool
+	IsSuspended *bool
+	Limit       int
+	Offset      int
 }
 
 type VerificationCode struct {
@@ -33,8 +45,20 @@ type ResetPasswordToken struct {
 }
 
 type RefreshToken struct {
-	Id        int64
-	AccountId int64
-	Token     string
-	ExpiredAt time.Time
+	Id               int64
+	AccountId        int64
+	AccountSessionId int64
+	Token            string
+	ExpiredAt        time.Time
+	IsUsed           bool
+	IsExpired        bool
+}
+
+type AccountSession struct {
+	Id         int64
+	AccountId  int64
+	UserAgent  string
+	IpAddress  string
+	LastSeenAt time.Time
+	CreatedAt  time.Time
 }
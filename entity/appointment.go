@@ -0,0 +1,33 @@
+package entity
+
+import "time"
+
+type AppointmentSlot struct {
+	StartAt  time.Time
+	EndAt    time.Time
+	IsBooked bool
+}
+
+type Appointment struct {
+	Id              int64
+	ChatRoomId      int64
+	UserAccountId   int64
+	UserName        string
+	UserEmail       string
+	DoctorAccountId int64
+	DoctorName      string
+	DoctorEmail     string
+	ScheduledAt     time.Time
+	EndAt           time.Time
+	Status          string
+	ReminderSentAt  *time.Time
+	CancelledAt     *time.Time
+	CreatedAt       time.Time
+}
+
+type AppointmentFilter struct {
+	AccountId int64
+	Status    string
+	Limit     int
+	Offset    int
+}
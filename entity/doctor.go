@@ -1,33 +1,91 @@
 package entity
 
 import (
+	"time"
+
 	"github.com/shopspring/decimal"
 )
 
 type Doctor struct {
-	Id                 int64
-	AccountId          int64
-	Certificate        string
-	FeePerPatient      decimal.Decimal
-	IsOnline           bool
-	Experience         int
-	SpecializationId   int64
-	SpecializationName string
+	Id                   int64
+	AccountId            int64
+	Certificate          string
+	FeePerPatient        decimal.Decimal
+	IsOnline             bool
+	Experience           int
+	SpecializationId     int64
+	SpecializationName   string
+	ConsultationDuration *int
+	Rating               decimal.Decimal
+	ReviewCount          int
 }
 
 type DetailedDoctor struct {
-	Id                 int64
-	Email              string
-	Name               string
-	ProfilePicture     string
-	Password           string
-	FeePerPatient      decimal.Decimal
-	Experience         int
-	SpecializationId   int64
-	SpecializationName string
+	Id                   int64
+	Email                string
+	Name                 string
+	ProfilePicture       string
+	Password             string
+	FeePerPatient        decimal.Decimal
+	Experience           int
+	SpecializationId     int64
+	SpecializationName   string
+	ConsultationDuration *int
+	Rating               decimal.Decimal
+	ReviewCount          int
 }
 
 type DoctorSpecialization struct {
-	Id   int64
-	Name string
+	Id                   int64
+	Name                 string
+	ConsultationDuration *int
+}
+
+type DoctorReview struct {
+	Id              int64
+	ChatRoomId      int64
+	UserAccountId   int64
+	UserName        string
+	DoctorAccountId int64
+	DoctorName      string
+	Rating          int
+	Review          string
+	Reply           *string
+	RepliedAt       *time.Time
+	HiddenAt        *time.Time
+	HiddenReason    string
+	CreatedAt       time.Time
+}
+
+type DoctorReviewFilter struct {
+	DoctorId *int64
+	IsHidden *bool
+	Limit    int
+	Offset   int
+}
+
+type DoctorScheduleSlot struct {
+	Id              int64
+	DoctorAccountId int64
+	Day             string
+	StartHour       string
+	EndHour         string
+}
+
+type DoctorScheduleException struct {
+	Id              int64
+	DoctorAccountId int64
+	Date            time.Time
+	IsAvailable     bool
+	StartHour       *string
+	EndHour         *string
+	Description     string
+}
+
+type DoctorSchedule struct {
+	DoctorAccountId         int64
+	Timezone                string
+	UseScheduleAvailability bool
+	Slots                   []DoctorScheduleSlot
+	Exceptions              []DoctorScheduleException
 }
@@ -68,17 +68,44 @@ type DrugQuantity struct {
 }
 
 type PrepareForCheckoutItem struct {
-	PharmacyId      int64
-	PharmacyName    string
-	PharmacyAddress string
-	Distance        float64
-	Subtotal        decimal.Decimal
-	DeliveryOptions []AvailableCourier
-	Weight          decimal.Decimal
-	DrugQuantities  []DrugQuantity
+	PharmacyId        int64
+	PharmacyName      string
+	PharmacyAddress   string
+	Distance          float64
+	Subtotal          decimal.Decimal
+	DeliveryOptions   []AvailableCourier
+	Weight            decimal.Decimal
+	DrugQuantities    []DrugQuantity
+	PharmacyCourierId int64
+	ShippingCost      decimal.Decimal
+	Etd               string
 }
 
 type PrepareForCheckout struct {
 	Items       []PrepareForCheckoutItem
 	UserAddress UserAddress
 }
+
+type FulfilmentCandidate struct {
+	Pharmacy     Pharmacy
+	DrugQuantity DrugQuantity
+}
+
+type FulfilmentPlan struct {
+	Items          []PrepareForCheckoutItem
+	Subtotal       decimal.Decimal
+	ShippingCost   decimal.Decimal
+	TotalCost      decimal.Decimal
+	EstimatedHours *int
+}
+
+type DrugSuggestion struct {
+	Id          int64
+	Name        string
+	GenericName string
+}
+
+type DrugSearchCorrection struct {
+	Word string
+	Term string
+}
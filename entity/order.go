@@ -64,6 +64,26 @@ type Order struct {
 	UpdatedAt       time.Time
 }
 
+type ExpiredOrder struct {
+	Id          int64
+	TotalAmount decimal.Decimal
+	CreatedAt   time.Time
+	UserEmail   string
+	UserName    string
+}
+
+type PharmacyCheckoutPricing struct {
+	PharmacyId        int64
+	PharmacyCourierId int64
+	Subtotal          decimal.Decimal
+	DeliveryFee       decimal.Decimal
+}
+
+type CheckoutPricing struct {
+	Pharmacies  []PharmacyCheckoutPricing
+	TotalAmount decimal.Decimal
+}
+
 type OrderStatus struct {
 	Id   int64
 	Name string
@@ -1,6 +1,8 @@
 package entity
 
 import (
+	"time"
+
 	"github.com/shopspring/decimal"
 )
 
@@ -38,6 +40,30 @@ type PharmacyOperational struct {
 	IsOpen         bool
 }
 
+type PharmacyHoliday struct {
+	Id          int64
+	PharmacyId  int64
+	Date        time.Time
+	IsOpen      bool
+	OpenHour    *string
+	CloseHour   *string
+	Description string
+}
+
+type PharmacySchedule struct {
+	PharmacyId        int64
+	PharmacyName      string
+	PharmacyManagerId int64
+	Timezone          string
+	Operationals      []PharmacyOperational
+	Holidays          []PharmacyHoliday
+}
+
+type PharmacyOpeningStatus struct {
+	IsOpen  bool
+	OpensAt *time.Time
+}
+
 type Pharmacy struct {
 	Id                      int64
 	PharmacyManagerId       int64
@@ -50,6 +76,7 @@ type Pharmacy struct {
 	Latitude                string
 	Longitude               string
 	Distance                float64
+	Timezone                string
 }
 
 type PharmacyJoinPharmacyDrug struct {
@@ -81,13 +108,15 @@ type Courier struct {
 }
 
 type PharmacyDrugDetail struct {
-	Id              int64
-	PharmacyId      int64
-	PharmacyName    string
-	PharmacyAddress string
-	DrugId          int64
-	Price           decimal.Decimal
-	Stock           int
+	Id                     int64
+	PharmacyId             int64
+	PharmacyName           string
+	PharmacyAddress        string
+	DrugId                 int64
+	Price                  decimal.Decimal
+	Stock                  int
+	DrugName               string
+	IsPrescriptionRequired bool
 }
 
 type CourierOption struct {
@@ -95,10 +124,18 @@ type CourierOption struct {
 	Etd   string  `json:"estimated_time_of_delivery"`
 }
 
+type ShippingRateRequest struct {
+	Origin      int64
+	Destination int64
+	Weight      int64
+	Courier     string
+}
+
 type AvailableCourier struct {
-	PharmacyCourierId int64           `json:"pharmacy_courier_id"`
-	CourierName       string          `json:"courier_name"`
-	CourierOptions    []CourierOption `json:"options"`
+	PharmacyCourierId   int64                `json:"pharmacy_courier_id"`
+	CourierName         string               `json:"courier_name"`
+	CourierOptions      []CourierOption      `json:"options"`
+	ShippingRateRequest *ShippingRateRequest `json:"-"`
 }
 
 type PharmacyDeliveryFee struct {
@@ -126,3 +163,17 @@ type DetailPharmacyDrug struct {
 	Drug  Drug
 	Price decimal.Decimal
 }
+
+type PharmacyDrugImportRow struct {
+	RowNumber      int
+	DrugId         *int64
+	DrugName       string
+	PharmacyDrugId *int64
+	Action         string
+	PreviousStock  *int
+	Stock          int
+	Price          decimal.Decimal
+	BatchNumber    string
+	ExpiryDate     string
+	Errors         []string
+}
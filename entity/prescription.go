@@ -3,20 +3,40 @@ package entity
 import "time"
 
 type PrescriptionDrug struct {
-	Id       int64
-	Drug     Drug
-	Quantity int
-	Note     string
+	Id                int64
+	PrescriptionId    int64
+	Drug              Drug
+	Quantity          int
+	RemainingQuantity int
+	Note              string
 }
 
 type Prescription struct {
-	Id                *int64
-	UserAccountId     int64
-	UserName          string
-	DoctorAccountId   int64
-	DoctorName        string
-	PrescriptionDrugs []PrescriptionDrug
-	RedeemedAt        *time.Time
-	OrderedAt         *time.Time
-	CreatedAt         *time.Time
+	Id                   *int64
+	UserAccountId        int64
+	UserName             string
+	DoctorAccountId      int64
+	DoctorName           string
+	DoctorSpecialization string
+	PrescriptionDrugs    []PrescriptionDrug
+	RedeemedAt           *time.Time
+	OrderedAt            *time.Time
+	CreatedAt            *time.Time
+	RefillCount          int
+	RefillsUsed          int
+	ValidForDays         int
+	ValidUntil           *time.Time
+	IsExpired            bool
+}
+
+type PrescriptionRedemption struct {
+	OrderId            int64
+	OrderPharmacyId    int64
+	OrderStatusId      int64
+	OrderItemId        int64
+	PrescriptionDrugId int64
+	DrugId             int64
+	DrugName           string
+	Quantity           int
+	CreatedAt          time.Time
 }
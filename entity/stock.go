@@ -8,22 +8,149 @@ type StockChange struct {
 	FinalStock     int
 	Amount         int
 	Description    string
+	ChangeType     string
+	ActorAccountId *int64
 	CreatedAt      time.Time
 }
 
+type StockChangeDetail struct {
+	StockChange
+	PharmacyId      int64
+	PharmacyName    string
+	PharmacyAddress string
+	DrugId          int64
+	DrugName        string
+	DrugImage       string
+	ActorName       *string
+}
+
+type StockChangeFilter struct {
+	ManagerId  int64
+	PharmacyId *int64
+	DrugId     *int64
+	ChangeType *string
+	AmountSign *string
+	StartDate  *time.Time
+	EndDate    *time.Time
+	Limit      int
+	Offset     int
+}
+
 type StockMutationRequest struct {
-	Id                  int64
-	PharmacyRequesterId int64
-	PharmacyTargetId    int64
-	DrugId              int64
-	Stock               int
-	StatusId            int64
-	CreatedAt           time.Time
-}
-
-type StockRequestStatus struct {
-	Id   int64
-	Name string
+	Id                      int64
+	PharmacyRequesterId     int64
+	PharmacyRequesterName   string
+	RequesterManagerId      int64
+	RequesterPharmacyDrugId *int64
+	PharmacyTargetId        int64
+	PharmacyTargetName      string
+	TargetManagerId         int64
+	TargetPharmacyDrugId    *int64
+	DrugId                  int64
+	DrugName                string
+	Stock                   int
+	OrderId                 *int64
+	Status                  string
+	RespondedAt             *time.Time
+	ShippedAt               *time.Time
+	ReceivedAt              *time.Time
+	CreatedAt               time.Time
+}
+
+type StockMutationRequestFilter struct {
+	ManagerId int64
+	Direction string
+	Status    *string
+	Limit     int
+	Offset    int
+}
+
+type PharmacyDrugBatch struct {
+	Id             int64
+	PharmacyDrugId int64
+	BatchNumber    string
+	ExpiryDate     *time.Time
+	Stock          int
+	WrittenOffAt   *time.Time
+	CreatedAt      time.Time
+}
+
+type PharmacyDrugBatchAllocation struct {
+	Id                     int64
+	PharmacyDrugId         int64
+	PharmacyDrugBatchId    *int64
+	BatchNumber            string
+	ExpiryDate             *time.Time
+	OrderPharmacyId        *int64
+	StockMutationRequestId *int64
+	Quantity               int
+}
+
+type ExpiringBatch struct {
+	PharmacyDrugBatch
+	PharmacyId   int64
+	PharmacyName string
+	DrugId       int64
+	DrugName     string
+}
+
+type ExpiringBatchFilter struct {
+	ManagerId  int64
+	PharmacyId *int64
+	Days       int
+	Limit      int
+	Offset     int
+}
+
+type ReorderThresholdStockChange struct {
+	StockChange
+	ReorderThreshold *int
+}
+
+type LowStockAlert struct {
+	Id                int64
+	PharmacyManagerId int64
+	ManagerName       string
+	ManagerEmail      string
+	PharmacyDrugId    int64
+	PharmacyId        int64
+	PharmacyName      string
+	DrugId            int64
+	DrugName          string
+	StockChangeId     int64
+	Stock             int
+	ReorderThreshold  int
+	ReadAt            *time.Time
+	CreatedAt         time.Time
+}
+
+type LowStockAlertFilter struct {
+	ManagerId int64
+	IsUnread  bool
+	Limit     int
+	Offset    int
+}
+
+type ReorderSuggestion struct {
+	PharmacyDrugId    int64
+	PharmacyId        int64
+	PharmacyName      string
+	DrugId            int64
+	DrugName          string
+	Stock             int
+	ReorderThreshold  *int
+	SoldQuantity      int
+	DailySales        float64
+	SuggestedQuantity int
+}
+
+type ReorderSuggestionFilter struct {
+	ManagerId       int64
+	PharmacyId      *int64
+	SalesWindowDays int
+	CoverDays       int
+	Limit           int
+	Offset          int
 }
 
 type PossibleStockMutation struct {
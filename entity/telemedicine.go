@@ -2,6 +2,8 @@ package entity
 
 import (
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
 type Chat struct {
@@ -26,6 +28,11 @@ type ChatRoom struct {
 	DoctorCertificateUrl string
 	ExpiredAt            *time.Time
 	ExpiredAtString      *string
+	AllowFallback        bool
+	QueuedAt             *time.Time
+	AssignedAt           *time.Time
+	StartedAt            *time.Time
+	ScheduledAt          *time.Time
 	Chats                []Chat
 }
 
@@ -35,9 +42,75 @@ type ChatRoomPreview struct {
 	ParticipantPictureUrl string
 	ExpiredAt             *time.Time
 	LastChat              Chat
+	QueuePosition         int
+}
+
+type ChatEvent struct {
+	Type            string
+	RoomId          int64
+	SenderAccountId int64
+	Chat            *Chat
+	ChatId          int64
+	IsTyping        bool
+	LastReadChatId  int64
+	ExpiredAt       *time.Time
+	Extension       *ChatRoomExtension
 }
 
-type Participant struct {
+type ChatSubscription struct {
 	AccountId int64
 	RoomId    int64
+	ExpiredAt *time.Time
+	Events    chan ChatEvent
+}
+
+type ConsultationPayment struct {
+	Id                  int64
+	ChatRoomId          int64
+	UserAccountId       int64
+	UserName            string
+	DoctorAccountId     int64
+	DoctorName          string
+	ChatRoomExtensionId *int64
+	Amount              decimal.Decimal
+	Status              string
+	PaymentProof        string
+	ConfirmedAt         *time.Time
+	CancelledAt         *time.Time
+	RefundedAt          *time.Time
+	CreatedAt           time.Time
+}
+
+type ConsultationPaymentFilter struct {
+	Status string
+	Limit  int
+	Offset int
+}
+
+type ConsultationQueueStatus struct {
+	RoomId          int64
+	DoctorAccountId int64
+	Status          string
+	Position        int
+	QueueLength     int
+	EstimatedWait   time.Duration
+	AssignedAt      *time.Time
+	ExpiresAt       *time.Time
+}
+
+type DoctorConsultationHistory struct {
+	ConsultationCount int
+	AveragePickup     time.Duration
+	AverageDuration   time.Duration
+}
+
+type ChatRoomExtension struct {
+	Id                   int64
+	ChatRoomId           int64
+	RequestedByAccountId int64
+	Minutes              int
+	Fee                  decimal.Decimal
+	Status               string
+	RespondedAt          *time.Time
+	CreatedAt            time.Time
 }
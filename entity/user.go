@@ -50,13 +50,15 @@ type CartItem struct {
 }
 
 type CartItemForCheckout struct {
-	Id             int64
-	DrugId         int64
-	DrugName       string
-	PharmacyDrugId int64
-	Price          int
-	Unit           string
-	Quantity       int
+	Id                     int64
+	DrugId                 int64
+	DrugName               string
+	PharmacyDrugId         int64
+	Price                  int
+	Unit                   string
+	Quantity               int
+	IsPrescriptionRequired bool
+	PrescriptionDrugId     *int64
 }
 
 type CartItemChanges struct {
@@ -77,4 +79,5 @@ type CartItemData struct {
 	DrugName       string
 	Image          string
 	PharmacyName   string
+	IsPharmacyOpen bool
 }
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sidiqPratomo/max-health-backend/appconstant"
+	"github.com/sidiqPratomo/max-health-backend/apperror"
+	"github.com/sidiqPratomo/max-health-backend/usecase"
+	"github.com/sidiqPratomo/max-health-backend/util"
+)
+
+type AccountSessionHandler struct {
+	accountSessionUsecase usecase.AccountSessionUsecase
+}
+
+func NewAccountSessionHandler(accountSessionUsecase usecase.AccountSessionUsecase) AccountSessionHandler {
+	return AccountSessionHandler{
+		accountSessionUsecase: accountSessionUsecase,
+	}
+}
+
+func (h *AccountSessionHandler) GetAllSessions(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	accountId, exists := ctx.Get(appconstant.AccountId)
+	if !exists {
+		ctx.Error(apperror.UnauthorizedError())
+		return
+	}
+
+	currentSessionId := ctx.GetInt64(appconstant.SessionId)
+
+	accountSessions, err := h.accountSessionUsecase.GetAllSessions(ctx.Request.Context(), accountId.(int64), currentSessionId)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, accountSessions)
+}
+
+func (h *AccountSessionHandler) RevokeOneSession(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	accountId, exists := ctx.Get(appconstant.AccountId)
+	if !exists {
+		ctx.Error(apperror.UnauthorizedError())
+		return
+	}
+
+	accountSessionIdStr := ctx.Param(appconstant.SessionIdString)
+	accountSessionId, err := strconv.Atoi(accountSessionIdStr)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	if err = h.accountSessionUsecase.RevokeOneSession(ctx.Request.Context(), accountId.(int64), int64(accountSessionId)); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, nil)
+}
+
+func (h *AccountSessionHandler) RevokeAllSessions(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	accountId, exists := ctx.Get(appconstant.AccountId)
+	if !exists {
+		ctx.Error(apperror.UnauthorizedError())
+		return
+	}
+
+	if err := h.accountSessionUsecase.RevokeAllSessions(ctx.Request.Context(), accountId.(int64)); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, nil)
+}
@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sidiqPratomo/max-health-backend/appconstant"
+	"github.com/sidiqPratomo/max-health-backend/apperror"
+	"github.com/sidiqPratomo/max-health-backend/dto"
+	"github.com/sidiqPratomo/max-health-backend/usecase"
+	"github.com/sidiqPratomo/max-health-backend/util"
+)
+
+type AdminAccountHandler struct {
+	adminAccountUsecase usecase.AdminAccountUsecase
+}
+
+func NewAdminAccountHandler(adminAccountUsecase usecase.AdminAccountUsecase) AdminAccountHandler {
+	return AdminAccountHandler{
+		adminAccountUsecase: adminAccountUsecase,
+	}
+}
+
+func accountIdParam(ctx *gin.Context) (int64, error) {
+	accountId, err := strconv.Atoi(ctx.Param(appconstant.AccountIdString))
+	if err != nil {
+		return 0, apperror.BadRequestError(err)
+	}
+
+	return int64(accountId), nil
+}
+
+func (h *AdminAccountHandler) GetAllAccounts(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	var query dto.AdminAccountQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(apperror.BadRequestError(err))
+		return
+	}
+
+	accounts, err := h.adminAccountUsecase.GetAllAccounts(ctx.Request.Context(), query)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, accounts)
+}
+
+func (h *AdminAccountHandler) GetOneAccount(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	accountId, err := accountIdParam(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	account, err := h.adminAccountUsecase.GetOneAccount(ctx.Request.Context(), accountId)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, account)
+}
+
+func (h *AdminAccountHandler) GetAccountAddresses(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	accountId, err := accountIdParam(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	addresses, err := h.adminAccountUsecase.GetAccountAddresses(ctx.Request.Context(), accountId)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, addresses)
+}
+
+func (h *AdminAccountHandler) GetAccountOrders(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	accountId, err := accountIdParam(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	query := util.GetOrderQuery{}
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(apperror.BadRequestError(err))
+		return
+	}
+
+	if err := validator.New().Struct(query); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	validatedQuery, err := util.ValidateGetOrderQuery(query)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	orders, err := h.adminAccountUsecase.GetAccountOrders(ctx.Request.Context(), accountId, validatedQuery)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, orders)
+}
+
+func (h *AdminAccountHandler) GetAccountChatRooms(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	accountId, err := accountIdParam(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	chatRooms, err := h.adminAccountUsecase.GetAccountChatRooms(ctx.Request.Context(), accountId)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, chatRooms)
+}
+
+func (h *AdminAccountHandler) SuspendAccount(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	accountId, err := accountIdParam(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	if err := h.adminAccountUsecase.SuspendAccount(ctx.Request.Context(), accountId); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, nil)
+}
+
+func (h *AdminAccountHandler) UnsuspendAccount(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	accountId, err := accountIdParam(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	if err := h.adminAccountUsecase.UnsuspendAccount(ctx.Request.Context(), accountId); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, nil)
+}
+
+func (h *AdminAccountHandler) SendVerificationEmail(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	accountId, err := accountIdParam(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	if err := h.adminAccountUsecase.SendVerificationEmail(ctx.Request.Context(), accountId); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, nil)
+}
+
+func (h *AdminAccountHandler) SendResetPasswordEmail(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	accountId, err := accountIdParam(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	if err := h.adminAccountUsecase.SendResetPasswordEmail(ctx.Request.Context(), accountId); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, nil)
+}
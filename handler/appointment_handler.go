@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sidiqPratomo/max-health-backend/appconstant"
+	"github.com/sidiqPratomo/max-health-backend/apperror"
+	"github.com/sidiqPratomo/max-health-backend/dto"
+	"github.com/sidiqPratomo/max-health-backend/usecase"
+	"github.com/sidiqPratomo/max-health-backend/util"
+)
+
+type AppointmentHandler struct {
+	appointmentUsecase usecase.AppointmentUsecase
+}
+
+func NewAppointmentHandler(appointmentUsecase usecase.AppointmentUsecase) AppointmentHandler {
+	return AppointmentHandler{
+		appointmentUsecase: appointmentUsecase,
+	}
+}
+
+func (h *AppointmentHandler) GetDoctorSchedule(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	accountId, exists := ctx.Get(appconstant.AccountId)
+	if !exists {
+		ctx.Error(apperror.UnauthorizedError())
+		return
+	}
+
+	schedule, err := h.appointmentUsecase.GetDoctorSchedule(ctx.Request.Context(), accountId.(int64))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, schedule)
+}
+
+func (h *AppointmentHandler) UpdateDoctorSchedule(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	accountId, exists := ctx.Get(appconstant.AccountId)
+	if !exists {
+		ctx.Error(apperror.UnauthorizedError())
+		return
+	}
+
+	var req dto.DoctorScheduleRequest
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	err = h.appointmentUsecase.UpdateDoctorSchedule(ctx.Request.Context(), accountId.(int64), req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, nil)
+}
+
+func (h *AppointmentHandler) AddScheduleException(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	accountId, exists := ctx.Get(appconstant.AccountId)
+	if !exists {
+		ctx.Error(apperror.UnauthorizedError())
+		return
+	}
+
+	var req dto.DoctorScheduleExceptionRequest
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	err = h.appointmentUsecase.AddScheduleException(ctx.Request.Context(), accountId.(int64), req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseCreated(ctx, nil)
+}
+
+func (h *AppointmentHandler) DeleteScheduleException(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	accountId, exists := ctx.Get(appconstant.AccountId)
+	if !exists {
+		ctx.Error(apperror.UnauthorizedError())
+		return
+	}
+
+	exceptionId, err := strconv.Atoi(ctx.Param(appconstant.ExceptionIdString))
+	if err != nil {
+		ctx.Error(apperror.BadRequestError(err))
+		return
+	}
+
+	err = h.appointmentUsecase.DeleteScheduleException(ctx.Request.Context(), accountId.(int64), int64(exceptionId))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, nil)
+}
+
+func (h *AppointmentHandler) GetAppointmentSlots(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	doctorId, err := strconv.Atoi(ctx.Param(appconstant.DoctorIdString))
+	if err != nil {
+		ctx.Error(apperror.BadRequestError(err))
+		return
+	}
+
+	var query dto.AppointmentSlotQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(apperror.BadRequestError(err))
+		return
+	}
+
+	slots, err := h.appointmentUsecase.GetAppointmentSlots(ctx.Request.Context(), int64(doctorId), query)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, slots)
+}
+
+func (h *AppointmentHandler) BookAppointment(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	accountId, exists := ctx.Get(appconstant.AccountId)
+	if !exists {
+		ctx.Error(apperror.UnauthorizedError())
+		return
+	}
+
+	var req dto.AppointmentRequest
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	appointment, err := h.appointmentUsecase.BookAppointment(ctx.Request.Context(), accountId.(int64), req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseCreated(ctx, appointment)
+}
+
+func (h *AppointmentHandler) GetAppointments(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	accountId, exists := ctx.Get(appconstant.AccountId)
+	if !exists {
+		ctx.Error(apperror.UnauthorizedError())
+		return
+	}
+
+	var query dto.AppointmentQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(apperror.BadRequestError(err))
+		return
+	}
+
+	appointments, err := h.appointmentUsecase.GetAppointments(ctx.Request.Context(), accountId.(int64), query)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, appointments)
+}
+
+func (h *AppointmentHandler) CancelAppointment(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	accountId, exists := ctx.Get(appconstant.AccountId)
+	if !exists {
+		ctx.Error(apperror.UnauthorizedError())
+		return
+	}
+
+	appointmentId, err := strconv.Atoi(ctx.Param(appconstant.AppointmentIdString))
+	if err != nil {
+		ctx.Error(apperror.BadRequestError(err))
+		return
+	}
+
+	err = h.appointmentUsecase.CancelAppointment(ctx.Request.Context(), accountId.(int64), int64(appointmentId))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, nil)
+}
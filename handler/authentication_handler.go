@@ -7,6 +7,7 @@ import (
 
 	"github.com/sidiqPratomo/max-health-backend/apperror"
 	"github.com/sidiqPratomo/max-health-backend/dto"
+	"github.com/sidiqPratomo/max-health-backend/entity"
 	"github.com/sidiqPratomo/max-health-backend/usecase"
 	"github.com/sidiqPratomo/max-health-backend/util"
 	"github.com/gin-gonic/gin"
@@ -33,7 +34,9 @@ func (h *AuthenticationHandler) Login(ctx *gin.Context) {
 	}
 	account := dto.LoginRequestToAccount(loginRequest)
 
-	tokens, err := h.authenticationUsecase.Login(ctx.Request.Context(), account)
+	accountSession := entity.AccountSession{UserAgent: ctx.Request.UserAgent(), IpAddress: ctx.ClientIP()}
+
+	tokens, err := h.authenticationUsecase.Login(ctx.Request.Context(), account, accountSession)
 	if err != nil {
 		ctx.Error(err)
 		return
@@ -139,14 +142,16 @@ func (h *AuthenticationHandler) GetNewAccessToken(ctx *gin.Context) {
 		return
 	}
 
-	accessToken, err := h.authenticationUsecase.GetNewAccessToken(ctx.Request.Context(), accessTokenRequest.RefreshToken)
+	accountSession := entity.AccountSession{UserAgent: ctx.Request.UserAgent(), IpAddress: ctx.ClientIP()}
+
+	tokens, err := h.authenticationUsecase.GetNewAccessToken(ctx.Request.Context(), accessTokenRequest.RefreshToken, accountSession)
 	if err != nil {
 		ctx.Error(err)
 		return
 	}
 
-	accessTokenRes := dto.ConvertAccessTokenToResponse(accessToken)
-	util.ResponseOK(ctx, accessTokenRes)
+	resTokens := dto.ConvertTokensToResponse(*tokens)
+	util.ResponseOK(ctx, resTokens)
 }
 
 func (h *AuthenticationHandler) VerifyOneAccount(ctx *gin.Context) {
@@ -61,7 +61,7 @@ func (h *CartHandler) CreateOneCart(ctx *gin.Context) {
 		return
 	}
 
-	err := h.cartUsecase.CreateOneCart(c, cartReq.PharmacyDrugId)
+	err := h.cartUsecase.CreateOneCart(c, cartReq.PharmacyDrugId, cartReq.PrescriptionId)
 	if err != nil {
 		ctx.Error(err)
 		return
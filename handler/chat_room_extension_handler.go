@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sidiqPratomo/max-health-backend/appconstant"
+	"github.com/sidiqPratomo/max-health-backend/apperror"
+	"github.com/sidiqPratomo/max-health-backend/dto"
+	"github.com/sidiqPratomo/max-health-backend/usecase"
+	"github.com/sidiqPratomo/max-health-backend/util"
+)
+
+type ChatRoomExtensionHandler struct {
+	chatRoomExtensionUsecase usecase.ChatRoomExtensionUsecase
+}
+
+func NewChatRoomExtensionHandler(chatRoomExtensionUsecase usecase.ChatRoomExtensionUsecase) ChatRoomExtensionHandler {
+	return ChatRoomExtensionHandler{
+		chatRoomExtensionUsecase: chatRoomExtensionUsecase,
+	}
+}
+
+func extensionIdParam(ctx *gin.Context) (int64, error) {
+	extensionId, err := strconv.Atoi(ctx.Param(appconstant.ExtensionIdString))
+	if err != nil {
+		return 0, apperror.BadRequestError(err)
+	}
+
+	return int64(extensionId), nil
+}
+
+func (h *ChatRoomExtensionHandler) RequestExtension(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	accountId, exists := ctx.Get(appconstant.AccountId)
+	if !exists {
+		ctx.Error(apperror.UnauthorizedError())
+		return
+	}
+
+	roomId, err := roomIdParam(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var req dto.ChatRoomExtensionRequest
+	err = ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	extension, err := h.chatRoomExtensionUsecase.RequestExtension(ctx.Request.Context(), accountId.(int64), roomId, req.Minutes)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseCreated(ctx, extension)
+}
+
+func (h *ChatRoomExtensionHandler) RespondExtension(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	accountId, exists := ctx.Get(appconstant.AccountId)
+	if !exists {
+		ctx.Error(apperror.UnauthorizedError())
+		return
+	}
+
+	roomId, err := roomIdParam(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	extensionId, err := extensionIdParam(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var req dto.ChatRoomExtensionRespondRequest
+	err = ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	extension, err := h.chatRoomExtensionUsecase.RespondExtension(ctx.Request.Context(), accountId.(int64), roomId, extensionId, *req.IsAccepted)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, extension)
+}
+
+func (h *ChatRoomExtensionHandler) UploadPaymentProof(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	accountId, exists := ctx.Get(appconstant.AccountId)
+	if !exists {
+		ctx.Error(apperror.UnauthorizedError())
+		return
+	}
+
+	file, fileHeader, err := ctx.Request.FormFile("file")
+	if err != nil {
+		if file == nil {
+			ctx.Error(apperror.FileNotAttachedError())
+			return
+		}
+
+		ctx.Error(err)
+		return
+	}
+
+	roomId, err := roomIdParam(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	extensionId, err := extensionIdParam(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	if err = h.chatRoomExtensionUsecase.UploadPaymentProof(ctx.Request.Context(), accountId.(int64), roomId, extensionId, file, *fileHeader); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, nil)
+}
+
+func (h *ChatRoomExtensionHandler) ConfirmPayment(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	roomId, err := roomIdParam(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	extensionId, err := extensionIdParam(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var req dto.ConsultationPaymentConfirmRequest
+	err = ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	extension, err := h.chatRoomExtensionUsecase.ConfirmPayment(ctx.Request.Context(), roomId, extensionId, *req.IsApproved)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, extension)
+}
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sidiqPratomo/max-health-backend/appconstant"
+	"github.com/sidiqPratomo/max-health-backend/apperror"
+	"github.com/sidiqPratomo/max-health-backend/dto"
+	"github.com/sidiqPratomo/max-health-backend/usecase"
+	"github.com/sidiqPratomo/max-health-backend/util"
+)
+
+type ConsultationPaymentHandler struct {
+	consultationPaymentUsecase usecase.ConsultationPaymentUsecase
+}
+
+func NewConsultationPaymentHandler(consultationPaymentUsecase usecase.ConsultationPaymentUsecase) ConsultationPaymentHandler {
+	return ConsultationPaymentHandler{
+		consultationPaymentUsecase: consultationPaymentUsecase,
+	}
+}
+
+func roomIdParam(ctx *gin.Context) (int64, error) {
+	roomId, err := strconv.Atoi(ctx.Param(appconstant.RoomIdString))
+	if err != nil {
+		return 0, apperror.BadRequestError(err)
+	}
+
+	return int64(roomId), nil
+}
+
+func (h *ConsultationPaymentHandler) GetConsultationPayment(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	accountId, exists := ctx.Get(appconstant.AccountId)
+	if !exists {
+		ctx.Error(apperror.UnauthorizedError())
+		return
+	}
+
+	roomId, err := roomIdParam(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	consultationPayment, err := h.consultationPaymentUsecase.GetConsultationPayment(ctx.Request.Context(), accountId.(int64), roomId)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, consultationPayment)
+}
+
+func (h *ConsultationPaymentHandler) GetAllConsultationPayments(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	var query dto.ConsultationPaymentQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(apperror.BadRequestError(err))
+		return
+	}
+
+	consultationPayments, err := h.consultationPaymentUsecase.GetAllConsultationPayments(ctx.Request.Context(), query)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, consultationPayments)
+}
+
+func (h *ConsultationPaymentHandler) UploadPaymentProof(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	accountId, exists := ctx.Get(appconstant.AccountId)
+	if !exists {
+		ctx.Error(apperror.UnauthorizedError())
+		return
+	}
+
+	file, fileHeader, err := ctx.Request.FormFile("file")
+	if err != nil {
+		if file == nil {
+			ctx.Error(apperror.FileNotAttachedError())
+			return
+		}
+
+		ctx.Error(err)
+		return
+	}
+
+	roomId, err := roomIdParam(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	if err = h.consultationPaymentUsecase.UploadPaymentProof(ctx.Request.Context(), accountId.(int64), roomId, file, *fileHeader); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, nil)
+}
+
+func (h *ConsultationPaymentHandler) ConfirmPayment(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	roomId, err := roomIdParam(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var req dto.ConsultationPaymentConfirmRequest
+	err = ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	err = h.consultationPaymentUsecase.ConfirmPayment(ctx.Request.Context(), roomId, *req.IsApproved)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, nil)
+}
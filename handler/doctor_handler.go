@@ -108,6 +108,31 @@ func (h *DoctorHandler) GetAllDoctorSpecialization(ctx *gin.Context) {
 	util.ResponseOK(ctx, specializationList)
 }
 
+func (h *DoctorHandler) UpdateSpecializationConsultationDuration(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	specializationId, err := strconv.Atoi(ctx.Param(appconstant.SpecializationIdString))
+	if err != nil {
+		ctx.Error(apperror.BadRequestError(err))
+		return
+	}
+
+	var request dto.UpdateDoctorSpecializationRequest
+	err = ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	err = h.doctorUsecase.UpdateSpecializationConsultationDuration(ctx.Request.Context(), int64(specializationId), request.ConsultationDuration)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, nil)
+}
+
 func (h *DoctorHandler) GetProfile(ctx *gin.Context) {
 	accountId, exists := ctx.Get(appconstant.AccountId)
 	if !exists {
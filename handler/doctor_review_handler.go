@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sidiqPratomo/max-health-backend/appconstant"
+	"github.com/sidiqPratomo/max-health-backend/apperror"
+	"github.com/sidiqPratomo/max-health-backend/dto"
+	"github.com/sidiqPratomo/max-health-backend/usecase"
+	"github.com/sidiqPratomo/max-health-backend/util"
+)
+
+type DoctorReviewHandler struct {
+	doctorReviewUsecase usecase.DoctorReviewUsecase
+}
+
+func NewDoctorReviewHandler(doctorReviewUsecase usecase.DoctorReviewUsecase) DoctorReviewHandler {
+	return DoctorReviewHandler{
+		doctorReviewUsecase: doctorReviewUsecase,
+	}
+}
+
+func reviewIdParam(ctx *gin.Context) (int64, error) {
+	reviewId, err := strconv.Atoi(ctx.Param(appconstant.ReviewIdString))
+	if err != nil {
+		return 0, apperror.BadRequestError(err)
+	}
+
+	return int64(reviewId), nil
+}
+
+func (h *DoctorReviewHandler) CreateReview(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	accountId, exists := ctx.Get(appconstant.AccountId)
+	if !exists {
+		ctx.Error(apperror.UnauthorizedError())
+		return
+	}
+
+	roomId, err := roomIdParam(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var req dto.DoctorReviewRequest
+	err = ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	review, err := h.doctorReviewUsecase.CreateReview(ctx.Request.Context(), accountId.(int64), roomId, req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseCreated(ctx, review)
+}
+
+func (h *DoctorReviewHandler) ReplyReview(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	accountId, exists := ctx.Get(appconstant.AccountId)
+	if !exists {
+		ctx.Error(apperror.UnauthorizedError())
+		return
+	}
+
+	reviewId, err := reviewIdParam(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var req dto.DoctorReviewReplyRequest
+	err = ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	err = h.doctorReviewUsecase.ReplyReview(ctx.Request.Context(), accountId.(int64), reviewId, req.Reply)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, nil)
+}
+
+func (h *DoctorReviewHandler) GetDoctorReviews(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	doctorId, err := strconv.Atoi(ctx.Param(appconstant.DoctorIdString))
+	if err != nil {
+		ctx.Error(apperror.BadRequestError(err))
+		return
+	}
+
+	var query dto.DoctorReviewQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(apperror.BadRequestError(err))
+		return
+	}
+
+	reviews, err := h.doctorReviewUsecase.GetDoctorReviews(ctx.Request.Context(), int64(doctorId), query)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, reviews)
+}
+
+func (h *DoctorReviewHandler) GetAllReviews(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	var query dto.DoctorReviewQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(apperror.BadRequestError(err))
+		return
+	}
+
+	reviews, err := h.doctorReviewUsecase.GetAllReviews(ctx.Request.Context(), query)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, reviews)
+}
+
+func (h *DoctorReviewHandler) ModerateReview(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	reviewId, err := reviewIdParam(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var req dto.DoctorReviewVisibilityRequest
+	err = ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	err = h.doctorReviewUsecase.ModerateReview(ctx.Request.Context(), reviewId, *req.IsHidden, req.Reason)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, nil)
+}
@@ -67,12 +67,29 @@ func (h *DrugHandler) GetAllDrugsForListing(ctx *gin.Context) {
 		return
 	}
 
-	productList, pageInfo, err := h.drugUsecase.GetAllDrugsForListing(ctx, resQuery)
+	res, err := h.drugUsecase.GetAllDrugsForListing(ctx, resQuery)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, res)
+}
+
+func (h *DrugHandler) SuggestDrugs(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	var query dto.DrugSuggestQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(apperror.BadRequestError(err))
+		return
+	}
+
+	res, err := h.drugUsecase.SuggestDrugs(ctx.Request.Context(), query)
 	if err != nil {
 		ctx.Error(err)
 		return
 	}
-	res := dto.DrugListingResponse{Drugs: productList, PageInfo: *pageInfo}
 
 	util.ResponseOK(ctx, res)
 }
@@ -255,7 +272,7 @@ func (h *DrugHandler) GetDrugsByPharmacyId(ctx *gin.Context) {
 
 func (h *DrugHandler) UpdateDrugsByPharmacyDrugId(ctx *gin.Context) {
 	ctx.Header("Content-Type", "application/json")
-	_, exists := ctx.Get(appconstant.AccountId)
+	accountId, exists := ctx.Get(appconstant.AccountId)
 	if !exists {
 		ctx.Error(apperror.UnauthorizedError())
 		return
@@ -274,7 +291,7 @@ func (h *DrugHandler) UpdateDrugsByPharmacyDrugId(ctx *gin.Context) {
 		return
 	}
 
-	err = h.drugUsecase.UpdateDrugsByPharmacyDrugId(ctx, int64(paramPharmacyDrugIdInt), updateDrugReq.Stock, updateDrugReq.Price)
+	err = h.drugUsecase.UpdateDrugsByPharmacyDrugId(ctx, accountId.(int64), int64(paramPharmacyDrugIdInt), updateDrugReq.Stock, updateDrugReq.Price, updateDrugReq.Batch)
 	if err != nil {
 		ctx.Error(err)
 		return
@@ -297,7 +314,7 @@ func (h *DrugHandler) AddDrugsByPharmacyManager(ctx *gin.Context) {
 		return
 	}
 
-	if err := h.drugUsecase.AddDrugsByPharmacyDrugId(ctx, int64(addDrugReq.PharmacyId), int64(addDrugReq.DrugId), addDrugReq.Stock, addDrugReq.Price); err != nil {
+	if err := h.drugUsecase.AddDrugsByPharmacyDrugId(ctx, int64(addDrugReq.PharmacyId), int64(addDrugReq.DrugId), addDrugReq.Stock, addDrugReq.Price, addDrugReq.Batch); err != nil {
 		ctx.Error(err)
 		return
 	}
@@ -334,6 +351,12 @@ func (h *DrugHandler) GetPossibleStockMutation(ctx *gin.Context) {
 
 func (h *DrugHandler) PostStockMutation(ctx *gin.Context) {
 	ctx.Header("Content-Type", "application/json")
+	accountId, exists := ctx.Get(appconstant.AccountId)
+	if !exists {
+		ctx.Error(apperror.UnauthorizedError())
+		return
+	}
+
 	paramPharmacyDrugId := ctx.Param(appconstant.PharmacyDrugIdString)
 	pharmacyDrugId, _ := strconv.Atoi(paramPharmacyDrugId)
 	postStockMutationReq := dto.PostStockMutationRequest{}
@@ -343,7 +366,7 @@ func (h *DrugHandler) PostStockMutation(ctx *gin.Context) {
 	}
 
 	postStockMutationReq.RecipientPharmacyDrugId = int64(pharmacyDrugId)
-	err := h.drugUsecase.PostStockMutation(ctx, postStockMutationReq)
+	err := h.drugUsecase.PostStockMutation(ctx, accountId.(int64), postStockMutationReq)
 	if err != nil {
 		ctx.Error(err)
 		return
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sidiqPratomo/max-health-backend/appconstant"
+	"github.com/sidiqPratomo/max-health-backend/apperror"
+	"github.com/sidiqPratomo/max-health-backend/dto"
+	"github.com/sidiqPratomo/max-health-backend/usecase"
+	"github.com/sidiqPratomo/max-health-backend/util"
+)
+
+type LowStockAlertHandler struct {
+	lowStockAlertUsecase usecase.LowStockAlertUsecase
+}
+
+func NewLowStockAlertHandler(lowStockAlertUsecase usecase.LowStockAlertUsecase) LowStockAlertHandler {
+	return LowStockAlertHandler{
+		lowStockAlertUsecase: lowStockAlertUsecase,
+	}
+}
+
+func (h *LowStockAlertHandler) UpdateReorderThreshold(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	accountId, exists := ctx.Get(appconstant.AccountId)
+	if !exists {
+		ctx.Error(apperror.UnauthorizedError())
+		return
+	}
+
+	pharmacyDrugId, err := strconv.Atoi(ctx.Param(appconstant.PharmacyDrugIdString))
+	if err != nil {
+		ctx.Error(apperror.BadRequestError(err))
+		return
+	}
+
+	var updateRequest dto.UpdateReorderThresholdRequest
+	if err := ctx.ShouldBindJSON(&updateRequest); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	err = h.lowStockAlertUsecase.UpdateReorderThreshold(ctx.Request.Context(), accountId.(int64), int64(pharmacyDrugId), updateRequest)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, nil)
+}
+
+func (h *LowStockAlertHandler) GetLowStockAlerts(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	accountId, exists := ctx.Get(appconstant.AccountId)
+	if !exists {
+		ctx.Error(apperror.UnauthorizedError())
+		return
+	}
+
+	var query dto.LowStockAlertQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(apperror.BadRequestError(err))
+		return
+	}
+
+	lowStockAlerts, err := h.lowStockAlertUsecase.GetLowStockAlerts(ctx.Request.Context(), accountId.(int64), query)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, lowStockAlerts)
+}
+
+func (h *LowStockAlertHandler) MarkLowStockAlertRead(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	accountId, exists := ctx.Get(appconstant.AccountId)
+	if !exists {
+		ctx.Error(apperror.UnauthorizedError())
+		return
+	}
+
+	lowStockAlertId, err := strconv.Atoi(ctx.Param(appconstant.LowStockAlertIdString))
+	if err != nil {
+		ctx.Error(apperror.BadRequestError(err))
+		return
+	}
+
+	err = h.lowStockAlertUsecase.MarkLowStockAlertRead(ctx.Request.Context(), accountId.(int64), int64(lowStockAlertId))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, nil)
+}
+
+func (h *LowStockAlertHandler) GetReorderSuggestions(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	accountId, exists := ctx.Get(appconstant.AccountId)
+	if !exists {
+		ctx.Error(apperror.UnauthorizedError())
+		return
+	}
+
+	var query dto.ReorderSuggestionQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(apperror.BadRequestError(err))
+		return
+	}
+
+	reorderSuggestions, err := h.lowStockAlertUsecase.GetReorderSuggestions(ctx.Request.Context(), accountId.(int64), query)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, reorderSuggestions)
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sidiqPratomo/max-health-backend/appconstant"
+	"github.com/sidiqPratomo/max-health-backend/apperror"
+	"github.com/sidiqPratomo/max-health-backend/dto"
+	"github.com/sidiqPratomo/max-health-backend/usecase"
+	"github.com/sidiqPratomo/max-health-backend/util"
+)
+
+type PharmacyDrugBatchHandler struct {
+	pharmacyDrugBatchUsecase usecase.PharmacyDrugBatchUsecase
+}
+
+func NewPharmacyDrugBatchHandler(pharmacyDrugBatchUsecase usecase.PharmacyDrugBatchUsecase) PharmacyDrugBatchHandler {
+	return PharmacyDrugBatchHandler{
+		pharmacyDrugBatchUsecase: pharmacyDrugBatchUsecase,
+	}
+}
+
+func (h *PharmacyDrugBatchHandler) GetPharmacyDrugBatches(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	accountId, exists := ctx.Get(appconstant.AccountId)
+	if !exists {
+		ctx.Error(apperror.UnauthorizedError())
+		return
+	}
+
+	pharmacyDrugId, err := strconv.Atoi(ctx.Param(appconstant.PharmacyDrugIdString))
+	if err != nil {
+		ctx.Error(apperror.BadRequestError(err))
+		return
+	}
+
+	pharmacyDrugBatches, err := h.pharmacyDrugBatchUsecase.GetPharmacyDrugBatches(ctx.Request.Context(), accountId.(int64), int64(pharmacyDrugId))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, pharmacyDrugBatches)
+}
+
+func (h *PharmacyDrugBatchHandler) ReceivePharmacyDrugBatch(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	accountId, exists := ctx.Get(appconstant.AccountId)
+	if !exists {
+		ctx.Error(apperror.UnauthorizedError())
+		return
+	}
+
+	pharmacyDrugId, err := strconv.Atoi(ctx.Param(appconstant.PharmacyDrugIdString))
+	if err != nil {
+		ctx.Error(apperror.BadRequestError(err))
+		return
+	}
+
+	var receiveRequest dto.ReceivePharmacyDrugBatchRequest
+	if err := ctx.ShouldBindJSON(&receiveRequest); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	pharmacyDrugBatches, err := h.pharmacyDrugBatchUsecase.ReceivePharmacyDrugBatch(ctx.Request.Context(), accountId.(int64), int64(pharmacyDrugId), receiveRequest)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseCreated(ctx, pharmacyDrugBatches)
+}
+
+func (h *PharmacyDrugBatchHandler) GetExpiringBatches(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	accountId, exists := ctx.Get(appconstant.AccountId)
+	if !exists {
+		ctx.Error(apperror.UnauthorizedError())
+		return
+	}
+
+	var query dto.ExpiringBatchQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(apperror.BadRequestError(err))
+		return
+	}
+
+	expiringBatches, err := h.pharmacyDrugBatchUsecase.GetExpiringBatches(ctx.Request.Context(), accountId.(int64), query)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, expiringBatches)
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sidiqPratomo/max-health-backend/appconstant"
+	"github.com/sidiqPratomo/max-health-backend/apperror"
+	"github.com/sidiqPratomo/max-health-backend/dto"
+	"github.com/sidiqPratomo/max-health-backend/usecase"
+	"github.com/sidiqPratomo/max-health-backend/util"
+)
+
+type PharmacyDrugImportHandler struct {
+	pharmacyDrugImportUsecase usecase.PharmacyDrugImportUsecase
+}
+
+func NewPharmacyDrugImportHandler(pharmacyDrugImportUsecase usecase.PharmacyDrugImportUsecase) PharmacyDrugImportHandler {
+	return PharmacyDrugImportHandler{
+		pharmacyDrugImportUsecase: pharmacyDrugImportUsecase,
+	}
+}
+
+func (h *PharmacyDrugImportHandler) ImportPharmacyDrugs(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	accountId, exists := ctx.Get(appconstant.AccountId)
+	if !exists {
+		ctx.Error(apperror.UnauthorizedError())
+		return
+	}
+
+	pharmacyId, err := strconv.Atoi(ctx.Param(appconstant.PharmacyIdString))
+	if err != nil {
+		ctx.Error(apperror.BadRequestError(err))
+		return
+	}
+
+	var query dto.PharmacyDrugImportQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(apperror.BadRequestError(err))
+		return
+	}
+
+	file, fileHeader, err := ctx.Request.FormFile("file")
+	if err != nil {
+		if file == nil {
+			ctx.Error(apperror.FileNotAttachedError())
+			return
+		}
+
+		ctx.Error(err)
+		return
+	}
+	defer file.Close()
+
+	importResponse, err := h.pharmacyDrugImportUsecase.ImportPharmacyDrugs(ctx.Request.Context(), accountId.(int64), int64(pharmacyId), query, file, fileHeader)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, importResponse)
+}
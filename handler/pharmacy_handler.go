@@ -181,3 +181,121 @@ func (h *PharmacyHandler) DeleteOnePharmacy(ctx *gin.Context) {
 
 	util.ResponseOK(ctx, nil)
 }
+
+func (h *PharmacyHandler) GetPharmacySchedule(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	accountId, exists := ctx.Get(appconstant.AccountId)
+	if !exists {
+		ctx.Error(apperror.UnauthorizedError())
+		return
+	}
+
+	pharmacyIdStr := ctx.Param(appconstant.PharmacyIdString)
+	pharmacyId, err := strconv.Atoi(pharmacyIdStr)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	pharmacySchedule, err := h.pharmacyUsecase.GetPharmacySchedule(ctx, accountId.(int64), int64(pharmacyId))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, pharmacySchedule)
+}
+
+func (h *PharmacyHandler) UpdatePharmacySchedule(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	var pharmacyScheduleRequest dto.PharmacyScheduleRequest
+
+	if err := ctx.ShouldBindJSON(&pharmacyScheduleRequest); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	accountId, exists := ctx.Get(appconstant.AccountId)
+	if !exists {
+		ctx.Error(apperror.UnauthorizedError())
+		return
+	}
+
+	pharmacyIdStr := ctx.Param(appconstant.PharmacyIdString)
+	pharmacyId, err := strconv.Atoi(pharmacyIdStr)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	if err = h.pharmacyUsecase.UpdatePharmacySchedule(ctx, accountId.(int64), int64(pharmacyId), pharmacyScheduleRequest); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, nil)
+}
+
+func (h *PharmacyHandler) AddPharmacyHoliday(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	var pharmacyHolidayRequest dto.PharmacyHolidayRequest
+
+	if err := ctx.ShouldBindJSON(&pharmacyHolidayRequest); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	accountId, exists := ctx.Get(appconstant.AccountId)
+	if !exists {
+		ctx.Error(apperror.UnauthorizedError())
+		return
+	}
+
+	pharmacyIdStr := ctx.Param(appconstant.PharmacyIdString)
+	pharmacyId, err := strconv.Atoi(pharmacyIdStr)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	if err = h.pharmacyUsecase.AddPharmacyHoliday(ctx, accountId.(int64), int64(pharmacyId), pharmacyHolidayRequest); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseCreated(ctx, nil)
+}
+
+func (h *PharmacyHandler) DeletePharmacyHoliday(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	accountId, exists := ctx.Get(appconstant.AccountId)
+	if !exists {
+		ctx.Error(apperror.UnauthorizedError())
+		return
+	}
+
+	pharmacyIdStr := ctx.Param(appconstant.PharmacyIdString)
+	pharmacyId, err := strconv.Atoi(pharmacyIdStr)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	pharmacyHolidayIdStr := ctx.Param(appconstant.PharmacyHolidayIdString)
+	pharmacyHolidayId, err := strconv.Atoi(pharmacyHolidayIdStr)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	if err = h.pharmacyUsecase.DeletePharmacyHoliday(ctx, accountId.(int64), int64(pharmacyId), int64(pharmacyHolidayId)); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, nil)
+}
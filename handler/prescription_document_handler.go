@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sidiqPratomo/max-health-backend/appconstant"
+	"github.com/sidiqPratomo/max-health-backend/apperror"
+	"github.com/sidiqPratomo/max-health-backend/usecase"
+	"github.com/sidiqPratomo/max-health-backend/util"
+)
+
+type PrescriptionDocumentHandler struct {
+	prescriptionDocumentUsecase usecase.PrescriptionDocumentUsecase
+}
+
+func NewPrescriptionDocumentHandler(prescriptionDocumentUsecase usecase.PrescriptionDocumentUsecase) PrescriptionDocumentHandler {
+	return PrescriptionDocumentHandler{
+		prescriptionDocumentUsecase: prescriptionDocumentUsecase,
+	}
+}
+
+func prescriptionIdParam(ctx *gin.Context) (int64, error) {
+	prescriptionId, err := strconv.Atoi(ctx.Param(appconstant.PrescriptionIdString))
+	if err != nil || prescriptionId < 1 {
+		return 0, apperror.PrescriptionIdNotANumberError()
+	}
+
+	return int64(prescriptionId), nil
+}
+
+func (h *PrescriptionDocumentHandler) GetPrescriptionDocument(ctx *gin.Context) {
+	accountId, exists := ctx.Get(appconstant.AccountId)
+	if !exists {
+		ctx.Error(apperror.UnauthorizedError())
+		return
+	}
+
+	prescriptionId, err := prescriptionIdParam(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	document, err := h.prescriptionDocumentUsecase.GetPrescriptionDocument(ctx.Request.Context(), accountId.(int64), prescriptionId)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	fileName := fmt.Sprintf(appconstant.PrescriptionDocumentFileName, prescriptionId)
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	ctx.Data(http.StatusOK, appconstant.PrescriptionDocumentContentType, document)
+}
+
+func (h *PrescriptionDocumentHandler) VerifyPrescription(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	prescriptionId, err := prescriptionIdParam(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	verification, err := h.prescriptionDocumentUsecase.VerifyPrescription(ctx.Request.Context(), prescriptionId, ctx.Query(appconstant.SignatureString))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, verification)
+}
@@ -1,12 +1,15 @@
 package handler
 
 import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
 	"github.com/sidiqPratomo/max-health-backend/appconstant"
 	"github.com/sidiqPratomo/max-health-backend/apperror"
 	"github.com/sidiqPratomo/max-health-backend/dto"
 	"github.com/sidiqPratomo/max-health-backend/usecase"
 	"github.com/sidiqPratomo/max-health-backend/util"
-	"github.com/gin-gonic/gin"
 )
 
 type StockHandler struct {
@@ -20,6 +23,8 @@ func NewStockHandler(stockUsecase usecase.StockUsecase) StockHandler {
 }
 
 func (h *StockHandler) GetAllStockChanges(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
 	accountId, exists := ctx.Get(appconstant.AccountId)
 	if !exists {
 		ctx.Error(apperror.UnauthorizedError())
@@ -29,11 +34,11 @@ func (h *StockHandler) GetAllStockChanges(ctx *gin.Context) {
 	var stockChangeQuery dto.StockChangeQuery
 
 	if err := ctx.ShouldBindQuery(&stockChangeQuery); err != nil {
-		ctx.Error(err)
+		ctx.Error(apperror.BadRequestError(err))
 		return
 	}
 
-	stockChanges, err := h.StockUsecase.GetAllStockChanges(ctx.Request.Context(), accountId.(int64), stockChangeQuery.PharmacyId)
+	stockChanges, err := h.StockUsecase.GetAllStockChanges(ctx.Request.Context(), accountId.(int64), stockChangeQuery)
 	if err != nil {
 		ctx.Error(err)
 		return
@@ -41,3 +46,59 @@ func (h *StockHandler) GetAllStockChanges(ctx *gin.Context) {
 
 	util.ResponseOK(ctx, stockChanges)
 }
+
+// stockChangeExportWriter only sends the CSV headers once the first row is
+// written, so errors raised before that still get the usual JSON response.
+type stockChangeExportWriter struct {
+	ctx        *gin.Context
+	hasStarted bool
+}
+
+func (w *stockChangeExportWriter) Write(p []byte) (int, error) {
+	if !w.hasStarted {
+		w.hasStarted = true
+		w.ctx.Header("Content-Type", appconstant.StockChangeExportContentType)
+		w.ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", appconstant.StockChangeExportFileName))
+		w.ctx.Status(http.StatusOK)
+	}
+
+	n, err := w.ctx.Writer.Write(p)
+	if err != nil {
+		return n, err
+	}
+	w.ctx.Writer.Flush()
+
+	return n, nil
+}
+
+func (h *StockHandler) ExportStockChanges(ctx *gin.Context) {
+	accountId, exists := ctx.Get(appconstant.AccountId)
+	if !exists {
+		ctx.Header("Content-Type", "application/json")
+		ctx.Error(apperror.UnauthorizedError())
+		return
+	}
+
+	var stockChangeQuery dto.StockChangeQuery
+
+	if err := ctx.ShouldBindQuery(&stockChangeQuery); err != nil {
+		ctx.Header("Content-Type", "application/json")
+		ctx.Error(apperror.BadRequestError(err))
+		return
+	}
+
+	exportWriter := stockChangeExportWriter{ctx: ctx}
+
+	err := h.StockUsecase.ExportStockChanges(ctx.Request.Context(), accountId.(int64), stockChangeQuery, &exportWriter)
+	if err != nil {
+		// rows are already on the wire, so reset the connection instead of
+		// ending the file and letting a partial export look complete
+		if exportWriter.hasStarted {
+			panic(http.ErrAbortHandler)
+		}
+
+		ctx.Header("Content-Type", "application/json")
+		ctx.Error(err)
+		return
+	}
+}
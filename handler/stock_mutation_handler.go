@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sidiqPratomo/max-health-backend/appconstant"
+	"github.com/sidiqPratomo/max-health-backend/apperror"
+	"github.com/sidiqPratomo/max-health-backend/dto"
+	"github.com/sidiqPratomo/max-health-backend/usecase"
+	"github.com/sidiqPratomo/max-health-backend/util"
+)
+
+type StockMutationHandler struct {
+	stockMutationUsecase usecase.StockMutationUsecase
+}
+
+func NewStockMutationHandler(stockMutationUsecase usecase.StockMutationUsecase) StockMutationHandler {
+	return StockMutationHandler{
+		stockMutationUsecase: stockMutationUsecase,
+	}
+}
+
+func (h *StockMutationHandler) GetStockMutationRequests(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	accountId, exists := ctx.Get(appconstant.AccountId)
+	if !exists {
+		ctx.Error(apperror.UnauthorizedError())
+		return
+	}
+
+	var query dto.StockMutationRequestQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.Error(apperror.BadRequestError(err))
+		return
+	}
+
+	stockMutationRequests, err := h.stockMutationUsecase.GetStockMutationRequests(ctx.Request.Context(), accountId.(int64), query)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, stockMutationRequests)
+}
+
+func (h *StockMutationHandler) ApproveStockMutationRequest(ctx *gin.Context) {
+	h.updateStockMutationRequestStatus(ctx, appconstant.StockMutationApproved)
+}
+
+func (h *StockMutationHandler) RejectStockMutationRequest(ctx *gin.Context) {
+	h.updateStockMutationRequestStatus(ctx, appconstant.StockMutationRejected)
+}
+
+func (h *StockMutationHandler) ShipStockMutationRequest(ctx *gin.Context) {
+	h.updateStockMutationRequestStatus(ctx, appconstant.StockMutationShipped)
+}
+
+func (h *StockMutationHandler) ReceiveStockMutationRequest(ctx *gin.Context) {
+	h.updateStockMutationRequestStatus(ctx, appconstant.StockMutationReceived)
+}
+
+func (h *StockMutationHandler) updateStockMutationRequestStatus(ctx *gin.Context, status string) {
+	ctx.Header("Content-Type", "application/json")
+
+	accountId, exists := ctx.Get(appconstant.AccountId)
+	if !exists {
+		ctx.Error(apperror.UnauthorizedError())
+		return
+	}
+
+	stockMutationRequestId, err := strconv.Atoi(ctx.Param(appconstant.StockMutationIdString))
+	if err != nil {
+		ctx.Error(apperror.BadRequestError(err))
+		return
+	}
+
+	stockMutationRequest, err := h.stockMutationUsecase.UpdateStockMutationRequestStatus(ctx.Request.Context(), accountId.(int64), int64(stockMutationRequestId), status)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, stockMutationRequest)
+}
@@ -1,25 +1,46 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/sidiqPratomo/max-health-backend/appconstant"
 	"github.com/sidiqPratomo/max-health-backend/apperror"
 	"github.com/sidiqPratomo/max-health-backend/dto"
+	"github.com/sidiqPratomo/max-health-backend/entity"
 	"github.com/sidiqPratomo/max-health-backend/usecase"
 	"github.com/sidiqPratomo/max-health-backend/util"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	chatWriteWait      = 10 * time.Second
+	chatPongWait       = 60 * time.Second
+	chatPingPeriod     = (chatPongWait * 9) / 10
+	chatMaxMessageSize = 1024
 )
 
 type TelemedicineHandler struct {
 	telemedicineUsecase usecase.TelemedicineUsecase
+	chatUpgrader        websocket.Upgrader
 }
 
-func NewTelemedicineHandler(telemedicineUsecase usecase.TelemedicineUsecase) TelemedicineHandler {
+func NewTelemedicineHandler(telemedicineUsecase usecase.TelemedicineUsecase, frontendOrigin string) TelemedicineHandler {
 	return TelemedicineHandler{
 		telemedicineUsecase: telemedicineUsecase,
+		chatUpgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			Subprotocols:    []string{appconstant.AccessTokenProtocol},
+			CheckOrigin: func(r *http.Request) bool {
+				return r.Header.Get("Origin") == frontendOrigin
+			},
+		},
 	}
 }
 
@@ -40,7 +61,7 @@ func (h *TelemedicineHandler) UserCreateRoom(ctx *gin.Context) {
 		return
 	}
 
-	roomId, err := h.telemedicineUsecase.UserCreateRoom(ctx.Request.Context(), userAccountId.(int64), createRoomRequest.DoctorAccountId)
+	roomId, err := h.telemedicineUsecase.UserCreateRoom(ctx.Request.Context(), userAccountId.(int64), createRoomRequest.DoctorAccountId, createRoomRequest.AllowFallback)
 	if err != nil {
 		ctx.Error(err)
 		return
@@ -75,6 +96,54 @@ func (h *TelemedicineHandler) DoctorJoinRoom(ctx *gin.Context) {
 	util.ResponseOK(ctx, nil)
 }
 
+func (h *TelemedicineHandler) DoctorDeclineRoom(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	doctorAccountId, exist := ctx.Get(appconstant.AccountId)
+	if !exist {
+		ctx.Error(apperror.UnauthorizedError())
+		return
+	}
+
+	roomId, err := roomIdParam(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	err = h.telemedicineUsecase.DoctorDeclineRoom(ctx.Request.Context(), doctorAccountId.(int64), roomId)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, nil)
+}
+
+func (h *TelemedicineHandler) GetQueueStatus(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	accountId, exist := ctx.Get(appconstant.AccountId)
+	if !exist {
+		ctx.Error(apperror.UnauthorizedError())
+		return
+	}
+
+	roomId, err := roomIdParam(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	queueStatus, err := h.telemedicineUsecase.GetQueueStatus(ctx.Request.Context(), accountId.(int64), roomId)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, queueStatus)
+}
+
 func (h *TelemedicineHandler) PostOneMessage(ctx *gin.Context) {
 	ctx.Header("Content-Type", "application/json")
 
@@ -143,9 +212,7 @@ func (h *TelemedicineHandler) PostOneMessage(ctx *gin.Context) {
 	util.ResponseCreated(ctx, response)
 }
 
-func (h *TelemedicineHandler) Listen(ctx *gin.Context) {
-	ctx.Header("Content-Type", "application/json")
-
+func (h *TelemedicineHandler) ChatStream(ctx *gin.Context) {
 	accountId, exist := ctx.Get(appconstant.AccountId)
 	if !exist {
 		ctx.Error(apperror.UnauthorizedError())
@@ -159,10 +226,6 @@ func (h *TelemedicineHandler) Listen(ctx *gin.Context) {
 	}
 
 	roomIdStr := ctx.Param(appconstant.RoomIdString)
-	if !exists {
-		ctx.Error(apperror.UnauthorizedError())
-		return
-	}
 
 	roomId, err := strconv.Atoi(roomIdStr)
 	if err != nil {
@@ -170,18 +233,125 @@ func (h *TelemedicineHandler) Listen(ctx *gin.Context) {
 		return
 	}
 
-	chat, err := h.telemedicineUsecase.Listen(ctx.Request.Context(), accountId.(int64), int64(roomId))
+	subscription, err := h.telemedicineUsecase.SubscribeChatRoom(ctx.Request.Context(), accountId.(int64), int64(roomId))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	defer h.telemedicineUsecase.UnsubscribeChatRoom(subscription)
+
+	conn, err := h.chatUpgrader.Upgrade(ctx.Writer, ctx.Request, nil)
 	if err != nil {
-		if err == apperror.AbortPreviousListenRequestError() {
-			util.ResponseOK(ctx, nil)
+		return
+	}
+	defer conn.Close()
+
+	done := make(chan struct{})
+	go h.readChatEvents(ctx.Request.Context(), conn, subscription, done)
+
+	h.writeChatEvents(conn, subscription, done)
+}
+
+func (h *TelemedicineHandler) readChatEvents(ctx context.Context, conn *websocket.Conn, subscription *entity.ChatSubscription, done chan struct{}) {
+	defer close(done)
+
+	conn.SetReadLimit(chatMaxMessageSize)
+	conn.SetReadDeadline(time.Now().Add(chatPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(chatPongWait))
+	})
+
+	for {
+		var chatEventRequest dto.ChatEventRequest
+
+		if err := conn.ReadJSON(&chatEventRequest); err != nil {
 			return
 		}
 
-		ctx.Error(err)
-		return
+		if err := validator.New().Struct(chatEventRequest); err != nil {
+			continue
+		}
+
+		err := h.telemedicineUsecase.PublishChatEvent(ctx, subscription.AccountId, subscription.RoomId, chatEventRequest)
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (h *TelemedicineHandler) writeChatEvents(conn *websocket.Conn, subscription *entity.ChatSubscription, done chan struct{}) {
+	pingTicker := time.NewTicker(chatPingPeriod)
+	defer pingTicker.Stop()
+
+	// The room gets an expiry once the doctor joins and a later one for every
+	// accepted extension, so both timers are replaced whenever that happens.
+	var roomExpiredTimer, roomExpiringTimer *time.Timer
+	var roomExpired, roomExpiring <-chan time.Time
+	var expiredAt time.Time
+
+	stopTimers := func() {
+		if roomExpiredTimer != nil {
+			roomExpiredTimer.Stop()
+			roomExpiringTimer.Stop()
+		}
+	}
+	defer stopTimers()
+
+	setExpiredAt := func(newExpiredAt time.Time) {
+		stopTimers()
+
+		expiredAt = newExpiredAt
+		roomExpiredTimer = time.NewTimer(time.Until(expiredAt))
+		roomExpiringTimer = time.NewTimer(time.Until(expiredAt.Add(-appconstant.ConsultationExpiryWarning)))
+		roomExpired = roomExpiredTimer.C
+		roomExpiring = roomExpiringTimer.C
+	}
+
+	if subscription.ExpiredAt != nil {
+		setExpiredAt(*subscription.ExpiredAt)
+	}
+
+	for {
+		select {
+		case chatEvent, ok := <-subscription.Events:
+			if !ok {
+				writeChatClose(conn, websocket.CloseTryAgainLater, "")
+				return
+			}
+
+			conn.SetWriteDeadline(time.Now().Add(chatWriteWait))
+			if err := conn.WriteJSON(dto.ConvertToChatEventDTO(chatEvent)); err != nil {
+				return
+			}
+
+			if chatEvent.Type == appconstant.ChatEventClosed {
+				writeChatClose(conn, websocket.CloseNormalClosure, appconstant.MsgChatRoomAlreadyClosed)
+				return
+			}
+
+			if chatEvent.ExpiredAt != nil {
+				setExpiredAt(*chatEvent.ExpiredAt)
+			}
+		case <-pingTicker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(chatWriteWait)); err != nil {
+				return
+			}
+		case <-roomExpiring:
+			conn.SetWriteDeadline(time.Now().Add(chatWriteWait))
+			if err := conn.WriteJSON(dto.ChatEvent{Type: appconstant.ChatEventExpiring, RoomId: subscription.RoomId, ExpiredAt: &expiredAt}); err != nil {
+				return
+			}
+		case <-roomExpired:
+			writeChatClose(conn, websocket.CloseNormalClosure, appconstant.MsgRoomIsNowExpired)
+			return
+		case <-done:
+			return
+		}
 	}
+}
 
-	util.ResponseOK(ctx, chat)
+func writeChatClose(conn *websocket.Conn, code int, text string) {
+	conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, text), time.Now().Add(chatWriteWait))
 }
 
 func (h *TelemedicineHandler) GetAllChat(ctx *gin.Context) {
@@ -315,6 +485,7 @@ func (h *TelemedicineHandler) PreapereForCheckout(ctx *gin.Context) {
 
 	prescriptionIdString := ctx.Param(appconstant.PrescriptionIdString)
 	addressId := ctx.Query(appconstant.AddressIdString)
+	strategy := ctx.Query(appconstant.StrategyString)
 
 	prescriptionId, err := strconv.Atoi(prescriptionIdString)
 	if err != nil {
@@ -322,7 +493,7 @@ func (h *TelemedicineHandler) PreapereForCheckout(ctx *gin.Context) {
 		return
 	}
 
-	nearestPharmacyDrugList, err := h.telemedicineUsecase.PrepareForCheckout(ctx.Request.Context(), accountId.(int64), int64(prescriptionId), addressId)
+	nearestPharmacyDrugList, err := h.telemedicineUsecase.PrepareForCheckout(ctx.Request.Context(), accountId.(int64), int64(prescriptionId), addressId, strategy)
 	if err != nil {
 		ctx.Error(err)
 		return
@@ -331,6 +502,30 @@ func (h *TelemedicineHandler) PreapereForCheckout(ctx *gin.Context) {
 	util.ResponseOK(ctx, *nearestPharmacyDrugList)
 }
 
+func (h *TelemedicineHandler) GetPrescriptionRedemptions(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json")
+
+	accountId, exists := ctx.Get(appconstant.AccountId)
+	if !exists {
+		ctx.Error(apperror.UnauthorizedError())
+		return
+	}
+
+	prescriptionId, err := prescriptionIdParam(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	redemptions, err := h.telemedicineUsecase.GetPrescriptionRedemptions(ctx.Request.Context(), accountId.(int64), prescriptionId)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	util.ResponseOK(ctx, redemptions)
+}
+
 func (h *TelemedicineHandler) CheckoutFromPrescription(ctx *gin.Context) {
 	ctx.Header("Content-Type", "application/json")
 
@@ -1,9 +1,16 @@
 package main
 
 import (
+	"os"
+
 	"github.com/sidiqPratomo/max-health-backend/server"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		server.Migrate(os.Args[2:])
+		return
+	}
+
 	server.Init()
 }
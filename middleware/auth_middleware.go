@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"strings"
 
@@ -9,11 +10,22 @@ import (
 	"github.com/sidiqPratomo/max-health-backend/dto"
 	"github.com/sidiqPratomo/max-health-backend/util"
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
 )
 
-func AuthMiddleware(tokenAuth util.TokenAuthentication, config *config.Config) gin.HandlerFunc {
+type TokenVersionFinder interface {
+	GetTokenVersion(ctx context.Context, accountId int64) (*int64, error)
+}
+
+func AuthMiddleware(tokenAuth util.TokenAuthentication, tokenVersionFinder TokenVersionFinder, config *config.Config) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.Request.Header.Get(appconstant.AuthorizationHeader)
+		if authHeader == "" && websocket.IsWebSocketUpgrade(c.Request) {
+			protocols := websocket.Subprotocols(c.Request)
+			if len(protocols) == 2 && protocols[0] == appconstant.AccessTokenProtocol {
+				authHeader = appconstant.Bearer + " " + protocols[1]
+			}
+		}
 		t := strings.Split(authHeader, " ")
 
 		if len(t) != 2 || t[0] != appconstant.Bearer {
@@ -29,8 +41,20 @@ func AuthMiddleware(tokenAuth util.TokenAuthentication, config *config.Config) g
 			return
 		}
 
+		tokenVersion, err := tokenVersionFinder.GetTokenVersion(c.Request.Context(), claims.UserId)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{Message: appconstant.MsgInternalServerError})
+			return
+		}
+
+		if tokenVersion == nil || *tokenVersion != claims.TokenVersion {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Message: appconstant.MsgUnauthorized})
+			return
+		}
+
 		c.Set(appconstant.AccountId, claims.UserId)
 		c.Set(appconstant.Role, claims.Role)
+		c.Set(appconstant.SessionId, claims.SessionId)
 		c.Next()
 	}
 }
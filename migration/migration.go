@@ -0,0 +1,345 @@
+package migration
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sidiqPratomo/max-health-backend/database"
+)
+
+//go:embed sql/*.sql
+var migrationFiles embed.FS
+
+const SourceDir = "migration/sql"
+
+var (
+	migrationFileRegex = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+	migrationNameRegex = regexp.MustCompile(`^[a-z0-9_]+$`)
+)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time
+}
+
+type Migrator struct {
+	db         *pgxpool.Pool
+	migrations []Migration
+}
+
+func NewMigrator(db *pgxpool.Pool) (*Migrator, error) {
+	migrations, err := loadMigrations(migrationFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+	}, nil
+}
+
+func loadMigrations(files fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(files, "sql")
+	if err != nil {
+		return nil, err
+	}
+
+	migrationsByVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		matches := migrationFileRegex.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			return nil, fmt.Errorf("invalid migration file name %s", entry.Name())
+		}
+
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		content, err := fs.ReadFile(files, "sql/"+entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := migrationsByVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: matches[2]}
+			migrationsByVersion[version] = migration
+		}
+		if migration.Name != matches[2] {
+			return nil, fmt.Errorf("migration %d has mismatched names %s and %s", version, migration.Name, matches[2])
+		}
+
+		if matches[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := []Migration{}
+	for _, migration := range migrationsByVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d must have both up and down files", migration.Version)
+		}
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+func (m *Migrator) LatestVersion() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+func (m *Migrator) CurrentVersion(ctx context.Context) (int64, error) {
+	if _, err := m.db.Exec(ctx, database.CreateSchemaMigrationsTable); err != nil {
+		return 0, err
+	}
+
+	var version int64
+	if err := m.db.QueryRow(ctx, database.FindLatestAppliedMigrationVersion).Scan(&version); err != nil {
+		return 0, err
+	}
+
+	return version, nil
+}
+
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	if _, err := m.db.Exec(ctx, database.CreateSchemaMigrationsTable); err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.Query(ctx, database.FindAllAppliedMigrations)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int64]MigrationStatus{}
+	for rows.Next() {
+		var status MigrationStatus
+		var appliedAt time.Time
+
+		if err := rows.Scan(&status.Version, &status.Name, &appliedAt); err != nil {
+			return nil, err
+		}
+
+		status.AppliedAt = &appliedAt
+		applied[status.Version] = status
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	statuses := []MigrationStatus{}
+	for _, migration := range m.migrations {
+		status, ok := applied[migration.Version]
+		if !ok {
+			status = MigrationStatus{Version: migration.Version, Name: migration.Name}
+		}
+		delete(applied, migration.Version)
+		statuses = append(statuses, status)
+	}
+
+	for _, status := range applied {
+		statuses = append(statuses, status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+
+	return statuses, nil
+}
+
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	if _, err := m.db.Exec(ctx, database.CreateSchemaMigrationsTable); err != nil {
+		return nil, err
+	}
+
+	appliedMigrations := []Migration{}
+	for _, migration := range m.migrations {
+		applied, err := m.apply(ctx, migration)
+		if err != nil {
+			return appliedMigrations, fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+
+		if applied {
+			appliedMigrations = append(appliedMigrations, migration)
+		}
+	}
+
+	return appliedMigrations, nil
+}
+
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	revertedMigrations := []Migration{}
+	for i := 0; i < steps; i++ {
+		version, err := m.CurrentVersion(ctx)
+		if err != nil {
+			return revertedMigrations, err
+		}
+
+		if version == 0 {
+			return revertedMigrations, nil
+		}
+
+		migration, ok := m.findByVersion(version)
+		if !ok {
+			return revertedMigrations, fmt.Errorf("applied migration %d is not known to this binary", version)
+		}
+
+		if err := m.revert(ctx, migration); err != nil {
+			return revertedMigrations, fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+
+		revertedMigrations = append(revertedMigrations, migration)
+	}
+
+	return revertedMigrations, nil
+}
+
+func (m *Migrator) findByVersion(version int64) (Migration, bool) {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration, true
+		}
+	}
+
+	return Migration{}, false
+}
+
+// Each migration runs in its own transaction behind an advisory lock, so
+// replicas starting at the same time apply every version exactly once.
+func (m *Migrator) apply(ctx context.Context, migration Migration) (applied bool, err error) {
+	tx, err := m.db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+			return
+		}
+
+		err = tx.Commit(ctx)
+	}()
+
+	if _, err = tx.Exec(ctx, database.LockSchemaMigrations); err != nil {
+		return false, err
+	}
+
+	if err = tx.QueryRow(ctx, database.FindAppliedMigrationByVersion, migration.Version).Scan(&applied); err != nil {
+		return false, err
+	}
+
+	if applied {
+		return false, nil
+	}
+
+	if _, err = tx.Exec(ctx, migration.Up); err != nil {
+		return false, err
+	}
+
+	if _, err = tx.Exec(ctx, database.CreateOneAppliedMigration, migration.Version, migration.Name); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (m *Migrator) revert(ctx context.Context, migration Migration) (err error) {
+	tx, err := m.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+			return
+		}
+
+		err = tx.Commit(ctx)
+	}()
+
+	if _, err = tx.Exec(ctx, database.LockSchemaMigrations); err != nil {
+		return err
+	}
+
+	var applied bool
+	if err = tx.QueryRow(ctx, database.FindAppliedMigrationByVersion, migration.Version).Scan(&applied); err != nil {
+		return err
+	}
+
+	if !applied {
+		return fmt.Errorf("version %d is not applied", migration.Version)
+	}
+
+	if _, err = tx.Exec(ctx, migration.Down); err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec(ctx, database.DeleteOneAppliedMigration, migration.Version); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func Create(name string) ([]string, error) {
+	if !migrationNameRegex.MatchString(name) {
+		return nil, fmt.Errorf("migration name must only contain lowercase letters, digits and underscores")
+	}
+
+	migrations, err := loadMigrations(os.DirFS(filepath.Dir(SourceDir)))
+	if err != nil {
+		return nil, err
+	}
+
+	version := int64(1)
+	if len(migrations) > 0 {
+		version = migrations[len(migrations)-1].Version + 1
+	}
+
+	paths := []string{}
+	for _, direction := range []string{"up", "down"} {
+		path := filepath.Join(SourceDir, fmt.Sprintf("%06d_%s.%s.sql", version, name, direction))
+		content := fmt.Sprintf("-- %s %s\n", name, direction)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
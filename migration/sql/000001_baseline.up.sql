@@ -0,0 +1,3 @@
+-- The baseline schema is created from sql/ddl.sql when the database is
+-- provisioned. This migration only marks the starting version.
+SELECT 1;
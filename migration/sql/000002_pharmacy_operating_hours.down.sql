@@ -0,0 +1,5 @@
+DROP FUNCTION IF EXISTS pharmacy_is_open(BIGINT, TIMESTAMPTZ);
+
+DROP TABLE IF EXISTS pharmacy_holidays;
+
+ALTER TABLE pharmacies DROP COLUMN IF EXISTS timezone;
@@ -0,0 +1,65 @@
+ALTER TABLE pharmacies ADD COLUMN IF NOT EXISTS timezone VARCHAR NOT NULL DEFAULT 'Asia/Jakarta';
+
+CREATE TABLE IF NOT EXISTS pharmacy_holidays (
+	pharmacy_holiday_id BIGSERIAL PRIMARY KEY,
+	pharmacy_id BIGINT NOT NULL REFERENCES pharmacies (pharmacy_id),
+	holiday_date DATE NOT NULL,
+	is_open BOOLEAN NOT NULL DEFAULT FALSE,
+	open_hour TIME,
+	close_hour TIME,
+	description VARCHAR NOT NULL DEFAULT '',
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	deleted_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS pharmacy_holidays_pharmacy_id_holiday_date_key
+	ON pharmacy_holidays (pharmacy_id, holiday_date)
+	WHERE deleted_at IS NULL;
+
+-- Mirrors util.GetPharmacyOpeningStatus so listings can filter closed pharmacies in SQL.
+CREATE OR REPLACE FUNCTION pharmacy_is_open(target_pharmacy_id BIGINT, at_time TIMESTAMPTZ)
+RETURNS BOOLEAN AS $$
+DECLARE
+	local_time TIMESTAMP;
+	window_is_open BOOLEAN;
+	window_open_hour TIME;
+	window_close_hour TIME;
+BEGIN
+	SELECT at_time AT TIME ZONE p.timezone INTO local_time
+	FROM pharmacies p
+	WHERE p.pharmacy_id = target_pharmacy_id;
+
+	IF local_time IS NULL THEN
+		RETURN FALSE;
+	END IF;
+
+	SELECT ph.is_open, ph.open_hour, ph.close_hour
+	INTO window_is_open, window_open_hour, window_close_hour
+	FROM pharmacy_holidays ph
+	WHERE ph.pharmacy_id = target_pharmacy_id
+	AND ph.holiday_date = local_time::DATE
+	AND ph.deleted_at IS NULL;
+
+	IF NOT FOUND THEN
+		SELECT po.is_open, po.open_hour::TIME, po.close_hour::TIME
+		INTO window_is_open, window_open_hour, window_close_hour
+		FROM pharmacy_operationals po
+		WHERE po.pharmacy_id = target_pharmacy_id
+		AND po.operational_day = TO_CHAR(local_time, 'FMDay')
+		AND po.deleted_at IS NULL
+		LIMIT 1;
+
+		IF NOT FOUND THEN
+			RETURN FALSE;
+		END IF;
+	END IF;
+
+	IF NOT window_is_open OR window_open_hour IS NULL OR window_close_hour IS NULL THEN
+		RETURN FALSE;
+	END IF;
+
+	RETURN window_open_hour = window_close_hour
+		OR (local_time::TIME >= window_open_hour AND local_time::TIME < window_close_hour);
+END;
+$$ LANGUAGE plpgsql STABLE;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS refresh_tokens_refresh_token_idx;
+
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS used_at;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS account_session_id;
+
+DROP TABLE IF EXISTS account_sessions;
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS account_sessions (
+	account_session_id BIGSERIAL PRIMARY KEY,
+	account_id BIGINT NOT NULL REFERENCES accounts (account_id),
+	user_agent VARCHAR NOT NULL DEFAULT '',
+	ip_address VARCHAR NOT NULL DEFAULT '',
+	last_seen_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	revoked_at TIMESTAMP,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	deleted_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS account_sessions_account_id_idx
+	ON account_sessions (account_id)
+	WHERE revoked_at IS NULL;
+
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS account_session_id BIGINT REFERENCES account_sessions (account_session_id);
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS used_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS refresh_tokens_refresh_token_idx ON refresh_tokens (refresh_token);
+
+-- Refresh tokens issued before sessions existed cannot be rotated, so their
+-- holders have to log in again.
+UPDATE refresh_tokens SET expired_at = NOW(), updated_at = NOW()
+WHERE account_session_id IS NULL AND expired_at > NOW();
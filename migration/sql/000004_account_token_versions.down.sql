@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS accounts_role_change_token_version ON accounts;
+DROP FUNCTION IF EXISTS bump_account_token_version_on_role_change();
+
+ALTER TABLE accounts DROP COLUMN IF EXISTS token_version;
@@ -0,0 +1,21 @@
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS token_version BIGINT NOT NULL DEFAULT 0;
+
+-- Role changes have no API of their own, so the bump happens in the database
+-- and also covers roles edited by hand.
+CREATE OR REPLACE FUNCTION bump_account_token_version_on_role_change()
+RETURNS TRIGGER AS $$
+BEGIN
+	IF NEW.role_id IS DISTINCT FROM OLD.role_id THEN
+		NEW.token_version := OLD.token_version + 1;
+	END IF;
+
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS accounts_role_change_token_version ON accounts;
+
+CREATE TRIGGER accounts_role_change_token_version
+	BEFORE UPDATE OF role_id ON accounts
+	FOR EACH ROW
+	EXECUTE FUNCTION bump_account_token_version_on_role_change();
@@ -0,0 +1,5 @@
+ALTER TABLE order_items DROP COLUMN IF EXISTS prescription_drug_id;
+ALTER TABLE cart_items DROP COLUMN IF EXISTS prescription_drug_id;
+
+ALTER TABLE prescription_drugs DROP CONSTRAINT IF EXISTS prescription_drugs_remaining_quantity_check;
+ALTER TABLE prescription_drugs DROP COLUMN IF EXISTS remaining_quantity;
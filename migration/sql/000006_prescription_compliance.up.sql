@@ -0,0 +1,15 @@
+ALTER TABLE prescription_drugs ADD COLUMN IF NOT EXISTS remaining_quantity INT;
+
+UPDATE prescription_drugs pd
+SET remaining_quantity = CASE WHEN p.ordered_at IS NULL THEN pd.quantity ELSE 0 END
+FROM prescriptions p
+WHERE p.prescription_id = pd.prescription_id
+AND pd.remaining_quantity IS NULL;
+
+UPDATE prescription_drugs SET remaining_quantity = 0 WHERE remaining_quantity IS NULL;
+
+ALTER TABLE prescription_drugs ALTER COLUMN remaining_quantity SET NOT NULL;
+ALTER TABLE prescription_drugs ADD CONSTRAINT prescription_drugs_remaining_quantity_check CHECK (remaining_quantity >= 0);
+
+ALTER TABLE cart_items ADD COLUMN IF NOT EXISTS prescription_drug_id BIGINT REFERENCES prescription_drugs (prescription_drug_id);
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS prescription_drug_id BIGINT REFERENCES prescription_drugs (prescription_drug_id);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS order_items_prescription_drug_id_idx;
+
+ALTER TABLE prescriptions DROP CONSTRAINT IF EXISTS prescriptions_refills_check;
+ALTER TABLE prescriptions DROP COLUMN IF EXISTS valid_until;
+ALTER TABLE prescriptions DROP COLUMN IF EXISTS refills_used;
+ALTER TABLE prescriptions DROP COLUMN IF EXISTS refill_count;
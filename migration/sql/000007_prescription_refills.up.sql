@@ -0,0 +1,7 @@
+ALTER TABLE prescriptions ADD COLUMN IF NOT EXISTS refill_count INT NOT NULL DEFAULT 0;
+ALTER TABLE prescriptions ADD COLUMN IF NOT EXISTS refills_used INT NOT NULL DEFAULT 0;
+ALTER TABLE prescriptions ADD COLUMN IF NOT EXISTS valid_until TIMESTAMP;
+
+ALTER TABLE prescriptions ADD CONSTRAINT prescriptions_refills_check CHECK (refills_used >= 0 AND refills_used <= refill_count);
+
+CREATE INDEX IF NOT EXISTS order_items_prescription_drug_id_idx ON order_items (prescription_drug_id) WHERE prescription_drug_id IS NOT NULL;
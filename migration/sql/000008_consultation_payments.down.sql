@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS consultation_payments_status_idx;
+DROP INDEX IF EXISTS consultation_payments_chat_room_id_key;
+DROP TABLE IF EXISTS consultation_payments;
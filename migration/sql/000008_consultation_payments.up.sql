@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS consultation_payments (
+	consultation_payment_id BIGSERIAL PRIMARY KEY,
+	chat_room_id BIGINT NOT NULL REFERENCES chat_rooms (chat_room_id),
+	amount NUMERIC NOT NULL CHECK (amount >= 0),
+	payment_status VARCHAR NOT NULL,
+	payment_proof VARCHAR NOT NULL DEFAULT '',
+	confirmed_at TIMESTAMP,
+	cancelled_at TIMESTAMP,
+	refunded_at TIMESTAMP,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	deleted_at TIMESTAMP,
+	CONSTRAINT consultation_payments_status_check CHECK (payment_status IN ('waiting_for_payment', 'waiting_for_confirmation', 'paid', 'cancelled', 'refunded'))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS consultation_payments_chat_room_id_key ON consultation_payments (chat_room_id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS consultation_payments_status_idx ON consultation_payments (payment_status) WHERE deleted_at IS NULL;
+
+INSERT INTO consultation_payments (chat_room_id, amount, payment_status, confirmed_at, created_at)
+SELECT cr.chat_room_id, 0, 'paid', cr.created_at, cr.created_at
+FROM chat_rooms cr
+WHERE NOT EXISTS (
+	SELECT 1
+	FROM consultation_payments cp
+	WHERE cp.chat_room_id = cr.chat_room_id
+);
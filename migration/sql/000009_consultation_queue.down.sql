@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS chat_room_declines_chat_room_id_idx;
+DROP TABLE IF EXISTS chat_room_declines;
+
+DROP INDEX IF EXISTS chat_rooms_doctor_queue_idx;
+
+ALTER TABLE chat_rooms DROP COLUMN IF EXISTS started_at;
+ALTER TABLE chat_rooms DROP COLUMN IF EXISTS assigned_at;
+ALTER TABLE chat_rooms DROP COLUMN IF EXISTS queued_at;
+ALTER TABLE chat_rooms DROP COLUMN IF EXISTS allow_fallback;
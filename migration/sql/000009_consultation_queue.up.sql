@@ -0,0 +1,24 @@
+ALTER TABLE chat_rooms ADD COLUMN IF NOT EXISTS allow_fallback BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE chat_rooms ADD COLUMN IF NOT EXISTS queued_at TIMESTAMP;
+ALTER TABLE chat_rooms ADD COLUMN IF NOT EXISTS assigned_at TIMESTAMP;
+ALTER TABLE chat_rooms ADD COLUMN IF NOT EXISTS started_at TIMESTAMP;
+
+UPDATE chat_rooms cr
+SET queued_at = cp.confirmed_at, assigned_at = cp.confirmed_at
+FROM consultation_payments cp
+WHERE cp.chat_room_id = cr.chat_room_id
+AND cp.payment_status = 'paid'
+AND cr.queued_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS chat_rooms_doctor_queue_idx ON chat_rooms (doctor_account_id, queued_at) WHERE expired_at IS NULL AND deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS chat_room_declines (
+	chat_room_decline_id BIGSERIAL PRIMARY KEY,
+	chat_room_id BIGINT NOT NULL REFERENCES chat_rooms (chat_room_id),
+	doctor_account_id BIGINT NOT NULL REFERENCES accounts (account_id),
+	reason VARCHAR NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	CONSTRAINT chat_room_declines_reason_check CHECK (reason IN ('declined', 'expired'))
+);
+
+CREATE INDEX IF NOT EXISTS chat_room_declines_chat_room_id_idx ON chat_room_declines (chat_room_id);
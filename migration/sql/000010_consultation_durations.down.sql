@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS chat_room_extensions_pending_key;
+DROP TABLE IF EXISTS chat_room_extensions;
+
+ALTER TABLE doctors DROP CONSTRAINT IF EXISTS doctors_consultation_duration_check;
+ALTER TABLE doctors DROP COLUMN IF EXISTS consultation_duration_minutes;
+
+ALTER TABLE doctor_specializations DROP CONSTRAINT IF EXISTS doctor_specializations_consultation_duration_check;
+ALTER TABLE doctor_specializations DROP COLUMN IF EXISTS consultation_duration_minutes;
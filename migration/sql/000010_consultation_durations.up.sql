@@ -0,0 +1,20 @@
+ALTER TABLE doctor_specializations ADD COLUMN IF NOT EXISTS consultation_duration_minutes INT;
+ALTER TABLE doctor_specializations ADD CONSTRAINT doctor_specializations_consultation_duration_check CHECK (consultation_duration_minutes BETWEEN 5 AND 240);
+
+ALTER TABLE doctors ADD COLUMN IF NOT EXISTS consultation_duration_minutes INT;
+ALTER TABLE doctors ADD CONSTRAINT doctors_consultation_duration_check CHECK (consultation_duration_minutes BETWEEN 5 AND 240);
+
+CREATE TABLE IF NOT EXISTS chat_room_extensions (
+	chat_room_extension_id BIGSERIAL PRIMARY KEY,
+	chat_room_id BIGINT NOT NULL REFERENCES chat_rooms (chat_room_id),
+	requested_by_account_id BIGINT NOT NULL REFERENCES accounts (account_id),
+	minutes INT NOT NULL CHECK (minutes > 0),
+	fee NUMERIC NOT NULL DEFAULT 0 CHECK (fee >= 0),
+	extension_status VARCHAR NOT NULL DEFAULT 'pending',
+	responded_at TIMESTAMP,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	CONSTRAINT chat_room_extensions_status_check CHECK (extension_status IN ('pending', 'accepted', 'rejected', 'expired'))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS chat_room_extensions_pending_key ON chat_room_extensions (chat_room_id) WHERE extension_status = 'pending';
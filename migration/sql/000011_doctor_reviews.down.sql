@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS doctors_rating_idx;
+DROP INDEX IF EXISTS doctor_reviews_doctor_account_id_idx;
+DROP INDEX IF EXISTS doctor_reviews_chat_room_id_key;
+DROP TABLE IF EXISTS doctor_reviews;
+
+ALTER TABLE doctors DROP COLUMN IF EXISTS review_count;
+ALTER TABLE doctors DROP COLUMN IF EXISTS rating;
@@ -0,0 +1,23 @@
+ALTER TABLE doctors ADD COLUMN IF NOT EXISTS rating NUMERIC(3, 2) NOT NULL DEFAULT 0;
+ALTER TABLE doctors ADD COLUMN IF NOT EXISTS review_count INT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS doctor_reviews (
+	doctor_review_id BIGSERIAL PRIMARY KEY,
+	chat_room_id BIGINT NOT NULL REFERENCES chat_rooms (chat_room_id),
+	user_account_id BIGINT NOT NULL REFERENCES accounts (account_id),
+	doctor_account_id BIGINT NOT NULL REFERENCES accounts (account_id),
+	rating SMALLINT NOT NULL,
+	review TEXT NOT NULL DEFAULT '',
+	reply TEXT,
+	replied_at TIMESTAMP,
+	hidden_at TIMESTAMP,
+	hidden_reason VARCHAR NOT NULL DEFAULT '',
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	deleted_at TIMESTAMP,
+	CONSTRAINT doctor_reviews_rating_check CHECK (rating BETWEEN 1 AND 5)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS doctor_reviews_chat_room_id_key ON doctor_reviews (chat_room_id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS doctor_reviews_doctor_account_id_idx ON doctor_reviews (doctor_account_id, created_at) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS doctors_rating_idx ON doctors (rating);
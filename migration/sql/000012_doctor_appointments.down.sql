@@ -0,0 +1,17 @@
+DROP FUNCTION IF EXISTS doctor_is_available(BIGINT, TIMESTAMPTZ);
+
+DROP INDEX IF EXISTS appointments_reminder_idx;
+DROP INDEX IF EXISTS appointments_doctor_scheduled_at_idx;
+DROP INDEX IF EXISTS appointments_chat_room_id_key;
+DROP TABLE IF EXISTS appointments;
+
+DROP INDEX IF EXISTS doctor_schedule_exceptions_doctor_date_key;
+DROP TABLE IF EXISTS doctor_schedule_exceptions;
+
+DROP INDEX IF EXISTS doctor_schedules_doctor_account_id_idx;
+DROP TABLE IF EXISTS doctor_schedules;
+
+ALTER TABLE chat_rooms DROP COLUMN IF EXISTS scheduled_at;
+
+ALTER TABLE doctors DROP COLUMN IF EXISTS use_schedule_availability;
+ALTER TABLE doctors DROP COLUMN IF EXISTS timezone;
@@ -0,0 +1,99 @@
+ALTER TABLE doctors ADD COLUMN IF NOT EXISTS timezone VARCHAR NOT NULL DEFAULT 'Asia/Jakarta';
+ALTER TABLE doctors ADD COLUMN IF NOT EXISTS use_schedule_availability BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE chat_rooms ADD COLUMN IF NOT EXISTS scheduled_at TIMESTAMP;
+
+CREATE TABLE IF NOT EXISTS doctor_schedules (
+	doctor_schedule_id BIGSERIAL PRIMARY KEY,
+	doctor_account_id BIGINT NOT NULL REFERENCES accounts (account_id),
+	schedule_day VARCHAR NOT NULL,
+	start_hour TIME NOT NULL,
+	end_hour TIME NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	deleted_at TIMESTAMP,
+	CONSTRAINT doctor_schedules_day_check CHECK (schedule_day IN ('Monday', 'Tuesday', 'Wednesday', 'Thursday', 'Friday', 'Saturday', 'Sunday')),
+	CONSTRAINT doctor_schedules_hours_check CHECK (end_hour > start_hour)
+);
+
+CREATE INDEX IF NOT EXISTS doctor_schedules_doctor_account_id_idx ON doctor_schedules (doctor_account_id) WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS doctor_schedule_exceptions (
+	doctor_schedule_exception_id BIGSERIAL PRIMARY KEY,
+	doctor_account_id BIGINT NOT NULL REFERENCES accounts (account_id),
+	exception_date DATE NOT NULL,
+	is_available BOOLEAN NOT NULL DEFAULT FALSE,
+	start_hour TIME,
+	end_hour TIME,
+	description VARCHAR NOT NULL DEFAULT '',
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	deleted_at TIMESTAMP,
+	CONSTRAINT doctor_schedule_exceptions_hours_check CHECK (NOT is_available OR end_hour > start_hour)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS doctor_schedule_exceptions_doctor_date_key
+	ON doctor_schedule_exceptions (doctor_account_id, exception_date)
+	WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS appointments (
+	appointment_id BIGSERIAL PRIMARY KEY,
+	chat_room_id BIGINT NOT NULL REFERENCES chat_rooms (chat_room_id),
+	user_account_id BIGINT NOT NULL REFERENCES accounts (account_id),
+	doctor_account_id BIGINT NOT NULL REFERENCES accounts (account_id),
+	scheduled_at TIMESTAMP NOT NULL,
+	end_at TIMESTAMP NOT NULL,
+	appointment_status VARCHAR NOT NULL DEFAULT 'booked',
+	reminder_sent_at TIMESTAMP,
+	cancelled_at TIMESTAMP,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	deleted_at TIMESTAMP,
+	CONSTRAINT appointments_status_check CHECK (appointment_status IN ('booked', 'cancelled'))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS appointments_chat_room_id_key ON appointments (chat_room_id);
+CREATE INDEX IF NOT EXISTS appointments_doctor_scheduled_at_idx ON appointments (doctor_account_id, scheduled_at) WHERE appointment_status = 'booked' AND deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS appointments_reminder_idx ON appointments (scheduled_at) WHERE appointment_status = 'booked' AND reminder_sent_at IS NULL AND deleted_at IS NULL;
+
+-- Mirrors util.GetDoctorAppointmentSlots so availability can be synced in SQL.
+CREATE OR REPLACE FUNCTION doctor_is_available(target_account_id BIGINT, at_time TIMESTAMPTZ)
+RETURNS BOOLEAN AS $$
+DECLARE
+	local_time TIMESTAMP;
+	exception_is_available BOOLEAN;
+	exception_start_hour TIME;
+	exception_end_hour TIME;
+BEGIN
+	SELECT at_time AT TIME ZONE d.timezone INTO local_time
+	FROM doctors d
+	WHERE d.account_id = target_account_id;
+
+	IF local_time IS NULL THEN
+		RETURN FALSE;
+	END IF;
+
+	SELECT dse.is_available, dse.start_hour, dse.end_hour
+	INTO exception_is_available, exception_start_hour, exception_end_hour
+	FROM doctor_schedule_exceptions dse
+	WHERE dse.doctor_account_id = target_account_id
+	AND dse.exception_date = local_time::DATE
+	AND dse.deleted_at IS NULL;
+
+	IF FOUND THEN
+		RETURN exception_is_available
+			AND local_time::TIME >= exception_start_hour
+			AND local_time::TIME < exception_end_hour;
+	END IF;
+
+	RETURN EXISTS (
+		SELECT 1
+		FROM doctor_schedules ds
+		WHERE ds.doctor_account_id = target_account_id
+		AND ds.schedule_day = TO_CHAR(local_time, 'FMDay')
+		AND local_time::TIME >= ds.start_hour
+		AND local_time::TIME < ds.end_hour
+		AND ds.deleted_at IS NULL
+	);
+END;
+$$ LANGUAGE plpgsql STABLE;
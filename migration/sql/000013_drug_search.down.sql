@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS drug_search_terms_term_trgm_idx;
+DROP INDEX IF EXISTS drug_search_terms_term_key;
+DROP MATERIALIZED VIEW IF EXISTS drug_search_terms;
+
+DROP INDEX IF EXISTS drugs_generic_name_trgm_idx;
+DROP INDEX IF EXISTS drugs_drug_name_trgm_idx;
+DROP INDEX IF EXISTS drugs_search_vector_idx;
+
+ALTER TABLE drugs DROP COLUMN IF EXISTS search_vector;
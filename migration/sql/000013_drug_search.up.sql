@@ -0,0 +1,35 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE drugs ADD COLUMN IF NOT EXISTS search_vector TSVECTOR
+	GENERATED ALWAYS AS (
+		setweight(to_tsvector('indonesian', COALESCE(drug_name, '')), 'A') ||
+		setweight(to_tsvector('indonesian', COALESCE(generic_name, '')), 'A') ||
+		setweight(to_tsvector('indonesian', COALESCE(content, '')), 'B') ||
+		setweight(to_tsvector('indonesian', COALESCE(manufacture, '')), 'C') ||
+		setweight(to_tsvector('indonesian', COALESCE(description, '')), 'D')
+	) STORED;
+
+CREATE INDEX IF NOT EXISTS drugs_search_vector_idx ON drugs USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS drugs_drug_name_trgm_idx ON drugs USING GIN (drug_name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS drugs_generic_name_trgm_idx ON drugs USING GIN (generic_name gin_trgm_ops);
+
+-- Vocabulary of every word used to describe an active drug, used to suggest
+-- corrections for misspelled searches.
+CREATE MATERIALIZED VIEW IF NOT EXISTS drug_search_terms AS
+	SELECT word AS term, ndoc AS frequency
+	FROM ts_stat($$
+		SELECT to_tsvector('simple',
+			COALESCE(drug_name, '') || ' ' ||
+			COALESCE(generic_name, '') || ' ' ||
+			COALESCE(content, '') || ' ' ||
+			COALESCE(manufacture, '') || ' ' ||
+			COALESCE(description, ''))
+		FROM drugs
+		WHERE deleted_at IS NULL
+		AND is_active
+	$$)
+	WHERE LENGTH(word) > 2
+	AND word ~ '^[[:alpha:]]+$';
+
+CREATE UNIQUE INDEX IF NOT EXISTS drug_search_terms_term_key ON drug_search_terms (term);
+CREATE INDEX IF NOT EXISTS drug_search_terms_term_trgm_idx ON drug_search_terms USING GIN (term gin_trgm_ops);
@@ -0,0 +1,16 @@
+DROP INDEX IF EXISTS stock_mutation_requests_order_idx;
+DROP INDEX IF EXISTS stock_mutation_requests_target_idx;
+DROP INDEX IF EXISTS stock_mutation_requests_requester_idx;
+
+ALTER TABLE stock_mutation_requests
+	DROP COLUMN IF EXISTS updated_at,
+	DROP COLUMN IF EXISTS received_at,
+	DROP COLUMN IF EXISTS shipped_at,
+	DROP COLUMN IF EXISTS responded_at,
+	DROP COLUMN IF EXISTS order_id,
+	DROP COLUMN IF EXISTS mutation_status;
+
+-- Requests created by the workflow have no legacy status, they were all
+-- inserted as status 2 before it.
+UPDATE stock_mutation_requests SET status_id = 2 WHERE status_id IS NULL;
+ALTER TABLE stock_mutation_requests ALTER COLUMN status_id SET NOT NULL;
@@ -0,0 +1,16 @@
+ALTER TABLE stock_mutation_requests ALTER COLUMN status_id DROP NOT NULL;
+
+-- Requests recorded before this migration already moved their stock.
+ALTER TABLE stock_mutation_requests ADD COLUMN IF NOT EXISTS mutation_status VARCHAR NOT NULL DEFAULT 'received';
+ALTER TABLE stock_mutation_requests ALTER COLUMN mutation_status SET DEFAULT 'pending';
+
+ALTER TABLE stock_mutation_requests
+	ADD COLUMN IF NOT EXISTS order_id BIGINT REFERENCES orders (order_id),
+	ADD COLUMN IF NOT EXISTS responded_at TIMESTAMPTZ,
+	ADD COLUMN IF NOT EXISTS shipped_at TIMESTAMPTZ,
+	ADD COLUMN IF NOT EXISTS received_at TIMESTAMPTZ,
+	ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+CREATE INDEX IF NOT EXISTS stock_mutation_requests_requester_idx ON stock_mutation_requests (pharmacy_requester_id, mutation_status);
+CREATE INDEX IF NOT EXISTS stock_mutation_requests_target_idx ON stock_mutation_requests (pharmacy_target_id, mutation_status);
+CREATE INDEX IF NOT EXISTS stock_mutation_requests_order_idx ON stock_mutation_requests (order_id, mutation_status);
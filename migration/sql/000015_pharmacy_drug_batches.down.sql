@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS pharmacy_drug_batch_allocations_pending_idx;
+DROP INDEX IF EXISTS pharmacy_drug_batch_allocations_order_pharmacy_idx;
+DROP TABLE IF EXISTS pharmacy_drug_batch_allocations;
+
+DROP INDEX IF EXISTS pharmacy_drug_batches_expiry_idx;
+DROP INDEX IF EXISTS pharmacy_drug_batches_fefo_idx;
+DROP INDEX IF EXISTS pharmacy_drug_batches_number_key;
+DROP TABLE IF EXISTS pharmacy_drug_batches;
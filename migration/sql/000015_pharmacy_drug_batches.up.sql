@@ -0,0 +1,46 @@
+CREATE TABLE IF NOT EXISTS pharmacy_drug_batches (
+	pharmacy_drug_batch_id BIGSERIAL PRIMARY KEY,
+	pharmacy_drug_id BIGINT NOT NULL REFERENCES pharmacy_drugs (pharmacy_drug_id),
+	batch_number VARCHAR NOT NULL,
+	expiry_date DATE,
+	stock INT NOT NULL DEFAULT 0 CHECK (stock >= 0),
+	written_off_at TIMESTAMPTZ,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	deleted_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS pharmacy_drug_batches_number_key ON pharmacy_drug_batches (pharmacy_drug_id, batch_number) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS pharmacy_drug_batches_fefo_idx ON pharmacy_drug_batches (pharmacy_drug_id, expiry_date) WHERE deleted_at IS NULL AND stock > 0;
+CREATE INDEX IF NOT EXISTS pharmacy_drug_batches_expiry_idx ON pharmacy_drug_batches (expiry_date) WHERE deleted_at IS NULL AND stock > 0;
+
+-- Allocations without a batch are backorders, quantity the pharmacy sold
+-- before it had the stock. They are filled as soon as stock is received.
+CREATE TABLE IF NOT EXISTS pharmacy_drug_batch_allocations (
+	pharmacy_drug_batch_allocation_id BIGSERIAL PRIMARY KEY,
+	pharmacy_drug_id BIGINT NOT NULL REFERENCES pharmacy_drugs (pharmacy_drug_id),
+	pharmacy_drug_batch_id BIGINT REFERENCES pharmacy_drug_batches (pharmacy_drug_batch_id),
+	order_pharmacy_id BIGINT REFERENCES order_pharmacies (order_pharmacy_id),
+	stock_mutation_request_id BIGINT REFERENCES stock_mutation_requests (stock_mutation_request_id),
+	quantity INT NOT NULL CHECK (quantity >= 0),
+	released_at TIMESTAMPTZ,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS pharmacy_drug_batch_allocations_order_pharmacy_idx ON pharmacy_drug_batch_allocations (order_pharmacy_id) WHERE released_at IS NULL;
+CREATE INDEX IF NOT EXISTS pharmacy_drug_batch_allocations_pending_idx ON pharmacy_drug_batch_allocations (pharmacy_drug_id) WHERE pharmacy_drug_batch_id IS NULL AND released_at IS NULL;
+
+-- Stock recorded before batches were tracked has no known lot or expiry date,
+-- it is sold after every dated batch.
+INSERT INTO pharmacy_drug_batches (pharmacy_drug_id, batch_number, stock)
+SELECT pharmacy_drug_id, 'UNTRACKED', stock
+FROM pharmacy_drugs
+WHERE deleted_at IS NULL
+AND stock > 0;
+
+INSERT INTO pharmacy_drug_batch_allocations (pharmacy_drug_id, quantity)
+SELECT pharmacy_drug_id, -stock
+FROM pharmacy_drugs
+WHERE deleted_at IS NULL
+AND stock < 0;
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS low_stock_alerts_manager_idx;
+DROP INDEX IF EXISTS low_stock_alerts_stock_change_id_key;
+DROP TABLE IF EXISTS low_stock_alerts;
+
+DROP INDEX IF EXISTS stock_changes_alert_unchecked_idx;
+ALTER TABLE stock_changes DROP COLUMN IF EXISTS alert_checked_at;
+
+ALTER TABLE pharmacy_drugs DROP COLUMN IF EXISTS reorder_threshold;
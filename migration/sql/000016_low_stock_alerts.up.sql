@@ -0,0 +1,22 @@
+ALTER TABLE pharmacy_drugs ADD COLUMN IF NOT EXISTS reorder_threshold INT CHECK (reorder_threshold >= 0);
+
+-- Stock changes recorded before this migration are not alerted on.
+ALTER TABLE stock_changes ADD COLUMN IF NOT EXISTS alert_checked_at TIMESTAMPTZ DEFAULT NOW();
+ALTER TABLE stock_changes ALTER COLUMN alert_checked_at DROP DEFAULT;
+
+CREATE INDEX IF NOT EXISTS stock_changes_alert_unchecked_idx ON stock_changes (stock_change_id) WHERE alert_checked_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS low_stock_alerts (
+	low_stock_alert_id BIGSERIAL PRIMARY KEY,
+	pharmacy_manager_id BIGINT NOT NULL REFERENCES pharmacy_managers (pharmacy_manager_id),
+	pharmacy_drug_id BIGINT NOT NULL REFERENCES pharmacy_drugs (pharmacy_drug_id),
+	stock_change_id BIGINT NOT NULL REFERENCES stock_changes (stock_change_id),
+	stock INT NOT NULL,
+	reorder_threshold INT NOT NULL,
+	read_at TIMESTAMPTZ,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS low_stock_alerts_stock_change_id_key ON low_stock_alerts (stock_change_id);
+CREATE INDEX IF NOT EXISTS low_stock_alerts_manager_idx ON low_stock_alerts (pharmacy_manager_id, created_at DESC);
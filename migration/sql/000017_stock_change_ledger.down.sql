@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS stock_changes_pharmacy_drug_created_at_idx;
+
+ALTER TABLE stock_changes DROP CONSTRAINT IF EXISTS stock_changes_change_type_check;
+
+ALTER TABLE stock_changes
+	DROP COLUMN IF EXISTS actor_account_id,
+	DROP COLUMN IF EXISTS change_type;
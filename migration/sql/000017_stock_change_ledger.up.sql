@@ -0,0 +1,19 @@
+ALTER TABLE stock_changes
+	ADD COLUMN IF NOT EXISTS change_type VARCHAR,
+	ADD COLUMN IF NOT EXISTS actor_account_id BIGINT REFERENCES accounts (account_id);
+
+-- Older rows only carry a free text description, the change type is read
+-- back from it. Who made those changes was never recorded.
+UPDATE stock_changes
+SET change_type = CASE
+	WHEN description = 'bought by customer' THEN 'sale'
+	WHEN description IN ('transfer from cancelled order', 'restored from expired order') THEN 'cancellation'
+	WHEN description = 'transfer from stock mutation' OR description LIKE 'stock mutation%' THEN 'mutation'
+	ELSE 'manual_adjustment'
+END
+WHERE change_type IS NULL;
+
+ALTER TABLE stock_changes ALTER COLUMN change_type SET NOT NULL;
+ALTER TABLE stock_changes ADD CONSTRAINT stock_changes_change_type_check CHECK (change_type IN ('sale', 'mutation', 'manual_adjustment', 'cancellation'));
+
+CREATE INDEX IF NOT EXISTS stock_changes_pharmacy_drug_created_at_idx ON stock_changes (pharmacy_drug_id, created_at);
@@ -0,0 +1,17 @@
+DROP INDEX IF EXISTS chat_room_extensions_pending_key;
+
+-- Extensions that were never paid for did not extend their room.
+UPDATE chat_room_extensions SET extension_status = 'rejected' WHERE extension_status = 'waiting_for_payment';
+
+ALTER TABLE chat_room_extensions DROP CONSTRAINT IF EXISTS chat_room_extensions_status_check;
+ALTER TABLE chat_room_extensions ADD CONSTRAINT chat_room_extensions_status_check CHECK (extension_status IN ('pending', 'accepted', 'rejected', 'expired'));
+
+CREATE UNIQUE INDEX IF NOT EXISTS chat_room_extensions_pending_key ON chat_room_extensions (chat_room_id) WHERE extension_status = 'pending';
+
+DELETE FROM consultation_payments WHERE chat_room_extension_id IS NOT NULL;
+
+DROP INDEX IF EXISTS consultation_payments_chat_room_extension_id_key;
+DROP INDEX IF EXISTS consultation_payments_chat_room_id_key;
+CREATE UNIQUE INDEX IF NOT EXISTS consultation_payments_chat_room_id_key ON consultation_payments (chat_room_id) WHERE deleted_at IS NULL;
+
+ALTER TABLE consultation_payments DROP COLUMN IF EXISTS chat_room_extension_id;
@@ -0,0 +1,13 @@
+ALTER TABLE consultation_payments ADD COLUMN IF NOT EXISTS chat_room_extension_id BIGINT REFERENCES chat_room_extensions (chat_room_extension_id);
+
+-- A room keeps a single consultation payment, extension fees are paid
+-- through one extra payment per extension.
+DROP INDEX IF EXISTS consultation_payments_chat_room_id_key;
+CREATE UNIQUE INDEX IF NOT EXISTS consultation_payments_chat_room_id_key ON consultation_payments (chat_room_id) WHERE deleted_at IS NULL AND chat_room_extension_id IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS consultation_payments_chat_room_extension_id_key ON consultation_payments (chat_room_extension_id) WHERE deleted_at IS NULL AND chat_room_extension_id IS NOT NULL;
+
+ALTER TABLE chat_room_extensions DROP CONSTRAINT IF EXISTS chat_room_extensions_status_check;
+ALTER TABLE chat_room_extensions ADD CONSTRAINT chat_room_extensions_status_check CHECK (extension_status IN ('pending', 'waiting_for_payment', 'accepted', 'rejected', 'expired'));
+
+DROP INDEX IF EXISTS chat_room_extensions_pending_key;
+CREATE UNIQUE INDEX IF NOT EXISTS chat_room_extensions_pending_key ON chat_room_extensions (chat_room_id) WHERE extension_status IN ('pending', 'waiting_for_payment');
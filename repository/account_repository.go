@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"math"
+	"strconv"
 	// "database/sql"
 
 	"github.com/jackc/pgx/v5"
@@ -20,6 +22,12 @@ type AccountRepository interface {
 	UpdateDataOne(ctx context.Context, account *entity.Account) error
 	UpdateNameAndProfilePictureOne(ctx context.Context, account *entity.Account) error
 	DeleteOneById(ctx context.Context, accountId int64) error
+	FindOneTokenVersionById(ctx context.Context, accountId int64) (*int64, error)
+	IncrementTokenVersionById(ctx context.Context, accountId int64) error
+	FindAll(ctx context.Context, accountFilter entity.AccountFilter) ([]entity.Account, *entity.PageInfo, error)
+	FindOneDetailById(ctx context.Context, accountId int64) (*entity.Account, error)
+	SuspendOneById(ctx context.Context, accountId int64) (int64, error)
+	UnsuspendOneById(ctx context.Context, accountId int64) (int64, error)
 }
 
 type accountRepositoryPostgres struct {
@@ -34,7 +42,7 @@ func NewAccountRepositoryPostgres(db *pgxpool.Pool) accountRepositoryPostgres {
 
 func (r *accountRepositoryPostgres) FindAccountByEmail(ctx context.Context, email string) (*entity.Account, error) {
 	var account entity.Account
-	err := r.db.QueryRow(ctx, database.FindAccountByEmailQuery, email).Scan(&account.Id, &account.Email, &account.Password, &account.RoleId, &account.RoleName, &account.Name, &account.ProfilePicture, &account.VerifiedAt)
+	err := r.db.QueryRow(ctx, database.FindAccountByEmailQuery, email).Scan(&account.Id, &account.Email, &account.Password, &account.RoleId, &account.RoleName, &account.Name, &account.ProfilePicture, &account.VerifiedAt, &account.TokenVersion, &account.SuspendedAt)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil
@@ -133,3 +141,148 @@ func (r *accountRepositoryPostgres) DeleteOneById(ctx context.Context, accountId
 	}
 	return nil
 }
+
+func (r *accountRepositoryPostgres) FindOneTokenVersionById(ctx context.Context, accountId int64) (*int64, error) {
+	var tokenVersion int64
+
+	err := r.db.QueryRow(ctx, database.FindOneAccountTokenVersionByIdQuery, accountId).Scan(&tokenVersion)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &tokenVersion, nil
+}
+
+func (r *accountRepositoryPostgres) IncrementTokenVersionById(ctx context.Context, accountId int64) error {
+	_, err := r.db.Exec(ctx, database.IncrementOneAccountTokenVersionByIdQuery, accountId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *accountRepositoryPostgres) FindAll(ctx context.Context, accountFilter entity.AccountFilter) ([]entity.Account, *entity.PageInfo, error) {
+	query := database.FindAllAccountsQuery
+	args := []interface{}{}
+
+	if accountFilter.Search != "" {
+		query += ` AND (a.email ILIKE $` + strconv.Itoa(len(args)+1) + ` OR a.account_name ILIKE $` + strconv.Itoa(len(args)+1) + `)`
+		args = append(args, "%"+accountFilter.Search+"%")
+	}
+
+	if accountFilter.RoleName != "" {
+		query += ` AND r.role_name = $` + strconv.Itoa(len(args)+1)
+		args = append(args, accountFilter.RoleName)
+	}
+
+	if accountFilter.IsVerified != nil {
+		if *accountFilter.IsVerified {
+			query += ` AND a.verified_at IS NOT NULL`
+		} else {
+			query += ` AND a.verified_at IS NULL`
+		}
+	}
+
+	if accountFilter.IsSuspended != nil {
+		if *accountFilter.IsSuspended {
+			query += ` AND a.suspended_at IS NOT NULL`
+		} else {
+			query += ` AND a.suspended_at IS NULL`
+		}
+	}
+
+	query += ` ORDER BY a.created_at DESC, a.account_id DESC`
+
+	query += ` LIMIT $` + strconv.Itoa(len(args)+1)
+	args = append(args, accountFilter.Limit)
+	query += ` OFFSET $` + strconv.Itoa(len(args)+1)
+	args = append(args, accountFilter.Offset)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	accounts := []entity.Account{}
+	pageInfo := entity.PageInfo{}
+
+	for rows.Next() {
+		var account entity.Account
+
+		err := rows.Scan(
+			&account.Id,
+			&account.Email,
+			&account.Name,
+			&account.RoleId,
+			&account.RoleName,
+			&account.ProfilePicture,
+			&account.VerifiedAt,
+			&account.SuspendedAt,
+			&account.CreatedAt,
+			&pageInfo.ItemCount,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		accounts = append(accounts, account)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	pageInfo.PageCount = int(math.Ceil(float64(pageInfo.ItemCount) / float64(accountFilter.Limit)))
+	pageInfo.Page = int(math.Ceil(float64(accountFilter.Offset+1) / float64(accountFilter.Limit)))
+
+	return accounts, &pageInfo, nil
+}
+
+func (r *accountRepositoryPostgres) FindOneDetailById(ctx context.Context, accountId int64) (*entity.Account, error) {
+	var account entity.Account
+
+	err := r.db.QueryRow(ctx, database.FindOneAccountDetailByIdQuery, accountId).Scan(
+		&account.Id,
+		&account.Email,
+		&account.Name,
+		&account.RoleId,
+		&account.RoleName,
+		&account.ProfilePicture,
+		&account.VerifiedAt,
+		&account.SuspendedAt,
+		&account.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+func (r *accountRepositoryPostgres) SuspendOneById(ctx context.Context, accountId int64) (int64, error) {
+	result, err := r.db.Exec(ctx, database.SuspendOneAccountByIdQuery, accountId)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+func (r *accountRepositoryPostgres) UnsuspendOneById(ctx context.Context, accountId int64) (int64, error) {
+	result, err := r.db.Exec(ctx, database.UnsuspendOneAccountByIdQuery, accountId)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
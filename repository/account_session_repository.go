@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sidiqPratomo/max-health-backend/database"
+	"github.com/sidiqPratomo/max-health-backend/entity"
+)
+
+type AccountSessionRepository interface {
+	CreateOne(ctx context.Context, accountSession entity.AccountSession) (*int64, error)
+	FindAllActiveByAccountId(ctx context.Context, accountId int64) ([]entity.AccountSession, error)
+	UpdateLastSeenOne(ctx context.Context, accountSession entity.AccountSession) error
+	RevokeOneById(ctx context.Context, accountId int64, accountSessionId int64) (int64, error)
+	RevokeAllByAccountId(ctx context.Context, accountId int64) error
+}
+
+type accountSessionRepositoryPostgres struct {
+	db DBTX
+}
+
+func NewAccountSessionRepositoryPostgres(db *pgxpool.Pool) accountSessionRepositoryPostgres {
+	return accountSessionRepositoryPostgres{
+		db: db,
+	}
+}
+
+func (r *accountSessionRepositoryPostgres) CreateOne(ctx context.Context, accountSession entity.AccountSession) (*int64, error) {
+	var accountSessionId int64
+
+	err := r.db.QueryRow(ctx, database.CreateOneAccountSessionQuery, accountSession.AccountId, accountSession.UserAgent, accountSession.IpAddress).Scan(&accountSessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &accountSessionId, nil
+}
+
+func (r *accountSessionRepositoryPostgres) FindAllActiveByAccountId(ctx context.Context, accountId int64) ([]entity.AccountSession, error) {
+	rows, err := r.db.Query(ctx, database.FindAllActiveAccountSessionsByAccountIdQuery, accountId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	accountSessions := []entity.AccountSession{}
+
+	for rows.Next() {
+		var accountSession entity.AccountSession
+
+		err := rows.Scan(&accountSession.Id, &accountSession.AccountId, &accountSession.UserAgent, &accountSession.IpAddress, &accountSession.LastSeenAt, &accountSession.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		accountSessions = append(accountSessions, accountSession)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return accountSessions, nil
+}
+
+func (r *accountSessionRepositoryPostgres) UpdateLastSeenOne(ctx context.Context, accountSession entity.AccountSession) error {
+	_, err := r.db.Exec(ctx, database.UpdateLastSeenOneAccountSessionQuery, accountSession.UserAgent, accountSession.IpAddress, accountSession.Id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *accountSessionRepositoryPostgres) RevokeOneById(ctx context.Context, accountId int64, accountSessionId int64) (int64, error) {
+	result, err := r.db.Exec(ctx, database.RevokeOneAccountSessionQuery, accountSessionId, accountId)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+func (r *accountSessionRepositoryPostgres) RevokeAllByAccountId(ctx context.Context, accountId int64) error {
+	_, err := r.db.Exec(ctx, database.RevokeAllAccountSessionsByAccountIdQuery, accountId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sidiqPratomo/max-health-backend/database"
+	"github.com/sidiqPratomo/max-health-backend/entity"
+)
+
+type AppointmentRepository interface {
+	LockDoctorAppointments(ctx context.Context, doctorAccountId int64) error
+	CountOverlapping(ctx context.Context, doctorAccountId int64, startAt, endAt time.Time) (int, error)
+	FindAllBookedBetween(ctx context.Context, doctorAccountId int64, startAt, endAt time.Time) ([]entity.Appointment, error)
+	CreateOne(ctx context.Context, appointment *entity.Appointment) error
+	FindOneById(ctx context.Context, appointmentId int64) (*entity.Appointment, error)
+	FindOneByIdForUpdate(ctx context.Context, appointmentId int64) (*entity.Appointment, error)
+	FindAll(ctx context.Context, appointmentFilter entity.AppointmentFilter) ([]entity.Appointment, *entity.PageInfo, error)
+	CancelOne(ctx context.Context, appointmentId int64) (int64, error)
+	CancelOneByChatRoomId(ctx context.Context, chatRoomId int64) (int64, error)
+	FindAllDueForReminderForUpdate(ctx context.Context, reminderLead int, limit int) ([]entity.Appointment, error)
+	MarkReminderSent(ctx context.Context, appointmentId int64) error
+}
+
+type appointmentRepositoryPostgres struct {
+	db DBTX
+}
+
+func NewAppointmentRepositoryPostgres(db *pgxpool.Pool) appointmentRepositoryPostgres {
+	return appointmentRepositoryPostgres{
+		db: db,
+	}
+}
+
+func (r *appointmentRepositoryPostgres) LockDoctorAppointments(ctx context.Context, doctorAccountId int64) error {
+	var doctorId int64
+
+	err := r.db.QueryRow(ctx, database.LockDoctorAppointmentsQuery, doctorAccountId).Scan(&doctorId)
+	if err != nil && err != pgx.ErrNoRows {
+		return err
+	}
+
+	return nil
+}
+
+func (r *appointmentRepositoryPostgres) CountOverlapping(ctx context.Context, doctorAccountId int64, startAt, endAt time.Time) (int, error) {
+	var count int
+
+	err := r.db.QueryRow(ctx, database.CountOverlappingAppointmentsQuery, doctorAccountId, startAt, endAt).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *appointmentRepositoryPostgres) FindAllBookedBetween(ctx context.Context, doctorAccountId int64, startAt, endAt time.Time) ([]entity.Appointment, error) {
+	return r.findAll(ctx, database.FindAllBookedAppointmentsBetweenQuery, doctorAccountId, startAt, endAt)
+}
+
+func (r *appointmentRepositoryPostgres) CreateOne(ctx context.Context, appointment *entity.Appointment) error {
+	return r.db.QueryRow(ctx, database.CreateOneAppointmentQuery,
+		appointment.ChatRoomId,
+		appointment.UserAccountId,
+		appointment.DoctorAccountId,
+		appointment.ScheduledAt,
+		appointment.EndAt,
+	).Scan(&appointment.Id, &appointment.Status, &appointment.CreatedAt)
+}
+
+func (r *appointmentRepositoryPostgres) FindOneById(ctx context.Context, appointmentId int64) (*entity.Appointment, error) {
+	return r.findOne(ctx, database.FindOneAppointmentByIdQuery, appointmentId)
+}
+
+func (r *appointmentRepositoryPostgres) FindOneByIdForUpdate(ctx context.Context, appointmentId int64) (*entity.Appointment, error) {
+	return r.findOne(ctx, database.FindOneAppointmentByIdForUpdateQuery, appointmentId)
+}
+
+func (r *appointmentRepositoryPostgres) findOne(ctx context.Context, query string, id int64) (*entity.Appointment, error) {
+	var appointment entity.Appointment
+
+	err := r.db.QueryRow(ctx, query, id).Scan(appointmentScanDest(&appointment)...)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &appointment, nil
+}
+
+func (r *appointmentRepositoryPostgres) findAll(ctx context.Context, query string, args ...interface{}) ([]entity.Appointment, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	appointments := []entity.Appointment{}
+	for rows.Next() {
+		var appointment entity.Appointment
+
+		err := rows.Scan(appointmentScanDest(&appointment)...)
+		if err != nil {
+			return nil, err
+		}
+
+		appointments = append(appointments, appointment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return appointments, nil
+}
+
+func (r *appointmentRepositoryPostgres) FindAll(ctx context.Context, appointmentFilter entity.AppointmentFilter) ([]entity.Appointment, *entity.PageInfo, error) {
+	query := database.FindAllAppointmentsQuery
+	args := []interface{}{}
+
+	query += ` AND (ap.user_account_id = $` + strconv.Itoa(len(args)+1) + ` OR ap.doctor_account_id = $` + strconv.Itoa(len(args)+1) + `)`
+	args = append(args, appointmentFilter.AccountId)
+
+	if appointmentFilter.Status != "" {
+		query += ` AND ap.appointment_status = $` + strconv.Itoa(len(args)+1)
+		args = append(args, appointmentFilter.Status)
+	}
+
+	query += ` ORDER BY ap.scheduled_at DESC, ap.appointment_id DESC`
+
+	query += ` LIMIT $` + strconv.Itoa(len(args)+1)
+	args = append(args, appointmentFilter.Limit)
+	query += ` OFFSET $` + strconv.Itoa(len(args)+1)
+	args = append(args, appointmentFilter.Offset)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	appointments := []entity.Appointment{}
+	pageInfo := entity.PageInfo{}
+
+	for rows.Next() {
+		var appointment entity.Appointment
+
+		err := rows.Scan(append(appointmentScanDest(&appointment), &pageInfo.ItemCount)...)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		appointments = append(appointments, appointment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	pageInfo.PageCount = int(math.Ceil(float64(pageInfo.ItemCount) / float64(appointmentFilter.Limit)))
+	pageInfo.Page = int(math.Ceil(float64(appointmentFilter.Offset+1) / float64(appointmentFilter.Limit)))
+
+	return appointments, &pageInfo, nil
+}
+
+func (r *appointmentRepositoryPostgres) CancelOne(ctx context.Context, appointmentId int64) (int64, error) {
+	result, err := r.db.Exec(ctx, database.CancelOneAppointmentQuery, appointmentId)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+func (r *appointmentRepositoryPostgres) CancelOneByChatRoomId(ctx context.Context, chatRoomId int64) (int64, error) {
+	result, err := r.db.Exec(ctx, database.CancelOneAppointmentByChatRoomIdQuery, chatRoomId)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+func (r *appointmentRepositoryPostgres) FindAllDueForReminderForUpdate(ctx context.Context, reminderLead int, limit int) ([]entity.Appointment, error) {
+	return r.findAll(ctx, database.FindAllAppointmentsDueForReminderForUpdateQuery, reminderLead, limit)
+}
+
+func (r *appointmentRepositoryPostgres) MarkReminderSent(ctx context.Context, appointmentId int64) error {
+	_, err := r.db.Exec(ctx, database.MarkAppointmentReminderSentQuery, appointmentId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func appointmentScanDest(appointment *entity.Appointment) []interface{} {
+	return []interface{}{
+		&appointment.Id,
+		&appointment.ChatRoomId,
+		&appointment.UserAccountId,
+		&appointment.UserName,
+		&appointment.UserEmail,
+		&appointment.DoctorAccountId,
+		&appointment.DoctorName,
+		&appointment.DoctorEmail,
+		&appointment.ScheduledAt,
+		&appointment.EndAt,
+		&appointment.Status,
+		&appointment.ReminderSentAt,
+		&appointment.CancelledAt,
+		&appointment.CreatedAt,
+	}
+}
@@ -10,11 +10,10 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/sidiqPratomo/max-health-backend/database"
 	"github.com/sidiqPratomo/max-health-backend/entity"
-	"github.com/sidiqPratomo/max-health-backend/util"
 )
 
 type CartRepository interface {
-	PostOneCart(ctx context.Context, accountID int64, pharmacyDrugId int64, quantity int) (*int64, error)
+	PostOneCart(ctx context.Context, accountID int64, pharmacyDrugId int64, quantity int, prescriptionDrugId *int64) (*int64, error)
 	UpdateOneCart(ctx context.Context, accountID int64, cartItemID int64, quantity int) error
 	DeleteOneCart(ctx context.Context, accountID int64, cartItemID int64) error
 	GetAllCart(ctx context.Context, accountID int64, Limit string, offset int) ([]entity.CartItemData, *entity.PageInfo, error)
@@ -22,6 +21,7 @@ type CartRepository interface {
 	GetCartsByIds(ctx context.Context, cartItemsIds []int64) ([]entity.CartItem, error)
 	GetStockByCartId(ctx context.Context, cartItemId int64) (*int, error)
 	GetAllCartDetailByIds(ctx context.Context, cartItemIds []int64) ([]entity.CartItemForCheckout, error)
+	GetAllCartDataByIds(ctx context.Context, cartItemIds []int64) ([]entity.CartItemData, error)
 	GetAllCartsForChangesByCartIds(ctx context.Context, cartItems []entity.CartItemForCheckout) ([]entity.CartItemChanges, error)
 	DeleteCarts(ctx context.Context, cartItems []entity.CartItemForCheckout) error
 }
@@ -36,7 +36,7 @@ func NewCartRepositoryPostgres(db *pgxpool.Pool) cartRepositoryPostgres {
 	}
 }
 
-func (r *cartRepositoryPostgres) PostOneCart(ctx context.Context, accountID int64, pharmacyDrugId int64, quantity int) (*int64, error) {
+func (r *cartRepositoryPostgres) PostOneCart(ctx context.Context, accountID int64, pharmacyDrugId int64, quantity int, prescriptionDrugId *int64) (*int64, error) {
 	var userID string
 	err := r.db.QueryRow(ctx, database.CheckUserQuery, accountID).Scan(&userID)
 	if err != nil {
@@ -47,7 +47,7 @@ func (r *cartRepositoryPostgres) PostOneCart(ctx context.Context, accountID int6
 	}
 
 	var cartItemId *int64
-	err = r.db.QueryRow(ctx, database.PostOneCartQuery, userID, pharmacyDrugId, quantity).Scan(&cartItemId)
+	err = r.db.QueryRow(ctx, database.PostOneCartQuery, userID, pharmacyDrugId, quantity, prescriptionDrugId).Scan(&cartItemId)
 	if err != nil {
 		return nil, err
 	}
@@ -132,7 +132,7 @@ func (r *cartRepositoryPostgres) GetAllCart(ctx context.Context, accountID int64
 	for rows.Next() {
 		cart := entity.CartItemData{}
 
-		err := rows.Scan(&cart.CartItemId, &cart.UserId, &cart.PharmacyDrugId, &cart.Quantity, &cart.PharmacyId, &cart.DrugId, &cart.Price, &cart.Stock, &cart.DrugName, &cart.Image, &cart.PharmacyName)
+		err := rows.Scan(&cart.CartItemId, &cart.UserId, &cart.PharmacyDrugId, &cart.Quantity, &cart.PharmacyId, &cart.DrugId, &cart.Price, &cart.Stock, &cart.DrugName, &cart.Image, &cart.PharmacyName, &cart.IsPharmacyOpen)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -221,11 +221,12 @@ func (r *cartRepositoryPostgres) GetPharmacyDeliveryFeeForCart(ctx context.Conte
 			courier.CourierOptions = append(courier.CourierOptions, courierOption)
 		} else {
 			if origin != nil && destination != nil {
-				options, err := util.GetUnofficialDelivery(int64(*origin), int64(*destination), int64(weight), courier.CourierName)
-				if err != nil {
-					return nil, err
+				courier.ShippingRateRequest = &entity.ShippingRateRequest{
+					Origin:      int64(*origin),
+					Destination: int64(*destination),
+					Weight:      int64(weight),
+					Courier:     courier.CourierName,
 				}
-				courier.CourierOptions = options
 			}
 		}
 
@@ -313,7 +314,7 @@ func (r *cartRepositoryPostgres) GetAllCartDetailByIds(ctx context.Context, cart
 
 	for rows.Next() {
 		cartItem := entity.CartItemForCheckout{}
-		err := rows.Scan(&cartItem.Id, &cartItem.DrugId, &cartItem.DrugName, &cartItem.PharmacyDrugId, &cartItem.Price, &cartItem.Unit, &cartItem.Quantity)
+		err := rows.Scan(&cartItem.Id, &cartItem.DrugId, &cartItem.DrugName, &cartItem.PharmacyDrugId, &cartItem.Price, &cartItem.Unit, &cartItem.Quantity, &cartItem.IsPrescriptionRequired, &cartItem.PrescriptionDrugId)
 		if err != nil {
 			return []entity.CartItemForCheckout{}, err
 		}
@@ -322,6 +323,47 @@ func (r *cartRepositoryPostgres) GetAllCartDetailByIds(ctx context.Context, cart
 	return cartItems, nil
 }
 
+func (r *cartRepositoryPostgres) GetAllCartDataByIds(ctx context.Context, cartItemIds []int64) ([]entity.CartItemData, error) {
+	carts := []entity.CartItemData{}
+	if len(cartItemIds) == 0 {
+		return carts, nil
+	}
+
+	query := database.GetAllCartDataByIds
+	args := []interface{}{}
+	query += ` AND (`
+	for i, cartItemId := range cartItemIds {
+		query += `ci.cart_item_id = $` + strconv.Itoa(len(args)+1)
+		args = append(args, cartItemId)
+		if i != len(cartItemIds)-1 {
+			query += ` OR `
+		}
+	}
+	query += `)`
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		cart := entity.CartItemData{}
+
+		err := rows.Scan(&cart.CartItemId, &cart.UserId, &cart.PharmacyDrugId, &cart.Quantity, &cart.PharmacyId, &cart.DrugId, &cart.Price, &cart.Stock, &cart.DrugName, &cart.Image, &cart.PharmacyName)
+		if err != nil {
+			return nil, err
+		}
+		carts = append(carts, cart)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return carts, nil
+}
+
 func (r *cartRepositoryPostgres) GetAllCartsForChangesByCartIds(ctx context.Context, cartItems []entity.CartItemForCheckout) ([]entity.CartItemChanges, error) {
 	cartItemChanges := []entity.CartItemChanges{}
 	query := database.GetAllCartsForChangesByCartIds
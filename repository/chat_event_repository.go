@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sidiqPratomo/max-health-backend/database"
+)
+
+type ChatEventRepository interface {
+	NotifyOne(ctx context.Context, payload string) error
+	ListenAll(ctx context.Context, handle func(payload string)) error
+}
+
+// chatEventRepositoryPostgres keeps the pool itself, because listening holds
+// one connection for as long as the listener runs.
+type chatEventRepositoryPostgres struct {
+	db *pgxpool.Pool
+}
+
+func NewChatEventRepositoryPostgres(db *pgxpool.Pool) chatEventRepositoryPostgres {
+	return chatEventRepositoryPostgres{
+		db: db,
+	}
+}
+
+func (r *chatEventRepositoryPostgres) NotifyOne(ctx context.Context, payload string) error {
+	_, err := r.db.Exec(ctx, database.NotifyChatEventQuery, payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ListenAll hands every chat event payload to handle and only returns once
+// the listening connection is lost or ctx is done.
+func (r *chatEventRepositoryPostgres) ListenAll(ctx context.Context, handle func(payload string)) error {
+	pooledConn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+
+	conn := pooledConn.Hijack()
+	defer conn.Close(context.Background())
+
+	_, err = conn.Exec(ctx, database.ListenChatEventsQuery)
+	if err != nil {
+		return err
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		handle(notification.Payload)
+	}
+}
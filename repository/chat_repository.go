@@ -4,6 +4,7 @@ import (
 	"context"
 	// "database/sql"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/sidiqPratomo/max-health-backend/database"
 	"github.com/sidiqPratomo/max-health-backend/entity"
@@ -12,6 +13,7 @@ import (
 type ChatRepository interface {
 	PostOneChat(ctx context.Context, chatRequest entity.Chat) (*int64, string, error)
 	GetAllChat(ctx context.Context, roomId int64) ([]entity.Chat, error)
+	FindOneChatById(ctx context.Context, chatId int64) (*entity.Chat, error)
 }
 
 type chatRepositoryPostgres struct {
@@ -74,3 +76,27 @@ func (r *chatRepositoryPostgres) GetAllChat(ctx context.Context, roomId int64) (
 
 	return chatList, nil
 }
+
+func (r *chatRepositoryPostgres) FindOneChatById(ctx context.Context, chatId int64) (*entity.Chat, error) {
+	var chat entity.Chat
+
+	err := r.db.QueryRow(ctx, database.FindOneChatByIdQuery, chatId).Scan(
+		&chat.Id,
+		&chat.RoomId,
+		&chat.SenderAccountId,
+		&chat.Message,
+		&chat.Attachment.Format,
+		&chat.Attachment.Url,
+		&chat.Prescription.Id,
+		&chat.Prescription.RedeemedAt,
+		&chat.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &chat, nil
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sidiqPratomo/max-health-backend/database"
+	"github.com/sidiqPratomo/max-health-backend/entity"
+)
+
+type ChatRoomExtensionRepository interface {
+	CreateOne(ctx context.Context, chatRoomExtension *entity.ChatRoomExtension) error
+	FindOneByIdForUpdate(ctx context.Context, chatRoomExtensionId int64) (*entity.ChatRoomExtension, error)
+	FindOnePendingByChatRoomId(ctx context.Context, chatRoomId int64) (*entity.ChatRoomExtension, error)
+	UpdateStatusOne(ctx context.Context, chatRoomExtensionId int64, currentStatus, status string) (*time.Time, error)
+}
+
+type chatRoomExtensionRepositoryPostgres struct {
+	db DBTX
+}
+
+func NewChatRoomExtensionRepositoryPostgres(db *pgxpool.Pool) chatRoomExtensionRepositoryPostgres {
+	return chatRoomExtensionRepositoryPostgres{
+		db: db,
+	}
+}
+
+func (r *chatRoomExtensionRepositoryPostgres) CreateOne(ctx context.Context, chatRoomExtension *entity.ChatRoomExtension) error {
+	return r.db.QueryRow(ctx, database.CreateOneChatRoomExtensionQuery,
+		chatRoomExtension.ChatRoomId,
+		chatRoomExtension.RequestedByAccountId,
+		chatRoomExtension.Minutes,
+		chatRoomExtension.Fee,
+	).Scan(&chatRoomExtension.Id, &chatRoomExtension.Status, &chatRoomExtension.CreatedAt)
+}
+
+func (r *chatRoomExtensionRepositoryPostgres) FindOneByIdForUpdate(ctx context.Context, chatRoomExtensionId int64) (*entity.ChatRoomExtension, error) {
+	return r.findOne(ctx, database.FindOneChatRoomExtensionByIdForUpdateQuery, chatRoomExtensionId)
+}
+
+func (r *chatRoomExtensionRepositoryPostgres) FindOnePendingByChatRoomId(ctx context.Context, chatRoomId int64) (*entity.ChatRoomExtension, error) {
+	return r.findOne(ctx, database.FindOnePendingChatRoomExtensionQuery, chatRoomId)
+}
+
+func (r *chatRoomExtensionRepositoryPostgres) findOne(ctx context.Context, query string, id int64) (*entity.ChatRoomExtension, error) {
+	var chatRoomExtension entity.ChatRoomExtension
+
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&chatRoomExtension.Id,
+		&chatRoomExtension.ChatRoomId,
+		&chatRoomExtension.RequestedByAccountId,
+		&chatRoomExtension.Minutes,
+		&chatRoomExtension.Fee,
+		&chatRoomExtension.Status,
+		&chatRoomExtension.RespondedAt,
+		&chatRoomExtension.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &chatRoomExtension, nil
+}
+
+func (r *chatRoomExtensionRepositoryPostgres) UpdateStatusOne(ctx context.Context, chatRoomExtensionId int64, currentStatus, status string) (*time.Time, error) {
+	var respondedAt time.Time
+
+	err := r.db.QueryRow(ctx, database.UpdateChatRoomExtensionStatusOneQuery, chatRoomExtensionId, status, currentStatus).Scan(&respondedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &respondedAt, nil
+}
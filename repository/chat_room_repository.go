@@ -2,21 +2,28 @@ package repository
 
 import (
 	"context"
-	"database/sql"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/sidiqPratomo/max-health-backend/database"
 	"github.com/sidiqPratomo/max-health-backend/entity"
 )
 
 type ChatRoomRepository interface {
-	CreateOneRoom(ctx context.Context, userAccountId, doctorAccountId int64) (*int64, error)
-	StartChat(ctx context.Context, roomId, doctorAccountId int64) error
+	CreateOneRoom(ctx context.Context, userAccountId, doctorAccountId int64, allowFallback bool, scheduledAt *time.Time) (*int64, error)
+	StartChat(ctx context.Context, roomId, doctorAccountId int64, durationMinutes int) (*time.Time, error)
+	ExtendOne(ctx context.Context, roomId int64, minutes int) (*time.Time, error)
 	FindActiveChatRoom(ctx context.Context, userAccountId, doctorAccountId int64) (*entity.ChatRoom, error)
 	FindChatRoomById(ctx context.Context, chatRoomId int64) (*entity.ChatRoom, error)
 	GetAllChatRoomPreview(ctx context.Context, accountId int64, role string) ([]entity.ChatRoomPreview, error)
 	DoctorGetChatRequest(ctx context.Context, accountId int64) ([]entity.ChatRoomPreview, error)
 	CloseChatRoom(ctx context.Context, roomId int64) error
+	EnqueueOne(ctx context.Context, roomId int64) error
+	ReassignOne(ctx context.Context, roomId, doctorAccountId int64) error
+	CreateOneDecline(ctx context.Context, roomId, doctorAccountId int64, reason string) error
+	GetQueuePosition(ctx context.Context, roomId, doctorAccountId int64) (int, int, error)
+	GetDoctorConsultationHistory(ctx context.Context, doctorAccountId int64, limit int) (*entity.DoctorConsultationHistory, error)
 }
 
 type chatRoomRepositoryPostgres struct {
@@ -29,10 +36,10 @@ func NewChatRoomRepositoryPostgres(db *pgxpool.Pool) chatRoomRepositoryPostgres
 	}
 }
 
-func (r *chatRoomRepositoryPostgres) CreateOneRoom(ctx context.Context, userAccountId, doctorAccountId int64) (*int64, error) {
+func (r *chatRoomRepositoryPostgres) CreateOneRoom(ctx context.Context, userAccountId, doctorAccountId int64, allowFallback bool, scheduledAt *time.Time) (*int64, error) {
 	var chatRoomId int64
 
-	err := r.db.QueryRow(ctx, database.CreateOneRoomQuery, userAccountId, doctorAccountId).Scan(&chatRoomId)
+	err := r.db.QueryRow(ctx, database.CreateOneRoomQuery, userAccountId, doctorAccountId, allowFallback, scheduledAt).Scan(&chatRoomId)
 	if err != nil {
 		return nil, err
 	}
@@ -40,13 +47,27 @@ func (r *chatRoomRepositoryPostgres) CreateOneRoom(ctx context.Context, userAcco
 	return &chatRoomId, nil
 }
 
-func (r *chatRoomRepositoryPostgres) StartChat(ctx context.Context, roomId, doctorAccountId int64) error {
-	_, err := r.db.Exec(ctx, database.StartChatQuery, roomId, doctorAccountId)
+func (r *chatRoomRepositoryPostgres) StartChat(ctx context.Context, roomId, doctorAccountId int64, durationMinutes int) (*time.Time, error) {
+	return r.updateExpiredAt(ctx, database.StartChatQuery, roomId, doctorAccountId, durationMinutes)
+}
+
+func (r *chatRoomRepositoryPostgres) ExtendOne(ctx context.Context, roomId int64, minutes int) (*time.Time, error) {
+	return r.updateExpiredAt(ctx, database.ExtendChatRoomQuery, roomId, minutes)
+}
+
+func (r *chatRoomRepositoryPostgres) updateExpiredAt(ctx context.Context, query string, args ...interface{}) (*time.Time, error) {
+	var expiredAt time.Time
+
+	err := r.db.QueryRow(ctx, query, args...).Scan(&expiredAt)
 	if err != nil {
-		return err
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
 	}
 
-	return nil
+	return &expiredAt, nil
 }
 
 func (r *chatRoomRepositoryPostgres) FindActiveChatRoom(ctx context.Context, userAccountId, doctorAccountId int64) (*entity.ChatRoom, error) {
@@ -54,7 +75,7 @@ func (r *chatRoomRepositoryPostgres) FindActiveChatRoom(ctx context.Context, use
 
 	err := r.db.QueryRow(ctx, database.FindActiveChatRoomQuery, userAccountId, doctorAccountId).Scan(&chatRoom.Id, &chatRoom.UserAccountId, &chatRoom.DoctorAccountId, &chatRoom.ExpiredAt)
 	if err != nil {
-		if err == sql.ErrNoRows {
+		if err == pgx.ErrNoRows {
 			return nil, nil
 		}
 
@@ -67,9 +88,9 @@ func (r *chatRoomRepositoryPostgres) FindActiveChatRoom(ctx context.Context, use
 func (r *chatRoomRepositoryPostgres) FindChatRoomById(ctx context.Context, chatRoomId int64) (*entity.ChatRoom, error) {
 	var chatRoom entity.ChatRoom
 
-	err := r.db.QueryRow(ctx, database.FindChatRoomByIdQuery, chatRoomId).Scan(&chatRoom.UserAccountId, &chatRoom.DoctorAccountId, &chatRoom.ExpiredAt)
+	err := r.db.QueryRow(ctx, database.FindChatRoomByIdQuery, chatRoomId).Scan(&chatRoom.UserAccountId, &chatRoom.DoctorAccountId, &chatRoom.ExpiredAt, &chatRoom.AllowFallback, &chatRoom.QueuedAt, &chatRoom.AssignedAt, &chatRoom.StartedAt, &chatRoom.ScheduledAt)
 	if err != nil {
-		if err == sql.ErrNoRows {
+		if err == pgx.ErrNoRows {
 			return nil, nil
 		}
 
@@ -138,6 +159,7 @@ func (r *chatRoomRepositoryPostgres) DoctorGetChatRequest(ctx context.Context, a
 			&chatRoomPreview.LastChat.Attachment.Format,
 			&chatRoomPreview.LastChat.Attachment.Url,
 			&chatRoomPreview.LastChat.CreatedAt,
+			&chatRoomPreview.QueuePosition,
 		)
 		if err != nil {
 			return nil, err
@@ -162,3 +184,60 @@ func (r *chatRoomRepositoryPostgres) CloseChatRoom(ctx context.Context, roomId i
 
 	return nil
 }
+
+func (r *chatRoomRepositoryPostgres) EnqueueOne(ctx context.Context, roomId int64) error {
+	_, err := r.db.Exec(ctx, database.EnqueueChatRoomQuery, roomId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *chatRoomRepositoryPostgres) ReassignOne(ctx context.Context, roomId, doctorAccountId int64) error {
+	_, err := r.db.Exec(ctx, database.ReassignChatRoomQuery, roomId, doctorAccountId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *chatRoomRepositoryPostgres) CreateOneDecline(ctx context.Context, roomId, doctorAccountId int64, reason string) error {
+	_, err := r.db.Exec(ctx, database.CreateOneChatRoomDeclineQuery, roomId, doctorAccountId, reason)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *chatRoomRepositoryPostgres) GetQueuePosition(ctx context.Context, roomId, doctorAccountId int64) (int, int, error) {
+	var position, queueLength int
+
+	err := r.db.QueryRow(ctx, database.GetChatRoomQueuePositionQuery, roomId, doctorAccountId).Scan(&position, &queueLength)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return 0, 0, nil
+		}
+
+		return 0, 0, err
+	}
+
+	return position, queueLength, nil
+}
+
+func (r *chatRoomRepositoryPostgres) GetDoctorConsultationHistory(ctx context.Context, doctorAccountId int64, limit int) (*entity.DoctorConsultationHistory, error) {
+	var history entity.DoctorConsultationHistory
+	var averagePickupSeconds, averageDurationSeconds float64
+
+	err := r.db.QueryRow(ctx, database.GetDoctorConsultationHistoryQuery, doctorAccountId, limit).Scan(&history.ConsultationCount, &averagePickupSeconds, &averageDurationSeconds)
+	if err != nil {
+		return nil, err
+	}
+
+	history.AveragePickup = time.Duration(averagePickupSeconds * float64(time.Second))
+	history.AverageDuration = time.Duration(averageDurationSeconds * float64(time.Second))
+
+	return &history, nil
+}
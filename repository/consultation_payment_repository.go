@@ -0,0 +1,262 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sidiqPratomo/max-health-backend/database"
+	"github.com/sidiqPratomo/max-health-backend/entity"
+)
+
+type ConsultationPaymentRepository interface {
+	CreateOne(ctx context.Context, consultationPayment entity.ConsultationPayment) (*int64, error)
+	FindOneByChatRoomId(ctx context.Context, chatRoomId int64) (*entity.ConsultationPayment, error)
+	FindOneByChatRoomIdForUpdate(ctx context.Context, chatRoomId int64) (*entity.ConsultationPayment, error)
+	FindAll(ctx context.Context, consultationPaymentFilter entity.ConsultationPaymentFilter) ([]entity.ConsultationPayment, *entity.PageInfo, error)
+	FindAllExpiredForUpdate(ctx context.Context, paymentTimeout, joinTimeout, limit int) ([]entity.ConsultationPayment, error)
+	UpdatePaymentProofOne(ctx context.Context, chatRoomId int64, paymentProof string) (int64, error)
+	ConfirmOne(ctx context.Context, chatRoomId int64) (int64, error)
+	RejectOne(ctx context.Context, chatRoomId int64) (int64, error)
+	CancelOne(ctx context.Context, chatRoomId int64) (int64, error)
+	FindOneByChatRoomExtensionId(ctx context.Context, chatRoomExtensionId int64) (*entity.ConsultationPayment, error)
+	FindOneByChatRoomExtensionIdForUpdate(ctx context.Context, chatRoomExtensionId int64) (*entity.ConsultationPayment, error)
+	UpdatePaymentProofOneByChatRoomExtensionId(ctx context.Context, chatRoomExtensionId int64, paymentProof string) (int64, error)
+	ConfirmOneByChatRoomExtensionId(ctx context.Context, chatRoomExtensionId int64) (int64, error)
+	RejectOneByChatRoomExtensionId(ctx context.Context, chatRoomExtensionId int64) (int64, error)
+	CancelOneByChatRoomExtensionId(ctx context.Context, chatRoomExtensionId int64) (int64, error)
+}
+
+type consultationPaymentRepositoryPostgres struct {
+	db DBTX
+}
+
+func NewConsultationPaymentRepositoryPostgres(db *pgxpool.Pool) consultationPaymentRepositoryPostgres {
+	return consultationPaymentRepositoryPostgres{
+		db: db,
+	}
+}
+
+func (r *consultationPaymentRepositoryPostgres) CreateOne(ctx context.Context, consultationPayment entity.ConsultationPayment) (*int64, error) {
+	var consultationPaymentId int64
+
+	err := r.db.QueryRow(ctx, database.CreateOneConsultationPaymentQuery, consultationPayment.ChatRoomId, consultationPayment.ChatRoomExtensionId, consultationPayment.Amount, consultationPayment.Status).Scan(&consultationPaymentId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &consultationPaymentId, nil
+}
+
+func (r *consultationPaymentRepositoryPostgres) FindOneByChatRoomId(ctx context.Context, chatRoomId int64) (*entity.ConsultationPayment, error) {
+	return r.findOne(ctx, database.FindOneConsultationPaymentByChatRoomIdQuery, chatRoomId)
+}
+
+func (r *consultationPaymentRepositoryPostgres) FindOneByChatRoomIdForUpdate(ctx context.Context, chatRoomId int64) (*entity.ConsultationPayment, error) {
+	return r.findOne(ctx, database.FindOneConsultationPaymentByChatRoomIdForUpdateQuery, chatRoomId)
+}
+
+func (r *consultationPaymentRepositoryPostgres) FindOneByChatRoomExtensionId(ctx context.Context, chatRoomExtensionId int64) (*entity.ConsultationPayment, error) {
+	return r.findOne(ctx, database.FindOneExtensionPaymentByChatRoomExtensionIdQuery, chatRoomExtensionId)
+}
+
+func (r *consultationPaymentRepositoryPostgres) FindOneByChatRoomExtensionIdForUpdate(ctx context.Context, chatRoomExtensionId int64) (*entity.ConsultationPayment, error) {
+	return r.findOne(ctx, database.FindOneExtensionPaymentByChatRoomExtensionIdForUpdateQuery, chatRoomExtensionId)
+}
+
+func (r *consultationPaymentRepositoryPostgres) findOne(ctx context.Context, query string, id int64) (*entity.ConsultationPayment, error) {
+	var consultationPayment entity.ConsultationPayment
+
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&consultationPayment.Id,
+		&consultationPayment.ChatRoomId,
+		&consultationPayment.UserAccountId,
+		&consultationPayment.UserName,
+		&consultationPayment.DoctorAccountId,
+		&consultationPayment.DoctorName,
+		&consultationPayment.ChatRoomExtensionId,
+		&consultationPayment.Amount,
+		&consultationPayment.Status,
+		&consultationPayment.PaymentProof,
+		&consultationPayment.ConfirmedAt,
+		&consultationPayment.CancelledAt,
+		&consultationPayment.RefundedAt,
+		&consultationPayment.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &consultationPayment, nil
+}
+
+func (r *consultationPaymentRepositoryPostgres) FindAll(ctx context.Context, consultationPaymentFilter entity.ConsultationPaymentFilter) ([]entity.ConsultationPayment, *entity.PageInfo, error) {
+	query := database.FindAllConsultationPaymentsQuery
+	args := []interface{}{}
+
+	if consultationPaymentFilter.Status != "" {
+		query += ` AND cp.payment_status = $` + strconv.Itoa(len(args)+1)
+		args = append(args, consultationPaymentFilter.Status)
+	}
+
+	query += ` ORDER BY cp.updated_at DESC, cp.consultation_payment_id DESC`
+
+	query += ` LIMIT $` + strconv.Itoa(len(args)+1)
+	args = append(args, consultationPaymentFilter.Limit)
+	query += ` OFFSET $` + strconv.Itoa(len(args)+1)
+	args = append(args, consultationPaymentFilter.Offset)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	consultationPayments := []entity.ConsultationPayment{}
+	pageInfo := entity.PageInfo{}
+
+	for rows.Next() {
+		var consultationPayment entity.ConsultationPayment
+
+		err := rows.Scan(
+			&consultationPayment.Id,
+			&consultationPayment.ChatRoomId,
+			&consultationPayment.UserAccountId,
+			&consultationPayment.UserName,
+			&consultationPayment.DoctorAccountId,
+			&consultationPayment.DoctorName,
+			&consultationPayment.ChatRoomExtensionId,
+			&consultationPayment.Amount,
+			&consultationPayment.Status,
+			&consultationPayment.PaymentProof,
+			&consultationPayment.ConfirmedAt,
+			&consultationPayment.CancelledAt,
+			&consultationPayment.RefundedAt,
+			&consultationPayment.CreatedAt,
+			&pageInfo.ItemCount,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		consultationPayments = append(consultationPayments, consultationPayment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	pageInfo.PageCount = int(math.Ceil(float64(pageInfo.ItemCount) / float64(consultationPaymentFilter.Limit)))
+	pageInfo.Page = int(math.Ceil(float64(consultationPaymentFilter.Offset+1) / float64(consultationPaymentFilter.Limit)))
+
+	return consultationPayments, &pageInfo, nil
+}
+
+func (r *consultationPaymentRepositoryPostgres) FindAllExpiredForUpdate(ctx context.Context, paymentTimeout, joinTimeout, limit int) ([]entity.ConsultationPayment, error) {
+	rows, err := r.db.Query(ctx, database.FindAllExpiredConsultationPaymentsForUpdateQuery, paymentTimeout, joinTimeout, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	consultationPayments := []entity.ConsultationPayment{}
+
+	for rows.Next() {
+		var consultationPayment entity.ConsultationPayment
+
+		err := rows.Scan(
+			&consultationPayment.Id,
+			&consultationPayment.ChatRoomId,
+			&consultationPayment.UserAccountId,
+			&consultationPayment.UserName,
+			&consultationPayment.DoctorAccountId,
+			&consultationPayment.DoctorName,
+			&consultationPayment.ChatRoomExtensionId,
+			&consultationPayment.Amount,
+			&consultationPayment.Status,
+			&consultationPayment.PaymentProof,
+			&consultationPayment.ConfirmedAt,
+			&consultationPayment.CancelledAt,
+			&consultationPayment.RefundedAt,
+			&consultationPayment.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		consultationPayments = append(consultationPayments, consultationPayment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return consultationPayments, nil
+}
+
+func (r *consultationPaymentRepositoryPostgres) UpdatePaymentProofOne(ctx context.Context, chatRoomId int64, paymentProof string) (int64, error) {
+	result, err := r.db.Exec(ctx, database.UpdateConsultationPaymentProofOneQuery, chatRoomId, paymentProof)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+func (r *consultationPaymentRepositoryPostgres) ConfirmOne(ctx context.Context, chatRoomId int64) (int64, error) {
+	result, err := r.db.Exec(ctx, database.ConfirmConsultationPaymentOneQuery, chatRoomId)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+func (r *consultationPaymentRepositoryPostgres) RejectOne(ctx context.Context, chatRoomId int64) (int64, error) {
+	result, err := r.db.Exec(ctx, database.RejectConsultationPaymentOneQuery, chatRoomId)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+func (r *consultationPaymentRepositoryPostgres) CancelOne(ctx context.Context, chatRoomId int64) (int64, error) {
+	result, err := r.db.Exec(ctx, database.CancelConsultationPaymentOneQuery, chatRoomId)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+func (r *consultationPaymentRepositoryPostgres) UpdatePaymentProofOneByChatRoomExtensionId(ctx context.Context, chatRoomExtensionId int64, paymentProof string) (int64, error) {
+	return r.exec(ctx, database.UpdateExtensionPaymentProofOneQuery, chatRoomExtensionId, paymentProof)
+}
+
+func (r *consultationPaymentRepositoryPostgres) ConfirmOneByChatRoomExtensionId(ctx context.Context, chatRoomExtensionId int64) (int64, error) {
+	return r.exec(ctx, database.ConfirmExtensionPaymentOneQuery, chatRoomExtensionId)
+}
+
+func (r *consultationPaymentRepositoryPostgres) RejectOneByChatRoomExtensionId(ctx context.Context, chatRoomExtensionId int64) (int64, error) {
+	return r.exec(ctx, database.RejectExtensionPaymentOneQuery, chatRoomExtensionId)
+}
+
+func (r *consultationPaymentRepositoryPostgres) CancelOneByChatRoomExtensionId(ctx context.Context, chatRoomExtensionId int64) (int64, error) {
+	return r.exec(ctx, database.CancelExtensionPaymentOneQuery, chatRoomExtensionId)
+}
+
+func (r *consultationPaymentRepositoryPostgres) exec(ctx context.Context, query string, args ...interface{}) (int64, error) {
+	result, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
@@ -6,7 +6,9 @@ import (
 	"math"
 	"strconv"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
 	"github.com/sidiqPratomo/max-health-backend/database"
 	"github.com/sidiqPratomo/max-health-backend/entity"
 )
@@ -20,6 +22,7 @@ type DoctorRepository interface {
 	FindDoctorByDoctorId(ctx context.Context, doctorId int64) (*entity.DetailedDoctor, error)
 	UpdateDoctorStatus(ctx context.Context, doctorAccountId int64, isOnline bool) error
 	GetDoctorIsOnline(ctx context.Context, doctorAccountId int64) (*bool, error)
+	FindFallbackDoctorAccountId(ctx context.Context, chatRoom entity.ChatRoom, maxFee decimal.Decimal) (*int64, error)
 }
 
 type doctorRepositoryPostgres struct {
@@ -42,7 +45,7 @@ func (r *doctorRepositoryPostgres) PostOneDoctor(ctx context.Context, accountId
 }
 
 func (r *doctorRepositoryPostgres) UpdateDataOne(ctx context.Context, doctor *entity.DetailedDoctor) error {
-	_, err := r.db.Exec(ctx, database.UpdateOneDoctorQuery, doctor.FeePerPatient, doctor.Experience, doctor.Id)
+	_, err := r.db.Exec(ctx, database.UpdateOneDoctorQuery, doctor.FeePerPatient, doctor.Experience, doctor.Id, doctor.ConsultationDuration)
 	if err != nil {
 		return err
 	}
@@ -97,7 +100,7 @@ func (r *doctorRepositoryPostgres) GetAllDoctor(ctx context.Context, Sort []stri
 	}
 
 	queries := `
-		SELECT d.doctor_id, d.account_id, d.certificate, d.fee_per_patient, d.is_online, d.experience, d.specialization_id 
+		SELECT d.doctor_id, d.account_id, d.certificate, d.fee_per_patient, d.is_online, d.experience, d.specialization_id, d.rating, d.review_count
 		FROM doctors d
 		JOIN accounts a ON a.account_id = d.account_id
 		` + whereClause + `
@@ -119,7 +122,7 @@ func (r *doctorRepositoryPostgres) GetAllDoctor(ctx context.Context, Sort []stri
 	for rows.Next() {
 		doctor := entity.Doctor{}
 
-		err := rows.Scan(&doctor.Id, &doctor.AccountId, &doctor.Certificate, &doctor.FeePerPatient, &doctor.IsOnline, &doctor.Experience, &doctor.SpecializationId)
+		err := rows.Scan(&doctor.Id, &doctor.AccountId, &doctor.Certificate, &doctor.FeePerPatient, &doctor.IsOnline, &doctor.Experience, &doctor.SpecializationId, &doctor.Rating, &doctor.ReviewCount)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -151,7 +154,8 @@ func (r *doctorRepositoryPostgres) FindDoctorByAccountId(ctx context.Context, ac
 	var doctor entity.Doctor
 
 	if err := r.db.QueryRow(ctx, database.FindDoctorByAccountIdQuery, accountId).Scan(&doctor.Id, &doctor.Experience, &doctor.SpecializationId,
-		&doctor.SpecializationName, &doctor.FeePerPatient, &doctor.Certificate); err != nil {
+		&doctor.SpecializationName, &doctor.FeePerPatient, &doctor.Certificate, &doctor.ConsultationDuration,
+		&doctor.Rating, &doctor.ReviewCount); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
@@ -166,7 +170,7 @@ func (r *doctorRepositoryPostgres) FindDoctorByDoctorId(ctx context.Context, doc
 
 	if err := r.db.QueryRow(ctx, database.FindDoctorByDoctorIdQuery, doctorId).Scan(&doctor.Id, &doctor.Email,
 		&doctor.Name, &doctor.ProfilePicture, &doctor.Experience, &doctor.SpecializationId,
-		&doctor.SpecializationName, &doctor.FeePerPatient); err != nil {
+		&doctor.SpecializationName, &doctor.FeePerPatient, &doctor.ConsultationDuration, &doctor.Rating, &doctor.ReviewCount); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
@@ -194,3 +198,18 @@ func (r *doctorRepositoryPostgres) GetDoctorIsOnline(ctx context.Context, doctor
 
 	return &isOnline, nil
 }
+
+func (r *doctorRepositoryPostgres) FindFallbackDoctorAccountId(ctx context.Context, chatRoom entity.ChatRoom, maxFee decimal.Decimal) (*int64, error) {
+	var doctorAccountId int64
+
+	err := r.db.QueryRow(ctx, database.FindFallbackDoctorAccountIdQuery, chatRoom.Id, chatRoom.DoctorAccountId, chatRoom.UserAccountId, maxFee).Scan(&doctorAccountId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &doctorAccountId, nil
+}
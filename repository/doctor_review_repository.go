@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sidiqPratomo/max-health-backend/database"
+	"github.com/sidiqPratomo/max-health-backend/entity"
+)
+
+type DoctorReviewRepository interface {
+	CreateOne(ctx context.Context, doctorReview *entity.DoctorReview) error
+	FindOneById(ctx context.Context, doctorReviewId int64) (*entity.DoctorReview, error)
+	FindOneByChatRoomId(ctx context.Context, chatRoomId int64) (*entity.DoctorReview, error)
+	FindAll(ctx context.Context, doctorReviewFilter entity.DoctorReviewFilter) ([]entity.DoctorReview, *entity.PageInfo, error)
+	UpdateReplyOne(ctx context.Context, doctorReviewId int64, reply string) (int64, error)
+	UpdateVisibilityOne(ctx context.Context, doctorReviewId int64, isHidden bool, hiddenReason string) (int64, error)
+	LockDoctorRating(ctx context.Context, doctorAccountId int64) error
+	RefreshDoctorRating(ctx context.Context, doctorAccountId int64) error
+}
+
+type doctorReviewRepositoryPostgres struct {
+	db DBTX
+}
+
+func NewDoctorReviewRepositoryPostgres(db *pgxpool.Pool) doctorReviewRepositoryPostgres {
+	return doctorReviewRepositoryPostgres{
+		db: db,
+	}
+}
+
+func (r *doctorReviewRepositoryPostgres) CreateOne(ctx context.Context, doctorReview *entity.DoctorReview) error {
+	return r.db.QueryRow(ctx, database.CreateOneDoctorReviewQuery,
+		doctorReview.ChatRoomId,
+		doctorReview.UserAccountId,
+		doctorReview.DoctorAccountId,
+		doctorReview.Rating,
+		doctorReview.Review,
+	).Scan(&doctorReview.Id, &doctorReview.CreatedAt)
+}
+
+func (r *doctorReviewRepositoryPostgres) FindOneById(ctx context.Context, doctorReviewId int64) (*entity.DoctorReview, error) {
+	return r.findOne(ctx, database.FindOneDoctorReviewByIdQuery, doctorReviewId)
+}
+
+func (r *doctorReviewRepositoryPostgres) FindOneByChatRoomId(ctx context.Context, chatRoomId int64) (*entity.DoctorReview, error) {
+	return r.findOne(ctx, database.FindOneDoctorReviewByChatRoomIdQuery, chatRoomId)
+}
+
+func (r *doctorReviewRepositoryPostgres) findOne(ctx context.Context, query string, id int64) (*entity.DoctorReview, error) {
+	var doctorReview entity.DoctorReview
+
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&doctorReview.Id,
+		&doctorReview.ChatRoomId,
+		&doctorReview.UserAccountId,
+		&doctorReview.UserName,
+		&doctorReview.DoctorAccountId,
+		&doctorReview.DoctorName,
+		&doctorReview.Rating,
+		&doctorReview.Review,
+		&doctorReview.Reply,
+		&doctorReview.RepliedAt,
+		&doctorReview.HiddenAt,
+		&doctorReview.HiddenReason,
+		&doctorReview.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &doctorReview, nil
+}
+
+func (r *doctorReviewRepositoryPostgres) FindAll(ctx context.Context, doctorReviewFilter entity.DoctorReviewFilter) ([]entity.DoctorReview, *entity.PageInfo, error) {
+	query := database.FindAllDoctorReviewsQuery
+	args := []interface{}{}
+
+	if doctorReviewFilter.DoctorId != nil {
+		query += ` AND dr.doctor_account_id = (SELECT account_id FROM doctors WHERE doctor_id = $` + strconv.Itoa(len(args)+1) + `)`
+		args = append(args, *doctorReviewFilter.DoctorId)
+	}
+
+	if doctorReviewFilter.IsHidden != nil {
+		if *doctorReviewFilter.IsHidden {
+			query += ` AND dr.hidden_at IS NOT NULL`
+		} else {
+			query += ` AND dr.hidden_at IS NULL`
+		}
+	}
+
+	query += ` ORDER BY dr.created_at DESC, dr.doctor_review_id DESC`
+
+	query += ` LIMIT $` + strconv.Itoa(len(args)+1)
+	args = append(args, doctorReviewFilter.Limit)
+	query += ` OFFSET $` + strconv.Itoa(len(args)+1)
+	args = append(args, doctorReviewFilter.Offset)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	doctorReviews := []entity.DoctorReview{}
+	pageInfo := entity.PageInfo{}
+
+	for rows.Next() {
+		var doctorReview entity.DoctorReview
+
+		err := rows.Scan(
+			&doctorReview.Id,
+			&doctorReview.ChatRoomId,
+			&doctorReview.UserAccountId,
+			&doctorReview.UserName,
+			&doctorReview.DoctorAccountId,
+			&doctorReview.DoctorName,
+			&doctorReview.Rating,
+			&doctorReview.Review,
+			&doctorReview.Reply,
+			&doctorReview.RepliedAt,
+			&doctorReview.HiddenAt,
+			&doctorReview.HiddenReason,
+			&doctorReview.CreatedAt,
+			&pageInfo.ItemCount,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		doctorReviews = append(doctorReviews, doctorReview)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	pageInfo.PageCount = int(math.Ceil(float64(pageInfo.ItemCount) / float64(doctorReviewFilter.Limit)))
+	pageInfo.Page = int(math.Ceil(float64(doctorReviewFilter.Offset+1) / float64(doctorReviewFilter.Limit)))
+
+	return doctorReviews, &pageInfo, nil
+}
+
+func (r *doctorReviewRepositoryPostgres) UpdateReplyOne(ctx context.Context, doctorReviewId int64, reply string) (int64, error) {
+	result, err := r.db.Exec(ctx, database.UpdateDoctorReviewReplyOneQuery, doctorReviewId, reply)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+func (r *doctorReviewRepositoryPostgres) UpdateVisibilityOne(ctx context.Context, doctorReviewId int64, isHidden bool, hiddenReason string) (int64, error) {
+	result, err := r.db.Exec(ctx, database.UpdateDoctorReviewVisibilityOneQuery, doctorReviewId, isHidden, hiddenReason)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+func (r *doctorReviewRepositoryPostgres) LockDoctorRating(ctx context.Context, doctorAccountId int64) error {
+	var doctorId int64
+
+	err := r.db.QueryRow(ctx, database.LockDoctorRatingQuery, doctorAccountId).Scan(&doctorId)
+	if err != nil && err != pgx.ErrNoRows {
+		return err
+	}
+
+	return nil
+}
+
+func (r *doctorReviewRepositoryPostgres) RefreshDoctorRating(ctx context.Context, doctorAccountId int64) error {
+	_, err := r.db.Exec(ctx, database.RefreshDoctorRatingQuery, doctorAccountId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sidiqPratomo/max-health-backend/database"
+	"github.com/sidiqPratomo/max-health-backend/entity"
+)
+
+type DoctorScheduleRepository interface {
+	FindOneByAccountId(ctx context.Context, doctorAccountId int64, from time.Time) (*entity.DoctorSchedule, error)
+	FindOneByDoctorId(ctx context.Context, doctorId int64, from time.Time) (*entity.DoctorSchedule, error)
+	UpdateSettingsOne(ctx context.Context, doctorAccountId int64, timezone string, useScheduleAvailability bool) (int64, error)
+	ReplaceSlots(ctx context.Context, doctorAccountId int64, doctorScheduleSlots []entity.DoctorScheduleSlot) error
+	CreateOneException(ctx context.Context, doctorScheduleException entity.DoctorScheduleException) (*int64, error)
+	DeleteOneException(ctx context.Context, doctorAccountId int64, doctorScheduleExceptionId int64) (int64, error)
+	SyncAvailability(ctx context.Context) (int64, error)
+}
+
+type doctorScheduleRepositoryPostgres struct {
+	db DBTX
+}
+
+func NewDoctorScheduleRepositoryPostgres(db *pgxpool.Pool) doctorScheduleRepositoryPostgres {
+	return doctorScheduleRepositoryPostgres{
+		db: db,
+	}
+}
+
+func (r *doctorScheduleRepositoryPostgres) FindOneByAccountId(ctx context.Context, doctorAccountId int64, from time.Time) (*entity.DoctorSchedule, error) {
+	doctorSchedule := entity.DoctorSchedule{DoctorAccountId: doctorAccountId}
+
+	err := r.db.QueryRow(ctx, database.FindDoctorScheduleSettingsByAccountIdQuery, doctorAccountId).Scan(&doctorSchedule.Timezone, &doctorSchedule.UseScheduleAvailability)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return r.withSlotsAndExceptions(ctx, doctorSchedule, from)
+}
+
+func (r *doctorScheduleRepositoryPostgres) FindOneByDoctorId(ctx context.Context, doctorId int64, from time.Time) (*entity.DoctorSchedule, error) {
+	doctorSchedule := entity.DoctorSchedule{}
+
+	err := r.db.QueryRow(ctx, database.FindDoctorScheduleSettingsByDoctorIdQuery, doctorId).Scan(&doctorSchedule.DoctorAccountId, &doctorSchedule.Timezone, &doctorSchedule.UseScheduleAvailability)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return r.withSlotsAndExceptions(ctx, doctorSchedule, from)
+}
+
+func (r *doctorScheduleRepositoryPostgres) withSlotsAndExceptions(ctx context.Context, doctorSchedule entity.DoctorSchedule, from time.Time) (*entity.DoctorSchedule, error) {
+	rows, err := r.db.Query(ctx, database.FindAllDoctorSchedulesByAccountIdQuery, doctorSchedule.DoctorAccountId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	doctorSchedule.Slots = []entity.DoctorScheduleSlot{}
+	for rows.Next() {
+		var doctorScheduleSlot entity.DoctorScheduleSlot
+
+		err := rows.Scan(&doctorScheduleSlot.Id, &doctorScheduleSlot.DoctorAccountId, &doctorScheduleSlot.Day, &doctorScheduleSlot.StartHour, &doctorScheduleSlot.EndHour)
+		if err != nil {
+			return nil, err
+		}
+
+		doctorSchedule.Slots = append(doctorSchedule.Slots, doctorScheduleSlot)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	exceptionRows, err := r.db.Query(ctx, database.FindAllUpcomingDoctorScheduleExceptionsByAccountIdQuery, doctorSchedule.DoctorAccountId, from.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	defer exceptionRows.Close()
+
+	doctorSchedule.Exceptions = []entity.DoctorScheduleException{}
+	for exceptionRows.Next() {
+		var doctorScheduleException entity.DoctorScheduleException
+
+		err := exceptionRows.Scan(&doctorScheduleException.Id, &doctorScheduleException.DoctorAccountId, &doctorScheduleException.Date, &doctorScheduleException.IsAvailable, &doctorScheduleException.StartHour, &doctorScheduleException.EndHour, &doctorScheduleException.Description)
+		if err != nil {
+			return nil, err
+		}
+
+		doctorSchedule.Exceptions = append(doctorSchedule.Exceptions, doctorScheduleException)
+	}
+
+	if err = exceptionRows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &doctorSchedule, nil
+}
+
+func (r *doctorScheduleRepositoryPostgres) UpdateSettingsOne(ctx context.Context, doctorAccountId int64, timezone string, useScheduleAvailability bool) (int64, error) {
+	result, err := r.db.Exec(ctx, database.UpdateDoctorScheduleSettingsQuery, doctorAccountId, timezone, useScheduleAvailability)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+func (r *doctorScheduleRepositoryPostgres) ReplaceSlots(ctx context.Context, doctorAccountId int64, doctorScheduleSlots []entity.DoctorScheduleSlot) error {
+	_, err := r.db.Exec(ctx, database.DeleteAllDoctorSchedulesByAccountIdQuery, doctorAccountId)
+	if err != nil {
+		return err
+	}
+
+	for _, doctorScheduleSlot := range doctorScheduleSlots {
+		_, err = r.db.Exec(ctx, database.CreateOneDoctorScheduleQuery, doctorAccountId, doctorScheduleSlot.Day, doctorScheduleSlot.StartHour, doctorScheduleSlot.EndHour)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *doctorScheduleRepositoryPostgres) CreateOneException(ctx context.Context, doctorScheduleException entity.DoctorScheduleException) (*int64, error) {
+	var doctorScheduleExceptionId int64
+
+	err := r.db.QueryRow(ctx, database.CreateOneDoctorScheduleExceptionQuery, doctorScheduleException.DoctorAccountId, doctorScheduleException.Date.Format("2006-01-02"), doctorScheduleException.IsAvailable, doctorScheduleException.StartHour, doctorScheduleException.EndHour, doctorScheduleException.Description).Scan(&doctorScheduleExceptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &doctorScheduleExceptionId, nil
+}
+
+func (r *doctorScheduleRepositoryPostgres) DeleteOneException(ctx context.Context, doctorAccountId int64, doctorScheduleExceptionId int64) (int64, error) {
+	result, err := r.db.Exec(ctx, database.DeleteOneDoctorScheduleExceptionQuery, doctorScheduleExceptionId, doctorAccountId)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+func (r *doctorScheduleRepositoryPostgres) SyncAvailability(ctx context.Context) (int64, error) {
+	result, err := r.db.Exec(ctx, database.SyncDoctorScheduleAvailabilityQuery)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
@@ -10,6 +10,7 @@ import (
 
 type DoctorSpecializationRepository interface {
 	GetAllDoctorSpecialization(ctx context.Context) ([]entity.DoctorSpecialization, error)
+	UpdateConsultationDurationOne(ctx context.Context, specializationId int64, consultationDuration *int) (int64, error)
 }
 
 type doctorSpecializationRepositoryPostgres struct {
@@ -34,7 +35,7 @@ func (r *doctorSpecializationRepositoryPostgres) GetAllDoctorSpecialization(ctx
 	for rows.Next() {
 		var specialization entity.DoctorSpecialization
 
-		err := rows.Scan(&specialization.Id, &specialization.Name)
+		err := rows.Scan(&specialization.Id, &specialization.Name, &specialization.ConsultationDuration)
 		if err != nil {
 			return nil, err
 		}
@@ -49,3 +50,12 @@ func (r *doctorSpecializationRepositoryPostgres) GetAllDoctorSpecialization(ctx
 
 	return specializationList, nil
 }
+
+func (r *doctorSpecializationRepositoryPostgres) UpdateConsultationDurationOne(ctx context.Context, specializationId int64, consultationDuration *int) (int64, error) {
+	result, err := r.db.Exec(ctx, database.UpdateDoctorSpecializationConsultationDurationQuery, specializationId, consultationDuration)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
@@ -24,6 +24,10 @@ type DrugRepository interface {
 	CreateOneDrug(ctx context.Context, drug entity.Drug) error
 	DeleteOneDrug(ctx context.Context, drugId int64) error
 	GetDrugsByPharmacyId(ctx context.Context, pharmacyId int64, Limit string, offset int, search string) ([]entity.PharmacyDrugByPharmacyId, *entity.PageInfo, error)
+	SuggestDrugs(ctx context.Context, search string, limit int) ([]entity.DrugSuggestion, error)
+	FindSearchCorrections(ctx context.Context, words []string) ([]entity.DrugSearchCorrection, error)
+	RefreshSearchTerms(ctx context.Context) error
+	GetDrugsByIdsOrNames(ctx context.Context, drugIds []int64, drugNames []string) ([]entity.Drug, error)
 }
 
 type drugRepositoryPostgres struct {
@@ -363,3 +367,93 @@ func (r *drugRepositoryPostgres) GetDrugsByPharmacyId(ctx context.Context, pharm
 
 	return drugs, pageInfo, nil
 }
+
+func (r *drugRepositoryPostgres) SuggestDrugs(ctx context.Context, search string, limit int) ([]entity.DrugSuggestion, error) {
+	rows, err := r.db.Query(ctx, database.SuggestDrugsQuery, search, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	drugSuggestions := []entity.DrugSuggestion{}
+
+	for rows.Next() {
+		var drugSuggestion entity.DrugSuggestion
+
+		err := rows.Scan(&drugSuggestion.Id, &drugSuggestion.Name, &drugSuggestion.GenericName)
+		if err != nil {
+			return nil, err
+		}
+
+		drugSuggestions = append(drugSuggestions, drugSuggestion)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return drugSuggestions, nil
+}
+
+func (r *drugRepositoryPostgres) FindSearchCorrections(ctx context.Context, words []string) ([]entity.DrugSearchCorrection, error) {
+	rows, err := r.db.Query(ctx, database.FindDrugSearchCorrectionsQuery, words)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	drugSearchCorrections := []entity.DrugSearchCorrection{}
+
+	for rows.Next() {
+		var drugSearchCorrection entity.DrugSearchCorrection
+
+		err := rows.Scan(&drugSearchCorrection.Word, &drugSearchCorrection.Term)
+		if err != nil {
+			return nil, err
+		}
+
+		drugSearchCorrections = append(drugSearchCorrections, drugSearchCorrection)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return drugSearchCorrections, nil
+}
+
+func (r *drugRepositoryPostgres) RefreshSearchTerms(ctx context.Context) error {
+	_, err := r.db.Exec(ctx, database.RefreshDrugSearchTermsQuery)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Names are matched case insensitively, drugNames are expected in lower case.
+func (r *drugRepositoryPostgres) GetDrugsByIdsOrNames(ctx context.Context, drugIds []int64, drugNames []string) ([]entity.Drug, error) {
+	rows, err := r.db.Query(ctx, database.GetDrugsByIdsOrNamesQuery, drugIds, drugNames)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	drugs := []entity.Drug{}
+	for rows.Next() {
+		var drug entity.Drug
+
+		err := rows.Scan(&drug.Id, &drug.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		drugs = append(drugs, drug)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return drugs, nil
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sidiqPratomo/max-health-backend/database"
+	"github.com/sidiqPratomo/max-health-backend/entity"
+)
+
+type LowStockAlertRepository interface {
+	CreateOne(ctx context.Context, stockChange entity.ReorderThresholdStockChange) (*int64, error)
+	FindAllByIds(ctx context.Context, lowStockAlertIds []int64) ([]entity.LowStockAlert, error)
+	FindAll(ctx context.Context, lowStockAlertFilter entity.LowStockAlertFilter) ([]entity.LowStockAlert, *entity.PageInfo, error)
+	MarkReadOne(ctx context.Context, lowStockAlertId, managerId int64) (int64, error)
+}
+
+type lowStockAlertRepositoryPostgres struct {
+	db DBTX
+}
+
+func NewLowStockAlertRepositoryPostgres(db *pgxpool.Pool) lowStockAlertRepositoryPostgres {
+	return lowStockAlertRepositoryPostgres{
+		db: db,
+	}
+}
+
+func lowStockAlertScanDest(lowStockAlert *entity.LowStockAlert) []interface{} {
+	return []interface{}{
+		&lowStockAlert.Id,
+		&lowStockAlert.PharmacyManagerId,
+		&lowStockAlert.ManagerName,
+		&lowStockAlert.ManagerEmail,
+		&lowStockAlert.PharmacyDrugId,
+		&lowStockAlert.PharmacyId,
+		&lowStockAlert.PharmacyName,
+		&lowStockAlert.DrugId,
+		&lowStockAlert.DrugName,
+		&lowStockAlert.StockChangeId,
+		&lowStockAlert.Stock,
+		&lowStockAlert.ReorderThreshold,
+		&lowStockAlert.ReadAt,
+		&lowStockAlert.CreatedAt,
+	}
+}
+
+// A stock change that already raised an alert is skipped, nil is returned
+// instead of an id.
+func (r *lowStockAlertRepositoryPostgres) CreateOne(ctx context.Context, stockChange entity.ReorderThresholdStockChange) (*int64, error) {
+	var lowStockAlertId int64
+
+	err := r.db.QueryRow(ctx, database.CreateLowStockAlertQuery,
+		stockChange.PharmacyDrugId,
+		stockChange.Id,
+		stockChange.FinalStock,
+		stockChange.ReorderThreshold,
+	).Scan(&lowStockAlertId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &lowStockAlertId, nil
+}
+
+func (r *lowStockAlertRepositoryPostgres) FindAllByIds(ctx context.Context, lowStockAlertIds []int64) ([]entity.LowStockAlert, error) {
+	lowStockAlerts := []entity.LowStockAlert{}
+	if len(lowStockAlertIds) == 0 {
+		return lowStockAlerts, nil
+	}
+
+	rows, err := r.db.Query(ctx, database.FindAllLowStockAlertsByIdsQuery, lowStockAlertIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var lowStockAlert entity.LowStockAlert
+
+		err := rows.Scan(lowStockAlertScanDest(&lowStockAlert)...)
+		if err != nil {
+			return nil, err
+		}
+
+		lowStockAlerts = append(lowStockAlerts, lowStockAlert)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return lowStockAlerts, nil
+}
+
+func (r *lowStockAlertRepositoryPostgres) FindAll(ctx context.Context, lowStockAlertFilter entity.LowStockAlertFilter) ([]entity.LowStockAlert, *entity.PageInfo, error) {
+	query := database.FindAllLowStockAlertsQuery
+	args := []interface{}{lowStockAlertFilter.ManagerId}
+
+	if lowStockAlertFilter.IsUnread {
+		query += ` AND la.read_at IS NULL`
+	}
+
+	query += ` ORDER BY la.created_at DESC, la.low_stock_alert_id DESC`
+
+	query += ` LIMIT $` + strconv.Itoa(len(args)+1)
+	args = append(args, lowStockAlertFilter.Limit)
+	query += ` OFFSET $` + strconv.Itoa(len(args)+1)
+	args = append(args, lowStockAlertFilter.Offset)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	lowStockAlerts := []entity.LowStockAlert{}
+	pageInfo := entity.PageInfo{}
+
+	for rows.Next() {
+		var lowStockAlert entity.LowStockAlert
+
+		dest := append(lowStockAlertScanDest(&lowStockAlert), &pageInfo.ItemCount)
+
+		err := rows.Scan(dest...)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		lowStockAlerts = append(lowStockAlerts, lowStockAlert)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	pageInfo.PageCount = int(math.Ceil(float64(pageInfo.ItemCount) / float64(lowStockAlertFilter.Limit)))
+	pageInfo.Page = int(math.Ceil(float64(lowStockAlertFilter.Offset+1) / float64(lowStockAlertFilter.Limit)))
+
+	return lowStockAlerts, &pageInfo, nil
+}
+
+func (r *lowStockAlertRepositoryPostgres) MarkReadOne(ctx context.Context, lowStockAlertId, managerId int64) (int64, error) {
+	result, err := r.db.Exec(ctx, database.MarkLowStockAlertReadQuery, lowStockAlertId, managerId)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
@@ -19,7 +19,9 @@ import (
 type OrderPharmacyRepository interface {
 	PostOrderPharmacies(ctx context.Context, orderId int64, orderCheckoutRequest dto.OrderCheckoutRequest) ([]entity.OrderPharmacyForCheckout, error)
 	FindAllByOrderId(ctx context.Context, orderId int64) ([]entity.OrderPharmacy, error)
+	FindAllByOrderIdForUpdate(ctx context.Context, orderId int64) ([]entity.OrderPharmacy, error)
 	UpdateStatusBulkByOrderId(ctx context.Context, orderId int64, newOrderStatusId int64) error
+	UpdateStatusBulkByOrderIdAndStatusId(ctx context.Context, orderId int64, currentOrderStatusId int64, newOrderStatusId int64) (int64, error)
 	FindAllOngoingIdsByPharmacyId(ctx context.Context, pharmacyId int64) ([]int64, error)
 	FindOneById(ctx context.Context, id int64) (*entity.OrderPharmacy, error)
 	FindAllByOrderUserId(ctx context.Context, userId int64, validatedGetOrderQuery util.ValidatedGetOrderQuery) ([]entity.OrderPharmacy, *entity.PageInfo, error)
@@ -74,8 +76,14 @@ func (r *orderPharmacyRepositoryPostgres) PostOrderPharmacies(ctx context.Contex
 }
 
 func (r *orderPharmacyRepositoryPostgres) FindAllByOrderId(ctx context.Context, orderId int64) ([]entity.OrderPharmacy, error) {
-	query := database.FindAllOrderPharmaciesByOrderId
+	return r.findAllByOrderId(ctx, database.FindAllOrderPharmaciesByOrderId, orderId)
+}
+
+func (r *orderPharmacyRepositoryPostgres) FindAllByOrderIdForUpdate(ctx context.Context, orderId int64) ([]entity.OrderPharmacy, error) {
+	return r.findAllByOrderId(ctx, database.FindAllOrderPharmaciesByOrderIdForUpdate, orderId)
+}
 
+func (r *orderPharmacyRepositoryPostgres) findAllByOrderId(ctx context.Context, query string, orderId int64) ([]entity.OrderPharmacy, error) {
 	rows, err := r.db.Query(ctx, query, orderId)
 	if err != nil {
 		return []entity.OrderPharmacy{}, err
@@ -146,6 +154,15 @@ func (r *orderPharmacyRepositoryPostgres) UpdateStatusBulkByOrderId(ctx context.
 	return nil
 }
 
+func (r *orderPharmacyRepositoryPostgres) UpdateStatusBulkByOrderIdAndStatusId(ctx context.Context, orderId int64, currentOrderStatusId int64, newOrderStatusId int64) (int64, error) {
+	commandTag, err := r.db.Exec(ctx, database.UpdateStatusBulkOrderPharmaciesByOrderIdAndStatusId, newOrderStatusId, orderId, currentOrderStatusId)
+	if err != nil {
+		return 0, err
+	}
+
+	return commandTag.RowsAffected(), nil
+}
+
 func (r *orderPharmacyRepositoryPostgres) FindOneById(ctx context.Context, id int64) (*entity.OrderPharmacy, error) {
 	var orderPharmacy entity.OrderPharmacy
 
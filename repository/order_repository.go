@@ -21,6 +21,7 @@ type OrderRepository interface {
 	FindAllWithDetails(ctx context.Context, orderIds []int64) ([]*entity.Order, error)
 	UpdatePaymentProofOne(ctx context.Context, order *entity.Order) error
 	FindOneOrderByOrderId(ctx context.Context, orderId int64) (*entity.Order, error)
+	FindExpiredUnpaidOrdersForUpdate(ctx context.Context, limit int) ([]entity.ExpiredOrder, error)
 }
 
 type orderRepositoryPostgres struct {
@@ -150,6 +151,11 @@ func (r *orderRepositoryPostgres) FindAll(ctx context.Context, validatedGetOrder
 		args = append(args, *validatedGetOrderQuery.StatusId)
 	}
 
+	if validatedGetOrderQuery.UserId != nil {
+		query += ` AND o.user_id = $` + strconv.Itoa(len(args)+1)
+		args = append(args, *validatedGetOrderQuery.UserId)
+	}
+
 	query += " GROUP BY o.order_id"
 	query += " ORDER BY o.created_at DESC"
 
@@ -276,3 +282,30 @@ func (r *orderRepositoryPostgres) FindOneOrderByOrderId(ctx context.Context, ord
 
 	return &order, nil
 }
+
+func (r *orderRepositoryPostgres) FindExpiredUnpaidOrdersForUpdate(ctx context.Context, limit int) ([]entity.ExpiredOrder, error) {
+	rows, err := r.db.Query(ctx, database.FindExpiredUnpaidOrdersForUpdate, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	expiredOrders := []entity.ExpiredOrder{}
+
+	for rows.Next() {
+		var expiredOrder entity.ExpiredOrder
+
+		err := rows.Scan(&expiredOrder.Id, &expiredOrder.TotalAmount, &expiredOrder.CreatedAt, &expiredOrder.UserEmail, &expiredOrder.UserName)
+		if err != nil {
+			return nil, err
+		}
+
+		expiredOrders = append(expiredOrders, expiredOrder)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return expiredOrders, nil
+}
@@ -0,0 +1,275 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sidiqPratomo/max-health-backend/database"
+	"github.com/sidiqPratomo/max-health-backend/entity"
+)
+
+type PharmacyDrugBatchRepository interface {
+	FindAllByPharmacyDrugId(ctx context.Context, pharmacyDrugId int64) ([]entity.PharmacyDrugBatch, error)
+	FindAllAvailableForUpdate(ctx context.Context, pharmacyDrugId int64) ([]entity.PharmacyDrugBatch, error)
+	FindAllExpired(ctx context.Context, limit int) ([]entity.PharmacyDrugBatch, error)
+	FindAllExpiring(ctx context.Context, expiringBatchFilter entity.ExpiringBatchFilter) ([]entity.ExpiringBatch, *entity.PageInfo, error)
+	UpsertStock(ctx context.Context, pharmacyDrugBatch *entity.PharmacyDrugBatch) error
+	DeductStock(ctx context.Context, pharmacyDrugBatchId int64, quantity int) (int64, error)
+	WriteOffOne(ctx context.Context, pharmacyDrugBatchId int64) (int, error)
+	PostAllocations(ctx context.Context, allocations []entity.PharmacyDrugBatchAllocation) error
+	FindAllPendingAllocationsForUpdate(ctx context.Context, pharmacyDrugId int64) ([]entity.PharmacyDrugBatchAllocation, error)
+	ReducePendingAllocationOne(ctx context.Context, allocationId int64, quantity int) (int64, error)
+	ReleaseAllocationsByOrderPharmacyId(ctx context.Context, orderPharmacyId int64) error
+	ReleaseAllocationsByOrderId(ctx context.Context, orderId int64) error
+}
+
+type pharmacyDrugBatchRepositoryPostgres struct {
+	db DBTX
+}
+
+func NewPharmacyDrugBatchRepositoryPostgres(db *pgxpool.Pool) pharmacyDrugBatchRepositoryPostgres {
+	return pharmacyDrugBatchRepositoryPostgres{
+		db: db,
+	}
+}
+
+func pharmacyDrugBatchScanDest(pharmacyDrugBatch *entity.PharmacyDrugBatch) []interface{} {
+	return []interface{}{
+		&pharmacyDrugBatch.Id,
+		&pharmacyDrugBatch.PharmacyDrugId,
+		&pharmacyDrugBatch.BatchNumber,
+		&pharmacyDrugBatch.ExpiryDate,
+		&pharmacyDrugBatch.Stock,
+		&pharmacyDrugBatch.WrittenOffAt,
+		&pharmacyDrugBatch.CreatedAt,
+	}
+}
+
+func (r *pharmacyDrugBatchRepositoryPostgres) findAll(ctx context.Context, query string, args ...interface{}) ([]entity.PharmacyDrugBatch, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pharmacyDrugBatches := []entity.PharmacyDrugBatch{}
+
+	for rows.Next() {
+		var pharmacyDrugBatch entity.PharmacyDrugBatch
+
+		err := rows.Scan(pharmacyDrugBatchScanDest(&pharmacyDrugBatch)...)
+		if err != nil {
+			return nil, err
+		}
+
+		pharmacyDrugBatches = append(pharmacyDrugBatches, pharmacyDrugBatch)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return pharmacyDrugBatches, nil
+}
+
+func (r *pharmacyDrugBatchRepositoryPostgres) FindAllByPharmacyDrugId(ctx context.Context, pharmacyDrugId int64) ([]entity.PharmacyDrugBatch, error) {
+	return r.findAll(ctx, database.FindAllPharmacyDrugBatchesByPharmacyDrugIdQuery, pharmacyDrugId)
+}
+
+func (r *pharmacyDrugBatchRepositoryPostgres) FindAllAvailableForUpdate(ctx context.Context, pharmacyDrugId int64) ([]entity.PharmacyDrugBatch, error) {
+	return r.findAll(ctx, database.FindAllAvailablePharmacyDrugBatchesForUpdateQuery, pharmacyDrugId)
+}
+
+func (r *pharmacyDrugBatchRepositoryPostgres) FindAllExpired(ctx context.Context, limit int) ([]entity.PharmacyDrugBatch, error) {
+	return r.findAll(ctx, database.FindAllExpiredPharmacyDrugBatchesQuery, limit)
+}
+
+func (r *pharmacyDrugBatchRepositoryPostgres) FindAllExpiring(ctx context.Context, expiringBatchFilter entity.ExpiringBatchFilter) ([]entity.ExpiringBatch, *entity.PageInfo, error) {
+	query := database.FindAllExpiringPharmacyDrugBatchesQuery
+	args := []interface{}{expiringBatchFilter.ManagerId, expiringBatchFilter.Days}
+
+	if expiringBatchFilter.PharmacyId != nil {
+		query += ` AND pd.pharmacy_id = $` + strconv.Itoa(len(args)+1)
+		args = append(args, *expiringBatchFilter.PharmacyId)
+	}
+
+	query += ` ORDER BY b.expiry_date ASC, b.pharmacy_drug_batch_id ASC`
+
+	query += ` LIMIT $` + strconv.Itoa(len(args)+1)
+	args = append(args, expiringBatchFilter.Limit)
+	query += ` OFFSET $` + strconv.Itoa(len(args)+1)
+	args = append(args, expiringBatchFilter.Offset)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	expiringBatches := []entity.ExpiringBatch{}
+	pageInfo := entity.PageInfo{}
+
+	for rows.Next() {
+		var expiringBatch entity.ExpiringBatch
+
+		dest := append(pharmacyDrugBatchScanDest(&expiringBatch.PharmacyDrugBatch),
+			&expiringBatch.PharmacyId,
+			&expiringBatch.PharmacyName,
+			&expiringBatch.DrugId,
+			&expiringBatch.DrugName,
+			&pageInfo.ItemCount,
+		)
+
+		err := rows.Scan(dest...)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		expiringBatches = append(expiringBatches, expiringBatch)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	pageInfo.PageCount = int(math.Ceil(float64(pageInfo.ItemCount) / float64(expiringBatchFilter.Limit)))
+	pageInfo.Page = int(math.Ceil(float64(expiringBatchFilter.Offset+1) / float64(expiringBatchFilter.Limit)))
+
+	return expiringBatches, &pageInfo, nil
+}
+
+// A batch number that is already on the shelf gets the new stock added to it,
+// the stored expiry date is returned so callers can spot a mismatch.
+func (r *pharmacyDrugBatchRepositoryPostgres) UpsertStock(ctx context.Context, pharmacyDrugBatch *entity.PharmacyDrugBatch) error {
+	var expiryDate *time.Time
+
+	err := r.db.QueryRow(ctx, database.UpsertPharmacyDrugBatchStockQuery,
+		pharmacyDrugBatch.PharmacyDrugId,
+		pharmacyDrugBatch.BatchNumber,
+		pharmacyDrugBatch.ExpiryDate,
+		pharmacyDrugBatch.Stock,
+	).Scan(&pharmacyDrugBatch.Id, &expiryDate)
+	if err != nil {
+		return err
+	}
+
+	pharmacyDrugBatch.ExpiryDate = expiryDate
+
+	return nil
+}
+
+func (r *pharmacyDrugBatchRepositoryPostgres) DeductStock(ctx context.Context, pharmacyDrugBatchId int64, quantity int) (int64, error) {
+	result, err := r.db.Exec(ctx, database.DeductPharmacyDrugBatchStockQuery, pharmacyDrugBatchId, quantity)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+func (r *pharmacyDrugBatchRepositoryPostgres) WriteOffOne(ctx context.Context, pharmacyDrugBatchId int64) (int, error) {
+	var writtenOffStock int
+
+	err := r.db.QueryRow(ctx, database.WriteOffPharmacyDrugBatchOneQuery, pharmacyDrugBatchId).Scan(&writtenOffStock)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return 0, nil
+		}
+
+		return 0, err
+	}
+
+	return writtenOffStock, nil
+}
+
+func (r *pharmacyDrugBatchRepositoryPostgres) PostAllocations(ctx context.Context, allocations []entity.PharmacyDrugBatchAllocation) error {
+	if len(allocations) == 0 {
+		return nil
+	}
+
+	query := database.CreatePharmacyDrugBatchAllocations
+	args := []interface{}{}
+	for i, allocation := range allocations {
+		query += `($` + strconv.Itoa(len(args)+1) + `, $` + strconv.Itoa(len(args)+2) + `, $` + strconv.Itoa(len(args)+3) +
+			`, $` + strconv.Itoa(len(args)+4) + `, $` + strconv.Itoa(len(args)+5) + `)`
+		args = append(args, allocation.PharmacyDrugId)
+		args = append(args, allocation.PharmacyDrugBatchId)
+		args = append(args, allocation.OrderPharmacyId)
+		args = append(args, allocation.StockMutationRequestId)
+		args = append(args, allocation.Quantity)
+		if i != len(allocations)-1 {
+			query += `,`
+		}
+	}
+
+	_, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *pharmacyDrugBatchRepositoryPostgres) FindAllPendingAllocationsForUpdate(ctx context.Context, pharmacyDrugId int64) ([]entity.PharmacyDrugBatchAllocation, error) {
+	rows, err := r.db.Query(ctx, database.FindAllPendingPharmacyDrugBatchAllocationsForUpdateQuery, pharmacyDrugId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	allocations := []entity.PharmacyDrugBatchAllocation{}
+
+	for rows.Next() {
+		var allocation entity.PharmacyDrugBatchAllocation
+
+		err := rows.Scan(
+			&allocation.Id,
+			&allocation.PharmacyDrugId,
+			&allocation.OrderPharmacyId,
+			&allocation.StockMutationRequestId,
+			&allocation.Quantity,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		allocations = append(allocations, allocation)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return allocations, nil
+}
+
+func (r *pharmacyDrugBatchRepositoryPostgres) ReducePendingAllocationOne(ctx context.Context, allocationId int64, quantity int) (int64, error) {
+	result, err := r.db.Exec(ctx, database.ReducePendingPharmacyDrugBatchAllocationOneQuery, allocationId, quantity)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+func (r *pharmacyDrugBatchRepositoryPostgres) ReleaseAllocationsByOrderPharmacyId(ctx context.Context, orderPharmacyId int64) error {
+	_, err := r.db.Exec(ctx, database.ReleasePharmacyDrugBatchAllocationsByOrderPharmacyIdQuery, orderPharmacyId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *pharmacyDrugBatchRepositoryPostgres) ReleaseAllocationsByOrderId(ctx context.Context, orderId int64) error {
+	_, err := r.db.Exec(ctx, database.ReleasePharmacyDrugBatchAllocationsByOrderIdQuery, orderId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
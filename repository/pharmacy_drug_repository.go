@@ -2,11 +2,13 @@ package repository
 
 import (
 	"context"
+	"math"
 	"strconv"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/shopspring/decimal"
+	"github.com/sidiqPratomo/max-health-backend/appconstant"
 	"github.com/sidiqPratomo/max-health-backend/database"
 	"github.com/sidiqPratomo/max-health-backend/entity"
 	"github.com/sidiqPratomo/max-health-backend/util"
@@ -21,13 +23,17 @@ type PharmacyDrugRepository interface {
 	UpdatePharmacyDrugsForStockMutation(ctx context.Context, stockChangesList []entity.StockChange) error
 	GetPharmacyDrugByPharmacyId(ctx context.Context, pharmacyId int64) ([]entity.PharmacyDrugDetail, error)
 	GetNearestAvailablePharmacyDrugByDrugId(ctx context.Context, drugId, userAddressId int64) (*entity.Pharmacy, *entity.DrugQuantity, error)
+	GetAllNearestAvailablePharmacyDrugsByDrugIds(ctx context.Context, drugIds []int64, userAddressId int64, limitPerDrug int) ([]entity.FulfilmentCandidate, error)
 	UpdatePharmacyDrugsByOrderPharmacyId(ctx context.Context, orderPharmacyId int64) ([]entity.StockChange, error)
 	UpdatePharmacyDrugsByOrderId(ctx context.Context, orderId int64) ([]entity.StockChange, error)
 	UpdatePharmacyDrugStockPrice(ctx context.Context, pharmacyDrugId int64, stock int, Price decimal.Decimal) error
 	DeletePharmacyDrug(ctx context.Context, pharmacyDrugId int64) error
-	AddPharmacyDrug(ctx context.Context, pharmacyId int64, drugId int64, stock int, price decimal.Decimal) error
+	AddPharmacyDrug(ctx context.Context, pharmacyId int64, drugId int64, stock int, price decimal.Decimal) (int64, error)
 	GetPossibleStockMutation(ctx context.Context, pharmacyDrugId int64) ([]entity.PharmacyDrugDetail, error)
 	GetPharmacyDrugByIdForUpdate(ctx context.Context, pharmacyDrugId int64) (*entity.PharmacyDrugDetail, error)
+	UpdateStockByAmount(ctx context.Context, pharmacyDrugId int64, amount int) (int, error)
+	UpdateReorderThreshold(ctx context.Context, pharmacyDrugId int64, reorderThreshold *int) error
+	FindAllReorderSuggestions(ctx context.Context, reorderSuggestionFilter entity.ReorderSuggestionFilter) ([]entity.ReorderSuggestion, *entity.PageInfo, error)
 }
 
 type pharmacyDrugRepositoryPostgres struct {
@@ -76,7 +82,7 @@ func (r *pharmacyDrugRepositoryPostgres) GetPharmacyDrugsByDrugId(ctx context.Co
 
 func (r *pharmacyDrugRepositoryPostgres) GetPharmacyDrugById(ctx context.Context, pharmacyDrugId int64) (*entity.PharmacyDrugDetail, error) {
 	pharmacyDrug := entity.PharmacyDrugDetail{}
-	err := r.db.QueryRow(ctx, database.GetPharmacyDrugById, pharmacyDrugId).Scan(&pharmacyDrug.Id, &pharmacyDrug.PharmacyId, &pharmacyDrug.DrugId, &pharmacyDrug.Price, &pharmacyDrug.Stock)
+	err := r.db.QueryRow(ctx, database.GetPharmacyDrugById, pharmacyDrugId).Scan(&pharmacyDrug.Id, &pharmacyDrug.PharmacyId, &pharmacyDrug.DrugId, &pharmacyDrug.Price, &pharmacyDrug.Stock, &pharmacyDrug.DrugName, &pharmacyDrug.IsPrescriptionRequired)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil
@@ -95,17 +101,24 @@ func (r *pharmacyDrugRepositoryPostgres) GetProductListing(ctx context.Context,
 	args = append(args, query.Longitude)
 	args = append(args, query.Latitude)
 
-	sql += `), ` + database.GetDrugListQuery
+	isSearching := query.Search != nil && *query.Search != ""
 
-	if query.Search != nil {
-		sql += `$` + strconv.Itoa(len(args)+1)
-		searchTerm := "%"
-		if query.Search != nil && *query.Search != "" {
-			searchTerm = "%" + *query.Search + "%"
-		}
-		args = append(args, searchTerm)
+	sql += `), ` + database.GetDrugListQuery
+	if isSearching {
+		sql += database.DrugSearchRankColumn
 	} else {
-		sql += `'%%'`
+		sql += database.DrugNoSearchRankColumn
+	}
+
+	sql += database.GetDrugListFromQuery
+	if isSearching {
+		sql += ` CROSS JOIN (SELECT $` + strconv.Itoa(len(args)+1) + `::TEXT AS term) st` + database.DrugSearchTermJoin
+		args = append(args, *query.Search)
+	}
+
+	sql += database.GetDrugListConditionQuery
+	if isSearching {
+		sql += database.DrugSearchCondition
 	}
 
 	if query.Category != nil {
@@ -148,7 +161,11 @@ func (r *pharmacyDrugRepositoryPostgres) GetProductListing(ctx context.Context,
 		pageInfo.PageCount += 1
 	}
 
-	sql1 += database.GetProductListingQuery + ` ORDER BY p.min_price`
+	sql1 += database.GetProductListingQuery + ` ORDER BY`
+	if isSearching && query.SortBy == nil {
+		sql1 += ` cd.search_rank DESC,`
+	}
+	sql1 += ` p.min_price`
 	if query.Sort != nil {
 		if *query.Sort == "desc" {
 			sql1 += ` DESC`
@@ -307,6 +324,48 @@ func (r *pharmacyDrugRepositoryPostgres) GetNearestAvailablePharmacyDrugByDrugId
 	return &pharmacy, &drugQuantity, nil
 }
 
+func (r *pharmacyDrugRepositoryPostgres) GetAllNearestAvailablePharmacyDrugsByDrugIds(ctx context.Context, drugIds []int64, userAddressId int64, limitPerDrug int) ([]entity.FulfilmentCandidate, error) {
+	rows, err := r.db.Query(ctx, database.GetAllNearestAvailablePharmacyDrugsByDrugIdsQuery, drugIds, userAddressId, limitPerDrug)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	candidates := []entity.FulfilmentCandidate{}
+
+	for rows.Next() {
+		var candidate entity.FulfilmentCandidate
+
+		err := rows.Scan(
+			&candidate.Pharmacy.Id,
+			&candidate.Pharmacy.Name,
+			&candidate.Pharmacy.Address,
+			&candidate.Pharmacy.Distance,
+			&candidate.DrugQuantity.PharmacyDrug.Id,
+			&candidate.DrugQuantity.PharmacyDrug.Drug.Id,
+			&candidate.DrugQuantity.PharmacyDrug.Drug.Name,
+			&candidate.DrugQuantity.PharmacyDrug.Drug.Manufacture,
+			&candidate.DrugQuantity.PharmacyDrug.Drug.Image,
+			&candidate.DrugQuantity.PharmacyDrug.Drug.Weight,
+			&candidate.DrugQuantity.PharmacyDrug.Drug.SellingUnit,
+			&candidate.DrugQuantity.PharmacyDrug.Drug.UnitInPack,
+			&candidate.DrugQuantity.PharmacyDrug.Price,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		candidates = append(candidates, candidate)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return candidates, nil
+}
+
 func (r *pharmacyDrugRepositoryPostgres) UpdatePharmacyDrugsByOrderPharmacyId(ctx context.Context, orderPharmacyId int64) ([]entity.StockChange, error) {
 	stockChanges := []entity.StockChange{}
 	query := database.GetPharmacyDrugsByOrderPharmacyId + database.UpdatePharmacyDrugsByOrderPharmacyId
@@ -367,14 +426,15 @@ func (r *pharmacyDrugRepositoryPostgres) DeletePharmacyDrug(ctx context.Context,
 	return nil
 }
 
-func (r *pharmacyDrugRepositoryPostgres) AddPharmacyDrug(ctx context.Context, pharmacyId int64, drugId int64, stock int, price decimal.Decimal) error {
+func (r *pharmacyDrugRepositoryPostgres) AddPharmacyDrug(ctx context.Context, pharmacyId int64, drugId int64, stock int, price decimal.Decimal) (int64, error) {
 	query := database.AddPharmacyDrug
 
-	_, err := r.db.Exec(ctx, query, pharmacyId, drugId, stock, price)
+	var pharmacyDrugId int64
+	err := r.db.QueryRow(ctx, query, pharmacyId, drugId, stock, price).Scan(&pharmacyDrugId)
 	if err != nil {
-		return err
+		return 0, err
 	}
-	return nil
+	return pharmacyDrugId, nil
 }
 
 func (r *pharmacyDrugRepositoryPostgres) GetPossibleStockMutation(ctx context.Context, pharmacyDrugId int64) ([]entity.PharmacyDrugDetail, error) {
@@ -399,7 +459,7 @@ func (r *pharmacyDrugRepositoryPostgres) GetPossibleStockMutation(ctx context.Co
 
 func (r *pharmacyDrugRepositoryPostgres) GetPharmacyDrugByIdForUpdate(ctx context.Context, pharmacyDrugId int64) (*entity.PharmacyDrugDetail, error) {
 	pharmacyDrug := entity.PharmacyDrugDetail{}
-	err := r.db.QueryRow(ctx, database.GetPharmacyDrugById, pharmacyDrugId).Scan(&pharmacyDrug.Id, &pharmacyDrug.PharmacyId, &pharmacyDrug.DrugId, &pharmacyDrug.Price, &pharmacyDrug.Stock)
+	err := r.db.QueryRow(ctx, database.GetPharmacyDrugByIdForUpdate, pharmacyDrugId).Scan(&pharmacyDrug.Id, &pharmacyDrug.PharmacyId, &pharmacyDrug.DrugId, &pharmacyDrug.Price, &pharmacyDrug.Stock, &pharmacyDrug.DrugName, &pharmacyDrug.IsPrescriptionRequired)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil
@@ -409,3 +469,81 @@ func (r *pharmacyDrugRepositoryPostgres) GetPharmacyDrugByIdForUpdate(ctx contex
 	}
 	return &pharmacyDrug, nil
 }
+
+func (r *pharmacyDrugRepositoryPostgres) UpdateStockByAmount(ctx context.Context, pharmacyDrugId int64, amount int) (int, error) {
+	var stock int
+
+	err := r.db.QueryRow(ctx, database.UpdatePharmacyDrugStockByAmount, pharmacyDrugId, amount).Scan(&stock)
+	if err != nil {
+		return 0, err
+	}
+
+	return stock, nil
+}
+
+func (r *pharmacyDrugRepositoryPostgres) UpdateReorderThreshold(ctx context.Context, pharmacyDrugId int64, reorderThreshold *int) error {
+	_, err := r.db.Exec(ctx, database.UpdatePharmacyDrugReorderThreshold, pharmacyDrugId, reorderThreshold)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *pharmacyDrugRepositoryPostgres) FindAllReorderSuggestions(ctx context.Context, reorderSuggestionFilter entity.ReorderSuggestionFilter) ([]entity.ReorderSuggestion, *entity.PageInfo, error) {
+	query := database.FindAllReorderSuggestionsQuery
+	args := []interface{}{reorderSuggestionFilter.ManagerId, reorderSuggestionFilter.SalesWindowDays, appconstant.OrderStatusCanceled, reorderSuggestionFilter.CoverDays}
+
+	if reorderSuggestionFilter.PharmacyId != nil {
+		query += ` AND pd.pharmacy_id = $` + strconv.Itoa(len(args)+1)
+		args = append(args, *reorderSuggestionFilter.PharmacyId)
+	}
+
+	query += database.FindAllReorderSuggestionsSelectQuery
+
+	query += ` LIMIT $` + strconv.Itoa(len(args)+1)
+	args = append(args, reorderSuggestionFilter.Limit)
+	query += ` OFFSET $` + strconv.Itoa(len(args)+1)
+	args = append(args, reorderSuggestionFilter.Offset)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	reorderSuggestions := []entity.ReorderSuggestion{}
+	pageInfo := entity.PageInfo{}
+
+	for rows.Next() {
+		var reorderSuggestion entity.ReorderSuggestion
+
+		err := rows.Scan(
+			&reorderSuggestion.PharmacyDrugId,
+			&reorderSuggestion.PharmacyId,
+			&reorderSuggestion.PharmacyName,
+			&reorderSuggestion.DrugId,
+			&reorderSuggestion.DrugName,
+			&reorderSuggestion.Stock,
+			&reorderSuggestion.ReorderThreshold,
+			&reorderSuggestion.SoldQuantity,
+			&reorderSuggestion.DailySales,
+			&reorderSuggestion.SuggestedQuantity,
+			&pageInfo.ItemCount,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		reorderSuggestions = append(reorderSuggestions, reorderSuggestion)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	pageInfo.PageCount = int(math.Ceil(float64(pageInfo.ItemCount) / float64(reorderSuggestionFilter.Limit)))
+	pageInfo.Page = int(math.Ceil(float64(reorderSuggestionFilter.Offset+1) / float64(reorderSuggestionFilter.Limit)))
+
+	return reorderSuggestions, &pageInfo, nil
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sidiqPratomo/max-health-backend/database"
+	"github.com/sidiqPratomo/max-health-backend/entity"
+)
+
+type PharmacyHolidayRepository interface {
+	FindAllUpcomingByPharmacyId(ctx context.Context, pharmacyId int64, from time.Time) ([]entity.PharmacyHoliday, error)
+	CreateOne(ctx context.Context, pharmacyHoliday entity.PharmacyHoliday) (*int64, error)
+	DeleteOneById(ctx context.Context, pharmacyId int64, pharmacyHolidayId int64) (int64, error)
+}
+
+type pharmacyHolidayRepositoryPostgres struct {
+	db DBTX
+}
+
+func NewPharmacyHolidayRepositoryPostgres(db *pgxpool.Pool) pharmacyHolidayRepositoryPostgres {
+	return pharmacyHolidayRepositoryPostgres{
+		db: db,
+	}
+}
+
+func (r *pharmacyHolidayRepositoryPostgres) FindAllUpcomingByPharmacyId(ctx context.Context, pharmacyId int64, from time.Time) ([]entity.PharmacyHoliday, error) {
+	rows, err := r.db.Query(ctx, database.FindAllUpcomingPharmacyHolidaysByPharmacyId, pharmacyId, from.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pharmacyHolidays := []entity.PharmacyHoliday{}
+	for rows.Next() {
+		var pharmacyHoliday entity.PharmacyHoliday
+
+		err := rows.Scan(&pharmacyHoliday.Id, &pharmacyHoliday.PharmacyId, &pharmacyHoliday.Date, &pharmacyHoliday.IsOpen, &pharmacyHoliday.OpenHour, &pharmacyHoliday.CloseHour, &pharmacyHoliday.Description)
+		if err != nil {
+			return nil, err
+		}
+
+		pharmacyHolidays = append(pharmacyHolidays, pharmacyHoliday)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return pharmacyHolidays, nil
+}
+
+func (r *pharmacyHolidayRepositoryPostgres) CreateOne(ctx context.Context, pharmacyHoliday entity.PharmacyHoliday) (*int64, error) {
+	var pharmacyHolidayId int64
+
+	err := r.db.QueryRow(ctx, database.CreateOnePharmacyHoliday, pharmacyHoliday.PharmacyId, pharmacyHoliday.Date.Format("2006-01-02"), pharmacyHoliday.IsOpen, pharmacyHoliday.OpenHour, pharmacyHoliday.CloseHour, pharmacyHoliday.Description).Scan(&pharmacyHolidayId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pharmacyHolidayId, nil
+}
+
+func (r *pharmacyHolidayRepositoryPostgres) DeleteOneById(ctx context.Context, pharmacyId int64, pharmacyHolidayId int64) (int64, error) {
+	commandTag, err := r.db.Exec(ctx, database.DeleteOnePharmacyHoliday, pharmacyHolidayId, pharmacyId)
+	if err != nil {
+		return 0, err
+	}
+
+	return commandTag.RowsAffected(), nil
+}
@@ -13,6 +13,8 @@ import (
 type PharmacyOperationalRepository interface {
 	CreateBulk(ctx context.Context, pharmacyId int64, days []string) error
 	UpdateOneById(ctx context.Context, pharmacyOperational entity.PharmacyOperational) error
+	FindAllByPharmacyId(ctx context.Context, pharmacyId int64) ([]entity.PharmacyOperational, error)
+	UpdateOneByPharmacyIdAndDay(ctx context.Context, pharmacyOperational entity.PharmacyOperational) (int64, error)
 	DeleteBulkByPharmacyId(ctx context.Context, pharmacyId int64) error
 }
 
@@ -54,6 +56,41 @@ func (r *pharmacyOperationalRepositoryPostgres) UpdateOneById(ctx context.Contex
 	return nil
 }
 
+func (r *pharmacyOperationalRepositoryPostgres) FindAllByPharmacyId(ctx context.Context, pharmacyId int64) ([]entity.PharmacyOperational, error) {
+	rows, err := r.db.Query(ctx, database.FindAllPharmacyOperationalsByPharmacyId, pharmacyId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pharmacyOperationals := []entity.PharmacyOperational{}
+	for rows.Next() {
+		var pharmacyOperational entity.PharmacyOperational
+
+		err := rows.Scan(&pharmacyOperational.Id, &pharmacyOperational.PharmacyId, &pharmacyOperational.OperationalDay, &pharmacyOperational.OpenHour, &pharmacyOperational.CloseHour, &pharmacyOperational.IsOpen)
+		if err != nil {
+			return nil, err
+		}
+
+		pharmacyOperationals = append(pharmacyOperationals, pharmacyOperational)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return pharmacyOperationals, nil
+}
+
+func (r *pharmacyOperationalRepositoryPostgres) UpdateOneByPharmacyIdAndDay(ctx context.Context, pharmacyOperational entity.PharmacyOperational) (int64, error) {
+	commandTag, err := r.db.Exec(ctx, database.UpdateOnePharmacyOperationalByPharmacyIdAndDay, pharmacyOperational.OpenHour, pharmacyOperational.CloseHour, pharmacyOperational.IsOpen, pharmacyOperational.PharmacyId, pharmacyOperational.OperationalDay)
+	if err != nil {
+		return 0, err
+	}
+
+	return commandTag.RowsAffected(), nil
+}
+
 func (r *pharmacyOperationalRepositoryPostgres) DeleteBulkByPharmacyId(ctx context.Context, pharmacyId int64) error {
 	_, err := r.db.Exec(ctx, database.DeleteBulkPharmacyOperationalByPharmacyId, pharmacyId)
 	if err != nil {
@@ -6,10 +6,10 @@ import (
 	"math"
 	"strconv"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/sidiqPratomo/max-health-backend/database"
 	"github.com/sidiqPratomo/max-health-backend/entity"
-	"github.com/sidiqPratomo/max-health-backend/util"
 )
 
 type PharmacyRepository interface {
@@ -20,6 +20,7 @@ type PharmacyRepository interface {
 	DeleteOneById(ctx context.Context, id int64) error
 	GetAllCourierOptionsByPharmacyId(ctx context.Context, userAddressId, pharmacyId int64, weight float64) ([]entity.AvailableCourier, error)
 	GetOnePharmacyByPharmacyId(ctx context.Context, pharmacyId int64) (*entity.Pharmacy, error)
+	UpdateTimezoneById(ctx context.Context, pharmacyId int64, timezone string) error
 }
 
 type pharmacyRepositoryPostgres struct {
@@ -48,8 +49,8 @@ func (r *pharmacyRepositoryPostgres) CreateOne(ctx context.Context, pharmacy *en
 func (r *pharmacyRepositoryPostgres) FindOneById(ctx context.Context, id int64) (*entity.Pharmacy, error) {
 	var pharmacy entity.Pharmacy
 
-	if err := r.db.QueryRow(ctx, database.FindOnePharmacyById, id).Scan(&pharmacy.Id, &pharmacy.Name, &pharmacy.PharmacyManagerId); err != nil {
-		if err == sql.ErrNoRows {
+	if err := r.db.QueryRow(ctx, database.FindOnePharmacyById, id).Scan(&pharmacy.Id, &pharmacy.Name, &pharmacy.PharmacyManagerId, &pharmacy.Timezone); err != nil {
+		if err == pgx.ErrNoRows {
 			return nil, nil
 		}
 
@@ -185,11 +186,12 @@ func (r *pharmacyRepositoryPostgres) GetAllCourierOptionsByPharmacyId(ctx contex
 			availableCourier.CourierOptions = append(availableCourier.CourierOptions, courierOption)
 		} else {
 			if origin != nil && destination != nil {
-				options, err := util.GetUnofficialDelivery(int64(*origin), int64(*destination), int64(weight), availableCourier.CourierName)
-				if err != nil {
-					return nil, err
+				availableCourier.ShippingRateRequest = &entity.ShippingRateRequest{
+					Origin:      *origin,
+					Destination: *destination,
+					Weight:      int64(weight),
+					Courier:     availableCourier.CourierName,
 				}
-				availableCourier.CourierOptions = options
 			}
 		}
 
@@ -203,3 +205,12 @@ func (r *pharmacyRepositoryPostgres) GetAllCourierOptionsByPharmacyId(ctx contex
 
 	return availableCourierList, nil
 }
+
+func (r *pharmacyRepositoryPostgres) UpdateTimezoneById(ctx context.Context, pharmacyId int64, timezone string) error {
+	_, err := r.db.Exec(ctx, database.UpdatePharmacyTimezone, timezone, pharmacyId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -2,8 +2,11 @@ package repository
 
 import (
 	"context"
+	"strconv"
+
 	// "database/sql"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/sidiqPratomo/max-health-backend/database"
 	"github.com/sidiqPratomo/max-health-backend/entity"
@@ -12,6 +15,12 @@ import (
 type PrescriptionDrugRepository interface {
 	PostOnePrescriptionDrug(ctx context.Context, prescriptionId int64, prescriptionDrug entity.PrescriptionDrug) error
 	GetAllPrescriptionDrug(ctx context.Context, prescriptionId int64) ([]entity.PrescriptionDrug, error)
+	FindOneRedeemable(ctx context.Context, userAccountId, prescriptionId, drugId int64) (*entity.PrescriptionDrug, error)
+	GetPrescriptionDrugByCartItemId(ctx context.Context, cartItemId int64) (*entity.PrescriptionDrug, error)
+	FindAllRedeemableByIdsForUpdate(ctx context.Context, userAccountId int64, prescriptionDrugIds []int64) ([]entity.PrescriptionDrug, error)
+	DecreaseRemainingQuantity(ctx context.Context, prescriptionDrugId int64, quantity int) (int64, error)
+	RestoreByOrderId(ctx context.Context, orderId int64) error
+	RestoreByOrderPharmacyId(ctx context.Context, orderPharmacyId int64) error
 }
 
 type prescriptionDrugRepositoryPostgres struct {
@@ -45,7 +54,7 @@ func (r *prescriptionDrugRepositoryPostgres) GetAllPrescriptionDrug(ctx context.
 	for rows.Next() {
 		var prescriptionDrug entity.PrescriptionDrug
 
-		err := rows.Scan(&prescriptionDrug.Id, &prescriptionDrug.Drug.Id, &prescriptionDrug.Drug.Name, &prescriptionDrug.Drug.Image, &prescriptionDrug.Drug.IsActive, &prescriptionDrug.Quantity, &prescriptionDrug.Note)
+		err := rows.Scan(&prescriptionDrug.Id, &prescriptionDrug.Drug.Id, &prescriptionDrug.Drug.Name, &prescriptionDrug.Drug.Image, &prescriptionDrug.Drug.IsActive, &prescriptionDrug.Quantity, &prescriptionDrug.RemainingQuantity, &prescriptionDrug.Note)
 		if err != nil {
 			return nil, err
 		}
@@ -61,6 +70,90 @@ func (r *prescriptionDrugRepositoryPostgres) GetAllPrescriptionDrug(ctx context.
 	return prescriptionDrugList, nil
 }
 
-func (r *prescriptionDrugRepositoryPostgres) GetPrescriptionDrugByCartItemId(ctx context.Context, cartItemId int64) error {
+func (r *prescriptionDrugRepositoryPostgres) scanOne(row pgx.Row) (*entity.PrescriptionDrug, error) {
+	var prescriptionDrug entity.PrescriptionDrug
+
+	err := row.Scan(&prescriptionDrug.Id, &prescriptionDrug.PrescriptionId, &prescriptionDrug.Drug.Id, &prescriptionDrug.Drug.Name, &prescriptionDrug.Quantity, &prescriptionDrug.RemainingQuantity)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &prescriptionDrug, nil
+}
+
+func (r *prescriptionDrugRepositoryPostgres) FindOneRedeemable(ctx context.Context, userAccountId, prescriptionId, drugId int64) (*entity.PrescriptionDrug, error) {
+	return r.scanOne(r.db.QueryRow(ctx, database.FindOneRedeemablePrescriptionDrugQuery, prescriptionId, drugId, userAccountId))
+}
+
+func (r *prescriptionDrugRepositoryPostgres) GetPrescriptionDrugByCartItemId(ctx context.Context, cartItemId int64) (*entity.PrescriptionDrug, error) {
+	return r.scanOne(r.db.QueryRow(ctx, database.FindOnePrescriptionDrugByCartItemIdQuery, cartItemId))
+}
+
+func (r *prescriptionDrugRepositoryPostgres) FindAllRedeemableByIdsForUpdate(ctx context.Context, userAccountId int64, prescriptionDrugIds []int64) ([]entity.PrescriptionDrug, error) {
+	query := database.FindAllRedeemablePrescriptionDrugsForUpdateQuery
+	args := []interface{}{userAccountId}
+
+	query += ` AND pd.prescription_drug_id IN (`
+	for i, prescriptionDrugId := range prescriptionDrugIds {
+		args = append(args, prescriptionDrugId)
+		query += `$` + strconv.Itoa(len(args))
+		if i != len(prescriptionDrugIds)-1 {
+			query += `, `
+		}
+	}
+	query += `) FOR UPDATE OF pd`
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prescriptionDrugs := []entity.PrescriptionDrug{}
+
+	for rows.Next() {
+		prescriptionDrug, err := r.scanOne(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		prescriptionDrugs = append(prescriptionDrugs, *prescriptionDrug)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return prescriptionDrugs, nil
+}
+
+func (r *prescriptionDrugRepositoryPostgres) DecreaseRemainingQuantity(ctx context.Context, prescriptionDrugId int64, quantity int) (int64, error) {
+	result, err := r.db.Exec(ctx, database.DecreaseOnePrescriptionDrugRemainingQuantityQuery, prescriptionDrugId, quantity)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+func (r *prescriptionDrugRepositoryPostgres) RestoreByOrderId(ctx context.Context, orderId int64) error {
+	_, err := r.db.Exec(ctx, database.RestorePrescriptionDrugsByOrderIdQuery, orderId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *prescriptionDrugRepositoryPostgres) RestoreByOrderPharmacyId(ctx context.Context, orderPharmacyId int64) error {
+	_, err := r.db.Exec(ctx, database.RestorePrescriptionDrugsByOrderPharmacyIdQuery, orderPharmacyId)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -11,12 +11,16 @@ import (
 )
 
 type PrescriptionRepository interface {
-	CreateOnePrescription(ctx context.Context, userAccountId, doctorAccountId int64) (*int64, error)
+	CreateOnePrescription(ctx context.Context, prescription entity.Prescription) (*int64, error)
 	GetPrescriptionById(ctx context.Context, prescriptionId int64) (*entity.Prescription, error)
+	GetPrescriptionDocumentById(ctx context.Context, prescriptionId int64) (*entity.Prescription, error)
 	SetPrescriptionRedeemedNow(ctx context.Context, prescriptionId int64) error
 	GetPrescriptionListByUserAccountId(ctx context.Context, accountId int64, limit, offset int) ([]entity.Prescription, error)
 	GetPrescriptionListByUserAccountIdTotalItem(ctx context.Context, accountId int64) (int, error)
 	SetPrescriptionOrderedAtNow(ctx context.Context, prescriptionId int64) error
+	SetPrescriptionOrderedAtNowIfExhausted(ctx context.Context, prescriptionId int64) error
+	RefillOneIfExhausted(ctx context.Context, prescriptionId int64) error
+	GetAllRedemptions(ctx context.Context, prescriptionId int64) ([]entity.PrescriptionRedemption, error)
 }
 
 type prescriptionRepositoryPostgres struct {
@@ -29,10 +33,10 @@ func NewPrescriptionRepositoryPostgres(db *pgxpool.Pool) prescriptionRepositoryP
 	}
 }
 
-func (r *prescriptionRepositoryPostgres) CreateOnePrescription(ctx context.Context, userAccountId, doctorAccountId int64) (*int64, error) {
+func (r *prescriptionRepositoryPostgres) CreateOnePrescription(ctx context.Context, prescription entity.Prescription) (*int64, error) {
 	var prescriptionId int64
 
-	err := r.db.QueryRow(ctx, database.CreateOnePrescriptionQuery, userAccountId, doctorAccountId).Scan(&prescriptionId)
+	err := r.db.QueryRow(ctx, database.CreateOnePrescriptionQuery, prescription.UserAccountId, prescription.DoctorAccountId, prescription.RefillCount, prescription.ValidForDays).Scan(&prescriptionId)
 	if err != nil {
 		return nil, err
 	}
@@ -43,7 +47,34 @@ func (r *prescriptionRepositoryPostgres) CreateOnePrescription(ctx context.Conte
 func (r *prescriptionRepositoryPostgres) GetPrescriptionById(ctx context.Context, prescriptionId int64) (*entity.Prescription, error) {
 	var prescription entity.Prescription
 
-	err := r.db.QueryRow(ctx, database.GetPrescriptionByIdQuery, prescriptionId).Scan(&prescription.UserAccountId, &prescription.DoctorAccountId, &prescription.RedeemedAt, &prescription.OrderedAt)
+	err := r.db.QueryRow(ctx, database.GetPrescriptionByIdQuery, prescriptionId).Scan(&prescription.UserAccountId, &prescription.DoctorAccountId, &prescription.RedeemedAt, &prescription.OrderedAt, &prescription.RefillCount, &prescription.RefillsUsed, &prescription.ValidUntil, &prescription.IsExpired)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	prescription.Id = &prescriptionId
+
+	return &prescription, nil
+}
+
+func (r *prescriptionRepositoryPostgres) GetPrescriptionDocumentById(ctx context.Context, prescriptionId int64) (*entity.Prescription, error) {
+	var prescription entity.Prescription
+
+	err := r.db.QueryRow(ctx, database.GetPrescriptionDocumentByIdQuery, prescriptionId).Scan(
+		&prescription.UserAccountId,
+		&prescription.UserName,
+		&prescription.DoctorAccountId,
+		&prescription.DoctorName,
+		&prescription.DoctorSpecialization,
+		&prescription.RefillCount,
+		&prescription.ValidUntil,
+		&prescription.IsExpired,
+		&prescription.CreatedAt,
+	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil
@@ -87,6 +118,10 @@ func (r *prescriptionRepositoryPostgres) GetPrescriptionListByUserAccountId(ctx
 			&prescription.RedeemedAt,
 			&prescription.OrderedAt,
 			&prescription.CreatedAt,
+			&prescription.RefillCount,
+			&prescription.RefillsUsed,
+			&prescription.ValidUntil,
+			&prescription.IsExpired,
 		)
 		if err != nil {
 			return nil, nil
@@ -122,3 +157,59 @@ func (r *prescriptionRepositoryPostgres) SetPrescriptionOrderedAtNow(ctx context
 
 	return nil
 }
+
+func (r *prescriptionRepositoryPostgres) SetPrescriptionOrderedAtNowIfExhausted(ctx context.Context, prescriptionId int64) error {
+	_, err := r.db.Exec(ctx, database.SetPrescriptionOrderedAtNowIfExhaustedQuery, prescriptionId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *prescriptionRepositoryPostgres) RefillOneIfExhausted(ctx context.Context, prescriptionId int64) error {
+	_, err := r.db.Exec(ctx, database.RefillOnePrescriptionIfExhaustedQuery, prescriptionId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *prescriptionRepositoryPostgres) GetAllRedemptions(ctx context.Context, prescriptionId int64) ([]entity.PrescriptionRedemption, error) {
+	rows, err := r.db.Query(ctx, database.FindAllPrescriptionRedemptionsQuery, prescriptionId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	redemptions := []entity.PrescriptionRedemption{}
+
+	for rows.Next() {
+		var redemption entity.PrescriptionRedemption
+
+		err := rows.Scan(
+			&redemption.OrderId,
+			&redemption.OrderPharmacyId,
+			&redemption.OrderStatusId,
+			&redemption.OrderItemId,
+			&redemption.PrescriptionDrugId,
+			&redemption.DrugId,
+			&redemption.DrugName,
+			&redemption.Quantity,
+			&redemption.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		redemptions = append(redemptions, redemption)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return redemptions, nil
+}
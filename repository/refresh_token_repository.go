@@ -7,12 +7,14 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/sidiqPratomo/max-health-backend/database"
+	"github.com/sidiqPratomo/max-health-backend/entity"
 )
 
 type RefreshTokenRepository interface {
 	InvalidateCodes(ctx context.Context, accountId int64) error
-	PostOneCode(ctx context.Context, accountId int64, code string) error
-	FindOneCode(ctx context.Context, refreshToken string) (string, error)
+	PostOneCode(ctx context.Context, accountId int64, accountSessionId int64, code string) error
+	FindOneCodeForUpdate(ctx context.Context, refreshToken string) (*entity.RefreshToken, error)
+	UpdateUsedAtOne(ctx context.Context, refreshTokenId int64) error
 }
 
 type refreshTokenRepositoryPostgres struct {
@@ -34,8 +36,8 @@ func (r *refreshTokenRepositoryPostgres) InvalidateCodes(ctx context.Context, ac
 	return nil
 }
 
-func (r *refreshTokenRepositoryPostgres) PostOneCode(ctx context.Context, accountId int64, code string) error {
-	_, err := r.db.Exec(ctx, database.PostOneRefreshTokenQuery, accountId, code)
+func (r *refreshTokenRepositoryPostgres) PostOneCode(ctx context.Context, accountId int64, accountSessionId int64, code string) error {
+	_, err := r.db.Exec(ctx, database.PostOneRefreshTokenQuery, accountId, accountSessionId, code)
 	if err != nil {
 		return err
 	}
@@ -43,14 +45,25 @@ func (r *refreshTokenRepositoryPostgres) PostOneCode(ctx context.Context, accoun
 	return nil
 }
 
-func (r *refreshTokenRepositoryPostgres) FindOneCode(ctx context.Context, refreshToken string) (string, error) {
-	err := r.db.QueryRow(ctx, database.FindOneRefreshTokenQuery, refreshToken).Scan(&refreshToken)
+func (r *refreshTokenRepositoryPostgres) FindOneCodeForUpdate(ctx context.Context, refreshToken string) (*entity.RefreshToken, error) {
+	token := entity.RefreshToken{Token: refreshToken}
+
+	err := r.db.QueryRow(ctx, database.FindOneRefreshTokenForUpdateQuery, refreshToken).Scan(&token.Id, &token.AccountId, &token.AccountSessionId, &token.IsUsed, &token.IsExpired)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return "", nil
+			return nil, nil
 		}
-		return "", err
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+func (r *refreshTokenRepositoryPostgres) UpdateUsedAtOne(ctx context.Context, refreshTokenId int64) error {
+	_, err := r.db.Exec(ctx, database.UpdateUsedAtOneRefreshTokenQuery, refreshTokenId)
+	if err != nil {
+		return err
 	}
 
-	return refreshToken, nil
+	return nil
 }
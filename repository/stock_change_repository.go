@@ -2,21 +2,24 @@ package repository
 
 import (
 	"context"
-	// "database/sql"
+	"math"
 	"strconv"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sidiqPratomo/max-health-backend/appconstant"
 	"github.com/sidiqPratomo/max-health-backend/database"
-	"github.com/sidiqPratomo/max-health-backend/dto"
 	"github.com/sidiqPratomo/max-health-backend/entity"
 )
 
 type StockChangeRepository interface {
-	PostStockChangesByCartIds(ctx context.Context, cartItems []entity.CartItemChanges) error
+	PostStockChangesByCartIds(ctx context.Context, cartItems []entity.CartItemChanges, actorAccountId int64) error
 	PostStockChangesFromMutation(ctx context.Context, stockChangesList []entity.StockChange) error
 	PostStockChanges(ctx context.Context, stockChanges []entity.StockChange) error
 	PostStockChangesFromUpdate(ctx context.Context, stockChanges []entity.StockChange) error
-	GetStockChanges(ctx context.Context, managerId int64, pharmacyId *int64) ([]dto.StockChangeResponse, error)
+	GetStockChanges(ctx context.Context, stockChangeFilter entity.StockChangeFilter) ([]entity.StockChangeDetail, *entity.PageInfo, error)
+	StreamStockChanges(ctx context.Context, stockChangeFilter entity.StockChangeFilter, onStockChange func(entity.StockChangeDetail) error) error
+	FindAllAlertUncheckedForUpdate(ctx context.Context, limit int) ([]entity.ReorderThresholdStockChange, error)
+	MarkAlertChecked(ctx context.Context, stockChangeIds []int64) error
 }
 
 type stockChangeRepositoryPostgres struct {
@@ -29,18 +32,19 @@ func NewStockChangeRepositoryPostgres(db *pgxpool.Pool) stockChangeRepositoryPos
 	}
 }
 
-func (r *stockChangeRepositoryPostgres) PostStockChangesByCartIds(ctx context.Context, cartItems []entity.CartItemChanges) error {
+func (r *stockChangeRepositoryPostgres) createStockChanges(ctx context.Context, stockChanges []entity.StockChange) error {
 	query := database.CreateStockChanges
-
 	args := []interface{}{}
-	for i, cartItem := range cartItems {
+	for i, stockChange := range stockChanges {
 		query += `($` + strconv.Itoa(len(args)+1) + `, $` + strconv.Itoa(len(args)+2) + `, $` + strconv.Itoa(len(args)+3) +
-			`, $` + strconv.Itoa(len(args)+4) + `)`
-		args = append(args, cartItem.PharmacyDrugId)
-		args = append(args, cartItem.Stock-cartItem.Quantity)
-		args = append(args, -1*cartItem.Quantity)
-		args = append(args, "bought by customer")
-		if i != len(cartItems)-1 {
+			`, $` + strconv.Itoa(len(args)+4) + `, $` + strconv.Itoa(len(args)+5) + `, $` + strconv.Itoa(len(args)+6) + `)`
+		args = append(args, stockChange.PharmacyDrugId)
+		args = append(args, stockChange.FinalStock)
+		args = append(args, stockChange.Amount)
+		args = append(args, stockChange.Description)
+		args = append(args, stockChange.ChangeType)
+		args = append(args, stockChange.ActorAccountId)
+		if i != len(stockChanges)-1 {
 			query += `,`
 		}
 	}
@@ -51,90 +55,214 @@ func (r *stockChangeRepositoryPostgres) PostStockChangesByCartIds(ctx context.Co
 	return nil
 }
 
-func (r *stockChangeRepositoryPostgres) PostStockChangesFromMutation(ctx context.Context, stockChangesList []entity.StockChange) error {
-	query := database.CreateStockChanges
-	args := []interface{}{}
-	for i, stockChange := range stockChangesList {
-		query += `($` + strconv.Itoa(len(args)+1) + `, $` + strconv.Itoa(len(args)+2) + `, $` + strconv.Itoa(len(args)+3) +
-			`, $` + strconv.Itoa(len(args)+4) + `)`
-		args = append(args, stockChange.PharmacyDrugId)
-		args = append(args, stockChange.FinalStock)
-		args = append(args, stockChange.Amount)
-		args = append(args, "transfer from stock mutation")
-		if i != len(stockChangesList)-1 {
-			query += `,`
-		}
+func (r *stockChangeRepositoryPostgres) PostStockChangesByCartIds(ctx context.Context, cartItems []entity.CartItemChanges, actorAccountId int64) error {
+	stockChanges := []entity.StockChange{}
+	for _, cartItem := range cartItems {
+		stockChanges = append(stockChanges, entity.StockChange{
+			PharmacyDrugId: cartItem.PharmacyDrugId,
+			FinalStock:     cartItem.Stock - cartItem.Quantity,
+			Amount:         -1 * cartItem.Quantity,
+			Description:    "bought by customer",
+			ChangeType:     appconstant.StockChangeTypeSale,
+			ActorAccountId: &actorAccountId,
+		})
 	}
+	return r.createStockChanges(ctx, stockChanges)
+}
 
-	_, err := r.db.Exec(ctx, query, args...)
-	if err != nil {
-		return err
+func (r *stockChangeRepositoryPostgres) PostStockChangesFromMutation(ctx context.Context, stockChangesList []entity.StockChange) error {
+	for i := range stockChangesList {
+		stockChangesList[i].ChangeType = appconstant.StockChangeTypeMutation
 	}
-	return nil
+	return r.createStockChanges(ctx, stockChangesList)
 }
 
 func (r *stockChangeRepositoryPostgres) PostStockChanges(ctx context.Context, stockChanges []entity.StockChange) error {
-	query := database.CreateStockChanges
-	args := []interface{}{}
-	for i, stockChange := range stockChanges {
-		query += `($` + strconv.Itoa(len(args)+1) + `, $` + strconv.Itoa(len(args)+2) + `, $` + strconv.Itoa(len(args)+3) + `, $` + strconv.Itoa(len(args)+4) + `)`
-		if i != len(stockChanges)-1 {
-			query += `,`
+	for i := range stockChanges {
+		stockChanges[i].Description = "transfer from cancelled order"
+		stockChanges[i].ChangeType = appconstant.StockChangeTypeCancellation
+	}
+	return r.createStockChanges(ctx, stockChanges)
+}
+
+func (r *stockChangeRepositoryPostgres) PostStockChangesFromUpdate(ctx context.Context, stockChanges []entity.StockChange) error {
+	for i := range stockChanges {
+		if stockChanges[i].ChangeType == "" {
+			stockChanges[i].ChangeType = appconstant.StockChangeTypeManualAdjustment
 		}
-		args = append(args, stockChange.PharmacyDrugId)
-		args = append(args, stockChange.FinalStock)
-		args = append(args, stockChange.Amount)
-		args = append(args, "transfer from cancelled order")
 	}
-	_, err := r.db.Exec(ctx, query, args...)
-	if err != nil {
-		return err
+	return r.createStockChanges(ctx, stockChanges)
+}
+
+func stockChangeFilterQuery(stockChangeFilter entity.StockChangeFilter) (string, []interface{}) {
+	query := database.GetStockChangesFrom
+	args := []interface{}{stockChangeFilter.ManagerId}
+
+	if stockChangeFilter.PharmacyId != nil {
+		query += ` AND p.pharmacy_id = $` + strconv.Itoa(len(args)+1)
+		args = append(args, *stockChangeFilter.PharmacyId)
 	}
-	return nil
+	if stockChangeFilter.DrugId != nil {
+		query += ` AND d.drug_id = $` + strconv.Itoa(len(args)+1)
+		args = append(args, *stockChangeFilter.DrugId)
+	}
+	if stockChangeFilter.ChangeType != nil {
+		query += ` AND sc.change_type = $` + strconv.Itoa(len(args)+1)
+		args = append(args, *stockChangeFilter.ChangeType)
+	}
+	if stockChangeFilter.AmountSign != nil {
+		switch *stockChangeFilter.AmountSign {
+		case appconstant.StockChangeSignPositive:
+			query += ` AND sc.amount > 0`
+		case appconstant.StockChangeSignNegative:
+			query += ` AND sc.amount < 0`
+		}
+	}
+	if stockChangeFilter.StartDate != nil {
+		query += ` AND sc.created_at >= $` + strconv.Itoa(len(args)+1)
+		args = append(args, *stockChangeFilter.StartDate)
+	}
+	if stockChangeFilter.EndDate != nil {
+		query += ` AND sc.created_at < $` + strconv.Itoa(len(args)+1)
+		args = append(args, *stockChangeFilter.EndDate)
+	}
+
+	query += ` ORDER BY sc.created_at, sc.stock_change_id`
+
+	return query, args
 }
 
-func (r *stockChangeRepositoryPostgres) PostStockChangesFromUpdate(ctx context.Context, stockChanges []entity.StockChange) error {
-	query := database.CreateStockChanges
-	args := []interface{}{}
-	for i, stockChange := range stockChanges {
-		query += `($` + strconv.Itoa(len(args)+1) + `, $` + strconv.Itoa(len(args)+2) + `, $` + strconv.Itoa(len(args)+3) + `, $` + strconv.Itoa(len(args)+4) + `)`
-		if i != len(stockChanges)-1 {
-			query += `,`
+func stockChangeDetailScanDest(stockChange *entity.StockChangeDetail) []interface{} {
+	return []interface{}{
+		&stockChange.Id,
+		&stockChange.PharmacyDrugId,
+		&stockChange.PharmacyId,
+		&stockChange.PharmacyName,
+		&stockChange.PharmacyAddress,
+		&stockChange.DrugId,
+		&stockChange.DrugName,
+		&stockChange.DrugImage,
+		&stockChange.FinalStock,
+		&stockChange.Amount,
+		&stockChange.Description,
+		&stockChange.ChangeType,
+		&stockChange.ActorAccountId,
+		&stockChange.ActorName,
+		&stockChange.CreatedAt,
+	}
+}
+
+func (r *stockChangeRepositoryPostgres) GetStockChanges(ctx context.Context, stockChangeFilter entity.StockChangeFilter) ([]entity.StockChangeDetail, *entity.PageInfo, error) {
+	filterQuery, args := stockChangeFilterQuery(stockChangeFilter)
+	query := database.GetStockChanges + database.GetStockChangesCount + filterQuery
+
+	query += ` LIMIT $` + strconv.Itoa(len(args)+1)
+	args = append(args, stockChangeFilter.Limit)
+	query += ` OFFSET $` + strconv.Itoa(len(args)+1)
+	args = append(args, stockChangeFilter.Offset)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	stockChanges := []entity.StockChangeDetail{}
+	pageInfo := entity.PageInfo{}
+
+	for rows.Next() {
+		var stockChange entity.StockChangeDetail
+
+		dest := append(stockChangeDetailScanDest(&stockChange), &pageInfo.ItemCount)
+
+		err := rows.Scan(dest...)
+		if err != nil {
+			return nil, nil, err
 		}
-		args = append(args, stockChange.PharmacyDrugId)
-		args = append(args, stockChange.FinalStock)
-		args = append(args, stockChange.Amount)
-		args = append(args, stockChange.Description)
+
+		stockChanges = append(stockChanges, stockChange)
 	}
-	_, err := r.db.Exec(ctx, query, args...)
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	pageInfo.PageCount = int(math.Ceil(float64(pageInfo.ItemCount) / float64(stockChangeFilter.Limit)))
+	pageInfo.Page = int(math.Ceil(float64(stockChangeFilter.Offset+1) / float64(stockChangeFilter.Limit)))
+
+	return stockChanges, &pageInfo, nil
+}
+
+// Rows are handed over one at a time so an export never holds the whole
+// ledger in memory.
+func (r *stockChangeRepositoryPostgres) StreamStockChanges(ctx context.Context, stockChangeFilter entity.StockChangeFilter, onStockChange func(entity.StockChangeDetail) error) error {
+	filterQuery, args := stockChangeFilterQuery(stockChangeFilter)
+
+	rows, err := r.db.Query(ctx, database.GetStockChanges+filterQuery, args...)
 	if err != nil {
 		return err
 	}
-	return nil
-}
+	defer rows.Close()
 
-func (r *stockChangeRepositoryPostgres) GetStockChanges(ctx context.Context, managerId int64, pharmacyId *int64) ([]dto.StockChangeResponse, error) {
-	stockChanges := []dto.StockChangeResponse{}
-	query := database.GetStockChanges
-	args := []interface{}{}
-	args = append(args, managerId)
-	if pharmacyId != nil {
-		query += `AND p.pharmacy_id = $2 `
-		args = append(args, *pharmacyId)
+	for rows.Next() {
+		var stockChange entity.StockChangeDetail
+
+		err := rows.Scan(stockChangeDetailScanDest(&stockChange)...)
+		if err != nil {
+			return err
+		}
+
+		err = onStockChange(stockChange)
+		if err != nil {
+			return err
+		}
 	}
-	query += `ORDER BY sc.created_at`
-	rows, err := r.db.Query(ctx, query, args...)
+
+	return rows.Err()
+}
+
+func (r *stockChangeRepositoryPostgres) FindAllAlertUncheckedForUpdate(ctx context.Context, limit int) ([]entity.ReorderThresholdStockChange, error) {
+	rows, err := r.db.Query(ctx, database.FindAllAlertUncheckedStockChangesForUpdate, limit)
 	if err != nil {
-		return stockChanges, err
+		return nil, err
 	}
 	defer rows.Close()
+
+	stockChanges := []entity.ReorderThresholdStockChange{}
+
 	for rows.Next() {
-		var stockChange dto.StockChangeResponse
-		err = rows.Scan(&stockChange.PharmacyName, &stockChange.PharmacyAddress, &stockChange.DrugName, &stockChange.DrugImage, &stockChange.FinalStock, &stockChange.Change, &stockChange.Description)
+		var stockChange entity.ReorderThresholdStockChange
+
+		err := rows.Scan(
+			&stockChange.Id,
+			&stockChange.PharmacyDrugId,
+			&stockChange.FinalStock,
+			&stockChange.Amount,
+			&stockChange.ReorderThreshold,
+		)
 		if err != nil {
-			return []dto.StockChangeResponse{}, err
+			return nil, err
 		}
+
 		stockChanges = append(stockChanges, stockChange)
 	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
 	return stockChanges, nil
 }
+
+func (r *stockChangeRepositoryPostgres) MarkAlertChecked(ctx context.Context, stockChangeIds []int64) error {
+	if len(stockChangeIds) == 0 {
+		return nil
+	}
+
+	_, err := r.db.Exec(ctx, database.MarkStockChangesAlertChecked, stockChangeIds)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -3,16 +3,24 @@ package repository
 import (
 	"context"
 	// "database/sql"
+	"math"
 	"strconv"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sidiqPratomo/max-health-backend/appconstant"
 	"github.com/sidiqPratomo/max-health-backend/database"
 	"github.com/sidiqPratomo/max-health-backend/entity"
 )
 
 type StockMutationRepository interface {
 	GetPossibleStockMutation(ctx context.Context, cartItems []entity.CartItemForCheckout) ([]entity.PossibleStockMutation, error)
-	PostStockMutations(ctx context.Context, stockMutationList []entity.PossibleStockMutation) error
+	PostStockMutations(ctx context.Context, stockMutationList []entity.PossibleStockMutation, orderId *int64) error
+	FindOneById(ctx context.Context, stockMutationRequestId int64) (*entity.StockMutationRequest, error)
+	FindOneByIdForUpdate(ctx context.Context, stockMutationRequestId int64) (*entity.StockMutationRequest, error)
+	FindAll(ctx context.Context, stockMutationRequestFilter entity.StockMutationRequestFilter) ([]entity.StockMutationRequest, *entity.PageInfo, error)
+	UpdateStatusOne(ctx context.Context, stockMutationRequestId int64, status string) (int64, error)
+	CancelPendingByOrderId(ctx context.Context, orderId int64) error
 }
 
 type stockMutationRepositoryPostgres struct {
@@ -67,7 +75,7 @@ func (r *stockMutationRepositoryPostgres) GetPossibleStockMutation(ctx context.C
 	return alternatives, nil
 }
 
-func (r *stockMutationRepositoryPostgres) PostStockMutations(ctx context.Context, stockMutationList []entity.PossibleStockMutation) error {
+func (r *stockMutationRepositoryPostgres) PostStockMutations(ctx context.Context, stockMutationList []entity.PossibleStockMutation, orderId *int64) error {
 	query := database.CreateStockMutations
 	args := []interface{}{}
 	for i, stockMutation := range stockMutationList {
@@ -77,7 +85,7 @@ func (r *stockMutationRepositoryPostgres) PostStockMutations(ctx context.Context
 		args = append(args, stockMutation.AlternativePharmacy)
 		args = append(args, stockMutation.DrugId)
 		args = append(args, stockMutation.AlternativeStock)
-		args = append(args, 2)
+		args = append(args, orderId)
 		if i != len(stockMutationList) - 1 {
 			query += `,`
 		}
@@ -88,4 +96,121 @@ func (r *stockMutationRepositoryPostgres) PostStockMutations(ctx context.Context
 		return err
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+func stockMutationRequestScanDest(stockMutationRequest *entity.StockMutationRequest) []interface{} {
+	return []interface{}{
+		&stockMutationRequest.Id,
+		&stockMutationRequest.PharmacyRequesterId,
+		&stockMutationRequest.PharmacyRequesterName,
+		&stockMutationRequest.RequesterManagerId,
+		&stockMutationRequest.RequesterPharmacyDrugId,
+		&stockMutationRequest.PharmacyTargetId,
+		&stockMutationRequest.PharmacyTargetName,
+		&stockMutationRequest.TargetManagerId,
+		&stockMutationRequest.TargetPharmacyDrugId,
+		&stockMutationRequest.DrugId,
+		&stockMutationRequest.DrugName,
+		&stockMutationRequest.Stock,
+		&stockMutationRequest.OrderId,
+		&stockMutationRequest.Status,
+		&stockMutationRequest.RespondedAt,
+		&stockMutationRequest.ShippedAt,
+		&stockMutationRequest.ReceivedAt,
+		&stockMutationRequest.CreatedAt,
+	}
+}
+
+func (r *stockMutationRepositoryPostgres) findOne(ctx context.Context, query string, stockMutationRequestId int64) (*entity.StockMutationRequest, error) {
+	var stockMutationRequest entity.StockMutationRequest
+
+	err := r.db.QueryRow(ctx, query, stockMutationRequestId).Scan(stockMutationRequestScanDest(&stockMutationRequest)...)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &stockMutationRequest, nil
+}
+
+func (r *stockMutationRepositoryPostgres) FindOneById(ctx context.Context, stockMutationRequestId int64) (*entity.StockMutationRequest, error) {
+	return r.findOne(ctx, database.FindOneStockMutationRequestByIdQuery, stockMutationRequestId)
+}
+
+func (r *stockMutationRepositoryPostgres) FindOneByIdForUpdate(ctx context.Context, stockMutationRequestId int64) (*entity.StockMutationRequest, error) {
+	return r.findOne(ctx, database.FindOneStockMutationRequestByIdForUpdateQuery, stockMutationRequestId)
+}
+
+func (r *stockMutationRepositoryPostgres) FindAll(ctx context.Context, stockMutationRequestFilter entity.StockMutationRequestFilter) ([]entity.StockMutationRequest, *entity.PageInfo, error) {
+	query := database.FindAllStockMutationRequestsQuery
+	args := []interface{}{}
+
+	if stockMutationRequestFilter.Direction == appconstant.StockMutationIncoming {
+		query += ` AND tp.pharmacy_manager_id = $` + strconv.Itoa(len(args)+1)
+	} else {
+		query += ` AND rp.pharmacy_manager_id = $` + strconv.Itoa(len(args)+1)
+	}
+	args = append(args, stockMutationRequestFilter.ManagerId)
+
+	if stockMutationRequestFilter.Status != nil {
+		query += ` AND smr.mutation_status = $` + strconv.Itoa(len(args)+1)
+		args = append(args, *stockMutationRequestFilter.Status)
+	}
+
+	query += ` ORDER BY smr.created_at DESC, smr.stock_mutation_request_id DESC`
+
+	query += ` LIMIT $` + strconv.Itoa(len(args)+1)
+	args = append(args, stockMutationRequestFilter.Limit)
+	query += ` OFFSET $` + strconv.Itoa(len(args)+1)
+	args = append(args, stockMutationRequestFilter.Offset)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	stockMutationRequests := []entity.StockMutationRequest{}
+	pageInfo := entity.PageInfo{}
+
+	for rows.Next() {
+		var stockMutationRequest entity.StockMutationRequest
+
+		err := rows.Scan(append(stockMutationRequestScanDest(&stockMutationRequest), &pageInfo.ItemCount)...)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		stockMutationRequests = append(stockMutationRequests, stockMutationRequest)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	pageInfo.PageCount = int(math.Ceil(float64(pageInfo.ItemCount) / float64(stockMutationRequestFilter.Limit)))
+	pageInfo.Page = int(math.Ceil(float64(stockMutationRequestFilter.Offset+1) / float64(stockMutationRequestFilter.Limit)))
+
+	return stockMutationRequests, &pageInfo, nil
+}
+
+func (r *stockMutationRepositoryPostgres) UpdateStatusOne(ctx context.Context, stockMutationRequestId int64, status string) (int64, error) {
+	result, err := r.db.Exec(ctx, database.UpdateStockMutationRequestStatusOneQuery, stockMutationRequestId, status)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+func (r *stockMutationRepositoryPostgres) CancelPendingByOrderId(ctx context.Context, orderId int64) error {
+	_, err := r.db.Exec(ctx, database.CancelPendingStockMutationRequestsByOrderIdQuery, orderId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -74,6 +74,15 @@ type Transaction interface {
 	PharmacyRepository() PharmacyRepository
 	PharmacyOperationalRepository() PharmacyOperationalRepository
 	PharmacyCourierRepository() PharmacyCourierRepository
+	AccountSessionRepository() AccountSessionRepository
+	ChatRoomRepository() ChatRoomRepository
+	ConsultationPaymentRepository() ConsultationPaymentRepository
+	ChatRoomExtensionRepository() ChatRoomExtensionRepository
+	DoctorReviewRepository() DoctorReviewRepository
+	DoctorScheduleRepository() DoctorScheduleRepository
+	AppointmentRepository() AppointmentRepository
+	PharmacyDrugBatchRepository() PharmacyDrugBatchRepository
+	LowStockAlertRepository() LowStockAlertRepository
 }
 
 type SqlTransaction struct {
@@ -257,3 +266,57 @@ func (s *SqlTransaction) PharmacyCourierRepository() PharmacyCourierRepository {
 		db: s.tx,
 	}
 }
+
+func (s *SqlTransaction) AccountSessionRepository() AccountSessionRepository {
+	return &accountSessionRepositoryPostgres{
+		db: s.tx,
+	}
+}
+
+func (s *SqlTransaction) ChatRoomRepository() ChatRoomRepository {
+	return &chatRoomRepositoryPostgres{
+		db: s.tx,
+	}
+}
+
+func (s *SqlTransaction) ConsultationPaymentRepository() ConsultationPaymentRepository {
+	return &consultationPaymentRepositoryPostgres{
+		db: s.tx,
+	}
+}
+
+func (s *SqlTransaction) ChatRoomExtensionRepository() ChatRoomExtensionRepository {
+	return &chatRoomExtensionRepositoryPostgres{
+		db: s.tx,
+	}
+}
+
+func (s *SqlTransaction) DoctorReviewRepository() DoctorReviewRepository {
+	return &doctorReviewRepositoryPostgres{
+		db: s.tx,
+	}
+}
+
+func (s *SqlTransaction) DoctorScheduleRepository() DoctorScheduleRepository {
+	return &doctorScheduleRepositoryPostgres{
+		db: s.tx,
+	}
+}
+
+func (s *SqlTransaction) AppointmentRepository() AppointmentRepository {
+	return &appointmentRepositoryPostgres{
+		db: s.tx,
+	}
+}
+
+func (s *SqlTransaction) PharmacyDrugBatchRepository() PharmacyDrugBatchRepository {
+	return &pharmacyDrugBatchRepositoryPostgres{
+		db: s.tx,
+	}
+}
+
+func (s *SqlTransaction) LowStockAlertRepository() LowStockAlertRepository {
+	return &lowStockAlertRepositoryPostgres{
+		db: s.tx,
+	}
+}
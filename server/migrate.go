@@ -0,0 +1,128 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sidiqPratomo/max-health-backend/config"
+	"github.com/sidiqPratomo/max-health-backend/database"
+	"github.com/sidiqPratomo/max-health-backend/migration"
+	"github.com/sidiqPratomo/max-health-backend/util"
+	"github.com/sirupsen/logrus"
+)
+
+const migrateUsage = "usage: migrate up | down [steps] | status | create <name>"
+
+func Migrate(args []string) {
+	log := util.NewLogger()
+
+	if len(args) == 0 {
+		log.Fatal(migrateUsage)
+	}
+
+	if args[0] == "create" {
+		if len(args) != 2 {
+			log.Fatal(migrateUsage)
+		}
+
+		paths, err := migration.Create(args[1])
+		if err != nil {
+			log.WithFields(logrus.Fields{
+				"error": err.Error(),
+			}).Fatal("error creating migration")
+		}
+
+		for _, path := range paths {
+			log.Infof("created %s", path)
+		}
+		return
+	}
+
+	if args[0] != "up" && args[0] != "down" && args[0] != "status" {
+		log.Fatal(migrateUsage)
+	}
+
+	config := config.InitDatabase(log)
+	db := database.ConnectDB(config, log)
+	defer db.Close()
+
+	migrator, err := migration.NewMigrator(db)
+	if err != nil {
+		log.WithFields(logrus.Fields{
+			"error": err.Error(),
+		}).Fatal("error loading migrations")
+	}
+
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		migrations, err := migrator.Up(ctx)
+		for _, applied := range migrations {
+			log.Infof("applied %06d_%s", applied.Version, applied.Name)
+		}
+		if err != nil {
+			log.WithFields(logrus.Fields{
+				"error": err.Error(),
+			}).Fatal("error applying migrations")
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				log.Fatal(migrateUsage)
+			}
+		}
+
+		migrations, err := migrator.Down(ctx, steps)
+		for _, reverted := range migrations {
+			log.Infof("reverted %06d_%s", reverted.Version, reverted.Name)
+		}
+		if err != nil {
+			log.WithFields(logrus.Fields{
+				"error": err.Error(),
+			}).Fatal("error reverting migrations")
+		}
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			log.WithFields(logrus.Fields{
+				"error": err.Error(),
+			}).Fatal("error reading migration status")
+		}
+
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%06d  %-40s  %s\n", status.Version, status.Name, appliedAt)
+		}
+	}
+}
+
+func checkMigrationVersion(ctx context.Context, log *logrus.Logger, db *pgxpool.Pool) {
+	migrator, err := migration.NewMigrator(db)
+	if err != nil {
+		log.WithFields(logrus.Fields{
+			"error": err.Error(),
+		}).Fatal("error loading migrations")
+	}
+
+	currentVersion, err := migrator.CurrentVersion(ctx)
+	if err != nil {
+		log.WithFields(logrus.Fields{
+			"error": err.Error(),
+		}).Fatal("error reading migration version")
+	}
+
+	if currentVersion < migrator.LatestVersion() {
+		log.WithFields(logrus.Fields{
+			"current_version":  currentVersion,
+			"expected_version": migrator.LatestVersion(),
+		}).Fatal("database schema is behind, run migrate up first")
+	}
+}
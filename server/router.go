@@ -9,6 +9,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/sidiqPratomo/max-health-backend/appconstant"
 	"github.com/sidiqPratomo/max-health-backend/config"
 	"github.com/sidiqPratomo/max-health-backend/database"
 	"github.com/sidiqPratomo/max-health-backend/handler"
@@ -20,9 +21,13 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func createRouter(log *logrus.Logger, config *config.Config) *gin.Engine {
+func createRouter(ctx context.Context, log *logrus.Logger, config *config.Config) *gin.Engine {
 	db := database.ConnectDB(config, log)
 
+	if config.RequireMigrations {
+		checkMigrationVersion(ctx, log, db)
+	}
+
 	accountRepository := repository.NewAccountRepositoryPostgres(db)
 	cartRepository := repository.NewCartRepositoryPostgres(db)
 	userRepository := repository.NewUserRepositoryPostgres(db)
@@ -30,6 +35,7 @@ func createRouter(log *logrus.Logger, config *config.Config) *gin.Engine {
 	verificationCodeRepository := repository.NewVerificationCodeRepositoryPostgres(db)
 	userAddressRepository := repository.NewUserAddressRepositoryPostgres(db)
 	refreshTokenRepository := repository.NewRefreshTokenRepositoryPostgres(db)
+	accountSessionRepository := repository.NewAccountSessionRepositoryPostgres(db)
 	resetPasswordTokenRepository := repository.NewResetPasswordTokenRepositoryPostgres(db)
 	pharmacyManagerRepository := repository.NewpharmacyManagerRepositoryPostgres(db)
 	addressRepository := repository.NewAddressRepositoryPostgres(db)
@@ -40,6 +46,7 @@ func createRouter(log *logrus.Logger, config *config.Config) *gin.Engine {
 	categoryRepository := repository.NewCategoryRepositoryPostgres(db)
 	chatRepository := repository.NewChatRepositoryPostgres(db)
 	chatRoomRepository := repository.NewChatRoomRepositoryPostgres(db)
+	chatEventRepository := repository.NewChatEventRepositoryPostgres(db)
 	doctorSpecializationRepository := repository.NewDoctorSpecializationRepositoryPostgres(db)
 	prescriptionRepository := repository.NewPrescriptionRepositoryPostgres(db)
 	prescriptionDrugRepository := repository.NewPrescriptionDrugRepositoryPostgres(db)
@@ -49,6 +56,15 @@ func createRouter(log *logrus.Logger, config *config.Config) *gin.Engine {
 	courierRepository := repository.NewCourierRepositoryPostgres(db)
 	orderItemRepository := repository.NewOrderItemRepositoryPostgres(db)
 	stockRepository := repository.NewStockChangeRepositoryPostgres(db)
+	pharmacyOperationalRepository := repository.NewPharmacyOperationalRepositoryPostgres(db)
+	pharmacyHolidayRepository := repository.NewPharmacyHolidayRepositoryPostgres(db)
+	consultationPaymentRepository := repository.NewConsultationPaymentRepositoryPostgres(db)
+	doctorReviewRepository := repository.NewDoctorReviewRepositoryPostgres(db)
+	doctorScheduleRepository := repository.NewDoctorScheduleRepositoryPostgres(db)
+	appointmentRepository := repository.NewAppointmentRepositoryPostgres(db)
+	stockMutationRepository := repository.NewStockMutationRepositoryPostgres(db)
+	pharmacyDrugBatchRepository := repository.NewPharmacyDrugBatchRepositoryPostgres(db)
+	lowStockAlertRepository := repository.NewLowStockAlertRepositoryPostgres(db)
 	transaction := repository.NewSqlTransaction(db)
 	emailHelper := util.NewEmailHelperIpl(config)
 	jwtAuthentication := util.JwtAuthentication{
@@ -56,6 +72,22 @@ func createRouter(log *logrus.Logger, config *config.Config) *gin.Engine {
 		Method: jwt.SigningMethodHS256,
 	}
 	hashHelper := &util.HashHelperImpl{}
+	prescriptionSigner := util.NewHmacPrescriptionSigner(config.PrescriptionSecret)
+
+	var chatBroker usecase.ChatBroker = usecase.NewInProcessChatBroker()
+	if config.ChatBroker == appconstant.PostgresChatBroker {
+		chatBroker = usecase.NewPostgresChatBroker(ctx, &chatEventRepository, &chatRepository, &prescriptionDrugRepository, chatBroker, log)
+	}
+
+	var shippingRateProvider util.ShippingRateProvider = util.NewRajaOngkirShippingRateProvider(config.RajaOngkirApiKey)
+	if config.ShippingRateProvider == appconstant.LocalShippingRateProvider {
+		shippingRateProvider = util.NewLocalShippingRateProvider(util.DefaultLocalShippingTariffs)
+	}
+	if config.ShippingRateCacheTtl > 0 {
+		shippingRateProvider = util.NewCachingShippingRateProvider(shippingRateProvider, time.Duration(config.ShippingRateCacheTtl)*time.Second)
+	}
+
+	accountTokenVersion := usecase.NewAccountTokenVersionImpl(&accountRepository, time.Duration(config.TokenVersionCacheTtl)*time.Second)
 
 	authenticationUsecase := usecase.NewAuthenticationUsecaseImpl(usecase.AuthenticationUsecaseImplOpts{
 		DrugRepository:               &drugRepository,
@@ -70,6 +102,7 @@ func createRouter(log *logrus.Logger, config *config.Config) *gin.Engine {
 		HashHelper:                   hashHelper,
 		JwtHelper:                    jwtAuthentication,
 		EmailHelper:                  &emailHelper,
+		AccountTokenVersion:          accountTokenVersion,
 	})
 
 	userUsecase := usecase.NewUserUsecaseImpl(&accountRepository, transaction, &userRepository, &userAddressRepository, &util.HashHelperImpl{})
@@ -81,13 +114,21 @@ func createRouter(log *logrus.Logger, config *config.Config) *gin.Engine {
 		Transaction:               transaction,
 		HashHelper:                hashHelper,
 		EmailHelper:               &emailHelper,
+		AccountTokenVersion:       accountTokenVersion,
 	})
 	addressUsecase := usecase.NewAddressUsecaseImpl(&addressRepository)
 	categoryUsecase := usecase.NewCategoryUsecaseImpl(&categoryRepository)
 
-	drugUsecase := usecase.NewDrugUsecaseImpl(transaction, &drugRepository, &drugPharmacyRepository, &drugClassificationRepository, &drugFormRepository, &categoryRepository, &pharmacyRepository)
+	drugSearchRefreshUsecase := usecase.NewDrugSearchRefreshUsecaseImpl(&drugRepository)
+	startDrugSearchRefresher(ctx, log, appconstant.DrugSearchRefreshDelay, &drugSearchRefreshUsecase)
+
+	drugUsecase := usecase.NewDrugUsecaseImpl(transaction, &drugRepository, &drugPharmacyRepository, &drugClassificationRepository, &drugFormRepository, &categoryRepository, &pharmacyRepository, &drugSearchRefreshUsecase)
 	drugFormUsecase := usecase.NewdrugFormUsecaseImpl(&drugFormRepository)
 	drugClassificationUsecase := usecase.NewDrugClassificationUsecaseImpl(&drugClassificationRepository)
+	prescriptionCompliance := usecase.NewPrescriptionComplianceImpl(&prescriptionDrugRepository)
+	fulfilmentOptimizer := usecase.NewFulfilmentOptimizerImpl(&drugPharmacyRepository, &pharmacyRepository, shippingRateProvider)
+	consultationQueue := usecase.NewConsultationQueueImpl(&chatRoomRepository, time.Duration(config.ConsultationJoinTimeout)*time.Second)
+	pricingEngine := usecase.NewPricingEngineImpl(&cartRepository, shippingRateProvider)
 	telemedicineUsecase := usecase.NewTelemedicineUsecaseImpl(
 		&chatRoomRepository,
 		&chatRepository,
@@ -100,16 +141,58 @@ func createRouter(log *logrus.Logger, config *config.Config) *gin.Engine {
 		&orderRepository,
 		&userAddressRepository,
 		&pharmacyRepository,
+		chatBroker,
+		&prescriptionCompliance,
+		&fulfilmentOptimizer,
+		&consultationQueue,
+		&pricingEngine,
 		transaction,
 	)
 
-	pharmacyUsecase := usecase.NewPharmacyUsecaseImpl(&pharmacyManagerRepository, &pharmacyRepository, &drugPharmacyRepository, &addressRepository, &courierRepository, &orderPharmacyRepository, transaction)
+	pharmacyHours := usecase.NewPharmacyHoursImpl(&pharmacyRepository, &pharmacyOperationalRepository, &pharmacyHolidayRepository)
+	pharmacyUsecase := usecase.NewPharmacyUsecaseImpl(&pharmacyManagerRepository, &pharmacyRepository, &drugPharmacyRepository, &addressRepository, &courierRepository, &orderPharmacyRepository, &pharmacyHolidayRepository, &pharmacyHours, transaction)
 
-	cartUsecase := usecase.NewCartUsecaseImpl(&drugPharmacyRepository, &userRepository, &userAddressRepository, &cartRepository)
-	orderUsecase := usecase.NewOrderUsecaseImpl(transaction, &userRepository, &orderRepository, &orderPharmacyRepository)
+	cartUsecase := usecase.NewCartUsecaseImpl(&drugPharmacyRepository, &userRepository, &userAddressRepository, &cartRepository, shippingRateProvider, &pharmacyHours, &prescriptionCompliance)
+	orderUsecase := usecase.NewOrderUsecaseImpl(transaction, &userRepository, &userAddressRepository, &orderRepository, &orderPharmacyRepository, &pricingEngine, &pharmacyHours, &prescriptionCompliance)
 	orderPharmacyUsecase := usecase.NewOrderPharmacyUsecaseImpl(transaction, &orderPharmacyRepository, &orderItemRepository, &userRepository, &pharmacyManagerRepository)
 	reportUsecase := usecase.NewreportUsecaseImpl(&orderItemRepository, &pharmacyRepository, &pharmacyManagerRepository)
 	stockUsecase := usecase.NewStockUsecaseImpl(&stockRepository, &pharmacyManagerRepository)
+	accountSessionUsecase := usecase.NewAccountSessionUsecaseImpl(&accountSessionRepository, accountTokenVersion, transaction)
+	adminAccountUsecase := usecase.NewAdminAccountUsecaseImpl(usecase.AdminAccountUsecaseImplOpts{
+		AccountRepository:     &accountRepository,
+		UserRepository:        &userRepository,
+		UserAddressRepository: &userAddressRepository,
+		OrderRepository:       &orderRepository,
+		ChatRoomRepository:    &chatRoomRepository,
+		AuthenticationUsecase: &authenticationUsecase,
+		AccountTokenVersion:   accountTokenVersion,
+		Transaction:           transaction,
+	})
+	prescriptionDocumentUsecase := usecase.NewPrescriptionDocumentUsecaseImpl(&prescriptionRepository, &prescriptionDrugRepository, prescriptionSigner, config.ApiBaseUrl)
+	consultationPaymentUsecase := usecase.NewConsultationPaymentUsecaseImpl(&consultationPaymentRepository, transaction)
+	chatRoomExtensionUsecase := usecase.NewChatRoomExtensionUsecaseImpl(&doctorRepository, &consultationPaymentRepository, chatBroker, transaction)
+	doctorReviewUsecase := usecase.NewDoctorReviewUsecaseImpl(&doctorReviewRepository, &chatRoomRepository, transaction)
+	stockMutationUsecase := usecase.NewStockMutationUsecaseImpl(&stockMutationRepository, &pharmacyManagerRepository, transaction)
+	pharmacyDrugBatchUsecase := usecase.NewPharmacyDrugBatchUsecaseImpl(&pharmacyDrugBatchRepository, &drugPharmacyRepository, &pharmacyRepository, &pharmacyManagerRepository, transaction)
+	pharmacyDrugImportUsecase := usecase.NewPharmacyDrugImportUsecaseImpl(&drugRepository, &drugPharmacyRepository, &pharmacyRepository, &pharmacyManagerRepository, transaction)
+	appointmentUsecase := usecase.NewAppointmentUsecaseImpl(&userRepository, &doctorRepository, &doctorScheduleRepository, &appointmentRepository, &chatRoomRepository, &consultationQueue, chatBroker, transaction)
+
+	orderExpiryEmailHelper := util.NewEmailHelperIpl(config)
+	orderExpiryUsecase := usecase.NewOrderExpiryUsecaseImpl(transaction, &orderExpiryEmailHelper)
+	startOrderExpiryScheduler(ctx, log, time.Duration(config.OrderExpiryInterval)*time.Second, &orderExpiryUsecase)
+
+	consultationExpiryUsecase := usecase.NewConsultationExpiryUsecaseImpl(transaction, &consultationQueue, config.ConsultationPaymentTimeout, config.ConsultationJoinTimeout)
+	startConsultationExpiryScheduler(ctx, log, time.Duration(config.ConsultationExpiryInterval)*time.Second, &consultationExpiryUsecase)
+
+	appointmentEmailHelper := util.NewEmailHelperIpl(config)
+	appointmentSchedulerUsecase := usecase.NewAppointmentSchedulerUsecaseImpl(&doctorScheduleRepository, transaction, &appointmentEmailHelper, config.AppointmentReminderLead)
+	startAppointmentScheduler(ctx, log, time.Duration(config.AppointmentInterval)*time.Second, &appointmentSchedulerUsecase)
+
+	startBatchWriteOffScheduler(ctx, log, time.Duration(config.BatchWriteOffInterval)*time.Second, &pharmacyDrugBatchUsecase)
+
+	lowStockAlertEmailHelper := util.NewEmailHelperIpl(config)
+	lowStockAlertUsecase := usecase.NewLowStockAlertUsecaseImpl(&lowStockAlertRepository, &drugPharmacyRepository, &pharmacyRepository, &pharmacyManagerRepository, transaction, &lowStockAlertEmailHelper)
+	startLowStockAlertScheduler(ctx, log, time.Duration(config.LowStockAlertInterval)*time.Second, &lowStockAlertUsecase)
 
 	pingHandler := handler.NewPingHandler(handler.PingHandlerOpts{})
 	authenticationHandler := handler.NewAuthenticationHandler(&authenticationUsecase)
@@ -123,36 +206,59 @@ func createRouter(log *logrus.Logger, config *config.Config) *gin.Engine {
 	drugFormHandler := handler.NewDrugFormHandler(&drugFormUsecase)
 	drugClassificationHandler := handler.NewDrugClassificationHandler(&drugClassificationUsecase)
 	categoryHandler := handler.NewCategoryHandler(&categoryUsecase)
-	telemedicineHandler := handler.NewTelemedicineHandler(&telemedicineUsecase)
+	telemedicineHandler := handler.NewTelemedicineHandler(&telemedicineUsecase, config.FEOrigin)
 	orderHandler := handler.NewOrderHandler(&orderUsecase)
 	pharmacyHandler := handler.NewPharmacyHandler(&pharmacyUsecase)
 	orderPharmacyHandler := handler.NewOrderPharmacyHandler(&orderPharmacyUsecase)
 	reportHandler := handler.NewReportHandler(&reportUsecase)
 	stockHandler := handler.NewStockHandler(&stockUsecase)
+	accountSessionHandler := handler.NewAccountSessionHandler(&accountSessionUsecase)
+	adminAccountHandler := handler.NewAdminAccountHandler(&adminAccountUsecase)
+	prescriptionDocumentHandler := handler.NewPrescriptionDocumentHandler(&prescriptionDocumentUsecase)
+	consultationPaymentHandler := handler.NewConsultationPaymentHandler(&consultationPaymentUsecase)
+	chatRoomExtensionHandler := handler.NewChatRoomExtensionHandler(&chatRoomExtensionUsecase)
+	doctorReviewHandler := handler.NewDoctorReviewHandler(&doctorReviewUsecase)
+	appointmentHandler := handler.NewAppointmentHandler(&appointmentUsecase)
+	stockMutationHandler := handler.NewStockMutationHandler(&stockMutationUsecase)
+	pharmacyDrugBatchHandler := handler.NewPharmacyDrugBatchHandler(&pharmacyDrugBatchUsecase)
+	lowStockAlertHandler := handler.NewLowStockAlertHandler(&lowStockAlertUsecase)
+	pharmacyDrugImportHandler := handler.NewPharmacyDrugImportHandler(&pharmacyDrugImportUsecase)
 
 	return newRouter(
 		routerOpts{
-			Ping:               pingHandler,
-			Authentication:     &authenticationHandler,
-			User:               &userHandler,
-			UserAddress:        &userAddressHandler,
-			Doctor:             &doctorHandler,
-			Partner:            &partnerHandler,
-			Address:            &addressHandler,
-			Drug:               &drugHandler,
-			Category:           &categoryHandler,
-			DrugForm:           &drugFormHandler,
-			DrugClassification: &drugClassificationHandler,
-			Cart:               &cartHandler,
-			Telemedicine:       &telemedicineHandler,
-			Order:              &orderHandler,
-			Pharmacy:           &pharmacyHandler,
-			OrderPharmacy:      &orderPharmacyHandler,
-			Report:             &reportHandler,
-			Stock:              &stockHandler,
+			Ping:                 pingHandler,
+			Authentication:       &authenticationHandler,
+			User:                 &userHandler,
+			UserAddress:          &userAddressHandler,
+			Doctor:               &doctorHandler,
+			Partner:              &partnerHandler,
+			Address:              &addressHandler,
+			Drug:                 &drugHandler,
+			Category:             &categoryHandler,
+			DrugForm:             &drugFormHandler,
+			DrugClassification:   &drugClassificationHandler,
+			Cart:                 &cartHandler,
+			Telemedicine:         &telemedicineHandler,
+			Order:                &orderHandler,
+			Pharmacy:             &pharmacyHandler,
+			OrderPharmacy:        &orderPharmacyHandler,
+			Report:               &reportHandler,
+			Stock:                &stockHandler,
+			AccountSession:       &accountSessionHandler,
+			AdminAccount:         &adminAccountHandler,
+			PrescriptionDocument: &prescriptionDocumentHandler,
+			ConsultationPayment:  &consultationPaymentHandler,
+			ChatRoomExtension:    &chatRoomExtensionHandler,
+			DoctorReview:         &doctorReviewHandler,
+			Appointment:          &appointmentHandler,
+			StockMutation:        &stockMutationHandler,
+			PharmacyDrugBatch:    &pharmacyDrugBatchHandler,
+			LowStockAlert:        &lowStockAlertHandler,
+			PharmacyDrugImport:   &pharmacyDrugImportHandler,
 		},
 		utilOpts{
-			JwtHelper: jwtAuthentication,
+			JwtHelper:           jwtAuthentication,
+			AccountTokenVersion: accountTokenVersion,
 		},
 		config,
 		log,
@@ -164,7 +270,10 @@ func Init() {
 
 	config := config.Init(log)
 
-	router := createRouter(log, config)
+	schedulerCtx, stopSchedulers := context.WithCancel(context.Background())
+	defer stopSchedulers()
+
+	router := createRouter(schedulerCtx, log, config)
 
 	srv := http.Server{
 		Handler: router,
@@ -183,6 +292,7 @@ func Init() {
 
 	<-quit
 	log.Info("Shutdown Server ...")
+	stopSchedulers()
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(config.GracefulPeriod)*time.Second)
 	defer cancel()
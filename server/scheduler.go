@@ -0,0 +1,193 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/sidiqPratomo/max-health-backend/usecase"
+	"github.com/sirupsen/logrus"
+)
+
+func startOrderExpiryScheduler(ctx context.Context, log *logrus.Logger, interval time.Duration, orderExpiryUsecase usecase.OrderExpiryUsecase) {
+	if interval <= 0 {
+		log.Warn("order expiry scheduler is disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				expiredCount, err := orderExpiryUsecase.ExpireUnpaidOrders(ctx)
+				if err != nil {
+					log.WithFields(logrus.Fields{
+						"error": err.Error(),
+					}).Error("failed to expire unpaid orders")
+				}
+
+				if expiredCount > 0 {
+					log.Infof("expired %d unpaid orders", expiredCount)
+				}
+			}
+		}
+	}()
+}
+
+func startConsultationExpiryScheduler(ctx context.Context, log *logrus.Logger, interval time.Duration, consultationExpiryUsecase usecase.ConsultationExpiryUsecase) {
+	if interval <= 0 {
+		log.Warn("consultation expiry scheduler is disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				expiredCount, err := consultationExpiryUsecase.ExpireConsultations(ctx)
+				if err != nil {
+					log.WithFields(logrus.Fields{
+						"error": err.Error(),
+					}).Error("failed to expire consultations")
+				}
+
+				if expiredCount > 0 {
+					log.Infof("expired %d consultations", expiredCount)
+				}
+			}
+		}
+	}()
+}
+
+func startAppointmentScheduler(ctx context.Context, log *logrus.Logger, interval time.Duration, appointmentSchedulerUsecase usecase.AppointmentSchedulerUsecase) {
+	if interval <= 0 {
+		log.Warn("appointment scheduler is disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				remindedCount, err := appointmentSchedulerUsecase.SendReminders(ctx)
+				if err != nil {
+					log.WithFields(logrus.Fields{
+						"error": err.Error(),
+					}).Error("failed to send appointment reminders")
+				}
+
+				if remindedCount > 0 {
+					log.Infof("sent %d appointment reminders", remindedCount)
+				}
+
+				_, err = appointmentSchedulerUsecase.SyncDoctorAvailability(ctx)
+				if err != nil {
+					log.WithFields(logrus.Fields{
+						"error": err.Error(),
+					}).Error("failed to sync doctor availability")
+				}
+			}
+		}
+	}()
+}
+
+func startBatchWriteOffScheduler(ctx context.Context, log *logrus.Logger, interval time.Duration, pharmacyDrugBatchUsecase usecase.PharmacyDrugBatchUsecase) {
+	if interval <= 0 {
+		log.Warn("batch write-off scheduler is disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				writtenOffCount, err := pharmacyDrugBatchUsecase.WriteOffExpiredBatches(ctx)
+				if err != nil {
+					log.WithFields(logrus.Fields{
+						"error": err.Error(),
+					}).Error("failed to write off expired batches")
+				}
+
+				if writtenOffCount > 0 {
+					log.Infof("wrote off %d expired batches", writtenOffCount)
+				}
+			}
+		}
+	}()
+}
+
+func startLowStockAlertScheduler(ctx context.Context, log *logrus.Logger, interval time.Duration, lowStockAlertUsecase usecase.LowStockAlertUsecase) {
+	if interval <= 0 {
+		log.Warn("low stock alert scheduler is disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				raisedCount, err := lowStockAlertUsecase.RaiseLowStockAlerts(ctx)
+				if err != nil {
+					log.WithFields(logrus.Fields{
+						"error": err.Error(),
+					}).Error("failed to raise low stock alerts")
+				}
+
+				if raisedCount > 0 {
+					log.Infof("raised %d low stock alerts", raisedCount)
+				}
+			}
+		}
+	}()
+}
+
+func startDrugSearchRefresher(ctx context.Context, log *logrus.Logger, delay time.Duration, drugSearchRefreshUsecase usecase.DrugSearchRefreshUsecase) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-drugSearchRefreshUsecase.RefreshRequests():
+			}
+
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+
+			err := drugSearchRefreshUsecase.RefreshSearchTerms(ctx)
+			if err != nil {
+				log.WithFields(logrus.Fields{
+					"error": err.Error(),
+				}).Error("failed to refresh drug search terms")
+			}
+		}
+	}()
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/sidiqPratomo/max-health-backend/config"
 	"github.com/sidiqPratomo/max-health-backend/handler"
 	"github.com/sidiqPratomo/max-health-backend/middleware"
+	"github.com/sidiqPratomo/max-health-backend/usecase"
 	"github.com/sidiqPratomo/max-health-backend/util"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -15,28 +16,40 @@ import (
 )
 
 type routerOpts struct {
-	Ping               *handler.PingHandler
-	Authentication     *handler.AuthenticationHandler
-	User               *handler.UserHandler
-	Doctor             *handler.DoctorHandler
-	UserAddress        *handler.UserAddressHandler
-	Partner            *handler.PartnerHandler
-	Address            *handler.AddressHandler
-	Drug               *handler.DrugHandler
-	DrugForm           *handler.DrugFormHandler
-	DrugClassification *handler.DrugClassificationHandler
-	Category           *handler.CategoryHandler
-	Cart               *handler.CartHandler
-	Telemedicine       *handler.TelemedicineHandler
-	Order              *handler.OrderHandler
-	Pharmacy           *handler.PharmacyHandler
-	OrderPharmacy      *handler.OrderPharmacyHandler
-	Report             *handler.ReportHandler
-	Stock              *handler.StockHandler
+	Ping                 *handler.PingHandler
+	Authentication       *handler.AuthenticationHandler
+	User                 *handler.UserHandler
+	Doctor               *handler.DoctorHandler
+	UserAddress          *handler.UserAddressHandler
+	Partner              *handler.PartnerHandler
+	Address              *handler.AddressHandler
+	Drug                 *handler.DrugHandler
+	DrugForm             *handler.DrugFormHandler
+	DrugClassification   *handler.DrugClassificationHandler
+	Category             *handler.CategoryHandler
+	Cart                 *handler.CartHandler
+	Telemedicine         *handler.TelemedicineHandler
+	Order                *handler.OrderHandler
+	Pharmacy             *handler.PharmacyHandler
+	OrderPharmacy        *handler.OrderPharmacyHandler
+	Report               *handler.ReportHandler
+	Stock                *handler.StockHandler
+	AccountSession       *handler.AccountSessionHandler
+	AdminAccount         *handler.AdminAccountHandler
+	PrescriptionDocument *handler.PrescriptionDocumentHandler
+	ConsultationPayment  *handler.ConsultationPaymentHandler
+	ChatRoomExtension    *handler.ChatRoomExtensionHandler
+	DoctorReview         *handler.DoctorReviewHandler
+	Appointment          *handler.AppointmentHandler
+	StockMutation        *handler.StockMutationHandler
+	PharmacyDrugBatch    *handler.PharmacyDrugBatchHandler
+	LowStockAlert        *handler.LowStockAlertHandler
+	PharmacyDrugImport   *handler.PharmacyDrugImportHandler
 }
 
 type utilOpts struct {
-	JwtHelper util.TokenAuthentication
+	JwtHelper           util.TokenAuthentication
+	AccountTokenVersion usecase.AccountTokenVersion
 }
 
 func newRouter(h routerOpts, u utilOpts, config *config.Config, log *logrus.Logger) *gin.Engine {
@@ -52,10 +65,10 @@ func newRouter(h routerOpts, u utilOpts, config *config.Config, log *logrus.Logg
 		middleware.Logger(log),
 		middleware.RequestIdHandlerMiddleware,
 		middleware.ErrorHandlerMiddleware,
-		gin.Recovery(),
+		gin.CustomRecovery(recoverUnlessAborted),
 	)
 
-	authMiddleware := middleware.AuthMiddleware(u.JwtHelper, config)
+	authMiddleware := middleware.AuthMiddleware(u.JwtHelper, u.AccountTokenVersion, config)
 
 	userAuthorizationMiddleware := middleware.UserAuthorizationMiddleware
 	doctorAuthorizationMiddleware := middleware.DoctorAuthorizationMiddleware
@@ -65,11 +78,13 @@ func newRouter(h routerOpts, u utilOpts, config *config.Config, log *logrus.Logg
 	corsRouting(router, corsConfig)
 	router.NoRoute(handler.NotFoundHandler)
 	authenticationRouting(router, h.Authentication)
+	accountSessionRouting(router, h.AccountSession, authMiddleware)
 	addressRouting(router, h.Address, authMiddleware)
-	doctorRouting(router, h.Doctor, authMiddleware, doctorAuthorizationMiddleware)
+	doctorRouting(router, h.Doctor, authMiddleware, doctorAuthorizationMiddleware, adminAuthorizationMiddleware)
 	userRouting(router, h.User, h.Cart, authMiddleware, userAuthorizationMiddleware)
 	userAddressRouting(router, h.UserAddress, authMiddleware, userAuthorizationMiddleware)
 	partnerRouting(router, h.Partner, authMiddleware, adminAuthorizationMiddleware)
+	adminAccountRouting(router, h.AdminAccount, authMiddleware, adminAuthorizationMiddleware)
 	drugRouting(router, h.Drug, authMiddleware, adminAuthorizationMiddleware, pharmacyManagerAuthorizationMiddleware)
 	drugFormRouting(router, h.DrugForm)
 	drugClassificationRouting(router, h.DrugClassification)
@@ -77,10 +92,19 @@ func newRouter(h routerOpts, u utilOpts, config *config.Config, log *logrus.Logg
 	categoryRouting(router, h.Category, authMiddleware, adminAuthorizationMiddleware)
 	cartRouting(router, h.Cart, authMiddleware, userAuthorizationMiddleware)
 	telemedicineRouting(router, h.Telemedicine, authMiddleware, userAuthorizationMiddleware, doctorAuthorizationMiddleware)
+	prescriptionDocumentRouting(router, h.PrescriptionDocument, authMiddleware)
+	consultationPaymentRouting(router, h.ConsultationPayment, authMiddleware, userAuthorizationMiddleware, adminAuthorizationMiddleware)
+	chatRoomExtensionRouting(router, h.ChatRoomExtension, authMiddleware, userAuthorizationMiddleware, doctorAuthorizationMiddleware, adminAuthorizationMiddleware)
+	doctorReviewRouting(router, h.DoctorReview, authMiddleware, userAuthorizationMiddleware, doctorAuthorizationMiddleware, adminAuthorizationMiddleware)
+	appointmentRouting(router, h.Appointment, authMiddleware, userAuthorizationMiddleware, doctorAuthorizationMiddleware)
 	orderRouting(router, h.Order, authMiddleware, userAuthorizationMiddleware, adminAuthorizationMiddleware, pharmacyManagerAuthorizationMiddleware)
 	orderPharmacyRouting(router, h.OrderPharmacy, authMiddleware, pharmacyManagerAuthorizationMiddleware, userAuthorizationMiddleware, adminAuthorizationMiddleware)
 	reportRouting(router, h.Report, authMiddleware, pharmacyManagerAuthorizationMiddleware, adminAuthorizationMiddleware)
 	stockRouting(router, h.Stock, authMiddleware, pharmacyManagerAuthorizationMiddleware)
+	stockMutationRouting(router, h.StockMutation, authMiddleware, pharmacyManagerAuthorizationMiddleware)
+	pharmacyDrugBatchRouting(router, h.PharmacyDrugBatch, authMiddleware, pharmacyManagerAuthorizationMiddleware)
+	lowStockAlertRouting(router, h.LowStockAlert, authMiddleware, pharmacyManagerAuthorizationMiddleware)
+	pharmacyDrugImportRouting(router, h.PharmacyDrugImport, authMiddleware, pharmacyManagerAuthorizationMiddleware)
 	pingRouting(router, h.Ping, authMiddleware, userAuthorizationMiddleware, doctorAuthorizationMiddleware, pharmacyManagerAuthorizationMiddleware, adminAuthorizationMiddleware)
 	pprofRouting(router)
 
@@ -96,6 +120,7 @@ func userAddressRouting(router *gin.Engine, handler *handler.UserAddressHandler,
 }
 
 func drugRouting(router *gin.Engine, handler *handler.DrugHandler, authMiddleware gin.HandlerFunc, adminAuthorizationMiddleware gin.HandlerFunc, pharmacyManagerAuthorizationMiddleware gin.HandlerFunc) {
+	router.GET("/drugs/suggest", handler.SuggestDrugs)
 	router.GET("/drugs/:drug_id", handler.GetPharmacyDrugByDrugId)
 	router.GET("/drugs", handler.GetAllDrugsForListing)
 
@@ -127,11 +152,12 @@ func userRouting(router *gin.Engine, handler *handler.UserHandler, cartHandler *
 	userRouter.GET("/profile", authMiddleware, userAuthorizationMiddleware, handler.GetProfile)
 }
 
-func doctorRouting(router *gin.Engine, handler *handler.DoctorHandler, authMiddleware gin.HandlerFunc, doctorAuthorizationMiddleware gin.HandlerFunc) {
+func doctorRouting(router *gin.Engine, handler *handler.DoctorHandler, authMiddleware gin.HandlerFunc, doctorAuthorizationMiddleware gin.HandlerFunc, adminAuthorizationMiddleware gin.HandlerFunc) {
 	doctorRouter := router.Group("/doctors")
 	doctorRouter.PATCH("/profile", authMiddleware, doctorAuthorizationMiddleware, handler.UpdateData)
 	doctorRouter.GET("/", handler.GetAllDoctors)
 	doctorRouter.GET("/specializations", handler.GetAllDoctorSpecialization)
+	doctorRouter.PATCH("/specializations/:specialization_id", authMiddleware, adminAuthorizationMiddleware, handler.UpdateSpecializationConsultationDuration)
 	doctorRouter.GET("/profile", authMiddleware, doctorAuthorizationMiddleware, handler.GetProfile)
 	doctorRouter.GET(":doctor_id", handler.GetProfileForPublic)
 	doctorRouter.PATCH("/availability", authMiddleware, doctorAuthorizationMiddleware, handler.UpdateDoctorStatus)
@@ -148,16 +174,60 @@ func partnerRouting(router *gin.Engine, handler *handler.PartnerHandler, authMid
 	partnerRouter.DELETE("/:pharmacy_manager_id", authMiddleware, adminAuthorizationMiddleware, handler.DeletePartner)
 }
 
+func adminAccountRouting(router *gin.Engine, handler *handler.AdminAccountHandler, authMiddleware gin.HandlerFunc, adminAuthorizationMiddleware gin.HandlerFunc) {
+	adminAccountRouter := router.Group("/admin/accounts")
+
+	adminAccountRouter.GET("", authMiddleware, adminAuthorizationMiddleware, handler.GetAllAccounts)
+	adminAccountRouter.GET("/:account_id", authMiddleware, adminAuthorizationMiddleware, handler.GetOneAccount)
+	adminAccountRouter.GET("/:account_id/addresses", authMiddleware, adminAuthorizationMiddleware, handler.GetAccountAddresses)
+	adminAccountRouter.GET("/:account_id/orders", authMiddleware, adminAuthorizationMiddleware, handler.GetAccountOrders)
+	adminAccountRouter.GET("/:account_id/chat-rooms", authMiddleware, adminAuthorizationMiddleware, handler.GetAccountChatRooms)
+	adminAccountRouter.PATCH("/:account_id/suspend", authMiddleware, adminAuthorizationMiddleware, handler.SuspendAccount)
+	adminAccountRouter.PATCH("/:account_id/unsuspend", authMiddleware, adminAuthorizationMiddleware, handler.UnsuspendAccount)
+	adminAccountRouter.POST("/:account_id/verification-email", authMiddleware, adminAuthorizationMiddleware, handler.SendVerificationEmail)
+	adminAccountRouter.POST("/:account_id/reset-password-email", authMiddleware, adminAuthorizationMiddleware, handler.SendResetPasswordEmail)
+}
+
 func pharmacyRouting(router *gin.Engine, handler *handler.PharmacyHandler, authMiddleware gin.HandlerFunc, pharmacyManagerAuthorizationMiddleware gin.HandlerFunc, adminAuthorizationMiddleware gin.HandlerFunc) {
 	router.GET("/managers/pharmacies", authMiddleware, pharmacyManagerAuthorizationMiddleware, handler.GetPharmacyByManagerId)
 	router.PUT("/pharmacies/:pharmacy_id", authMiddleware, pharmacyManagerAuthorizationMiddleware, handler.UpdateOnePharmacy)
 	router.DELETE("/pharmacies/:pharmacy_id", authMiddleware, pharmacyManagerAuthorizationMiddleware, handler.DeleteOnePharmacy)
 	router.POST("/pharmacies", authMiddleware, adminAuthorizationMiddleware, handler.CreateOnePharmacy)
 	router.GET("/admin/manager/:pharmacy_manager_id/pharmacies", authMiddleware, adminAuthorizationMiddleware, handler.AdminGetPharmacyByManagerId)
+	router.GET("/managers/pharmacies/:pharmacy_id/operational-hours", authMiddleware, pharmacyManagerAuthorizationMiddleware, handler.GetPharmacySchedule)
+	router.PUT("/managers/pharmacies/:pharmacy_id/operational-hours", authMiddleware, pharmacyManagerAuthorizationMiddleware, handler.UpdatePharmacySchedule)
+	router.POST("/managers/pharmacies/:pharmacy_id/holidays", authMiddleware, pharmacyManagerAuthorizationMiddleware, handler.AddPharmacyHoliday)
+	router.DELETE("/managers/pharmacies/:pharmacy_id/holidays/:pharmacy_holiday_id", authMiddleware, pharmacyManagerAuthorizationMiddleware, handler.DeletePharmacyHoliday)
 }
 
 func stockRouting(router *gin.Engine, handler *handler.StockHandler, authMiddleware gin.HandlerFunc, pharmacyManagerAuthorizationMiddleware gin.HandlerFunc) {
 	router.GET("/managers/stock-change", authMiddleware, pharmacyManagerAuthorizationMiddleware, handler.GetAllStockChanges)
+	router.GET("/managers/stock-change/export", authMiddleware, pharmacyManagerAuthorizationMiddleware, handler.ExportStockChanges)
+}
+
+func stockMutationRouting(router *gin.Engine, handler *handler.StockMutationHandler, authMiddleware gin.HandlerFunc, pharmacyManagerAuthorizationMiddleware gin.HandlerFunc) {
+	router.GET("/managers/stock-mutations", authMiddleware, pharmacyManagerAuthorizationMiddleware, handler.GetStockMutationRequests)
+	router.PATCH("/managers/stock-mutations/:stock_mutation_id/approve", authMiddleware, pharmacyManagerAuthorizationMiddleware, handler.ApproveStockMutationRequest)
+	router.PATCH("/managers/stock-mutations/:stock_mutation_id/reject", authMiddleware, pharmacyManagerAuthorizationMiddleware, handler.RejectStockMutationRequest)
+	router.PATCH("/managers/stock-mutations/:stock_mutation_id/ship", authMiddleware, pharmacyManagerAuthorizationMiddleware, handler.ShipStockMutationRequest)
+	router.PATCH("/managers/stock-mutations/:stock_mutation_id/receive", authMiddleware, pharmacyManagerAuthorizationMiddleware, handler.ReceiveStockMutationRequest)
+}
+
+func pharmacyDrugBatchRouting(router *gin.Engine, handler *handler.PharmacyDrugBatchHandler, authMiddleware gin.HandlerFunc, pharmacyManagerAuthorizationMiddleware gin.HandlerFunc) {
+	router.GET("/managers/pharmacies/drugs/:pharmacy_drug_id/batches", authMiddleware, pharmacyManagerAuthorizationMiddleware, handler.GetPharmacyDrugBatches)
+	router.POST("/managers/pharmacies/drugs/:pharmacy_drug_id/batches", authMiddleware, pharmacyManagerAuthorizationMiddleware, handler.ReceivePharmacyDrugBatch)
+	router.GET("/managers/batches/expiring", authMiddleware, pharmacyManagerAuthorizationMiddleware, handler.GetExpiringBatches)
+}
+
+func lowStockAlertRouting(router *gin.Engine, handler *handler.LowStockAlertHandler, authMiddleware gin.HandlerFunc, pharmacyManagerAuthorizationMiddleware gin.HandlerFunc) {
+	router.PUT("/managers/pharmacies/drugs/:pharmacy_drug_id/reorder-threshold", authMiddleware, pharmacyManagerAuthorizationMiddleware, handler.UpdateReorderThreshold)
+	router.GET("/managers/low-stock-alerts", authMiddleware, pharmacyManagerAuthorizationMiddleware, handler.GetLowStockAlerts)
+	router.PATCH("/managers/low-stock-alerts/:low_stock_alert_id/read", authMiddleware, pharmacyManagerAuthorizationMiddleware, handler.MarkLowStockAlertRead)
+	router.GET("/managers/reorder-suggestions", authMiddleware, pharmacyManagerAuthorizationMiddleware, handler.GetReorderSuggestions)
+}
+
+func pharmacyDrugImportRouting(router *gin.Engine, handler *handler.PharmacyDrugImportHandler, authMiddleware gin.HandlerFunc, pharmacyManagerAuthorizationMiddleware gin.HandlerFunc) {
+	router.POST("/managers/pharmacies/:pharmacy_id/drugs/import", authMiddleware, pharmacyManagerAuthorizationMiddleware, handler.ImportPharmacyDrugs)
 }
 
 func addressRouting(router *gin.Engine, handler *handler.AddressHandler, authMiddleware gin.HandlerFunc) {
@@ -171,17 +241,69 @@ func telemedicineRouting(router *gin.Engine, handler *handler.TelemedicineHandle
 	router.POST("/chat-rooms", authMiddleware, userAuthorizationMiddleware, handler.UserCreateRoom)
 	router.PATCH("/chat-rooms", authMiddleware, doctorAuthorizationMiddleware, handler.DoctorJoinRoom)
 	router.POST("/chat-rooms/chats", authMiddleware, handler.PostOneMessage)
-	router.GET("/chat-rooms/chats/:room_id", authMiddleware, handler.Listen)
+	router.GET("/chat-rooms/chats/:room_id", authMiddleware, handler.ChatStream)
 	router.GET("/chat-rooms/:room_id", authMiddleware, handler.GetAllChat)
 	router.GET("/chat-rooms", authMiddleware, handler.GetAllChatRoomPreview)
 	router.GET("/chat-rooms/requests", authMiddleware, doctorAuthorizationMiddleware, handler.DoctorGetChatRequest)
 	router.PATCH("/chat-rooms/:room_id/close-room", authMiddleware, userAuthorizationMiddleware, handler.CloseChatRoom)
+	router.PATCH("/chat-rooms/:room_id/decline", authMiddleware, doctorAuthorizationMiddleware, handler.DoctorDeclineRoom)
+	router.GET("/chat-rooms/:room_id/queue", authMiddleware, handler.GetQueueStatus)
 	router.PATCH("/prescriptions/:prescription_id", authMiddleware, userAuthorizationMiddleware, handler.SavePrescription)
 	router.GET("/prescriptions", authMiddleware, userAuthorizationMiddleware, handler.GetAllPrescriptions)
 	router.GET("/prescriptions/:prescription_id", authMiddleware, userAuthorizationMiddleware, handler.PreapereForCheckout)
+	router.GET("/prescriptions/:prescription_id/orders", authMiddleware, handler.GetPrescriptionRedemptions)
 	router.POST("/prescriptions/checkout", authMiddleware, userAuthorizationMiddleware, handler.CheckoutFromPrescription)
 }
 
+func prescriptionDocumentRouting(router *gin.Engine, handler *handler.PrescriptionDocumentHandler, authMiddleware gin.HandlerFunc) {
+	router.GET("/prescriptions/:prescription_id/document", authMiddleware, handler.GetPrescriptionDocument)
+	router.GET("/prescriptions/:prescription_id/verification", handler.VerifyPrescription)
+}
+
+func consultationPaymentRouting(router *gin.Engine, handler *handler.ConsultationPaymentHandler, authMiddleware gin.HandlerFunc, userAuthorizationMiddleware gin.HandlerFunc, adminAuthorizationMiddleware gin.HandlerFunc) {
+	router.GET("/chat-rooms/:room_id/payment", authMiddleware, handler.GetConsultationPayment)
+	router.PATCH("/chat-rooms/:room_id/payment-proof", authMiddleware, userAuthorizationMiddleware, handler.UploadPaymentProof)
+	router.PATCH("/chat-rooms/:room_id/confirm-payment", authMiddleware, adminAuthorizationMiddleware, handler.ConfirmPayment)
+	router.GET("/consultation-payments", authMiddleware, adminAuthorizationMiddleware, handler.GetAllConsultationPayments)
+}
+
+func chatRoomExtensionRouting(router *gin.Engine, handler *handler.ChatRoomExtensionHandler, authMiddleware gin.HandlerFunc, userAuthorizationMiddleware gin.HandlerFunc, doctorAuthorizationMiddleware gin.HandlerFunc, adminAuthorizationMiddleware gin.HandlerFunc) {
+	router.POST("/chat-rooms/:room_id/extensions", authMiddleware, doctorAuthorizationMiddleware, handler.RequestExtension)
+	router.PATCH("/chat-rooms/:room_id/extensions/:extension_id", authMiddleware, userAuthorizationMiddleware, handler.RespondExtension)
+	router.PATCH("/chat-rooms/:room_id/extensions/:extension_id/payment-proof", authMiddleware, userAuthorizationMiddleware, handler.UploadPaymentProof)
+	router.PATCH("/chat-rooms/:room_id/extensions/:extension_id/confirm-payment", authMiddleware, adminAuthorizationMiddleware, handler.ConfirmPayment)
+}
+
+func doctorReviewRouting(router *gin.Engine, handler *handler.DoctorReviewHandler, authMiddleware gin.HandlerFunc, userAuthorizationMiddleware gin.HandlerFunc, doctorAuthorizationMiddleware gin.HandlerFunc, adminAuthorizationMiddleware gin.HandlerFunc) {
+	router.POST("/chat-rooms/:room_id/review", authMiddleware, userAuthorizationMiddleware, handler.CreateReview)
+	router.GET("/doctors/:doctor_id/reviews", handler.GetDoctorReviews)
+	router.PATCH("/doctor-reviews/:review_id/reply", authMiddleware, doctorAuthorizationMiddleware, handler.ReplyReview)
+	router.GET("/doctor-reviews", authMiddleware, adminAuthorizationMiddleware, handler.GetAllReviews)
+	router.PATCH("/doctor-reviews/:review_id/visibility", authMiddleware, adminAuthorizationMiddleware, handler.ModerateReview)
+}
+
+func appointmentRouting(router *gin.Engine, handler *handler.AppointmentHandler, authMiddleware gin.HandlerFunc, userAuthorizationMiddleware gin.HandlerFunc, doctorAuthorizationMiddleware gin.HandlerFunc) {
+	router.GET("/doctors/schedule", authMiddleware, doctorAuthorizationMiddleware, handler.GetDoctorSchedule)
+	router.PUT("/doctors/schedule", authMiddleware, doctorAuthorizationMiddleware, handler.UpdateDoctorSchedule)
+	router.POST("/doctors/schedule/exceptions", authMiddleware, doctorAuthorizationMiddleware, handler.AddScheduleException)
+	router.DELETE("/doctors/schedule/exceptions/:exception_id", authMiddleware, doctorAuthorizationMiddleware, handler.DeleteScheduleException)
+	router.GET("/doctors/:doctor_id/slots", handler.GetAppointmentSlots)
+
+	router.POST("/appointments", authMiddleware, userAuthorizationMiddleware, handler.BookAppointment)
+	router.GET("/appointments", authMiddleware, handler.GetAppointments)
+	router.PATCH("/appointments/:appointment_id/cancel", authMiddleware, handler.CancelAppointment)
+}
+
+// http.ErrAbortHandler is passed on to net/http, which then drops the
+// connection without finishing the response.
+func recoverUnlessAborted(c *gin.Context, err any) {
+	if err == http.ErrAbortHandler {
+		panic(err)
+	}
+
+	c.AbortWithStatus(http.StatusInternalServerError)
+}
+
 func corsRouting(router *gin.Engine, configCors cors.Config) {
 	configCors.AllowAllOrigins = true
 	configCors.AllowMethods = []string{"POST", "GET", "PUT", "PATCH", "DELETE"}
@@ -202,6 +324,12 @@ func authenticationRouting(router *gin.Engine, handler *handler.AuthenticationHa
 	router.POST("/reset-password/verification", handler.ResetPasswordOneAccount)
 }
 
+func accountSessionRouting(router *gin.Engine, handler *handler.AccountSessionHandler, authMiddleware gin.HandlerFunc) {
+	router.GET("/sessions", authMiddleware, handler.GetAllSessions)
+	router.DELETE("/sessions", authMiddleware, handler.RevokeAllSessions)
+	router.DELETE("/sessions/:session_id", authMiddleware, handler.RevokeOneSession)
+}
+
 func categoryRouting(router *gin.Engine, handler *handler.CategoryHandler, authMiddleware gin.HandlerFunc, adminAuthorizationMiddleware gin.HandlerFunc) {
 	categoryRouter := router.Group("/categories")
 
@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/sidiqPratomo/max-health-backend/apperror"
+	"github.com/sidiqPratomo/max-health-backend/dto"
+	"github.com/sidiqPratomo/max-health-backend/repository"
+)
+
+type AccountSessionUsecase interface {
+	GetAllSessions(ctx context.Context, accountId int64, currentSessionId int64) ([]dto.AccountSessionResponse, error)
+	RevokeOneSession(ctx context.Context, accountId int64, accountSessionId int64) error
+	RevokeAllSessions(ctx context.Context, accountId int64) error
+}
+
+type accountSessionUsecaseImpl struct {
+	accountSessionRepository repository.AccountSessionRepository
+	accountTokenVersion      AccountTokenVersion
+	transaction              repository.Transaction
+}
+
+func NewAccountSessionUsecaseImpl(accountSessionRepository repository.AccountSessionRepository, accountTokenVersion AccountTokenVersion, transaction repository.Transaction) accountSessionUsecaseImpl {
+	return accountSessionUsecaseImpl{
+		accountSessionRepository: accountSessionRepository,
+		accountTokenVersion:      accountTokenVersion,
+		transaction:              transaction,
+	}
+}
+
+func (u *accountSessionUsecaseImpl) GetAllSessions(ctx context.Context, accountId int64, currentSessionId int64) ([]dto.AccountSessionResponse, error) {
+	accountSessions, err := u.accountSessionRepository.FindAllActiveByAccountId(ctx, accountId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	return dto.ConvertToAccountSessionResponses(accountSessions, currentSessionId), nil
+}
+
+// Revoking one session only stops its refresh token, an access token already
+// issued for it keeps working until it expires. Use RevokeAllSessions to cut
+// off access tokens immediately.
+func (u *accountSessionUsecaseImpl) RevokeOneSession(ctx context.Context, accountId int64, accountSessionId int64) error {
+	revokedCount, err := u.accountSessionRepository.RevokeOneById(ctx, accountId, accountSessionId)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+	if revokedCount == 0 {
+		return apperror.SessionNotFoundError()
+	}
+
+	return nil
+}
+
+func (u *accountSessionUsecaseImpl) RevokeAllSessions(ctx context.Context, accountId int64) error {
+	err := u.revokeAllSessions(ctx, accountId)
+	if err != nil {
+		return err
+	}
+
+	u.accountTokenVersion.Forget(accountId)
+
+	return nil
+}
+
+// The token version is bumped as well, so access tokens of every session stop
+// working right away instead of when they expire.
+func (u *accountSessionUsecaseImpl) revokeAllSessions(ctx context.Context, accountId int64) error {
+	tx, err := u.transaction.BeginTx(ctx)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	accountRepo := tx.AccountRepository()
+	accountSessionRepo := tx.AccountSessionRepository()
+	refreshTokenRepo := tx.RefreshTokenRepository()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+
+		tx.Commit()
+	}()
+
+	if err = accountSessionRepo.RevokeAllByAccountId(ctx, accountId); err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	if err = refreshTokenRepo.InvalidateCodes(ctx, accountId); err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	if err = accountRepo.IncrementTokenVersionById(ctx, accountId); err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sidiqPratomo/max-health-backend/apperror"
+	"github.com/sidiqPratomo/max-health-backend/repository"
+)
+
+type AccountTokenVersion interface {
+	GetTokenVersion(ctx context.Context, accountId int64) (*int64, error)
+	Forget(accountId int64)
+}
+
+type cachedAccountTokenVersion struct {
+	tokenVersion *int64
+	expiredAt    time.Time
+}
+
+type accountTokenVersionImpl struct {
+	accountRepository repository.AccountRepository
+	ttl               time.Duration
+	mu                sync.Mutex
+	entries           map[int64]cachedAccountTokenVersion
+}
+
+// Versions are cached per replica for ttl, so a bump made on another replica
+// takes effect there after at most ttl.
+func NewAccountTokenVersionImpl(accountRepository repository.AccountRepository, ttl time.Duration) *accountTokenVersionImpl {
+	return &accountTokenVersionImpl{
+		accountRepository: accountRepository,
+		ttl:               ttl,
+		entries:           map[int64]cachedAccountTokenVersion{},
+	}
+}
+
+func (a *accountTokenVersionImpl) GetTokenVersion(ctx context.Context, accountId int64) (*int64, error) {
+	now := time.Now()
+
+	a.mu.Lock()
+	cached, ok := a.entries[accountId]
+	if ok && now.Before(cached.expiredAt) {
+		a.mu.Unlock()
+		return cached.tokenVersion, nil
+	}
+	a.mu.Unlock()
+
+	tokenVersion, err := a.accountRepository.FindOneTokenVersionById(ctx, accountId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	if a.ttl > 0 {
+		a.mu.Lock()
+		for cachedAccountId, entry := range a.entries {
+			if now.After(entry.expiredAt) {
+				delete(a.entries, cachedAccountId)
+			}
+		}
+		a.entries[accountId] = cachedAccountTokenVersion{
+			tokenVersion: tokenVersion,
+			expiredAt:    now.Add(a.ttl),
+		}
+		a.mu.Unlock()
+	}
+
+	return tokenVersion, nil
+}
+
+func (a *accountTokenVersionImpl) Forget(accountId int64) {
+	a.mu.Lock()
+	delete(a.entries, accountId)
+	a.mu.Unlock()
+}
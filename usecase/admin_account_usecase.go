@@ -0,0 +1,277 @@
+package usecase
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/sidiqPratomo/max-health-backend/appconstant"
+	"github.com/sidiqPratomo/max-health-backend/apperror"
+	"github.com/sidiqPratomo/max-health-backend/dto"
+	"github.com/sidiqPratomo/max-health-backend/entity"
+	"github.com/sidiqPratomo/max-health-backend/repository"
+	"github.com/sidiqPratomo/max-health-backend/util"
+)
+
+type AdminAccountUsecase interface {
+	GetAllAccounts(ctx context.Context, query dto.AdminAccountQuery) (*dto.AllAdminAccountsResponse, error)
+	GetOneAccount(ctx context.Context, accountId int64) (*dto.AdminAccountResponse, error)
+	GetAccountAddresses(ctx context.Context, accountId int64) (*dto.AllUserAddressResponse, error)
+	GetAccountOrders(ctx context.Context, accountId int64, validatedQuery *util.ValidatedGetOrderQuery) (*dto.AllOrdersResponse, error)
+	GetAccountChatRooms(ctx context.Context, accountId int64) ([]dto.ChatRoomPreview, error)
+	SuspendAccount(ctx context.Context, accountId int64) error
+	UnsuspendAccount(ctx context.Context, accountId int64) error
+	SendVerificationEmail(ctx context.Context, accountId int64) error
+	SendResetPasswordEmail(ctx context.Context, accountId int64) error
+}
+
+type adminAccountUsecaseImpl struct {
+	accountRepository     repository.AccountRepository
+	userRepository        repository.UserRepository
+	userAddressRepository repository.UserAddressRepository
+	orderRepository       repository.OrderRepository
+	chatRoomRepository    repository.ChatRoomRepository
+	authenticationUsecase AuthenticationUsecase
+	accountTokenVersion   AccountTokenVersion
+	transaction           repository.Transaction
+}
+
+type AdminAccountUsecaseImplOpts struct {
+	AccountRepository     repository.AccountRepository
+	UserRepository        repository.UserRepository
+	UserAddressRepository repository.UserAddressRepository
+	OrderRepository       repository.OrderRepository
+	ChatRoomRepository    repository.ChatRoomRepository
+	AuthenticationUsecase AuthenticationUsecase
+	AccountTokenVersion   AccountTokenVersion
+	Transaction           repository.Transaction
+}
+
+func NewAdminAccountUsecaseImpl(opts AdminAccountUsecaseImplOpts) adminAccountUsecaseImpl {
+	return adminAccountUsecaseImpl{
+		accountRepository:     opts.AccountRepository,
+		userRepository:        opts.UserRepository,
+		userAddressRepository: opts.UserAddressRepository,
+		orderRepository:       opts.OrderRepository,
+		chatRoomRepository:    opts.ChatRoomRepository,
+		authenticationUsecase: opts.AuthenticationUsecase,
+		accountTokenVersion:   opts.AccountTokenVersion,
+		transaction:           opts.Transaction,
+	}
+}
+
+func (u *adminAccountUsecaseImpl) findAccount(ctx context.Context, accountId int64) (*entity.Account, error) {
+	account, err := u.accountRepository.FindOneDetailById(ctx, accountId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+	if account == nil {
+		return nil, apperror.AccountNotFoundByIdError()
+	}
+
+	return account, nil
+}
+
+func (u *adminAccountUsecaseImpl) GetAllAccounts(ctx context.Context, query dto.AdminAccountQuery) (*dto.AllAdminAccountsResponse, error) {
+	params, err := util.SetDefaultQueryParams(util.QueryParam{Page: query.Page, Limit: query.Limit})
+	if err != nil {
+		return nil, err
+	}
+
+	limit, _ := strconv.Atoi(params.Limit)
+	offset, _ := strconv.Atoi(params.Offset)
+
+	accounts, pageInfo, err := u.accountRepository.FindAll(ctx, entity.AccountFilter{
+		Search:      query.Search,
+		RoleName:    query.Role,
+		IsVerified:  query.Verified,
+		IsSuspended: query.Suspended,
+		Limit:       limit,
+		Offset:      offset,
+	})
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	accountsResponse := dto.ConvertToAllAdminAccountsResponse(accounts, *pageInfo)
+
+	return &accountsResponse, nil
+}
+
+func (u *adminAccountUsecaseImpl) GetOneAccount(ctx context.Context, accountId int64) (*dto.AdminAccountResponse, error) {
+	account, err := u.findAccount(ctx, accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	accountResponse := dto.ConvertToAdminAccountResponse(*account)
+
+	return &accountResponse, nil
+}
+
+func (u *adminAccountUsecaseImpl) GetAccountAddresses(ctx context.Context, accountId int64) (*dto.AllUserAddressResponse, error) {
+	if _, err := u.findAccount(ctx, accountId); err != nil {
+		return nil, err
+	}
+
+	user, err := u.userRepository.FindUserByAccountId(ctx, accountId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+	if user == nil {
+		return dto.ConvertToAllUserAddressResponse([]entity.UserAddress{}), nil
+	}
+
+	userAddresses, err := u.userAddressRepository.FindAllByUserId(ctx, user.Id)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	return dto.ConvertToAllUserAddressResponse(userAddresses), nil
+}
+
+func (u *adminAccountUsecaseImpl) GetAccountOrders(ctx context.Context, accountId int64, validatedQuery *util.ValidatedGetOrderQuery) (*dto.AllOrdersResponse, error) {
+	if _, err := u.findAccount(ctx, accountId); err != nil {
+		return nil, err
+	}
+
+	user, err := u.userRepository.FindUserByAccountId(ctx, accountId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+	if user == nil {
+		return dto.ConvertToAllOrdersResponse([]*entity.Order{}, entity.PageInfo{Page: validatedQuery.Page}), nil
+	}
+
+	validatedQuery.UserId = &user.Id
+
+	orderIds, pageInfo, err := u.orderRepository.FindAll(ctx, *validatedQuery)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	ordersWithDetails := []*entity.Order{}
+
+	if len(orderIds) > 0 {
+		ordersWithDetails, err = u.orderRepository.FindAllWithDetails(ctx, orderIds)
+		if err != nil {
+			return nil, apperror.InternalServerError(err)
+		}
+	}
+
+	return dto.ConvertToAllOrdersResponse(ordersWithDetails, *pageInfo), nil
+}
+
+func (u *adminAccountUsecaseImpl) GetAccountChatRooms(ctx context.Context, accountId int64) ([]dto.ChatRoomPreview, error) {
+	account, err := u.findAccount(ctx, accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	chatRoomPreviewList, err := u.chatRoomRepository.GetAllChatRoomPreview(ctx, accountId, account.RoleName)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	chatRoomPreviewResponse := dto.ConvertToChatRoomPreviewList(chatRoomPreviewList)
+	if chatRoomPreviewResponse == nil {
+		chatRoomPreviewResponse = []dto.ChatRoomPreview{}
+	}
+
+	return chatRoomPreviewResponse, nil
+}
+
+func (u *adminAccountUsecaseImpl) SuspendAccount(ctx context.Context, accountId int64) error {
+	account, err := u.findAccount(ctx, accountId)
+	if err != nil {
+		return err
+	}
+
+	if account.RoleName == appconstant.AdminRoleName {
+		return apperror.ForbiddenAction()
+	}
+
+	err = u.suspendAccount(ctx, accountId)
+	if err != nil {
+		return err
+	}
+
+	u.accountTokenVersion.Forget(accountId)
+
+	return nil
+}
+
+// The cached token version is only forgotten once the suspension is
+// committed, otherwise a request in between could cache the old version again.
+func (u *adminAccountUsecaseImpl) suspendAccount(ctx context.Context, accountId int64) error {
+	tx, err := u.transaction.BeginTx(ctx)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	accountRepo := tx.AccountRepository()
+	accountSessionRepo := tx.AccountSessionRepository()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+
+		tx.Commit()
+	}()
+
+	suspendedCount, err := accountRepo.SuspendOneById(ctx, accountId)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+	if suspendedCount == 0 {
+		return apperror.AccountAlreadySuspendedError()
+	}
+
+	err = accountRepo.IncrementTokenVersionById(ctx, accountId)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	err = accountSessionRepo.RevokeAllByAccountId(ctx, accountId)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	return nil
+}
+
+func (u *adminAccountUsecaseImpl) UnsuspendAccount(ctx context.Context, accountId int64) error {
+	if _, err := u.findAccount(ctx, accountId); err != nil {
+		return err
+	}
+
+	unsuspendedCount, err := u.accountRepository.UnsuspendOneById(ctx, accountId)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+	if unsuspendedCount == 0 {
+		return apperror.AccountNotSuspendedError()
+	}
+
+	u.accountTokenVersion.Forget(accountId)
+
+	return nil
+}
+
+func (u *adminAccountUsecaseImpl) SendVerificationEmail(ctx context.Context, accountId int64) error {
+	account, err := u.findAccount(ctx, accountId)
+	if err != nil {
+		return err
+	}
+
+	return u.authenticationUsecase.SendVerificationEmail(ctx, dto.SendEmailRequest{Email: account.Email})
+}
+
+func (u *adminAccountUsecaseImpl) SendResetPasswordEmail(ctx context.Context, accountId int64) error {
+	account, err := u.findAccount(ctx, accountId)
+	if err != nil {
+		return err
+	}
+
+	return u.authenticationUsecase.SendResetPasswordToken(ctx, dto.SendEmailRequest{Email: account.Email})
+}
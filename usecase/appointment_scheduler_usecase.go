@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/sidiqPratomo/max-health-backend/appconstant"
+	"github.com/sidiqPratomo/max-health-backend/apperror"
+	"github.com/sidiqPratomo/max-health-backend/entity"
+	"github.com/sidiqPratomo/max-health-backend/repository"
+	"github.com/sidiqPratomo/max-health-backend/util"
+)
+
+const (
+	appointmentReminderBatchSize = 50
+	appointmentReminderLayout    = "Monday, 02 January 2006 15:04 MST"
+)
+
+type AppointmentSchedulerUsecase interface {
+	SendReminders(ctx context.Context) (int, error)
+	SyncDoctorAvailability(ctx context.Context) (int64, error)
+}
+
+type appointmentSchedulerUsecaseImpl struct {
+	doctorScheduleRepository repository.DoctorScheduleRepository
+	transaction              repository.Transaction
+	emailHelper              util.EmailHelper
+	reminderLead             int
+}
+
+func NewAppointmentSchedulerUsecaseImpl(doctorScheduleRepository repository.DoctorScheduleRepository, transaction repository.Transaction, emailHelper util.EmailHelper, reminderLead int) appointmentSchedulerUsecaseImpl {
+	return appointmentSchedulerUsecaseImpl{
+		doctorScheduleRepository: doctorScheduleRepository,
+		transaction:              transaction,
+		emailHelper:              emailHelper,
+		reminderLead:             reminderLead,
+	}
+}
+
+func (u *appointmentSchedulerUsecaseImpl) SendReminders(ctx context.Context) (int, error) {
+	remindedCount := 0
+	var emailErr error
+
+	for {
+		appointments, err := u.markReminderBatch(ctx)
+		if err != nil {
+			return remindedCount, err
+		}
+
+		remindedCount += len(appointments)
+
+		for _, appointment := range appointments {
+			if err := u.sendAppointmentReminderEmails(appointment); err != nil && emailErr == nil {
+				emailErr = apperror.InternalServerError(err)
+			}
+		}
+
+		if len(appointments) < appointmentReminderBatchSize {
+			return remindedCount, emailErr
+		}
+	}
+}
+
+// Reminders are marked as sent before the emails go out, so a failed email
+// is not retried on every tick and replicas never remind twice.
+func (u *appointmentSchedulerUsecaseImpl) markReminderBatch(ctx context.Context) ([]entity.Appointment, error) {
+	tx, err := u.transaction.BeginTx(ctx)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	appointmentRepo := tx.AppointmentRepository()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+
+		tx.Commit()
+	}()
+
+	appointments, err := appointmentRepo.FindAllDueForReminderForUpdate(ctx, u.reminderLead, appointmentReminderBatchSize)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	for _, appointment := range appointments {
+		err = appointmentRepo.MarkReminderSent(ctx, appointment.Id)
+		if err != nil {
+			return nil, apperror.InternalServerError(err)
+		}
+	}
+
+	return appointments, nil
+}
+
+func (u *appointmentSchedulerUsecaseImpl) sendAppointmentReminderEmails(appointment entity.Appointment) error {
+	scheduledAt := appointment.ScheduledAt.Format(appointmentReminderLayout)
+
+	err := u.sendAppointmentReminderEmail(appointment.UserEmail, appointment.UserName, appointment.DoctorName, scheduledAt)
+	if err != nil {
+		return err
+	}
+
+	return u.sendAppointmentReminderEmail(appointment.DoctorEmail, appointment.DoctorName, appointment.UserName, scheduledAt)
+}
+
+func (u *appointmentSchedulerUsecaseImpl) sendAppointmentReminderEmail(email, name, counterpartName, scheduledAt string) error {
+	u.emailHelper.AddRequest([]string{email}, appconstant.AppointmentReminderEmailSubject)
+
+	err := u.emailHelper.CreateBody(appconstant.AppointmentReminderEmailTemplate, struct {
+		Name            string
+		CounterpartName string
+		ScheduledAt     string
+	}{
+		Name:            name,
+		CounterpartName: counterpartName,
+		ScheduledAt:     scheduledAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	return u.emailHelper.SendEmail()
+}
+
+// Only doctors who opted in have their online status follow their schedule.
+func (u *appointmentSchedulerUsecaseImpl) SyncDoctorAvailability(ctx context.Context) (int64, error) {
+	updatedCount, err := u.doctorScheduleRepository.SyncAvailability(ctx)
+	if err != nil {
+		return 0, apperror.InternalServerError(err)
+	}
+
+	return updatedCount, nil
+}
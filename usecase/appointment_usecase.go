@@ -0,0 +1,506 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sidiqPratomo/max-health-backend/appconstant"
+	"github.com/sidiqPratomo/max-health-backend/apperror"
+	"github.com/sidiqPratomo/max-health-backend/dto"
+	"github.com/sidiqPratomo/max-health-backend/entity"
+	"github.com/sidiqPratomo/max-health-backend/repository"
+	"github.com/sidiqPratomo/max-health-backend/util"
+)
+
+type AppointmentUsecase interface {
+	GetDoctorSchedule(ctx context.Context, doctorAccountId int64) (*dto.DoctorScheduleResponse, error)
+	UpdateDoctorSchedule(ctx context.Context, doctorAccountId int64, doctorScheduleRequest dto.DoctorScheduleRequest) error
+	AddScheduleException(ctx context.Context, doctorAccountId int64, doctorScheduleExceptionRequest dto.DoctorScheduleExceptionRequest) error
+	DeleteScheduleException(ctx context.Context, doctorAccountId int64, doctorScheduleExceptionId int64) error
+	GetAppointmentSlots(ctx context.Context, doctorId int64, appointmentSlotQuery dto.AppointmentSlotQuery) (*dto.AppointmentSlotsResponse, error)
+	BookAppointment(ctx context.Context, userAccountId int64, appointmentRequest dto.AppointmentRequest) (*dto.AppointmentResponse, error)
+	GetAppointments(ctx context.Context, accountId int64, appointmentQuery dto.AppointmentQuery) (*dto.AllAppointmentsResponse, error)
+	CancelAppointment(ctx context.Context, accountId int64, appointmentId int64) error
+}
+
+type appointmentUsecaseImpl struct {
+	userRepository           repository.UserRepository
+	doctorRepository         repository.DoctorRepository
+	doctorScheduleRepository repository.DoctorScheduleRepository
+	appointmentRepository    repository.AppointmentRepository
+	chatRoomRepository       repository.ChatRoomRepository
+	consultationQueue        ConsultationQueue
+	chatBroker               ChatBroker
+	transaction              repository.Transaction
+}
+
+func NewAppointmentUsecaseImpl(userRepository repository.UserRepository, doctorRepository repository.DoctorRepository, doctorScheduleRepository repository.DoctorScheduleRepository, appointmentRepository repository.AppointmentRepository, chatRoomRepository repository.ChatRoomRepository, consultationQueue ConsultationQueue, chatBroker ChatBroker, transaction repository.Transaction) appointmentUsecaseImpl {
+	return appointmentUsecaseImpl{
+		userRepository:           userRepository,
+		doctorRepository:         doctorRepository,
+		doctorScheduleRepository: doctorScheduleRepository,
+		appointmentRepository:    appointmentRepository,
+		chatRoomRepository:       chatRoomRepository,
+		consultationQueue:        consultationQueue,
+		chatBroker:               chatBroker,
+		transaction:              transaction,
+	}
+}
+
+func (u *appointmentUsecaseImpl) GetDoctorSchedule(ctx context.Context, doctorAccountId int64) (*dto.DoctorScheduleResponse, error) {
+	doctorSchedule, err := u.findDoctorSchedule(ctx, doctorAccountId)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.ConvertToDoctorScheduleResponse(*doctorSchedule)
+
+	return &response, nil
+}
+
+func (u *appointmentUsecaseImpl) findDoctorSchedule(ctx context.Context, doctorAccountId int64) (*entity.DoctorSchedule, error) {
+	doctorSchedule, err := u.doctorScheduleRepository.FindOneByAccountId(ctx, doctorAccountId, time.Now().AddDate(0, 0, -1))
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+	if doctorSchedule == nil {
+		return nil, apperror.ForbiddenAction()
+	}
+
+	return doctorSchedule, nil
+}
+
+// Windows on the same day may not overlap, otherwise the same slot would be
+// offered twice.
+func (u *appointmentUsecaseImpl) UpdateDoctorSchedule(ctx context.Context, doctorAccountId int64, doctorScheduleRequest dto.DoctorScheduleRequest) error {
+	doctorScheduleSlots := dto.DoctorScheduleRequestToDoctorScheduleSlots(doctorScheduleRequest)
+
+	windowsByDay := map[string][][2]time.Duration{}
+	for _, slot := range doctorScheduleSlots {
+		startHour, endHour, ok := parseDoctorScheduleHours(slot.StartHour, slot.EndHour)
+		if !ok {
+			return apperror.InvalidDoctorScheduleError()
+		}
+
+		windowsByDay[slot.Day] = append(windowsByDay[slot.Day], [2]time.Duration{startHour, endHour})
+	}
+
+	for _, windows := range windowsByDay {
+		sort.Slice(windows, func(i, j int) bool {
+			return windows[i][0] < windows[j][0]
+		})
+
+		for i := 1; i < len(windows); i++ {
+			if windows[i][0] < windows[i-1][1] {
+				return apperror.InvalidDoctorScheduleError()
+			}
+		}
+	}
+
+	if _, err := u.findDoctorSchedule(ctx, doctorAccountId); err != nil {
+		return err
+	}
+
+	tx, err := u.transaction.BeginTx(ctx)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	doctorScheduleRepo := tx.DoctorScheduleRepository()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+
+		tx.Commit()
+	}()
+
+	_, err = doctorScheduleRepo.UpdateSettingsOne(ctx, doctorAccountId, doctorScheduleRequest.Timezone, doctorScheduleRequest.UseScheduleAvailability)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	err = doctorScheduleRepo.ReplaceSlots(ctx, doctorAccountId, doctorScheduleSlots)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	_, err = doctorScheduleRepo.SyncAvailability(ctx)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	return nil
+}
+
+func (u *appointmentUsecaseImpl) AddScheduleException(ctx context.Context, doctorAccountId int64, doctorScheduleExceptionRequest dto.DoctorScheduleExceptionRequest) error {
+	date, err := time.Parse("2006-01-02", doctorScheduleExceptionRequest.Date)
+	if err != nil {
+		return apperror.InvalidDoctorScheduleError()
+	}
+
+	doctorScheduleException := entity.DoctorScheduleException{
+		DoctorAccountId: doctorAccountId,
+		Date:            date,
+		IsAvailable:     doctorScheduleExceptionRequest.IsAvailable,
+		Description:     strings.TrimSpace(doctorScheduleExceptionRequest.Description),
+	}
+
+	if doctorScheduleException.IsAvailable {
+		if doctorScheduleExceptionRequest.StartHour == nil || doctorScheduleExceptionRequest.EndHour == nil {
+			return apperror.InvalidDoctorScheduleError()
+		}
+
+		if _, _, ok := parseDoctorScheduleHours(*doctorScheduleExceptionRequest.StartHour, *doctorScheduleExceptionRequest.EndHour); !ok {
+			return apperror.InvalidDoctorScheduleError()
+		}
+
+		doctorScheduleException.StartHour = doctorScheduleExceptionRequest.StartHour
+		doctorScheduleException.EndHour = doctorScheduleExceptionRequest.EndHour
+	}
+
+	doctorSchedule, err := u.findDoctorSchedule(ctx, doctorAccountId)
+	if err != nil {
+		return err
+	}
+
+	location, err := time.LoadLocation(doctorSchedule.Timezone)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	now := time.Now().In(location)
+	if date.Before(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)) {
+		return apperror.InvalidDoctorScheduleError()
+	}
+
+	if _, err = u.doctorScheduleRepository.CreateOneException(ctx, doctorScheduleException); err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	return nil
+}
+
+func (u *appointmentUsecaseImpl) DeleteScheduleException(ctx context.Context, doctorAccountId int64, doctorScheduleExceptionId int64) error {
+	deletedCount, err := u.doctorScheduleRepository.DeleteOneException(ctx, doctorAccountId, doctorScheduleExceptionId)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+	if deletedCount == 0 {
+		return apperror.ScheduleExceptionNotFoundError()
+	}
+
+	return nil
+}
+
+// Slots that already started are left out. Booked slots stay in the list so
+// clients can show them as taken.
+func (u *appointmentUsecaseImpl) GetAppointmentSlots(ctx context.Context, doctorId int64, appointmentSlotQuery dto.AppointmentSlotQuery) (*dto.AppointmentSlotsResponse, error) {
+	doctor, doctorSchedule, err := u.findBookableDoctor(ctx, doctorId)
+	if err != nil {
+		return nil, err
+	}
+
+	location, err := time.LoadLocation(doctorSchedule.Timezone)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	date, err := time.ParseInLocation("2006-01-02", appointmentSlotQuery.Date, location)
+	if err != nil {
+		return nil, apperror.BadRequestError(err)
+	}
+
+	durationMinutes := consultationDurationMinutes(doctor.ConsultationDuration)
+
+	appointmentSlots, err := u.findOpenAppointmentSlots(ctx, *doctorSchedule, date, durationMinutes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.AppointmentSlotsResponse{
+		DoctorId:        doctorId,
+		Date:            appointmentSlotQuery.Date,
+		Timezone:        doctorSchedule.Timezone,
+		DurationMinutes: durationMinutes,
+		Slots:           dto.ConvertToAppointmentSlotResponses(appointmentSlots),
+	}, nil
+}
+
+func (u *appointmentUsecaseImpl) findBookableDoctor(ctx context.Context, doctorId int64) (*entity.DetailedDoctor, *entity.DoctorSchedule, error) {
+	doctor, err := u.doctorRepository.FindDoctorByDoctorId(ctx, doctorId)
+	if err != nil {
+		return nil, nil, apperror.InternalServerError(err)
+	}
+	if doctor == nil {
+		return nil, nil, apperror.DoctorNotFoundError()
+	}
+
+	doctorSchedule, err := u.doctorScheduleRepository.FindOneByDoctorId(ctx, doctorId, time.Now().AddDate(0, 0, -1))
+	if err != nil {
+		return nil, nil, apperror.InternalServerError(err)
+	}
+	if doctorSchedule == nil {
+		return nil, nil, apperror.DoctorNotFoundError()
+	}
+
+	return doctor, doctorSchedule, nil
+}
+
+func (u *appointmentUsecaseImpl) findOpenAppointmentSlots(ctx context.Context, doctorSchedule entity.DoctorSchedule, date time.Time, durationMinutes int) ([]entity.AppointmentSlot, error) {
+	slots, err := util.GetDoctorAppointmentSlots(doctorSchedule, date, time.Duration(durationMinutes)*time.Minute)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	now := time.Now()
+	appointmentSlots := []entity.AppointmentSlot{}
+	for _, slot := range slots {
+		if slot.StartAt.After(now) {
+			appointmentSlots = append(appointmentSlots, slot)
+		}
+	}
+
+	if len(appointmentSlots) == 0 {
+		return appointmentSlots, nil
+	}
+
+	appointments, err := u.appointmentRepository.FindAllBookedBetween(ctx, doctorSchedule.DoctorAccountId, appointmentSlots[0].StartAt, appointmentSlots[len(appointmentSlots)-1].EndAt)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	for i := range appointmentSlots {
+		for _, appointment := range appointments {
+			if appointment.ScheduledAt.Before(appointmentSlots[i].EndAt) && appointment.EndAt.After(appointmentSlots[i].StartAt) {
+				appointmentSlots[i].IsBooked = true
+				break
+			}
+		}
+	}
+
+	return appointmentSlots, nil
+}
+
+// The chat room is created right away so the fee can be paid ahead of time,
+// but it only reaches the doctor's queue at the booked time.
+func (u *appointmentUsecaseImpl) BookAppointment(ctx context.Context, userAccountId int64, appointmentRequest dto.AppointmentRequest) (*dto.AppointmentResponse, error) {
+	user, err := u.userRepository.FindUserByAccountId(ctx, userAccountId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+	if user == nil {
+		return nil, apperror.ForbiddenAction()
+	}
+
+	doctor, doctorSchedule, err := u.findBookableDoctor(ctx, appointmentRequest.DoctorId)
+	if err != nil {
+		return nil, err
+	}
+
+	appointmentSlots, err := u.findOpenAppointmentSlots(ctx, *doctorSchedule, appointmentRequest.StartAt, consultationDurationMinutes(doctor.ConsultationDuration))
+	if err != nil {
+		return nil, err
+	}
+
+	var appointmentSlot *entity.AppointmentSlot
+	for i := range appointmentSlots {
+		if appointmentSlots[i].StartAt.Equal(appointmentRequest.StartAt) {
+			appointmentSlot = &appointmentSlots[i]
+			break
+		}
+	}
+	if appointmentSlot == nil {
+		return nil, apperror.InvalidAppointmentSlotError()
+	}
+	if appointmentSlot.IsBooked {
+		return nil, apperror.AppointmentSlotTakenError()
+	}
+
+	room, err := u.chatRoomRepository.FindActiveChatRoom(ctx, userAccountId, doctorSchedule.DoctorAccountId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+	if room != nil {
+		return nil, apperror.OnGoingChatExistError()
+	}
+
+	tx, err := u.transaction.BeginTx(ctx)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	appointmentRepo := tx.AppointmentRepository()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+
+		tx.Commit()
+	}()
+
+	err = appointmentRepo.LockDoctorAppointments(ctx, doctorSchedule.DoctorAccountId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	overlappingCount, err := appointmentRepo.CountOverlapping(ctx, doctorSchedule.DoctorAccountId, appointmentSlot.StartAt, appointmentSlot.EndAt)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+	if overlappingCount > 0 {
+		return nil, apperror.AppointmentSlotTakenError()
+	}
+
+	roomId, err := u.consultationQueue.OpenRoom(ctx, tx, userAccountId, doctorSchedule.DoctorAccountId, doctor.FeePerPatient, false, &appointmentSlot.StartAt)
+	if err != nil {
+		return nil, err
+	}
+
+	appointment := entity.Appointment{
+		ChatRoomId:      *roomId,
+		UserAccountId:   userAccountId,
+		DoctorAccountId: doctorSchedule.DoctorAccountId,
+		ScheduledAt:     appointmentSlot.StartAt,
+		EndAt:           appointmentSlot.EndAt,
+	}
+
+	err = appointmentRepo.CreateOne(ctx, &appointment)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	createdAppointment, err := appointmentRepo.FindOneById(ctx, appointment.Id)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	response := dto.ConvertToAppointmentResponse(*createdAppointment)
+
+	return &response, nil
+}
+
+func (u *appointmentUsecaseImpl) GetAppointments(ctx context.Context, accountId int64, appointmentQuery dto.AppointmentQuery) (*dto.AllAppointmentsResponse, error) {
+	params, err := util.SetDefaultQueryParams(util.QueryParam{Page: appointmentQuery.Page, Limit: appointmentQuery.Limit})
+	if err != nil {
+		return nil, err
+	}
+
+	appointmentFilter := entity.AppointmentFilter{
+		AccountId: accountId,
+		Status:    appointmentQuery.Status,
+	}
+	appointmentFilter.Limit, _ = strconv.Atoi(params.Limit)
+	appointmentFilter.Offset, _ = strconv.Atoi(params.Offset)
+
+	appointments, pageInfo, err := u.appointmentRepository.FindAll(ctx, appointmentFilter)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	response := dto.ConvertToAllAppointmentsResponse(appointments, *pageInfo)
+
+	return &response, nil
+}
+
+func (u *appointmentUsecaseImpl) CancelAppointment(ctx context.Context, accountId int64, appointmentId int64) error {
+	appointment, err := u.appointmentRepository.FindOneById(ctx, appointmentId)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+	if appointment == nil {
+		return apperror.AppointmentNotFoundError()
+	}
+	if appointment.UserAccountId != accountId && appointment.DoctorAccountId != accountId {
+		return apperror.ForbiddenAction()
+	}
+
+	err = u.cancelAppointment(ctx, *appointment)
+	if err != nil {
+		return err
+	}
+
+	err = u.chatBroker.Publish(ctx, entity.ChatEvent{
+		Type:            appconstant.ChatEventClosed,
+		RoomId:          appointment.ChatRoomId,
+		SenderAccountId: accountId,
+	})
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	return nil
+}
+
+// Appointments can be cancelled until they start. The consultation fee is
+// cancelled, or refunded when it has already been paid.
+func (u *appointmentUsecaseImpl) cancelAppointment(ctx context.Context, appointment entity.Appointment) error {
+	tx, err := u.transaction.BeginTx(ctx)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	chatRoomRepo := tx.ChatRoomRepository()
+	consultationPaymentRepo := tx.ConsultationPaymentRepository()
+	appointmentRepo := tx.AppointmentRepository()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+
+		tx.Commit()
+	}()
+
+	_, err = consultationPaymentRepo.FindOneByChatRoomIdForUpdate(ctx, appointment.ChatRoomId)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	lockedAppointment, err := appointmentRepo.FindOneByIdForUpdate(ctx, appointment.Id)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+	if lockedAppointment == nil {
+		return apperror.AppointmentNotFoundError()
+	}
+	if lockedAppointment.Status != appconstant.AppointmentStatusBooked || !lockedAppointment.ScheduledAt.After(time.Now()) {
+		return apperror.AppointmentNotCancellableError()
+	}
+
+	_, err = consultationPaymentRepo.CancelOne(ctx, appointment.ChatRoomId)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	err = chatRoomRepo.CloseChatRoom(ctx, appointment.ChatRoomId)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	_, err = appointmentRepo.CancelOne(ctx, appointment.Id)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	return nil
+}
+
+func parseDoctorScheduleHours(startHour string, endHour string) (time.Duration, time.Duration, bool) {
+	startDuration, err := util.ParsePharmacyHour(startHour)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	endDuration, err := util.ParsePharmacyHour(endHour)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return startDuration, endDuration, endDuration > startDuration
+}
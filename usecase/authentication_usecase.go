@@ -22,9 +22,9 @@ import (
 type AuthenticationUsecase interface {
 	RegisterDoctor(ctx context.Context, registerRequest dto.RegisterRequest, specializationId int64, file multipart.File, fileHeader multipart.FileHeader) error
 	RegisterUser(ctx context.Context, registerRequest dto.RegisterRequest) error
-	Login(ctx context.Context, account entity.Account) (*entity.Tokens, error)
+	Login(ctx context.Context, account entity.Account, accountSession entity.AccountSession) (*entity.Tokens, error)
 	SendVerificationEmail(ctx context.Context, sendEmailRequest dto.SendEmailRequest) error
-	GetNewAccessToken(ctx context.Context, refreshToken string) (string, error)
+	GetNewAccessToken(ctx context.Context, refreshToken string, accountSession entity.AccountSession) (*entity.Tokens, error)
 	VerifyOneAccount(ctx context.Context, verificationPasswordRequest dto.VerificationPasswordRequest) error
 	SendResetPasswordToken(ctx context.Context, sendEmailRequest dto.SendEmailRequest) error
 	ResetPassword(ctx context.Context, resetPasswordTokenVerificationRequest dto.ResetPasswordVerificationRequest) error
@@ -41,6 +41,7 @@ type authenticationUsecaseImpl struct {
 	hashHelper                   util.HashHelperIntf
 	jwtHelper                    util.JwtAuthentication
 	emailHelper                  util.EmailHelper
+	accountTokenVersion          AccountTokenVersion
 }
 
 type AuthenticationUsecaseImplOpts struct {
@@ -57,6 +58,7 @@ type AuthenticationUsecaseImplOpts struct {
 	HashHelper                   util.HashHelperIntf
 	JwtHelper                    util.JwtAuthentication
 	EmailHelper                  util.EmailHelper
+	AccountTokenVersion          AccountTokenVersion
 }
 
 func NewAuthenticationUsecaseImpl(opts AuthenticationUsecaseImplOpts) authenticationUsecaseImpl {
@@ -71,6 +73,7 @@ func NewAuthenticationUsecaseImpl(opts AuthenticationUsecaseImplOpts) authentica
 		hashHelper:                   opts.HashHelper,
 		jwtHelper:                    opts.JwtHelper,
 		emailHelper:                  opts.EmailHelper,
+		accountTokenVersion:          opts.AccountTokenVersion,
 	}
 }
 
@@ -274,8 +277,7 @@ func (u *authenticationUsecaseImpl) SendVerificationEmail(ctx context.Context, s
 	return nil
 }
 
-func (u *authenticationUsecaseImpl) Login(ctx context.Context, account entity.Account) (*entity.Tokens, error) {
-	tokens := entity.Tokens{}
+func (u *authenticationUsecaseImpl) Login(ctx context.Context, account entity.Account, accountSession entity.AccountSession) (*entity.Tokens, error) {
 	userCredential, err := u.accountRepository.FindAccountByEmail(ctx, account.Email)
 	if err != nil {
 		return nil, apperror.InternalServerError(err)
@@ -294,26 +296,89 @@ func (u *authenticationUsecaseImpl) Login(ctx context.Context, account entity.Ac
 		return nil, apperror.WrongPasswordError(err)
 	}
 
-	customClaims := util.JwtCustomClaims{UserId: userCredential.Id, Email: userCredential.Email, Role: userCredential.RoleName, TokenDuration: 15}
-	accessToken, err := u.jwtHelper.CreateAndSign(customClaims, u.jwtHelper.Config.AccessSecret)
+	if userCredential.SuspendedAt != nil {
+		return nil, apperror.AccountSuspendedError()
+	}
+
+	tx, err := u.transaction.BeginTx(ctx)
 	if err != nil {
 		return nil, apperror.InternalServerError(err)
 	}
 
+	accountSessionRepo := tx.AccountSessionRepository()
+	refreshTokenRepo := tx.RefreshTokenRepository()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+
+		tx.Commit()
+	}()
+
+	accountSession.AccountId = userCredential.Id
+	accountSessionId, err := accountSessionRepo.CreateOne(ctx, accountSession)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	customClaims := util.JwtCustomClaims{UserId: userCredential.Id, Email: userCredential.Email, Role: userCredential.RoleName, SessionId: *accountSessionId, TokenVersion: userCredential.TokenVersion}
+	tokens, err := u.createTokens(customClaims)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	err = refreshTokenRepo.PostOneCode(ctx, userCredential.Id, *accountSessionId, tokens.RefreshToken)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	return tokens, nil
+}
+
+func (u *authenticationUsecaseImpl) createTokens(customClaims util.JwtCustomClaims) (*entity.Tokens, error) {
+	customClaims.TokenDuration = 15
+	accessToken, err := u.jwtHelper.CreateAndSign(customClaims, u.jwtHelper.Config.AccessSecret)
+	if err != nil {
+		return nil, err
+	}
+
 	customClaims.TokenDuration = 24 * 60
 	refreshToken, err := u.jwtHelper.CreateAndSign(customClaims, u.jwtHelper.Config.RefreshSecret)
 	if err != nil {
-		return nil, apperror.InternalServerError(err)
+		return nil, err
 	}
 
-	tokens.AccessToken = *accessToken
-	tokens.RefreshToken = *refreshToken
+	return &entity.Tokens{AccessToken: *accessToken, RefreshToken: *refreshToken}, nil
+}
+
+func (u *authenticationUsecaseImpl) GetNewAccessToken(ctx context.Context, refreshToken string, accountSession entity.AccountSession) (*entity.Tokens, error) {
+	claim, err := u.jwtHelper.ParseAndVerify(refreshToken, u.jwtHelper.Config.RefreshSecret)
+	if err != nil {
+		return nil, apperror.RefreshTokenExpiredError()
+	}
 
+	tokens, isReused, err := u.rotateRefreshToken(ctx, refreshToken, *claim, accountSession)
+	if err != nil {
+		return nil, err
+	}
+	if isReused {
+		return nil, apperror.RefreshTokenReusedError()
+	}
+
+	return tokens, nil
+}
+
+// A refresh token can only be exchanged once. Presenting a used one again means
+// it was copied, so the whole session is revoked and the revocation is committed.
+func (u *authenticationUsecaseImpl) rotateRefreshToken(ctx context.Context, refreshToken string, claim util.JwtCustomClaims, accountSession entity.AccountSession) (*entity.Tokens, bool, error) {
 	tx, err := u.transaction.BeginTx(ctx)
 	if err != nil {
-		return nil, apperror.InternalServerError(err)
+		return nil, false, apperror.InternalServerError(err)
 	}
 
+	accountRepo := tx.AccountRepository()
+	accountSessionRepo := tx.AccountSessionRepository()
 	refreshTokenRepo := tx.RefreshTokenRepository()
 
 	defer func() {
@@ -324,40 +389,55 @@ func (u *authenticationUsecaseImpl) Login(ctx context.Context, account entity.Ac
 		tx.Commit()
 	}()
 
-	err = refreshTokenRepo.InvalidateCodes(ctx, userCredential.Id)
+	storedToken, err := refreshTokenRepo.FindOneCodeForUpdate(ctx, refreshToken)
 	if err != nil {
-		return nil, apperror.InternalServerError(err)
+		return nil, false, apperror.InternalServerError(err)
+	}
+	if storedToken == nil || storedToken.AccountId != claim.UserId {
+		return nil, false, apperror.RefreshTokenExpiredError()
 	}
 
-	err = refreshTokenRepo.PostOneCode(ctx, userCredential.Id, *refreshToken)
-	if err != nil {
-		return nil, apperror.InternalServerError(err)
+	if storedToken.IsUsed {
+		_, err = accountSessionRepo.RevokeOneById(ctx, storedToken.AccountId, storedToken.AccountSessionId)
+		if err != nil {
+			return nil, false, apperror.InternalServerError(err)
+		}
+
+		return nil, true, nil
 	}
 
-	return &tokens, nil
-}
+	if storedToken.IsExpired {
+		return nil, false, apperror.RefreshTokenExpiredError()
+	}
 
-func (u *authenticationUsecaseImpl) GetNewAccessToken(ctx context.Context, refreshToken string) (string, error) {
-	claim, err := u.jwtHelper.ParseAndVerify(refreshToken, u.jwtHelper.Config.RefreshSecret)
+	tokenVersion, err := accountRepo.FindOneTokenVersionById(ctx, storedToken.AccountId)
 	if err != nil {
-		return "", apperror.InternalServerError(err)
+		return nil, false, apperror.InternalServerError(err)
+	}
+	if tokenVersion == nil || *tokenVersion != claim.TokenVersion {
+		return nil, false, apperror.RefreshTokenExpiredError()
 	}
 
-	refreshToken, err = u.refreshTokenRepository.FindOneCode(ctx, refreshToken)
+	claim.SessionId = storedToken.AccountSessionId
+	tokens, err := u.createTokens(claim)
 	if err != nil {
-		return "", apperror.InternalServerError(err)
+		return nil, false, apperror.InternalServerError(err)
 	}
-	if refreshToken == "" {
-		return "", apperror.RefreshTokenExpiredError()
+
+	if err = refreshTokenRepo.UpdateUsedAtOne(ctx, storedToken.Id); err != nil {
+		return nil, false, apperror.InternalServerError(err)
 	}
 
-	claim.TokenDuration = 15
-	accessToken, err := u.jwtHelper.CreateAndSign(*claim, u.jwtHelper.Config.AccessSecret)
-	if err != nil {
-		return "", apperror.InternalServerError(err)
+	if err = refreshTokenRepo.PostOneCode(ctx, storedToken.AccountId, storedToken.AccountSessionId, tokens.RefreshToken); err != nil {
+		return nil, false, apperror.InternalServerError(err)
+	}
+
+	accountSession.Id = storedToken.AccountSessionId
+	if err = accountSessionRepo.UpdateLastSeenOne(ctx, accountSession); err != nil {
+		return nil, false, apperror.InternalServerError(err)
 	}
 
-	return *accessToken, nil
+	return tokens, false, nil
 }
 
 func (u *authenticationUsecaseImpl) VerifyOneAccount(ctx context.Context, verificationPasswordRequest dto.VerificationPasswordRequest) error {
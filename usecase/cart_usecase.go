@@ -4,16 +4,18 @@ import (
 	"context"
 	"errors"
 	"strconv"
+	"time"
 
 	"github.com/sidiqPratomo/max-health-backend/appconstant"
 	"github.com/sidiqPratomo/max-health-backend/apperror"
 	"github.com/sidiqPratomo/max-health-backend/dto"
 	"github.com/sidiqPratomo/max-health-backend/repository"
+	"github.com/sidiqPratomo/max-health-backend/util"
 )
 
 type CartUsecase interface {
 	CalculateDeliveryFee(ctx context.Context, deliveryFeeRequest dto.DeliveryFeeRequest) (*dto.AllDeliveryFeeResponse, error)
-	CreateOneCart(ctx context.Context, pharmacyDrugId int64) error
+	CreateOneCart(ctx context.Context, pharmacyDrugId int64, prescriptionId *int64) error
 	UpdateOneCart(ctx context.Context, cartItemID int64, quantity int) error
 	DeleteOneCart(ctx context.Context, cartItemID int64) error
 	GetAllCartById(ctx context.Context, page string, limit string) (*dto.CartDTOResponse, error)
@@ -24,14 +26,20 @@ type cartUsecaseImpl struct {
 	userAddressRepository  repository.UserAddressRepository
 	cartRepository         repository.CartRepository
 	pharmacyDrugRepository repository.PharmacyDrugRepository
+	shippingRateProvider   util.ShippingRateProvider
+	pharmacyHours          PharmacyHours
+	prescriptionCompliance PrescriptionCompliance
 }
 
-func NewCartUsecaseImpl(pharmacyDrugRepository repository.PharmacyDrugRepository, userRepository repository.UserRepository, userAddressRepository repository.UserAddressRepository, cartRepository repository.CartRepository) cartUsecaseImpl {
+func NewCartUsecaseImpl(pharmacyDrugRepository repository.PharmacyDrugRepository, userRepository repository.UserRepository, userAddressRepository repository.UserAddressRepository, cartRepository repository.CartRepository, shippingRateProvider util.ShippingRateProvider, pharmacyHours PharmacyHours, prescriptionCompliance PrescriptionCompliance) cartUsecaseImpl {
 	return cartUsecaseImpl{
 		userRepository:         userRepository,
 		userAddressRepository:  userAddressRepository,
 		cartRepository:         cartRepository,
 		pharmacyDrugRepository: pharmacyDrugRepository,
+		shippingRateProvider:   shippingRateProvider,
+		pharmacyHours:          pharmacyHours,
+		prescriptionCompliance: prescriptionCompliance,
 	}
 }
 
@@ -78,12 +86,18 @@ func (u *cartUsecaseImpl) CalculateDeliveryFee(ctx context.Context, deliveryFeeR
 		return nil, apperror.InternalServerError(err)
 	}
 
+	for i := range deliveryFees {
+		if err := fillCourierOptions(ctx, u.shippingRateProvider, deliveryFees[i].Couriers); err != nil {
+			return nil, err
+		}
+	}
+
 	deliveryFeesResponse := dto.AllDeliveryFeeResponse{Pharmacies: deliveryFees}
 
 	return &deliveryFeesResponse, nil
 }
 
-func (u *cartUsecaseImpl) CreateOneCart(ctx context.Context, pharmacyDrugId int64) error {
+func (u *cartUsecaseImpl) CreateOneCart(ctx context.Context, pharmacyDrugId int64, prescriptionId *int64) error {
 	id := appconstant.AccountId
 
 	accountId := ctx.Value(id)
@@ -104,12 +118,25 @@ func (u *cartUsecaseImpl) CreateOneCart(ctx context.Context, pharmacyDrugId int6
 	if err != nil {
 		return apperror.BadRequestError(err)
 	}
+	if pharmacyDrug == nil {
+		return apperror.DrugNotFoundError()
+	}
 
 	if pharmacyDrug.Stock < 1 {
 		return apperror.NewAppError(422, errors.New("insufficient stock"), "insufficient stock")
 	}
 
-	_, err = u.cartRepository.PostOneCart(ctx, accountID, pharmacyDrugId, 1)
+	err = u.pharmacyHours.CheckPharmacyIsOpen(ctx, pharmacyDrug.PharmacyId, time.Now())
+	if err != nil {
+		return err
+	}
+
+	prescriptionDrugId, err := u.prescriptionCompliance.FindPrescriptionDrugForCart(ctx, accountID, *pharmacyDrug, prescriptionId, 1)
+	if err != nil {
+		return err
+	}
+
+	_, err = u.cartRepository.PostOneCart(ctx, accountID, pharmacyDrugId, 1, prescriptionDrugId)
 	if err != nil {
 		return apperror.InternalServerError(err)
 	}
@@ -143,6 +170,11 @@ func (u *cartUsecaseImpl) UpdateOneCart(ctx context.Context, cartItemID int64, q
 		return apperror.NewAppError(422, errors.New("insufficient stock"), "insufficient stock")
 	}
 
+	err = u.prescriptionCompliance.CheckCartQuantity(ctx, cartItemID, quantity)
+	if err != nil {
+		return err
+	}
+
 	err = u.cartRepository.UpdateOneCart(ctx, accountID, cartItemID, quantity)
 	if err != nil {
 		return apperror.InternalServerError(err)
@@ -213,13 +245,14 @@ func (u *cartUsecaseImpl) GetAllCartById(ctx context.Context, limit string, page
 			UserId:   cart.UserId,
 			Quantity: cart.Quantity,
 			PharmacyDrugs: dto.PharmacyDrugsDto{
-				Id:           cart.PharmacyDrugId,
-				PharmacyId:   cart.PharmacyId,
-				Name:         cart.DrugName,
-				Price:        cart.Price,
-				Image:        cart.Image,
-				PharmacyName: cart.PharmacyName,
-				Stock:        cart.Stock,
+				Id:             cart.PharmacyDrugId,
+				PharmacyId:     cart.PharmacyId,
+				Name:           cart.DrugName,
+				Price:          cart.Price,
+				Image:          cart.Image,
+				PharmacyName:   cart.PharmacyName,
+				Stock:          cart.Stock,
+				IsPharmacyOpen: cart.IsPharmacyOpen,
 			},
 		}
 
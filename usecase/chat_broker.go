@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/sidiqPratomo/max-health-backend/apperror"
+	"github.com/sidiqPratomo/max-health-backend/entity"
+	"github.com/sidiqPratomo/max-health-backend/repository"
+	"github.com/sirupsen/logrus"
+)
+
+const chatListenRetryInterval = 3 * time.Second
+
+type ChatBroker interface {
+	Subscribe(subscription *entity.ChatSubscription)
+	Unsubscribe(subscription *entity.ChatSubscription)
+	Publish(ctx context.Context, chatEvent entity.ChatEvent) error
+}
+
+// inProcessChatBroker delivers chat events to the subscribers of this replica only.
+type inProcessChatBroker struct {
+	hub *chatHub
+}
+
+func NewInProcessChatBroker() *inProcessChatBroker {
+	return &inProcessChatBroker{
+		hub: newChatHub(),
+	}
+}
+
+func (b *inProcessChatBroker) Subscribe(subscription *entity.ChatSubscription) {
+	b.hub.subscribe(subscription)
+}
+
+func (b *inProcessChatBroker) Unsubscribe(subscription *entity.ChatSubscription) {
+	b.hub.unsubscribe(subscription)
+}
+
+func (b *inProcessChatBroker) Publish(ctx context.Context, chatEvent entity.ChatEvent) error {
+	b.hub.publish(chatEvent)
+
+	return nil
+}
+
+// postgresChatBroker fans chat events out to every backend replica through
+// LISTEN/NOTIFY, each replica then delivers them to its own subscribers.
+type postgresChatBroker struct {
+	chatEventRepository        repository.ChatEventRepository
+	chatRepository             repository.ChatRepository
+	prescriptionDrugRepository repository.PrescriptionDrugRepository
+	local                      ChatBroker
+	log                        *logrus.Logger
+}
+
+func NewPostgresChatBroker(ctx context.Context, chatEventRepository repository.ChatEventRepository, chatRepository repository.ChatRepository, prescriptionDrugRepository repository.PrescriptionDrugRepository, local ChatBroker, log *logrus.Logger) *postgresChatBroker {
+	broker := &postgresChatBroker{
+		chatEventRepository:        chatEventRepository,
+		chatRepository:             chatRepository,
+		prescriptionDrugRepository: prescriptionDrugRepository,
+		local:                      local,
+		log:                        log,
+	}
+
+	go broker.listen(ctx)
+
+	return broker
+}
+
+func (b *postgresChatBroker) Subscribe(subscription *entity.ChatSubscription) {
+	b.local.Subscribe(subscription)
+}
+
+func (b *postgresChatBroker) Unsubscribe(subscription *entity.ChatSubscription) {
+	b.local.Unsubscribe(subscription)
+}
+
+// NOTIFY payloads are capped at 8000 bytes, so only the chat id is sent and
+// every replica loads the chat itself before delivering the event.
+func (b *postgresChatBroker) Publish(ctx context.Context, chatEvent entity.ChatEvent) error {
+	if chatEvent.Chat != nil {
+		chatEvent.ChatId = chatEvent.Chat.Id
+		chatEvent.Chat = nil
+	}
+
+	payload, err := json.Marshal(chatEvent)
+	if err != nil {
+		return err
+	}
+
+	return b.chatEventRepository.NotifyOne(ctx, string(payload))
+}
+
+func (b *postgresChatBroker) listen(ctx context.Context) {
+	for {
+		err := b.chatEventRepository.ListenAll(ctx, func(payload string) {
+			b.receive(ctx, payload)
+		})
+		if ctx.Err() != nil {
+			return
+		}
+
+		b.log.WithFields(logrus.Fields{
+			"error": err.Error(),
+		}).Error("chat broker lost its listen connection")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(chatListenRetryInterval):
+		}
+	}
+}
+
+func (b *postgresChatBroker) receive(ctx context.Context, payload string) {
+	var chatEvent entity.ChatEvent
+
+	err := json.Unmarshal([]byte(payload), &chatEvent)
+	if err != nil {
+		b.log.WithFields(logrus.Fields{
+			"error": err.Error(),
+		}).Error("chat broker received a malformed event")
+		return
+	}
+
+	if chatEvent.ChatId != 0 {
+		chatEvent.Chat, err = b.findChat(ctx, chatEvent.ChatId)
+		if err != nil {
+			b.log.WithFields(logrus.Fields{
+				"error":   err.Error(),
+				"chat_id": chatEvent.ChatId,
+			}).Error("chat broker could not load the chat of an event")
+			return
+		}
+	}
+
+	b.local.Publish(ctx, chatEvent)
+}
+
+func (b *postgresChatBroker) findChat(ctx context.Context, chatId int64) (*entity.Chat, error) {
+	chat, err := b.chatRepository.FindOneChatById(ctx, chatId)
+	if err != nil {
+		return nil, err
+	}
+	if chat == nil {
+		return nil, apperror.NotFoundError()
+	}
+
+	if chat.Prescription.Id != nil {
+		prescriptionDrugList, err := b.prescriptionDrugRepository.GetAllPrescriptionDrug(ctx, *chat.Prescription.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		chat.Prescription.PrescriptionDrugs = prescriptionDrugList
+	}
+
+	return chat, nil
+}
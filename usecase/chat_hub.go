@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"sync"
+
+	"github.com/sidiqPratomo/max-health-backend/appconstant"
+	"github.com/sidiqPratomo/max-health-backend/entity"
+)
+
+const chatSubscriptionBufferSize = 64
+
+type chatHub struct {
+	subscriptions     map[int64]map[*entity.ChatSubscription]struct{}
+	subscriptionsLock sync.Mutex
+}
+
+func newChatHub() *chatHub {
+	return &chatHub{
+		subscriptions: make(map[int64]map[*entity.ChatSubscription]struct{}),
+	}
+}
+
+func (h *chatHub) subscribe(subscription *entity.ChatSubscription) {
+	h.subscriptionsLock.Lock()
+	defer h.subscriptionsLock.Unlock()
+
+	subscription.Events = make(chan entity.ChatEvent, chatSubscriptionBufferSize)
+
+	if h.subscriptions[subscription.RoomId] == nil {
+		h.subscriptions[subscription.RoomId] = make(map[*entity.ChatSubscription]struct{})
+	}
+
+	h.subscriptions[subscription.RoomId][subscription] = struct{}{}
+}
+
+func (h *chatHub) unsubscribe(subscription *entity.ChatSubscription) {
+	h.subscriptionsLock.Lock()
+	defer h.subscriptionsLock.Unlock()
+
+	h.remove(subscription)
+}
+
+// publish never blocks the sender, a subscriber that can not keep up is dropped
+// and its channel closed so the client reconnects and reloads the room history.
+func (h *chatHub) publish(chatEvent entity.ChatEvent) {
+	h.subscriptionsLock.Lock()
+	defer h.subscriptionsLock.Unlock()
+
+	for subscription := range h.subscriptions[chatEvent.RoomId] {
+		isOwnSignal := chatEvent.Type == appconstant.ChatEventTyping || chatEvent.Type == appconstant.ChatEventRead
+		if isOwnSignal && chatEvent.SenderAccountId == subscription.AccountId {
+			continue
+		}
+
+		select {
+		case subscription.Events <- chatEvent:
+		default:
+			h.remove(subscription)
+		}
+	}
+}
+
+func (h *chatHub) remove(subscription *entity.ChatSubscription) {
+	roomSubscriptions, ok := h.subscriptions[subscription.RoomId]
+	if !ok {
+		return
+	}
+
+	if _, ok := roomSubscriptions[subscription]; !ok {
+		return
+	}
+
+	delete(roomSubscriptions, subscription)
+	close(subscription.Events)
+
+	if len(roomSubscriptions) == 0 {
+		delete(h.subscriptions, subscription.RoomId)
+	}
+}
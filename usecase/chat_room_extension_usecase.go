@@ -0,0 +1,408 @@
+package usecase
+
+import (
+	"context"
+	"mime/multipart"
+	"net/http"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/sidiqPratomo/max-health-backend/appconstant"
+	"github.com/sidiqPratomo/max-health-backend/apperror"
+	"github.com/sidiqPratomo/max-health-backend/dto"
+	"github.com/sidiqPratomo/max-health-backend/entity"
+	"github.com/sidiqPratomo/max-health-backend/repository"
+	"github.com/sidiqPratomo/max-health-backend/util"
+)
+
+type ChatRoomExtensionUsecase interface {
+	RequestExtension(ctx context.Context, doctorAccountId, roomId int64, minutes int) (*dto.ChatRoomExtensionResponse, error)
+	RespondExtension(ctx context.Context, userAccountId, roomId, extensionId int64, isAccepted bool) (*dto.ChatRoomExtensionResponse, error)
+	UploadPaymentProof(ctx context.Context, userAccountId, roomId, extensionId int64, file multipart.File, fileHeader multipart.FileHeader) error
+	ConfirmPayment(ctx context.Context, roomId, extensionId int64, isApproved bool) (*dto.ChatRoomExtensionResponse, error)
+}
+
+type chatRoomExtensionUsecaseImpl struct {
+	doctorRepository              repository.DoctorRepository
+	consultationPaymentRepository repository.ConsultationPaymentRepository
+	chatBroker                    ChatBroker
+	transaction                   repository.Transaction
+}
+
+func NewChatRoomExtensionUsecaseImpl(doctorRepository repository.DoctorRepository, consultationPaymentRepository repository.ConsultationPaymentRepository, chatBroker ChatBroker, transaction repository.Transaction) chatRoomExtensionUsecaseImpl {
+	return chatRoomExtensionUsecaseImpl{
+		doctorRepository:              doctorRepository,
+		consultationPaymentRepository: consultationPaymentRepository,
+		chatBroker:                    chatBroker,
+		transaction:                   transaction,
+	}
+}
+
+func (u *chatRoomExtensionUsecaseImpl) RequestExtension(ctx context.Context, doctorAccountId, roomId int64, minutes int) (*dto.ChatRoomExtensionResponse, error) {
+	doctor, err := u.doctorRepository.FindDoctorByAccountId(ctx, doctorAccountId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+	if doctor == nil {
+		return nil, apperror.ForbiddenAction()
+	}
+
+	chatRoomExtension, err := u.createChatRoomExtension(ctx, doctorAccountId, roomId, minutes, consultationDurationMinutes(doctor.ConsultationDuration))
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.chatBroker.Publish(ctx, entity.ChatEvent{
+		Type:            appconstant.ChatEventExtensionRequest,
+		RoomId:          roomId,
+		SenderAccountId: doctorAccountId,
+		Extension:       chatRoomExtension,
+	})
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	response := dto.ConvertToChatRoomExtensionResponse(*chatRoomExtension)
+
+	return &response, nil
+}
+
+// The extra minutes are billed at the same rate as the consultation the user
+// already paid for.
+func (u *chatRoomExtensionUsecaseImpl) createChatRoomExtension(ctx context.Context, doctorAccountId, roomId int64, minutes, durationMinutes int) (*entity.ChatRoomExtension, error) {
+	tx, err := u.transaction.BeginTx(ctx)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	chatRoomRepo := tx.ChatRoomRepository()
+	consultationPaymentRepo := tx.ConsultationPaymentRepository()
+	chatRoomExtensionRepo := tx.ChatRoomExtensionRepository()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+
+		tx.Commit()
+	}()
+
+	consultationPayment, err := consultationPaymentRepo.FindOneByChatRoomIdForUpdate(ctx, roomId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+	if consultationPayment == nil {
+		return nil, apperror.ChatRoomNotFoundError()
+	}
+
+	chatRoom, err := chatRoomRepo.FindChatRoomById(ctx, roomId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+	if chatRoom == nil {
+		return nil, apperror.ChatRoomNotFoundError()
+	}
+	if chatRoom.DoctorAccountId != doctorAccountId {
+		return nil, apperror.ForbiddenAction()
+	}
+	if chatRoom.StartedAt == nil || chatRoom.ExpiredAt == nil {
+		return nil, apperror.ChatRoomNotStartedError()
+	}
+	if chatRoom.ExpiredAt.Before(time.Now()) {
+		return nil, apperror.RoomIsNowExpiredError()
+	}
+
+	pendingExtension, err := chatRoomExtensionRepo.FindOnePendingByChatRoomId(ctx, roomId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+	if pendingExtension != nil {
+		return nil, apperror.ExtensionAlreadyPendingError()
+	}
+
+	chatRoomExtension := entity.ChatRoomExtension{
+		ChatRoomId:           roomId,
+		RequestedByAccountId: doctorAccountId,
+		Minutes:              minutes,
+		Fee:                  consultationPayment.Amount.Mul(decimal.NewFromInt(int64(minutes))).Div(decimal.NewFromInt(int64(durationMinutes))).Round(0),
+	}
+
+	err = chatRoomExtensionRepo.CreateOne(ctx, &chatRoomExtension)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	return &chatRoomExtension, nil
+}
+
+func (u *chatRoomExtensionUsecaseImpl) RespondExtension(ctx context.Context, userAccountId, roomId, extensionId int64, isAccepted bool) (*dto.ChatRoomExtensionResponse, error) {
+	chatRoomExtension, expiredAt, err := u.respondChatRoomExtension(ctx, userAccountId, roomId, extensionId, isAccepted)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.ConvertToChatRoomExtensionResponse(*chatRoomExtension)
+	response.ExpiredAt = expiredAt
+
+	if chatRoomExtension.Status == appconstant.ChatRoomExtensionExpired {
+		return &response, nil
+	}
+
+	chatEventType := appconstant.ChatEventExtensionRejected
+	switch chatRoomExtension.Status {
+	case appconstant.ChatRoomExtensionWaitingForPayment:
+		chatEventType = appconstant.ChatEventExtensionUnpaid
+	case appconstant.ChatRoomExtensionAccepted:
+		chatEventType = appconstant.ChatEventExtensionAccepted
+	}
+
+	err = u.chatBroker.Publish(ctx, entity.ChatEvent{
+		Type:            chatEventType,
+		RoomId:          roomId,
+		SenderAccountId: userAccountId,
+		ExpiredAt:       expiredAt,
+		Extension:       chatRoomExtension,
+	})
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	return &response, nil
+}
+
+// An accepted extension with a fee only waits for its payment, the room is
+// extended once that payment is confirmed. An extension accepted after the
+// room already ran out is marked expired instead of reopening the room.
+func (u *chatRoomExtensionUsecaseImpl) respondChatRoomExtension(ctx context.Context, userAccountId, roomId, extensionId int64, isAccepted bool) (*entity.ChatRoomExtension, *time.Time, error) {
+	tx, err := u.transaction.BeginTx(ctx)
+	if err != nil {
+		return nil, nil, apperror.InternalServerError(err)
+	}
+
+	chatRoomRepo := tx.ChatRoomRepository()
+	consultationPaymentRepo := tx.ConsultationPaymentRepository()
+	chatRoomExtensionRepo := tx.ChatRoomExtensionRepository()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+
+		tx.Commit()
+	}()
+
+	consultationPayment, err := consultationPaymentRepo.FindOneByChatRoomIdForUpdate(ctx, roomId)
+	if err != nil {
+		return nil, nil, apperror.InternalServerError(err)
+	}
+	if consultationPayment == nil {
+		return nil, nil, apperror.ChatRoomNotFoundError()
+	}
+	if consultationPayment.UserAccountId != userAccountId {
+		return nil, nil, apperror.ForbiddenAction()
+	}
+
+	chatRoomExtension, err := chatRoomExtensionRepo.FindOneByIdForUpdate(ctx, extensionId)
+	if err != nil {
+		return nil, nil, apperror.InternalServerError(err)
+	}
+	if chatRoomExtension == nil || chatRoomExtension.ChatRoomId != roomId {
+		return nil, nil, apperror.ExtensionNotFoundError()
+	}
+	if chatRoomExtension.Status != appconstant.ChatRoomExtensionPending {
+		return nil, nil, apperror.ExtensionNotPendingError()
+	}
+
+	var expiredAt *time.Time
+
+	chatRoomExtension.Status = appconstant.ChatRoomExtensionRejected
+	if isAccepted && chatRoomExtension.Fee.IsZero() {
+		expiredAt, err = chatRoomRepo.ExtendOne(ctx, roomId, chatRoomExtension.Minutes)
+		if err != nil {
+			return nil, nil, apperror.InternalServerError(err)
+		}
+
+		chatRoomExtension.Status = appconstant.ChatRoomExtensionAccepted
+		if expiredAt == nil {
+			chatRoomExtension.Status = appconstant.ChatRoomExtensionExpired
+		}
+	}
+
+	if isAccepted && !chatRoomExtension.Fee.IsZero() {
+		var chatRoom *entity.ChatRoom
+
+		chatRoom, err = chatRoomRepo.FindChatRoomById(ctx, roomId)
+		if err != nil {
+			return nil, nil, apperror.InternalServerError(err)
+		}
+
+		chatRoomExtension.Status = appconstant.ChatRoomExtensionExpired
+		if chatRoom != nil && chatRoom.ExpiredAt != nil && chatRoom.ExpiredAt.After(time.Now()) {
+			_, err = consultationPaymentRepo.CreateOne(ctx, entity.ConsultationPayment{
+				ChatRoomId:          roomId,
+				ChatRoomExtensionId: &chatRoomExtension.Id,
+				Amount:              chatRoomExtension.Fee,
+				Status:              appconstant.ConsultationPaymentWaitingForPayment,
+			})
+			if err != nil {
+				return nil, nil, apperror.InternalServerError(err)
+			}
+
+			chatRoomExtension.Status = appconstant.ChatRoomExtensionWaitingForPayment
+		}
+	}
+
+	chatRoomExtension.RespondedAt, err = chatRoomExtensionRepo.UpdateStatusOne(ctx, extensionId, appconstant.ChatRoomExtensionPending, chatRoomExtension.Status)
+	if err != nil {
+		return nil, nil, apperror.InternalServerError(err)
+	}
+
+	return chatRoomExtension, expiredAt, nil
+}
+
+func (u *chatRoomExtensionUsecaseImpl) UploadPaymentProof(ctx context.Context, userAccountId, roomId, extensionId int64, file multipart.File, fileHeader multipart.FileHeader) error {
+	extensionPayment, err := u.consultationPaymentRepository.FindOneByChatRoomExtensionId(ctx, extensionId)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+	if extensionPayment == nil || extensionPayment.ChatRoomId != roomId {
+		return apperror.ConsultationPaymentNotFoundError()
+	}
+	if extensionPayment.UserAccountId != userAccountId {
+		return apperror.ForbiddenAction()
+	}
+	if extensionPayment.Status != appconstant.ConsultationPaymentWaitingForPayment {
+		return apperror.InvalidConsultationPaymentStatusError()
+	}
+
+	filePath, _, err := util.ValidateFile(fileHeader, appconstant.ConsultationPaymentProofsUrl, []string{"png", "jpg", "jpeg"}, 2000000)
+	if err != nil {
+		return apperror.NewAppError(http.StatusBadRequest, err, err.Error())
+	}
+
+	paymentProofUrl, err := util.UploadToCloudinary(file, *filePath)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	updatedCount, err := u.consultationPaymentRepository.UpdatePaymentProofOneByChatRoomExtensionId(ctx, extensionId, paymentProofUrl)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+	if updatedCount == 0 {
+		return apperror.InvalidConsultationPaymentStatusError()
+	}
+
+	return nil
+}
+
+func (u *chatRoomExtensionUsecaseImpl) ConfirmPayment(ctx context.Context, roomId, extensionId int64, isApproved bool) (*dto.ChatRoomExtensionResponse, error) {
+	chatRoomExtension, extensionPayment, expiredAt, err := u.confirmExtensionPayment(ctx, roomId, extensionId, isApproved)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isApproved {
+		deleteConsultationPaymentProof(extensionPayment.PaymentProof)
+	}
+
+	response := dto.ConvertToChatRoomExtensionResponse(*chatRoomExtension)
+	response.ExpiredAt = expiredAt
+
+	if chatRoomExtension.Status != appconstant.ChatRoomExtensionAccepted {
+		return &response, nil
+	}
+
+	err = u.chatBroker.Publish(ctx, entity.ChatEvent{
+		Type:            appconstant.ChatEventExtensionAccepted,
+		RoomId:          roomId,
+		SenderAccountId: extensionPayment.UserAccountId,
+		ExpiredAt:       expiredAt,
+		Extension:       chatRoomExtension,
+	})
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	return &response, nil
+}
+
+// A payment confirmed after the room already ran out is refunded and its
+// extension marked expired. A rejected proof is deleted by the caller once
+// the rejection is committed.
+func (u *chatRoomExtensionUsecaseImpl) confirmExtensionPayment(ctx context.Context, roomId, extensionId int64, isApproved bool) (*entity.ChatRoomExtension, *entity.ConsultationPayment, *time.Time, error) {
+	tx, err := u.transaction.BeginTx(ctx)
+	if err != nil {
+		return nil, nil, nil, apperror.InternalServerError(err)
+	}
+
+	chatRoomRepo := tx.ChatRoomRepository()
+	consultationPaymentRepo := tx.ConsultationPaymentRepository()
+	chatRoomExtensionRepo := tx.ChatRoomExtensionRepository()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+
+		tx.Commit()
+	}()
+
+	extensionPayment, err := consultationPaymentRepo.FindOneByChatRoomExtensionIdForUpdate(ctx, extensionId)
+	if err != nil {
+		return nil, nil, nil, apperror.InternalServerError(err)
+	}
+	if extensionPayment == nil || extensionPayment.ChatRoomId != roomId {
+		return nil, nil, nil, apperror.ConsultationPaymentNotFoundError()
+	}
+	if extensionPayment.Status != appconstant.ConsultationPaymentWaitingForConfirmation {
+		return nil, nil, nil, apperror.InvalidConsultationPaymentStatusError()
+	}
+	if extensionPayment.PaymentProof == "" {
+		return nil, nil, nil, apperror.PaymentProofIsEmptyError()
+	}
+
+	chatRoomExtension, err := chatRoomExtensionRepo.FindOneByIdForUpdate(ctx, extensionId)
+	if err != nil {
+		return nil, nil, nil, apperror.InternalServerError(err)
+	}
+	if chatRoomExtension == nil {
+		return nil, nil, nil, apperror.ExtensionNotFoundError()
+	}
+	if chatRoomExtension.Status != appconstant.ChatRoomExtensionWaitingForPayment {
+		return nil, nil, nil, apperror.ExtensionNotPendingError()
+	}
+
+	if !isApproved {
+		if _, err = consultationPaymentRepo.RejectOneByChatRoomExtensionId(ctx, extensionId); err != nil {
+			return nil, nil, nil, apperror.InternalServerError(err)
+		}
+
+		return chatRoomExtension, extensionPayment, nil, nil
+	}
+
+	if _, err = consultationPaymentRepo.ConfirmOneByChatRoomExtensionId(ctx, extensionId); err != nil {
+		return nil, nil, nil, apperror.InternalServerError(err)
+	}
+
+	expiredAt, err := chatRoomRepo.ExtendOne(ctx, roomId, chatRoomExtension.Minutes)
+	if err != nil {
+		return nil, nil, nil, apperror.InternalServerError(err)
+	}
+
+	chatRoomExtension.Status = appconstant.ChatRoomExtensionAccepted
+	if expiredAt == nil {
+		chatRoomExtension.Status = appconstant.ChatRoomExtensionExpired
+
+		if _, err = consultationPaymentRepo.CancelOneByChatRoomExtensionId(ctx, extensionId); err != nil {
+			return nil, nil, nil, apperror.InternalServerError(err)
+		}
+	}
+
+	chatRoomExtension.RespondedAt, err = chatRoomExtensionRepo.UpdateStatusOne(ctx, extensionId, appconstant.ChatRoomExtensionWaitingForPayment, chatRoomExtension.Status)
+	if err != nil {
+		return nil, nil, nil, apperror.InternalServerError(err)
+	}
+
+	return chatRoomExtension, extensionPayment, expiredAt, nil
+}
@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/sidiqPratomo/max-health-backend/appconstant"
+	"github.com/sidiqPratomo/max-health-backend/apperror"
+	"github.com/sidiqPratomo/max-health-backend/entity"
+	"github.com/sidiqPratomo/max-health-backend/repository"
+)
+
+const expiredConsultationBatchSize = 50
+
+type ConsultationExpiryUsecase interface {
+	ExpireConsultations(ctx context.Context) (int, error)
+}
+
+type consultationExpiryUsecaseImpl struct {
+	transaction       repository.Transaction
+	consultationQueue ConsultationQueue
+	paymentTimeout    int
+	joinTimeout       int
+}
+
+func NewConsultationExpiryUsecaseImpl(transaction repository.Transaction, consultationQueue ConsultationQueue, paymentTimeout int, joinTimeout int) consultationExpiryUsecaseImpl {
+	return consultationExpiryUsecaseImpl{
+		transaction:       transaction,
+		consultationQueue: consultationQueue,
+		paymentTimeout:    paymentTimeout,
+		joinTimeout:       joinTimeout,
+	}
+}
+
+func (u *consultationExpiryUsecaseImpl) ExpireConsultations(ctx context.Context) (int, error) {
+	expiredCount := 0
+
+	for {
+		batchCount, lockedCount, err := u.expireConsultationBatch(ctx)
+		if err != nil {
+			return expiredCount, err
+		}
+
+		expiredCount += batchCount
+
+		if lockedCount < expiredConsultationBatchSize {
+			return expiredCount, nil
+		}
+	}
+}
+
+// Unpaid consultations are cancelled along with their appointment and their
+// room is closed. Paid requests the doctor never answered are released from
+// the doctor's queue, which moves them to a fallback doctor or refunds the fee.
+func (u *consultationExpiryUsecaseImpl) expireConsultationBatch(ctx context.Context) (int, int, error) {
+	tx, err := u.transaction.BeginTx(ctx)
+	if err != nil {
+		return 0, 0, apperror.InternalServerError(err)
+	}
+
+	consultationPaymentRepo := tx.ConsultationPaymentRepository()
+	chatRoomRepo := tx.ChatRoomRepository()
+	appointmentRepo := tx.AppointmentRepository()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+
+		tx.Commit()
+	}()
+
+	lockedConsultationPayments, err := consultationPaymentRepo.FindAllExpiredForUpdate(ctx, u.paymentTimeout, u.joinTimeout, expiredConsultationBatchSize)
+	if err != nil {
+		return 0, 0, apperror.InternalServerError(err)
+	}
+
+	expiredCount := 0
+
+	for _, lockedConsultationPayment := range lockedConsultationPayments {
+		if lockedConsultationPayment.Status == appconstant.ConsultationPaymentPaid {
+			var chatRoom *entity.ChatRoom
+			chatRoom, err = chatRoomRepo.FindChatRoomById(ctx, lockedConsultationPayment.ChatRoomId)
+			if err != nil {
+				return 0, 0, apperror.InternalServerError(err)
+			}
+
+			_, err = u.consultationQueue.ReleaseRoom(ctx, tx, *chatRoom, lockedConsultationPayment, appconstant.ChatRoomDeclineReasonExpired)
+			if err != nil {
+				return 0, 0, err
+			}
+
+			expiredCount++
+			continue
+		}
+
+		var updatedCount int64
+		updatedCount, err = consultationPaymentRepo.CancelOne(ctx, lockedConsultationPayment.ChatRoomId)
+		if err != nil {
+			return 0, 0, apperror.InternalServerError(err)
+		}
+
+		if updatedCount == 0 {
+			continue
+		}
+
+		err = chatRoomRepo.CloseChatRoom(ctx, lockedConsultationPayment.ChatRoomId)
+		if err != nil {
+			return 0, 0, apperror.InternalServerError(err)
+		}
+
+		// The booked slot would otherwise keep blocking the doctor's schedule.
+		_, err = appointmentRepo.CancelOneByChatRoomId(ctx, lockedConsultationPayment.ChatRoomId)
+		if err != nil {
+			return 0, 0, apperror.InternalServerError(err)
+		}
+
+		expiredCount++
+	}
+
+	return expiredCount, len(lockedConsultationPayments), nil
+}
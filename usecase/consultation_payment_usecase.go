@@ -0,0 +1,189 @@
+package usecase
+
+import (
+	"context"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/sidiqPratomo/max-health-backend/appconstant"
+	"github.com/sidiqPratomo/max-health-backend/apperror"
+	"github.com/sidiqPratomo/max-health-backend/dto"
+	"github.com/sidiqPratomo/max-health-backend/entity"
+	"github.com/sidiqPratomo/max-health-backend/repository"
+	"github.com/sidiqPratomo/max-health-backend/util"
+)
+
+type ConsultationPaymentUsecase interface {
+	GetConsultationPayment(ctx context.Context, accountId, roomId int64) (*dto.ConsultationPaymentResponse, error)
+	GetAllConsultationPayments(ctx context.Context, query dto.ConsultationPaymentQuery) (*dto.AllConsultationPaymentsResponse, error)
+	UploadPaymentProof(ctx context.Context, accountId, roomId int64, file multipart.File, fileHeader multipart.FileHeader) error
+	ConfirmPayment(ctx context.Context, roomId int64, isApproved bool) error
+}
+
+type consultationPaymentUsecaseImpl struct {
+	consultationPaymentRepository repository.ConsultationPaymentRepository
+	transaction                   repository.Transaction
+}
+
+func NewConsultationPaymentUsecaseImpl(consultationPaymentRepository repository.ConsultationPaymentRepository, transaction repository.Transaction) consultationPaymentUsecaseImpl {
+	return consultationPaymentUsecaseImpl{
+		consultationPaymentRepository: consultationPaymentRepository,
+		transaction:                   transaction,
+	}
+}
+
+func (u *consultationPaymentUsecaseImpl) GetConsultationPayment(ctx context.Context, accountId, roomId int64) (*dto.ConsultationPaymentResponse, error) {
+	consultationPayment, err := u.consultationPaymentRepository.FindOneByChatRoomId(ctx, roomId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+	if consultationPayment == nil {
+		return nil, apperror.ConsultationPaymentNotFoundError()
+	}
+
+	if consultationPayment.UserAccountId != accountId && consultationPayment.DoctorAccountId != accountId {
+		return nil, apperror.ForbiddenAction()
+	}
+
+	response := dto.ConvertToConsultationPaymentResponse(*consultationPayment)
+
+	return &response, nil
+}
+
+func (u *consultationPaymentUsecaseImpl) GetAllConsultationPayments(ctx context.Context, query dto.ConsultationPaymentQuery) (*dto.AllConsultationPaymentsResponse, error) {
+	params, err := util.SetDefaultQueryParams(util.QueryParam{Page: query.Page, Limit: query.Limit})
+	if err != nil {
+		return nil, err
+	}
+
+	limit, _ := strconv.Atoi(params.Limit)
+	offset, _ := strconv.Atoi(params.Offset)
+
+	consultationPayments, pageInfo, err := u.consultationPaymentRepository.FindAll(ctx, entity.ConsultationPaymentFilter{
+		Status: query.Status,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	response := dto.ConvertToAllConsultationPaymentsResponse(consultationPayments, *pageInfo)
+
+	return &response, nil
+}
+
+func (u *consultationPaymentUsecaseImpl) UploadPaymentProof(ctx context.Context, accountId, roomId int64, file multipart.File, fileHeader multipart.FileHeader) error {
+	consultationPayment, err := u.consultationPaymentRepository.FindOneByChatRoomId(ctx, roomId)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+	if consultationPayment == nil {
+		return apperror.ConsultationPaymentNotFoundError()
+	}
+	if consultationPayment.UserAccountId != accountId {
+		return apperror.ForbiddenAction()
+	}
+	if consultationPayment.Status != appconstant.ConsultationPaymentWaitingForPayment {
+		return apperror.InvalidConsultationPaymentStatusError()
+	}
+
+	filePath, _, err := util.ValidateFile(fileHeader, appconstant.ConsultationPaymentProofsUrl, []string{"png", "jpg", "jpeg"}, 2000000)
+	if err != nil {
+		return apperror.NewAppError(http.StatusBadRequest, err, err.Error())
+	}
+
+	paymentProofUrl, err := util.UploadToCloudinary(file, *filePath)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	updatedCount, err := u.consultationPaymentRepository.UpdatePaymentProofOne(ctx, roomId, paymentProofUrl)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+	if updatedCount == 0 {
+		return apperror.InvalidConsultationPaymentStatusError()
+	}
+
+	return nil
+}
+
+func (u *consultationPaymentUsecaseImpl) ConfirmPayment(ctx context.Context, roomId int64, isApproved bool) error {
+	rejectedPaymentProof, err := u.confirmPayment(ctx, roomId, isApproved)
+	if err != nil {
+		return err
+	}
+
+	if rejectedPaymentProof != "" {
+		deleteConsultationPaymentProof(rejectedPaymentProof)
+	}
+
+	return nil
+}
+
+// The rejected proof is returned instead of deleted here, so the file is only
+// removed once the rejection is committed.
+func (u *consultationPaymentUsecaseImpl) confirmPayment(ctx context.Context, roomId int64, isApproved bool) (string, error) {
+	tx, err := u.transaction.BeginTx(ctx)
+	if err != nil {
+		return "", apperror.InternalServerError(err)
+	}
+
+	consultationPaymentRepo := tx.ConsultationPaymentRepository()
+	chatRoomRepo := tx.ChatRoomRepository()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+
+		tx.Commit()
+	}()
+
+	consultationPayment, err := consultationPaymentRepo.FindOneByChatRoomIdForUpdate(ctx, roomId)
+	if err != nil {
+		return "", apperror.InternalServerError(err)
+	}
+	if consultationPayment == nil {
+		return "", apperror.ConsultationPaymentNotFoundError()
+	}
+	if consultationPayment.Status != appconstant.ConsultationPaymentWaitingForConfirmation {
+		return "", apperror.InvalidConsultationPaymentStatusError()
+	}
+
+	if consultationPayment.PaymentProof == "" {
+		return "", apperror.PaymentProofIsEmptyError()
+	}
+
+	if isApproved {
+		if _, err = consultationPaymentRepo.ConfirmOne(ctx, roomId); err != nil {
+			return "", apperror.InternalServerError(err)
+		}
+
+		if err = chatRoomRepo.EnqueueOne(ctx, roomId); err != nil {
+			return "", apperror.InternalServerError(err)
+		}
+
+		return "", nil
+	}
+
+	if _, err = consultationPaymentRepo.RejectOne(ctx, roomId); err != nil {
+		return "", apperror.InternalServerError(err)
+	}
+
+	return consultationPayment.PaymentProof, nil
+}
+
+// Only proofs uploaded to Cloudinary are deleted, a malformed URL is left
+// alone.
+func deleteConsultationPaymentProof(paymentProof string) {
+	paymentProofUrl, err := url.Parse(paymentProof)
+	if err != nil || paymentProofUrl.Host != "res.cloudinary.com" {
+		return
+	}
+
+	util.DeleteInCloudinary(paymentProof)
+}
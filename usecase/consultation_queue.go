@@ -0,0 +1,183 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/sidiqPratomo/max-health-backend/appconstant"
+	"github.com/sidiqPratomo/max-health-backend/apperror"
+	"github.com/sidiqPratomo/max-health-backend/entity"
+	"github.com/sidiqPratomo/max-health-backend/repository"
+)
+
+type ConsultationQueue interface {
+	OpenRoom(ctx context.Context, tx repository.Transaction, userAccountId, doctorAccountId int64, feePerPatient decimal.Decimal, allowFallback bool, scheduledAt *time.Time) (*int64, error)
+	GetQueueStatus(ctx context.Context, chatRoom entity.ChatRoom) (*entity.ConsultationQueueStatus, error)
+	ReleaseRoom(ctx context.Context, tx repository.Transaction, chatRoom entity.ChatRoom, consultationPayment entity.ConsultationPayment, reason string) (*int64, error)
+}
+
+type consultationQueueImpl struct {
+	chatRoomRepository repository.ChatRoomRepository
+	joinTimeout        time.Duration
+}
+
+func NewConsultationQueueImpl(chatRoomRepository repository.ChatRoomRepository, joinTimeout time.Duration) consultationQueueImpl {
+	return consultationQueueImpl{
+		chatRoomRepository: chatRoomRepository,
+		joinTimeout:        joinTimeout,
+	}
+}
+
+// A free consultation joins the doctor's queue right away, a paid one once its
+// fee is confirmed. Scheduled rooms only reach the queue at their booked time.
+func (q *consultationQueueImpl) OpenRoom(ctx context.Context, tx repository.Transaction, userAccountId, doctorAccountId int64, feePerPatient decimal.Decimal, allowFallback bool, scheduledAt *time.Time) (*int64, error) {
+	chatRoomRepo := tx.ChatRoomRepository()
+	consultationPaymentRepo := tx.ConsultationPaymentRepository()
+
+	roomId, err := chatRoomRepo.CreateOneRoom(ctx, userAccountId, doctorAccountId, allowFallback, scheduledAt)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	paymentStatus := appconstant.ConsultationPaymentWaitingForPayment
+	if !feePerPatient.IsPositive() {
+		paymentStatus = appconstant.ConsultationPaymentPaid
+	}
+
+	_, err = consultationPaymentRepo.CreateOne(ctx, entity.ConsultationPayment{
+		ChatRoomId: *roomId,
+		Amount:     feePerPatient,
+		Status:     paymentStatus,
+	})
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	if paymentStatus == appconstant.ConsultationPaymentPaid {
+		err = chatRoomRepo.EnqueueOne(ctx, *roomId)
+		if err != nil {
+			return nil, apperror.InternalServerError(err)
+		}
+	}
+
+	return roomId, nil
+}
+
+// The first user in line waits as long as the doctor usually takes to pick
+// up a request, and every user ahead adds one average consultation.
+func (q *consultationQueueImpl) GetQueueStatus(ctx context.Context, chatRoom entity.ChatRoom) (*entity.ConsultationQueueStatus, error) {
+	queueStatus := entity.ConsultationQueueStatus{
+		RoomId:          chatRoom.Id,
+		DoctorAccountId: chatRoom.DoctorAccountId,
+		AssignedAt:      chatRoom.AssignedAt,
+	}
+
+	if chatRoom.ExpiredAt != nil {
+		queueStatus.Status = appconstant.ConsultationQueueClosed
+		if chatRoom.StartedAt != nil && chatRoom.ExpiredAt.After(time.Now()) {
+			queueStatus.Status = appconstant.ConsultationQueueInConsultation
+		}
+
+		return &queueStatus, nil
+	}
+
+	if chatRoom.QueuedAt == nil {
+		queueStatus.Status = appconstant.ConsultationQueueWaitingForPayment
+		return &queueStatus, nil
+	}
+
+	if chatRoom.QueuedAt.After(time.Now()) {
+		queueStatus.Status = appconstant.ConsultationQueueScheduled
+		return &queueStatus, nil
+	}
+
+	position, queueLength, err := q.chatRoomRepository.GetQueuePosition(ctx, chatRoom.Id, chatRoom.DoctorAccountId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	history, err := q.chatRoomRepository.GetDoctorConsultationHistory(ctx, chatRoom.DoctorAccountId, appconstant.ConsultationHistorySampleLength)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	averageDuration := history.AverageDuration
+	if averageDuration <= 0 {
+		averageDuration = appconstant.ConsultationDefaultDuration
+	}
+
+	queueStatus.Status = appconstant.ConsultationQueueQueued
+	queueStatus.Position = position
+	queueStatus.QueueLength = queueLength
+
+	if position > 0 {
+		queueStatus.EstimatedWait = history.AveragePickup + time.Duration(position-1)*averageDuration
+	}
+
+	if chatRoom.AssignedAt != nil && q.joinTimeout > 0 {
+		expiresAt := chatRoom.AssignedAt.Add(q.joinTimeout)
+		queueStatus.ExpiresAt = &expiresAt
+	}
+
+	return &queueStatus, nil
+}
+
+// A declined or unanswered request moves to another available doctor with
+// the same specialization when the user allowed it. Otherwise the room is
+// closed and its fee cancelled or refunded. The caller must hold the lock on
+// the consultation payment.
+func (q *consultationQueueImpl) ReleaseRoom(ctx context.Context, tx repository.Transaction, chatRoom entity.ChatRoom, consultationPayment entity.ConsultationPayment, reason string) (*int64, error) {
+	chatRoomRepo := tx.ChatRoomRepository()
+	doctorRepo := tx.DoctorRepository()
+	consultationPaymentRepo := tx.ConsultationPaymentRepository()
+	appointmentRepo := tx.AppointmentRepository()
+
+	err := chatRoomRepo.CreateOneDecline(ctx, chatRoom.Id, chatRoom.DoctorAccountId, reason)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	if chatRoom.AllowFallback {
+		doctorAccountId, err := doctorRepo.FindFallbackDoctorAccountId(ctx, chatRoom, consultationPayment.Amount)
+		if err != nil {
+			return nil, apperror.InternalServerError(err)
+		}
+
+		if doctorAccountId != nil {
+			err = chatRoomRepo.ReassignOne(ctx, chatRoom.Id, *doctorAccountId)
+			if err != nil {
+				return nil, apperror.InternalServerError(err)
+			}
+
+			return doctorAccountId, nil
+		}
+	}
+
+	_, err = consultationPaymentRepo.CancelOne(ctx, chatRoom.Id)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	err = chatRoomRepo.CloseChatRoom(ctx, chatRoom.Id)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	_, err = appointmentRepo.CancelOneByChatRoomId(ctx, chatRoom.Id)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	return nil, nil
+}
+
+// The doctor's own duration already falls back to the one set for their
+// specialization. Without either the default length is used.
+func consultationDurationMinutes(consultationDuration *int) int {
+	if consultationDuration == nil {
+		return int(appconstant.ConsultationDefaultDuration.Minutes())
+	}
+
+	return *consultationDuration
+}
@@ -0,0 +1,213 @@
+package usecase
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sidiqPratomo/max-health-backend/apperror"
+	"github.com/sidiqPratomo/max-health-backend/dto"
+	"github.com/sidiqPratomo/max-health-backend/entity"
+	"github.com/sidiqPratomo/max-health-backend/repository"
+	"github.com/sidiqPratomo/max-health-backend/util"
+)
+
+type DoctorReviewUsecase interface {
+	CreateReview(ctx context.Context, userAccountId, roomId int64, doctorReviewRequest dto.DoctorReviewRequest) (*dto.DoctorReviewResponse, error)
+	ReplyReview(ctx context.Context, doctorAccountId, reviewId int64, reply string) error
+	GetDoctorReviews(ctx context.Context, doctorId int64, query dto.DoctorReviewQuery) (*dto.AllDoctorReviewsResponse, error)
+	GetAllReviews(ctx context.Context, query dto.DoctorReviewQuery) (*dto.AllDoctorReviewsResponse, error)
+	ModerateReview(ctx context.Context, reviewId int64, isHidden bool, reason string) error
+}
+
+type doctorReviewUsecaseImpl struct {
+	doctorReviewRepository repository.DoctorReviewRepository
+	chatRoomRepository     repository.ChatRoomRepository
+	transaction            repository.Transaction
+}
+
+func NewDoctorReviewUsecaseImpl(doctorReviewRepository repository.DoctorReviewRepository, chatRoomRepository repository.ChatRoomRepository, transaction repository.Transaction) doctorReviewUsecaseImpl {
+	return doctorReviewUsecaseImpl{
+		doctorReviewRepository: doctorReviewRepository,
+		chatRoomRepository:     chatRoomRepository,
+		transaction:            transaction,
+	}
+}
+
+// Only consultations the doctor actually joined and that have ended can be
+// reviewed, once per chat room.
+func (u *doctorReviewUsecaseImpl) CreateReview(ctx context.Context, userAccountId, roomId int64, doctorReviewRequest dto.DoctorReviewRequest) (*dto.DoctorReviewResponse, error) {
+	chatRoom, err := u.chatRoomRepository.FindChatRoomById(ctx, roomId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+	if chatRoom == nil {
+		return nil, apperror.ChatRoomNotFoundError()
+	}
+	if chatRoom.UserAccountId != userAccountId {
+		return nil, apperror.ForbiddenAction()
+	}
+	if chatRoom.StartedAt == nil || chatRoom.ExpiredAt == nil || chatRoom.ExpiredAt.After(time.Now()) {
+		return nil, apperror.ChatRoomNotReviewableError()
+	}
+
+	tx, err := u.transaction.BeginTx(ctx)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	doctorReviewRepo := tx.DoctorReviewRepository()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+
+		tx.Commit()
+	}()
+
+	err = doctorReviewRepo.LockDoctorRating(ctx, chatRoom.DoctorAccountId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	existingReview, err := doctorReviewRepo.FindOneByChatRoomId(ctx, roomId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+	if existingReview != nil {
+		return nil, apperror.ReviewAlreadyExistsError()
+	}
+
+	doctorReview := entity.DoctorReview{
+		ChatRoomId:      roomId,
+		UserAccountId:   userAccountId,
+		DoctorAccountId: chatRoom.DoctorAccountId,
+		Rating:          doctorReviewRequest.Rating,
+		Review:          strings.TrimSpace(doctorReviewRequest.Review),
+	}
+
+	err = doctorReviewRepo.CreateOne(ctx, &doctorReview)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	err = doctorReviewRepo.RefreshDoctorRating(ctx, chatRoom.DoctorAccountId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	createdReview, err := doctorReviewRepo.FindOneById(ctx, doctorReview.Id)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	response := dto.ConvertToDoctorReviewResponse(*createdReview)
+
+	return &response, nil
+}
+
+func (u *doctorReviewUsecaseImpl) ReplyReview(ctx context.Context, doctorAccountId, reviewId int64, reply string) error {
+	doctorReview, err := u.doctorReviewRepository.FindOneById(ctx, reviewId)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+	if doctorReview == nil {
+		return apperror.ReviewNotFoundError()
+	}
+	if doctorReview.DoctorAccountId != doctorAccountId {
+		return apperror.ForbiddenAction()
+	}
+
+	_, err = u.doctorReviewRepository.UpdateReplyOne(ctx, reviewId, strings.TrimSpace(reply))
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	return nil
+}
+
+func (u *doctorReviewUsecaseImpl) GetDoctorReviews(ctx context.Context, doctorId int64, query dto.DoctorReviewQuery) (*dto.AllDoctorReviewsResponse, error) {
+	isHidden := false
+
+	return u.getReviews(ctx, entity.DoctorReviewFilter{DoctorId: &doctorId, IsHidden: &isHidden}, query)
+}
+
+func (u *doctorReviewUsecaseImpl) GetAllReviews(ctx context.Context, query dto.DoctorReviewQuery) (*dto.AllDoctorReviewsResponse, error) {
+	doctorReviewFilter := entity.DoctorReviewFilter{}
+
+	if query.IsHidden != "" {
+		isHidden := query.IsHidden == "true"
+		doctorReviewFilter.IsHidden = &isHidden
+	}
+
+	return u.getReviews(ctx, doctorReviewFilter, query)
+}
+
+func (u *doctorReviewUsecaseImpl) getReviews(ctx context.Context, doctorReviewFilter entity.DoctorReviewFilter, query dto.DoctorReviewQuery) (*dto.AllDoctorReviewsResponse, error) {
+	params, err := util.SetDefaultQueryParams(util.QueryParam{Page: query.Page, Limit: query.Limit})
+	if err != nil {
+		return nil, err
+	}
+
+	doctorReviewFilter.Limit, _ = strconv.Atoi(params.Limit)
+	doctorReviewFilter.Offset, _ = strconv.Atoi(params.Offset)
+
+	doctorReviews, pageInfo, err := u.doctorReviewRepository.FindAll(ctx, doctorReviewFilter)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	response := dto.ConvertToAllDoctorReviewsResponse(doctorReviews, *pageInfo)
+
+	return &response, nil
+}
+
+// Hidden reviews stay visible to admins but no longer count towards the
+// doctor's rating.
+func (u *doctorReviewUsecaseImpl) ModerateReview(ctx context.Context, reviewId int64, isHidden bool, reason string) error {
+	doctorReview, err := u.doctorReviewRepository.FindOneById(ctx, reviewId)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+	if doctorReview == nil {
+		return apperror.ReviewNotFoundError()
+	}
+
+	if !isHidden {
+		reason = ""
+	}
+
+	tx, err := u.transaction.BeginTx(ctx)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	doctorReviewRepo := tx.DoctorReviewRepository()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+
+		tx.Commit()
+	}()
+
+	err = doctorReviewRepo.LockDoctorRating(ctx, doctorReview.DoctorAccountId)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	_, err = doctorReviewRepo.UpdateVisibilityOne(ctx, reviewId, isHidden, strings.TrimSpace(reason))
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	err = doctorReviewRepo.RefreshDoctorRating(ctx, doctorReview.DoctorAccountId)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	return nil
+}
@@ -30,6 +30,7 @@ type DoctorUsecase interface {
 		SpecializationId string,
 		Page string) (*dto.GetAllDoctorResponse, error)
 	GetAllDoctorSpecialization(ctx context.Context) ([]dto.DoctorSpecialization, error)
+	UpdateSpecializationConsultationDuration(ctx context.Context, specializationId int64, consultationDuration *int) error
 	GetProfile(ctx context.Context, accountId int64) (*dto.DoctorProfileResponse, error)
 	GetProfileForPublic(ctx context.Context, doctorId int64) (*dto.DoctorProfileResponse, error)
 	UpdateDoctorStatus(ctx context.Context, doctorAccountId int64, isOnline bool) error
@@ -179,7 +180,7 @@ func (u *doctorUsecaseImpl) GetAllDoctors(
 
 	if SortBy != "" {
 		for _, sortByName := range SortByList {
-			if sortByName != "fee_per_patient" && sortByName != "experience" {
+			if sortByName != "fee_per_patient" && sortByName != "experience" && sortByName != "rating" {
 				return nil, apperror.BadRequestError(errors.New("invalid sortBy Name"))
 			}
 		}
@@ -215,6 +216,8 @@ func (u *doctorUsecaseImpl) GetAllDoctors(
 			Experience:         doctor.Experience,
 			Name:               account.Name,
 			SpecializationName: *specialist,
+			Rating:             doctor.Rating,
+			ReviewCount:        doctor.ReviewCount,
 		}
 
 		getAllDoctor = append(getAllDoctor, doctorDto)
@@ -237,6 +240,18 @@ func (u *doctorUsecaseImpl) GetAllDoctorSpecialization(ctx context.Context) ([]d
 	return doctorSpecializationListDTO, nil
 }
 
+func (u *doctorUsecaseImpl) UpdateSpecializationConsultationDuration(ctx context.Context, specializationId int64, consultationDuration *int) error {
+	updatedCount, err := u.doctorSpecializationRepository.UpdateConsultationDurationOne(ctx, specializationId, consultationDuration)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+	if updatedCount == 0 {
+		return apperror.SpecializationNotFoundError()
+	}
+
+	return nil
+}
+
 func (u *doctorUsecaseImpl) GetProfile(ctx context.Context, accountId int64) (*dto.DoctorProfileResponse, error) {
 	account, err := u.accountRepository.FindOneById(ctx, accountId)
 	if err != nil {
@@ -264,6 +279,9 @@ func (u *doctorUsecaseImpl) GetProfile(ctx context.Context, accountId int64) (*d
 	res.SpecializationId = doctor.SpecializationId
 	res.FeePerPatient = doctor.FeePerPatient
 	res.Experience = doctor.Experience
+	res.ConsultationDuration = consultationDurationMinutes(doctor.ConsultationDuration)
+	res.Rating = doctor.Rating
+	res.ReviewCount = doctor.ReviewCount
 
 	return res, nil
 }
@@ -287,6 +305,9 @@ func (u *doctorUsecaseImpl) GetProfileForPublic(ctx context.Context, doctorId in
 	res.SpecializationId = doctor.SpecializationId
 	res.FeePerPatient = doctor.FeePerPatient
 	res.Experience = doctor.Experience
+	res.ConsultationDuration = consultationDurationMinutes(doctor.ConsultationDuration)
+	res.Rating = doctor.Rating
+	res.ReviewCount = doctor.ReviewCount
 
 	return res, nil
 }
@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/sidiqPratomo/max-health-backend/repository"
+)
+
+// DrugSearchRefreshUsecase rebuilds the drug search terms outside of the
+// request that changed a drug, requests made while one is already waiting are
+// merged into it.
+type DrugSearchRefreshUsecase interface {
+	RequestRefresh()
+	RefreshRequests() <-chan struct{}
+	RefreshSearchTerms(ctx context.Context) error
+}
+
+type drugSearchRefreshUsecaseImpl struct {
+	drugRepository repository.DrugRepository
+	requests       chan struct{}
+}
+
+func NewDrugSearchRefreshUsecaseImpl(drugRepository repository.DrugRepository) drugSearchRefreshUsecaseImpl {
+	return drugSearchRefreshUsecaseImpl{
+		drugRepository: drugRepository,
+		requests:       make(chan struct{}, 1),
+	}
+}
+
+func (u *drugSearchRefreshUsecaseImpl) RequestRefresh() {
+	select {
+	case u.requests <- struct{}{}:
+	default:
+	}
+}
+
+func (u *drugSearchRefreshUsecaseImpl) RefreshRequests() <-chan struct{} {
+	return u.requests
+}
+
+func (u *drugSearchRefreshUsecaseImpl) RefreshSearchTerms(ctx context.Context) error {
+	// Anything requested before the refresh starts is covered by it.
+	select {
+	case <-u.requests:
+	default:
+	}
+
+	return u.drugRepository.RefreshSearchTerms(ctx)
+}
@@ -17,20 +17,26 @@ import (
 	"github.com/sidiqPratomo/max-health-backend/util"
 )
 
+const (
+	drugSuggestionDefaultLimit = 10
+	drugSuggestionMaxLimit     = 20
+)
+
 type DrugUsecase interface {
 	GetPharmacyDrugByDrugId(ctx context.Context, drugId int64, latitude, longitude, page, limit string) (*dto.DrugDetailResponse, error)
-	GetAllDrugsForListing(ctx context.Context, query *util.ValidatedGetProductQuery) ([]entity.DrugListing, *entity.PageInfo, error)
+	GetAllDrugsForListing(ctx context.Context, query *util.ValidatedGetProductQuery) (*dto.DrugListingResponse, error)
+	SuggestDrugs(ctx context.Context, query dto.DrugSuggestQuery) (*dto.DrugSuggestionsResponse, error)
 	UpdateOneDrug(ctx context.Context, drugId int64, drugRequest dto.UpdateDrugRequest, file multipart.File, fileHeader *multipart.FileHeader) error
 	GetAllDrugs(ctx context.Context, query *util.ValidatedGetDrugAdminQuery) (*dto.AllDrugsResponse, error)
 	GetOneDrugByDrugId(ctx context.Context, drugId int64) (*dto.DrugResponse, error)
 	CreateOneDrug(ctx context.Context, drugRequest dto.CreateDrugRequest, file multipart.File, fileHeader *multipart.FileHeader) error
 	DeleteOneDrug(ctx context.Context, drugId int64) error
 	GetDrugsByPharmacyId(ctx context.Context, pharmacyId string, limit string, page string, search string) (*dto.PharmacyDrugsByPharmacyResponse, error)
-	UpdateDrugsByPharmacyDrugId(ctx context.Context, pharmacyDrugId int64, stock int, price decimal.Decimal) error
+	UpdateDrugsByPharmacyDrugId(ctx context.Context, accountId, pharmacyDrugId int64, stock int, price decimal.Decimal, batch *dto.PharmacyDrugBatchRequest) error
 	DeleteDrugsByPharmacyDrugId(ctx context.Context, pharmacyDrugId int64) error
-	AddDrugsByPharmacyDrugId(ctx context.Context, pharmacyId int64, drugId int64, stock int, price decimal.Decimal) error
+	AddDrugsByPharmacyDrugId(ctx context.Context, pharmacyId int64, drugId int64, stock int, price decimal.Decimal, batch *dto.PharmacyDrugBatchRequest) error
 	GetPossibleStockMutation(ctx context.Context, pharmacyDrugId int64) ([]dto.PharmacyDrugMutationsResponse, error)
-	PostStockMutation(ctx context.Context, req dto.PostStockMutationRequest) error
+	PostStockMutation(ctx context.Context, accountId int64, req dto.PostStockMutationRequest) error
 }
 
 type drugUsecaseImpl struct {
@@ -41,9 +47,10 @@ type drugUsecaseImpl struct {
 	drugClassificationRepository repository.DrugClassificationRepository
 	drugFormRepository           repository.DrugFormRepository
 	pharmacyRepository           repository.PharmacyRepository
+	drugSearchRefresh            DrugSearchRefreshUsecase
 }
 
-func NewDrugUsecaseImpl(transaction repository.Transaction, drugRepository repository.DrugRepository, pharmacyDrugRepository repository.PharmacyDrugRepository, drugClassificationRepository repository.DrugClassificationRepository, drugFormRepository repository.DrugFormRepository, categoryRepository repository.CategoryRepository, pharmacyRepository repository.PharmacyRepository) drugUsecaseImpl {
+func NewDrugUsecaseImpl(transaction repository.Transaction, drugRepository repository.DrugRepository, pharmacyDrugRepository repository.PharmacyDrugRepository, drugClassificationRepository repository.DrugClassificationRepository, drugFormRepository repository.DrugFormRepository, categoryRepository repository.CategoryRepository, pharmacyRepository repository.PharmacyRepository, drugSearchRefresh DrugSearchRefreshUsecase) drugUsecaseImpl {
 	return drugUsecaseImpl{
 		transaction:                  transaction,
 		drugRepository:               drugRepository,
@@ -52,6 +59,7 @@ func NewDrugUsecaseImpl(transaction repository.Transaction, drugRepository repos
 		drugFormRepository:           drugFormRepository,
 		categoryRepository:           categoryRepository,
 		pharmacyRepository:           pharmacyRepository,
+		drugSearchRefresh:            drugSearchRefresh,
 	}
 }
 
@@ -89,22 +97,93 @@ func (u *drugUsecaseImpl) GetPharmacyDrugByDrugId(ctx context.Context, drugId in
 	return &pharmacyDrugListResponse, err
 }
 
-func (u *drugUsecaseImpl) GetAllDrugsForListing(ctx context.Context, query *util.ValidatedGetProductQuery) ([]entity.DrugListing, *entity.PageInfo, error) {
+func (u *drugUsecaseImpl) GetAllDrugsForListing(ctx context.Context, query *util.ValidatedGetProductQuery) (*dto.DrugListingResponse, error) {
 	if query.Category != nil {
 		category, err := u.categoryRepository.FindOneCategoryById(ctx, *query.Category)
 		if err != nil {
-			return nil, nil, apperror.InternalServerError(err)
+			return nil, apperror.InternalServerError(err)
 		}
 		if category == nil {
-			return nil, nil, apperror.CategoryNotFoundError()
+			return nil, apperror.CategoryNotFoundError()
 		}
 	}
 
 	drugList, pageInfo, err := u.pharmacyDrugRepository.GetProductListing(ctx, query)
 	if err != nil {
-		return nil, nil, apperror.InternalServerError(err)
+		return nil, apperror.InternalServerError(err)
+	}
+
+	res := dto.DrugListingResponse{Drugs: drugList, PageInfo: *pageInfo}
+
+	if query.Search != nil {
+		res.DidYouMean, err = u.getDidYouMean(ctx, *query.Search)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &res, nil
+}
+
+func (u *drugUsecaseImpl) SuggestDrugs(ctx context.Context, query dto.DrugSuggestQuery) (*dto.DrugSuggestionsResponse, error) {
+	limit := drugSuggestionDefaultLimit
+	if query.Limit != "" {
+		limitInt, err := strconv.Atoi(query.Limit)
+		if err != nil || limitInt < 1 {
+			return nil, apperror.InvalidLimitError()
+		}
+		limit = limitInt
+	}
+	if limit > drugSuggestionMaxLimit {
+		limit = drugSuggestionMaxLimit
+	}
+
+	search := strings.TrimSpace(query.Query)
+
+	drugSuggestions, err := u.drugRepository.SuggestDrugs(ctx, search, limit)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	didYouMean, err := u.getDidYouMean(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+
+	res := dto.ConvertToDrugSuggestionsResponse(drugSuggestions, didYouMean)
+
+	return &res, nil
+}
+
+// Every word of the search is swapped for the closest word known from the
+// drug catalogue; nothing is suggested when all words are already known.
+func (u *drugUsecaseImpl) getDidYouMean(ctx context.Context, search string) (*string, error) {
+	words := strings.Fields(strings.ToLower(search))
+	if len(words) == 0 {
+		return nil, nil
+	}
+
+	drugSearchCorrections, err := u.drugRepository.FindSearchCorrections(ctx, words)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	isCorrected := false
+	correctedWords := []string{}
+	for _, drugSearchCorrection := range drugSearchCorrections {
+		if drugSearchCorrection.Term != drugSearchCorrection.Word {
+			isCorrected = true
+		}
+		correctedWords = append(correctedWords, drugSearchCorrection.Term)
+	}
+
+	if !isCorrected {
+		return nil, nil
 	}
-	return drugList, pageInfo, nil
+
+	didYouMean := strings.Join(correctedWords, " ")
+
+	return &didYouMean, nil
 }
 
 func (u *drugUsecaseImpl) UpdateOneDrug(ctx context.Context, drugId int64, drugRequest dto.UpdateDrugRequest, file multipart.File, fileHeader *multipart.FileHeader) error {
@@ -153,6 +232,8 @@ func (u *drugUsecaseImpl) UpdateOneDrug(ctx context.Context, drugId int64, drugR
 		return apperror.InternalServerError(err)
 	}
 
+	u.drugSearchRefresh.RequestRefresh()
+
 	return nil
 }
 
@@ -232,6 +313,8 @@ func (u *drugUsecaseImpl) CreateOneDrug(ctx context.Context, createDrugRequest d
 		return apperror.InternalServerError(err)
 	}
 
+	u.drugSearchRefresh.RequestRefresh()
+
 	return nil
 }
 
@@ -247,6 +330,9 @@ func (u *drugUsecaseImpl) DeleteOneDrug(ctx context.Context, drugId int64) error
 	if err = u.drugRepository.DeleteOneDrug(ctx, drugId); err != nil {
 		return apperror.InternalServerError(err)
 	}
+
+	u.drugSearchRefresh.RequestRefresh()
+
 	return nil
 }
 
@@ -329,13 +415,23 @@ func (u *drugUsecaseImpl) GetDrugsByPharmacyId(ctx context.Context, pharmacyId s
 	return &getDrugsByPharmacyResponse, nil
 }
 
-func (u *drugUsecaseImpl) UpdateDrugsByPharmacyDrugId(ctx context.Context, pharmacyDrugId int64, stock int, price decimal.Decimal) error {
+func (u *drugUsecaseImpl) UpdateDrugsByPharmacyDrugId(ctx context.Context, accountId, pharmacyDrugId int64, stock int, price decimal.Decimal, batch *dto.PharmacyDrugBatchRequest) error {
 	if stock < 0 {
 		return apperror.BadRequestError(errors.New("stock cannot be less than 0"))
 	}
 	if price.Cmp(decimal.NewFromInt(500)) < 0 {
 		return apperror.BadRequestError(errors.New("price cannot be less than 500"))
 	}
+
+	var receivedBatch *entity.PharmacyDrugBatch
+	var err error
+	if batch != nil {
+		receivedBatch, err = newReceivedBatch(pharmacyDrugId, *batch)
+		if err != nil {
+			return err
+		}
+	}
+
 	tx, err := u.transaction.BeginTx(ctx)
 	if err != nil {
 		return apperror.InternalServerError(err)
@@ -343,6 +439,7 @@ func (u *drugUsecaseImpl) UpdateDrugsByPharmacyDrugId(ctx context.Context, pharm
 
 	pharmacyDrugRepo := tx.PharmacyDrugRepo()
 	stockChangeRepo := tx.StockChangeRepo()
+	pharmacyDrugBatchRepo := tx.PharmacyDrugBatchRepository()
 	defer func() {
 		if err != nil {
 			tx.Rollback()
@@ -358,12 +455,33 @@ func (u *drugUsecaseImpl) UpdateDrugsByPharmacyDrugId(ctx context.Context, pharm
 		err = apperror.DrugNotFoundError()
 		return err
 	}
+
+	amount := stock - pharmacyDrug.Stock
+	if amount < 0 {
+		_, err = deductBatches(ctx, pharmacyDrugBatchRepo, pharmacyDrugId, -amount, entity.PharmacyDrugBatchAllocation{})
+		if err != nil {
+			return err
+		}
+	}
+	if amount > 0 {
+		if receivedBatch == nil {
+			err = apperror.BatchRequiredError()
+			return err
+		}
+
+		receivedBatch.Stock = amount
+		err = restockBatch(ctx, pharmacyDrugBatchRepo, *receivedBatch)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = pharmacyDrugRepo.UpdatePharmacyDrugStockPrice(ctx, pharmacyDrugId, stock, price)
 	if err != nil {
 		return apperror.InternalServerError(err)
 	}
-	stockChange := entity.StockChange{PharmacyDrugId: pharmacyDrug.Id, FinalStock: stock, Amount: stock - pharmacyDrug.Stock,
-		Description: "updated by manager",}
+	stockChange := entity.StockChange{PharmacyDrugId: pharmacyDrug.Id, FinalStock: stock, Amount: amount,
+		Description: "updated by manager", ChangeType: appconstant.StockChangeTypeManualAdjustment, ActorAccountId: &accountId}
 	err = stockChangeRepo.PostStockChangesFromUpdate(ctx, []entity.StockChange{stockChange})
 	if err != nil {
 		return apperror.InternalServerError(err)
@@ -379,7 +497,7 @@ func (u *drugUsecaseImpl) DeleteDrugsByPharmacyDrugId(ctx context.Context, pharm
 	return nil
 }
 
-func (u *drugUsecaseImpl) AddDrugsByPharmacyDrugId(ctx context.Context, pharmacyId int64, drugId int64, stock int, price decimal.Decimal) error {
+func (u *drugUsecaseImpl) AddDrugsByPharmacyDrugId(ctx context.Context, pharmacyId int64, drugId int64, stock int, price decimal.Decimal, batch *dto.PharmacyDrugBatchRequest) error {
 	if stock < 0 {
 		return apperror.BadRequestError(errors.New("stock cannot be less than 0"))
 	}
@@ -405,10 +523,43 @@ func (u *drugUsecaseImpl) AddDrugsByPharmacyDrugId(ctx context.Context, pharmacy
 		return apperror.BadRequestError(errors.New("drug id doesn't exist"))
 	}
 
-	err = u.pharmacyDrugRepository.AddPharmacyDrug(ctx, pharmacyId, drugId, stock, price)
+	var receivedBatch *entity.PharmacyDrugBatch
+	if stock > 0 {
+		if batch == nil {
+			return apperror.BatchRequiredError()
+		}
+
+		receivedBatch, err = newReceivedBatch(0, *batch)
+		if err != nil {
+			return err
+		}
+	}
+
+	tx, err := u.transaction.BeginTx(ctx)
 	if err != nil {
 		return apperror.InternalServerError(err)
 	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+
+		tx.Commit()
+	}()
+
+	pharmacyDrugId, err := tx.PharmacyDrugRepo().AddPharmacyDrug(ctx, pharmacyId, drugId, stock, price)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	if receivedBatch != nil {
+		receivedBatch.PharmacyDrugId = pharmacyDrugId
+		receivedBatch.Stock = stock
+		err = restockBatch(ctx, tx.PharmacyDrugBatchRepository(), *receivedBatch)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -429,7 +580,7 @@ func (u *drugUsecaseImpl) GetPossibleStockMutation(ctx context.Context, pharmacy
 	return dto.ConvertToMutationPharmacyDrugs(pharmacyDrugs), nil
 }
 
-func (u *drugUsecaseImpl) PostStockMutation(ctx context.Context, req dto.PostStockMutationRequest) error {
+func (u *drugUsecaseImpl) PostStockMutation(ctx context.Context, accountId int64, req dto.PostStockMutationRequest) error {
 	if req.RecipientPharmacyDrugId == req.SenderPharmacyDrugId {
 		return apperror.DuplicatePharmacyDrugIdError()
 	}
@@ -471,27 +622,16 @@ func (u *drugUsecaseImpl) PostStockMutation(ctx context.Context, req dto.PostSto
 		return err
 	}
 
-	err = pharmacyDrugRepo.UpdatePharmacyDrugStockPrice(ctx, req.RecipientPharmacyDrugId, recipientDrug.Stock+req.Quantity, recipientDrug.Price)
-	if err != nil {
-		return apperror.InternalServerError(err)
-	}
-	err = pharmacyDrugRepo.UpdatePharmacyDrugStockPrice(ctx, req.SenderPharmacyDrugId, senderDrug.Stock-req.Quantity, senderDrug.Price)
-	if err != nil {
-		return apperror.InternalServerError(err)
-	}
-
 	stockMutation := entity.PossibleStockMutation{DrugId: recipientDrug.DrugId, OriginalPharmacy: recipientDrug.PharmacyId,
 		AlternativePharmacy: senderDrug.PharmacyId, AlternativeStock: req.Quantity}
-	err = stockMutationRepo.PostStockMutations(ctx, []entity.PossibleStockMutation{stockMutation})
+	err = stockMutationRepo.PostStockMutations(ctx, []entity.PossibleStockMutation{stockMutation}, nil)
 	if err != nil {
 		return apperror.InternalServerError(err)
 	}
 
-	recipientStockChange := entity.StockChange{PharmacyDrugId: req.RecipientPharmacyDrugId, FinalStock: recipientDrug.Stock + req.Quantity,
-		Amount: req.Quantity}
-	senderStockChange := entity.StockChange{PharmacyDrugId: req.SenderPharmacyDrugId, FinalStock: senderDrug.Stock - req.Quantity,
-		Amount: req.Quantity * -1}
-	err = stockChangeRepo.PostStockChangesFromMutation(ctx, []entity.StockChange{recipientStockChange, senderStockChange})
+	recipientStockChange := entity.StockChange{PharmacyDrugId: req.RecipientPharmacyDrugId, FinalStock: recipientDrug.Stock,
+		Amount: 0, Description: appconstant.StockMutationRequestedDescription, ActorAccountId: &accountId}
+	err = stockChangeRepo.PostStockChangesFromMutation(ctx, []entity.StockChange{recipientStockChange})
 	if err != nil {
 		return apperror.InternalServerError(err)
 	}
@@ -0,0 +1,472 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	"github.com/shopspring/decimal"
+	"github.com/sidiqPratomo/max-health-backend/appconstant"
+	"github.com/sidiqPratomo/max-health-backend/apperror"
+	"github.com/sidiqPratomo/max-health-backend/entity"
+	"github.com/sidiqPratomo/max-health-backend/repository"
+	"github.com/sidiqPratomo/max-health-backend/util"
+)
+
+const (
+	fulfilmentCandidatesPerDrug = 5
+	fulfilmentMaxPharmacies     = 8
+	fulfilmentMaxAlternatives   = 5
+	fulfilmentShortlistSize     = 2 * fulfilmentMaxAlternatives
+)
+
+type FulfilmentOptimizer interface {
+	PlanPrescriptionFulfilment(ctx context.Context, userAddressId int64, prescriptionDrugs []entity.PrescriptionDrug, strategy string) ([]entity.FulfilmentPlan, error)
+}
+
+type fulfilmentOptimizerImpl struct {
+	pharmacyDrugRepository repository.PharmacyDrugRepository
+	pharmacyRepository     repository.PharmacyRepository
+	shippingRateProvider   util.ShippingRateProvider
+}
+
+func NewFulfilmentOptimizerImpl(pharmacyDrugRepository repository.PharmacyDrugRepository, pharmacyRepository repository.PharmacyRepository, shippingRateProvider util.ShippingRateProvider) fulfilmentOptimizerImpl {
+	return fulfilmentOptimizerImpl{
+		pharmacyDrugRepository: pharmacyDrugRepository,
+		pharmacyRepository:     pharmacyRepository,
+		shippingRateProvider:   shippingRateProvider,
+	}
+}
+
+type fulfilmentSearch struct {
+	optimizer                 *fulfilmentOptimizerImpl
+	userAddressId             int64
+	strategy                  string
+	drugIds                   []int64
+	quantities                map[int64]int
+	pharmacies                []entity.Pharmacy
+	offers                    map[int64]map[int64]entity.DrugQuantity
+	couriersByPharmacy        map[int64][]entity.AvailableCourier
+	deliveryOptionsByShipment map[string][]entity.AvailableCourier
+	estimatedOptions          map[int64][]entity.AvailableCourier
+}
+
+type deliveryOptionsFunc func(ctx context.Context, pharmacyId int64, weight decimal.Decimal) ([]entity.AvailableCourier, error)
+
+// Every combination of nearby pharmacies that covers the prescription is
+// priced, with each drug taken from the cheapest pharmacy in the combination.
+// Combinations with a pharmacy that ends up supplying nothing are skipped,
+// because the smaller combination without it is already considered.
+//
+// Shipping rates are quoted once per pharmacy before the search, for the
+// heaviest parcel it could send, so ranking the combinations makes no courier
+// calls. Only the shortlisted combinations are quoted for their real weight.
+//
+// When covering the prescription already takes more than
+// fulfilmentMaxPharmacies pharmacies, the combinations are not searched and
+// every drug is taken from its nearest pharmacy instead.
+func (o *fulfilmentOptimizerImpl) PlanPrescriptionFulfilment(ctx context.Context, userAddressId int64, prescriptionDrugs []entity.PrescriptionDrug, strategy string) ([]entity.FulfilmentPlan, error) {
+	search := fulfilmentSearch{
+		optimizer:                 o,
+		userAddressId:             userAddressId,
+		strategy:                  strategy,
+		quantities:                map[int64]int{},
+		offers:                    map[int64]map[int64]entity.DrugQuantity{},
+		couriersByPharmacy:        map[int64][]entity.AvailableCourier{},
+		deliveryOptionsByShipment: map[string][]entity.AvailableCourier{},
+		estimatedOptions:          map[int64][]entity.AvailableCourier{},
+	}
+
+	for _, prescriptionDrug := range prescriptionDrugs {
+		if _, ok := search.quantities[prescriptionDrug.Drug.Id]; !ok {
+			search.drugIds = append(search.drugIds, prescriptionDrug.Drug.Id)
+		}
+		search.quantities[prescriptionDrug.Drug.Id] += prescriptionDrug.RemainingQuantity
+	}
+
+	if len(search.drugIds) == 0 {
+		return []entity.FulfilmentPlan{}, nil
+	}
+
+	candidates, err := o.pharmacyDrugRepository.GetAllNearestAvailablePharmacyDrugsByDrugIds(ctx, search.drugIds, userAddressId, fulfilmentCandidatesPerDrug)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	isWithinLimit := search.selectPharmacies(candidates)
+
+	for _, drugId := range search.drugIds {
+		if len(search.offersForDrug(drugId)) == 0 {
+			return nil, apperror.NoDrugNearby()
+		}
+	}
+
+	if !isWithinLimit {
+		plan, err := search.planFor(ctx, search.pharmacies, search.deliveryOptions)
+		if err != nil {
+			return nil, err
+		}
+		if plan == nil {
+			return nil, apperror.NoDrugNearby()
+		}
+
+		return []entity.FulfilmentPlan{*plan}, nil
+	}
+
+	err = search.estimateDeliveryOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	estimatedPlans := []fulfilmentEstimate{}
+	for mask := 1; mask < 1<<len(search.pharmacies); mask++ {
+		plan, err := search.planFor(ctx, search.pharmaciesIn(mask), search.estimatedDeliveryOptions)
+		if err != nil {
+			return nil, err
+		}
+		if plan != nil {
+			estimatedPlans = append(estimatedPlans, fulfilmentEstimate{mask: mask, plan: *plan})
+		}
+	}
+
+	sort.SliceStable(estimatedPlans, func(i, j int) bool {
+		return fulfilmentPlanLess(strategy, estimatedPlans[i].plan, estimatedPlans[j].plan)
+	})
+
+	if len(estimatedPlans) > fulfilmentShortlistSize {
+		estimatedPlans = estimatedPlans[:fulfilmentShortlistSize]
+	}
+
+	plans := []entity.FulfilmentPlan{}
+	for _, estimatedPlan := range estimatedPlans {
+		plan, err := search.planFor(ctx, search.pharmaciesIn(estimatedPlan.mask), search.deliveryOptions)
+		if err != nil {
+			return nil, err
+		}
+		if plan != nil {
+			plans = append(plans, *plan)
+		}
+	}
+
+	if len(plans) == 0 {
+		return nil, apperror.NoDrugNearby()
+	}
+
+	sort.SliceStable(plans, func(i, j int) bool {
+		return fulfilmentPlanLess(strategy, plans[i], plans[j])
+	})
+
+	if len(plans) > fulfilmentMaxAlternatives {
+		plans = plans[:fulfilmentMaxAlternatives]
+	}
+
+	return plans, nil
+}
+
+type fulfilmentEstimate struct {
+	mask int
+	plan entity.FulfilmentPlan
+}
+
+// Shipping rates never go down as the parcel gets heavier, so quoting every
+// pharmacy for all the drugs it offers gives an upper bound for any plan.
+func (s *fulfilmentSearch) estimateDeliveryOptions(ctx context.Context) error {
+	for _, pharmacy := range s.pharmacies {
+		weight := decimal.Zero
+		for _, drugId := range s.drugIds {
+			offer, ok := s.offers[pharmacy.Id][drugId]
+			if !ok {
+				continue
+			}
+			quantity := decimal.NewFromInt(int64(s.quantities[drugId]))
+			weight = weight.Add(quantity.Mul(offer.PharmacyDrug.Drug.Weight))
+		}
+
+		deliveryOptions, err := s.deliveryOptions(ctx, pharmacy.Id, weight)
+		if err != nil {
+			return err
+		}
+		s.estimatedOptions[pharmacy.Id] = deliveryOptions
+	}
+
+	return nil
+}
+
+func (s *fulfilmentSearch) estimatedDeliveryOptions(ctx context.Context, pharmacyId int64, weight decimal.Decimal) ([]entity.AvailableCourier, error) {
+	return s.estimatedOptions[pharmacyId], nil
+}
+
+// The nearest pharmacy for every drug is always kept so the search can still
+// cover the prescription, and the remaining slots go to the closest others.
+// It reports false when the nearest pharmacies alone exceed
+// fulfilmentMaxPharmacies, in which case only they are kept and every drug is
+// offered by its nearest pharmacy only.
+func (s *fulfilmentSearch) selectPharmacies(candidates []entity.FulfilmentCandidate) bool {
+	pharmaciesById := map[int64]entity.Pharmacy{}
+	nearestByDrug := map[int64]int64{}
+
+	for _, candidate := range candidates {
+		pharmacyId := candidate.Pharmacy.Id
+		drugId := candidate.DrugQuantity.PharmacyDrug.Drug.Id
+
+		pharmaciesById[pharmacyId] = candidate.Pharmacy
+		if s.offers[pharmacyId] == nil {
+			s.offers[pharmacyId] = map[int64]entity.DrugQuantity{}
+		}
+		s.offers[pharmacyId][drugId] = candidate.DrugQuantity
+
+		nearest, ok := nearestByDrug[drugId]
+		if !ok || candidate.Pharmacy.Distance < pharmaciesById[nearest].Distance {
+			nearestByDrug[drugId] = pharmacyId
+		}
+	}
+
+	allPharmacies := []entity.Pharmacy{}
+	for _, pharmacy := range pharmaciesById {
+		allPharmacies = append(allPharmacies, pharmacy)
+	}
+	sort.Slice(allPharmacies, func(i, j int) bool {
+		if allPharmacies[i].Distance == allPharmacies[j].Distance {
+			return allPharmacies[i].Id < allPharmacies[j].Id
+		}
+		return allPharmacies[i].Distance < allPharmacies[j].Distance
+	})
+
+	selected := map[int64]bool{}
+	for _, pharmacyId := range nearestByDrug {
+		selected[pharmacyId] = true
+	}
+
+	isWithinLimit := len(selected) <= fulfilmentMaxPharmacies
+	if !isWithinLimit {
+		for drugId, nearest := range nearestByDrug {
+			for pharmacyId, offers := range s.offers {
+				if pharmacyId != nearest {
+					delete(offers, drugId)
+				}
+			}
+		}
+	}
+
+	for _, pharmacy := range allPharmacies {
+		if len(selected) >= fulfilmentMaxPharmacies {
+			break
+		}
+		selected[pharmacy.Id] = true
+	}
+
+	for _, pharmacy := range allPharmacies {
+		if selected[pharmacy.Id] {
+			s.pharmacies = append(s.pharmacies, pharmacy)
+		}
+	}
+
+	return isWithinLimit
+}
+
+func (s *fulfilmentSearch) pharmaciesIn(mask int) []entity.Pharmacy {
+	pharmacies := []entity.Pharmacy{}
+	for i, pharmacy := range s.pharmacies {
+		if mask&(1<<i) != 0 {
+			pharmacies = append(pharmacies, pharmacy)
+		}
+	}
+
+	return pharmacies
+}
+
+func (s *fulfilmentSearch) offersForDrug(drugId int64) []entity.Pharmacy {
+	pharmacies := []entity.Pharmacy{}
+	for _, pharmacy := range s.pharmacies {
+		if _, ok := s.offers[pharmacy.Id][drugId]; ok {
+			pharmacies = append(pharmacies, pharmacy)
+		}
+	}
+
+	return pharmacies
+}
+
+func (s *fulfilmentSearch) planFor(ctx context.Context, pharmacies []entity.Pharmacy, deliveryOptionsFor deliveryOptionsFunc) (*entity.FulfilmentPlan, error) {
+	itemsByPharmacy := map[int64]*entity.PrepareForCheckoutItem{}
+
+	for _, drugId := range s.drugIds {
+		var chosen *entity.Pharmacy
+		var chosenOffer entity.DrugQuantity
+
+		for i, pharmacy := range pharmacies {
+			offer, ok := s.offers[pharmacy.Id][drugId]
+			if !ok {
+				continue
+			}
+
+			if chosen == nil || offer.PharmacyDrug.Price.LessThan(chosenOffer.PharmacyDrug.Price) {
+				chosen = &pharmacies[i]
+				chosenOffer = offer
+			}
+		}
+
+		if chosen == nil {
+			return nil, nil
+		}
+
+		item, ok := itemsByPharmacy[chosen.Id]
+		if !ok {
+			item = &entity.PrepareForCheckoutItem{
+				PharmacyId:      chosen.Id,
+				PharmacyName:    chosen.Name,
+				PharmacyAddress: chosen.Address,
+				Distance:        chosen.Distance,
+				Subtotal:        decimal.Zero,
+				Weight:          decimal.Zero,
+			}
+			itemsByPharmacy[chosen.Id] = item
+		}
+
+		quantity := decimal.NewFromInt(int64(s.quantities[drugId]))
+		chosenOffer.Quantity = s.quantities[drugId]
+		item.DrugQuantities = append(item.DrugQuantities, chosenOffer)
+		item.Subtotal = item.Subtotal.Add(quantity.Mul(chosenOffer.PharmacyDrug.Price))
+		item.Weight = item.Weight.Add(quantity.Mul(chosenOffer.PharmacyDrug.Drug.Weight))
+	}
+
+	if len(itemsByPharmacy) != len(pharmacies) {
+		return nil, nil
+	}
+
+	plan := entity.FulfilmentPlan{
+		Subtotal:     decimal.Zero,
+		ShippingCost: decimal.Zero,
+	}
+	estimatedHours := 0
+	isEtdKnown := true
+
+	for _, pharmacy := range pharmacies {
+		item := itemsByPharmacy[pharmacy.Id]
+
+		deliveryOptions, err := deliveryOptionsFor(ctx, pharmacy.Id, item.Weight)
+		if err != nil {
+			return nil, err
+		}
+
+		pharmacyCourierId, courierOption, ok := pickCourierOption(s.strategy, deliveryOptions)
+		if !ok {
+			return nil, nil
+		}
+
+		item.DeliveryOptions = deliveryOptions
+		item.PharmacyCourierId = pharmacyCourierId
+		item.ShippingCost = decimal.NewFromFloat(courierOption.Price)
+		item.Etd = courierOption.Etd
+
+		hours, ok := util.CourierEtdHours(courierOption.Etd)
+		if !ok {
+			isEtdKnown = false
+		}
+		if hours > estimatedHours {
+			estimatedHours = hours
+		}
+
+		plan.Items = append(plan.Items, *item)
+		plan.Subtotal = plan.Subtotal.Add(item.Subtotal)
+		plan.ShippingCost = plan.ShippingCost.Add(item.ShippingCost)
+	}
+
+	plan.TotalCost = plan.Subtotal.Add(plan.ShippingCost)
+	if isEtdKnown {
+		plan.EstimatedHours = &estimatedHours
+	}
+
+	return &plan, nil
+}
+
+// Courier rows only depend on the pharmacy, while the quoted rates also depend
+// on the parcel weight, so both are cached for the duration of one search.
+func (s *fulfilmentSearch) deliveryOptions(ctx context.Context, pharmacyId int64, weight decimal.Decimal) ([]entity.AvailableCourier, error) {
+	shipmentKey := strconv.FormatInt(pharmacyId, 10) + ":" + weight.String()
+	if deliveryOptions, ok := s.deliveryOptionsByShipment[shipmentKey]; ok {
+		return deliveryOptions, nil
+	}
+
+	couriers, ok := s.couriersByPharmacy[pharmacyId]
+	if !ok {
+		var err error
+		couriers, err = s.optimizer.pharmacyRepository.GetAllCourierOptionsByPharmacyId(ctx, s.userAddressId, pharmacyId, 0)
+		if err != nil {
+			return nil, apperror.InternalServerError(err)
+		}
+		s.couriersByPharmacy[pharmacyId] = couriers
+	}
+
+	deliveryOptions := []entity.AvailableCourier{}
+	for _, courier := range couriers {
+		if courier.ShippingRateRequest != nil {
+			shippingRateRequest := *courier.ShippingRateRequest
+			shippingRateRequest.Weight = weight.IntPart()
+			courier.ShippingRateRequest = &shippingRateRequest
+		}
+		deliveryOptions = append(deliveryOptions, courier)
+	}
+
+	if err := fillCourierOptions(ctx, s.optimizer.shippingRateProvider, deliveryOptions); err != nil {
+		return nil, err
+	}
+
+	s.deliveryOptionsByShipment[shipmentKey] = deliveryOptions
+
+	return deliveryOptions, nil
+}
+
+func pickCourierOption(strategy string, deliveryOptions []entity.AvailableCourier) (int64, entity.CourierOption, bool) {
+	var pharmacyCourierId int64
+	var picked entity.CourierOption
+	isPicked := false
+
+	for _, courier := range deliveryOptions {
+		for _, courierOption := range courier.CourierOptions {
+			if !isPicked || courierOptionLess(strategy, courierOption, picked) {
+				pharmacyCourierId = courier.PharmacyCourierId
+				picked = courierOption
+				isPicked = true
+			}
+		}
+	}
+
+	return pharmacyCourierId, picked, isPicked
+}
+
+func courierOptionLess(strategy string, a, b entity.CourierOption) bool {
+	if strategy == appconstant.FulfilmentStrategyFastest {
+		aHours, aKnown := util.CourierEtdHours(a.Etd)
+		bHours, bKnown := util.CourierEtdHours(b.Etd)
+		if aKnown != bKnown {
+			return aKnown
+		}
+		if aHours != bHours {
+			return aHours < bHours
+		}
+	}
+
+	return a.Price < b.Price
+}
+
+func fulfilmentPlanLess(strategy string, a, b entity.FulfilmentPlan) bool {
+	switch strategy {
+	case appconstant.FulfilmentStrategyFewestShipments:
+		if len(a.Items) != len(b.Items) {
+			return len(a.Items) < len(b.Items)
+		}
+	case appconstant.FulfilmentStrategyFastest:
+		if (a.EstimatedHours == nil) != (b.EstimatedHours == nil) {
+			return a.EstimatedHours != nil
+		}
+		if a.EstimatedHours != nil && *a.EstimatedHours != *b.EstimatedHours {
+			return *a.EstimatedHours < *b.EstimatedHours
+		}
+	}
+
+	if !a.TotalCost.Equal(b.TotalCost) {
+		return a.TotalCost.LessThan(b.TotalCost)
+	}
+
+	return len(a.Items) < len(b.Items)
+}
@@ -0,0 +1,275 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/sidiqPratomo/max-health-backend/appconstant"
+	"github.com/sidiqPratomo/max-health-backend/apperror"
+	"github.com/sidiqPratomo/max-health-backend/entity"
+	"github.com/sidiqPratomo/max-health-backend/repository"
+)
+
+type fakeFulfilmentPharmacyDrugRepository struct {
+	repository.PharmacyDrugRepository
+	candidates []entity.FulfilmentCandidate
+}
+
+func (r *fakeFulfilmentPharmacyDrugRepository) GetAllNearestAvailablePharmacyDrugsByDrugIds(ctx context.Context, drugIds []int64, userAddressId int64, limitPerDrug int) ([]entity.FulfilmentCandidate, error) {
+	return r.candidates, nil
+}
+
+type fakeFulfilmentPharmacyRepository struct {
+	repository.PharmacyRepository
+}
+
+func (r *fakeFulfilmentPharmacyRepository) GetAllCourierOptionsByPharmacyId(ctx context.Context, userAddressId, pharmacyId int64, weight float64) ([]entity.AvailableCourier, error) {
+	return []entity.AvailableCourier{{
+		PharmacyCourierId:   pharmacyId * 10,
+		CourierName:         "jne",
+		ShippingRateRequest: &entity.ShippingRateRequest{Origin: pharmacyId, Destination: userAddressId, Courier: "jne"},
+	}}, nil
+}
+
+// fakeShippingRateProvider charges a flat fee per origin plus one rupiah per
+// gram, and records every quote it is asked for.
+type fakeShippingRateProvider struct {
+	flatFees map[int64]float64
+	etds     map[int64]string
+	requests []entity.ShippingRateRequest
+}
+
+func (p *fakeShippingRateProvider) GetShippingRates(ctx context.Context, shippingRateRequest entity.ShippingRateRequest) ([]entity.CourierOption, error) {
+	p.requests = append(p.requests, shippingRateRequest)
+
+	etd := p.etds[shippingRateRequest.Origin]
+	if etd == "" {
+		etd = "2-3 days"
+	}
+
+	return []entity.CourierOption{{Price: p.flatFees[shippingRateRequest.Origin] + float64(shippingRateRequest.Weight), Etd: etd}}, nil
+}
+
+func newFulfilmentCandidate(pharmacyId int64, distance float64, drugId int64, price int64) entity.FulfilmentCandidate {
+	return entity.FulfilmentCandidate{
+		Pharmacy: entity.Pharmacy{Id: pharmacyId, Distance: distance},
+		DrugQuantity: entity.DrugQuantity{PharmacyDrug: entity.DetailPharmacyDrug{
+			Id:    pharmacyId*100 + drugId,
+			Drug:  entity.Drug{Id: drugId, Weight: decimal.NewFromInt(100)},
+			Price: decimal.NewFromInt(price),
+		}},
+	}
+}
+
+func newTestFulfilmentOptimizer(candidates []entity.FulfilmentCandidate, shippingRateProvider *fakeShippingRateProvider) fulfilmentOptimizerImpl {
+	return NewFulfilmentOptimizerImpl(
+		&fakeFulfilmentPharmacyDrugRepository{candidates: candidates},
+		&fakeFulfilmentPharmacyRepository{},
+		shippingRateProvider,
+	)
+}
+
+func testPrescriptionDrugs(drugIds ...int64) []entity.PrescriptionDrug {
+	prescriptionDrugs := []entity.PrescriptionDrug{}
+	for _, drugId := range drugIds {
+		prescriptionDrugs = append(prescriptionDrugs, entity.PrescriptionDrug{Drug: entity.Drug{Id: drugId}, Quantity: 1, RemainingQuantity: 1})
+	}
+	return prescriptionDrugs
+}
+
+func fulfilmentPlanPharmacyIds(plan entity.FulfilmentPlan) []int64 {
+	pharmacyIds := []int64{}
+	for _, item := range plan.Items {
+		pharmacyIds = append(pharmacyIds, item.PharmacyId)
+	}
+	return pharmacyIds
+}
+
+func TestPlanPrescriptionFulfilment(t *testing.T) {
+	// Pharmacy 1 has both drugs but sells drug 2 expensively, pharmacy 2
+	// only has drug 2 and sells it cheaply.
+	candidates := []entity.FulfilmentCandidate{
+		newFulfilmentCandidate(1, 1, 1, 1000),
+		newFulfilmentCandidate(1, 1, 2, 20000),
+		newFulfilmentCandidate(2, 2, 2, 1000),
+	}
+
+	tests := []struct {
+		name          string
+		strategy      string
+		etds          map[int64]string
+		wantPharmacy  [][]int64
+		wantTotalCost []int64
+	}{
+		{
+			name:          "cheapest splits the order",
+			strategy:      appconstant.FulfilmentStrategyCheapest,
+			wantPharmacy:  [][]int64{{1, 2}, {1}},
+			wantTotalCost: []int64{2000 + 9100 + 9100, 21000 + 9200},
+		},
+		{
+			name:          "fewest shipments keeps one pharmacy",
+			strategy:      appconstant.FulfilmentStrategyFewestShipments,
+			wantPharmacy:  [][]int64{{1}, {1, 2}},
+			wantTotalCost: []int64{21000 + 9200, 2000 + 9100 + 9100},
+		},
+		{
+			name:          "fastest waits for the slowest parcel",
+			strategy:      appconstant.FulfilmentStrategyFastest,
+			etds:          map[int64]string{1: "1 day", 2: "5 days"},
+			wantPharmacy:  [][]int64{{1}, {1, 2}},
+			wantTotalCost: []int64{21000 + 9200, 2000 + 9100 + 9100},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shippingRateProvider := &fakeShippingRateProvider{flatFees: map[int64]float64{1: 9000, 2: 9000}, etds: tt.etds}
+			optimizer := newTestFulfilmentOptimizer(candidates, shippingRateProvider)
+
+			plans, err := optimizer.PlanPrescriptionFulfilment(context.Background(), 7, testPrescriptionDrugs(1, 2), tt.strategy)
+			if err != nil {
+				t.Fatalf("PlanPrescriptionFulfilment() error = %v", err)
+			}
+			if len(plans) != len(tt.wantPharmacy) {
+				t.Fatalf("PlanPrescriptionFulfilment() returned %d plans, want %d", len(plans), len(tt.wantPharmacy))
+			}
+
+			for i, plan := range plans {
+				pharmacyIds := fulfilmentPlanPharmacyIds(plan)
+				if len(pharmacyIds) != len(tt.wantPharmacy[i]) {
+					t.Fatalf("plan %d pharmacies = %v, want %v", i, pharmacyIds, tt.wantPharmacy[i])
+				}
+				for j := range pharmacyIds {
+					if pharmacyIds[j] != tt.wantPharmacy[i][j] {
+						t.Fatalf("plan %d pharmacies = %v, want %v", i, pharmacyIds, tt.wantPharmacy[i])
+					}
+				}
+
+				// Shipping is quoted for the parcel each pharmacy really sends,
+				// not the heavier estimate used while ranking.
+				if !plan.TotalCost.Equal(decimal.NewFromInt(tt.wantTotalCost[i])) {
+					t.Errorf("plan %d total cost = %s, want %d", i, plan.TotalCost, tt.wantTotalCost[i])
+				}
+				if !plan.TotalCost.Equal(plan.Subtotal.Add(plan.ShippingCost)) {
+					t.Errorf("plan %d total cost = %s, want subtotal %s + shipping %s", i, plan.TotalCost, plan.Subtotal, plan.ShippingCost)
+				}
+			}
+		})
+	}
+}
+
+func TestPlanPrescriptionFulfilmentNoDrugNearby(t *testing.T) {
+	candidates := []entity.FulfilmentCandidate{newFulfilmentCandidate(1, 1, 1, 1000)}
+	optimizer := newTestFulfilmentOptimizer(candidates, &fakeShippingRateProvider{})
+
+	_, err := optimizer.PlanPrescriptionFulfilment(context.Background(), 7, testPrescriptionDrugs(1, 2), appconstant.FulfilmentStrategyCheapest)
+
+	appErr, ok := err.(*apperror.AppError)
+	if !ok || appErr.Code == http.StatusInternalServerError {
+		t.Fatalf("PlanPrescriptionFulfilment() error = %v, want a no drug nearby error", err)
+	}
+	if appErr.Message != apperror.NoDrugNearby().Message {
+		t.Errorf("PlanPrescriptionFulfilment() error = %q, want %q", appErr.Message, apperror.NoDrugNearby().Message)
+	}
+}
+
+func TestPlanPrescriptionFulfilmentBoundsCourierCalls(t *testing.T) {
+	drugIds := []int64{1, 2, 3}
+	candidates := []entity.FulfilmentCandidate{}
+	flatFees := map[int64]float64{}
+	for pharmacyId := int64(1); pharmacyId <= 20; pharmacyId++ {
+		flatFees[pharmacyId] = float64(9000 + pharmacyId)
+		for _, drugId := range drugIds {
+			candidates = append(candidates, newFulfilmentCandidate(pharmacyId, float64(pharmacyId), drugId, 1000+pharmacyId*drugId))
+		}
+	}
+
+	shippingRateProvider := &fakeShippingRateProvider{flatFees: flatFees}
+	optimizer := newTestFulfilmentOptimizer(candidates, shippingRateProvider)
+
+	plans, err := optimizer.PlanPrescriptionFulfilment(context.Background(), 7, testPrescriptionDrugs(drugIds...), appconstant.FulfilmentStrategyCheapest)
+	if err != nil {
+		t.Fatalf("PlanPrescriptionFulfilment() error = %v", err)
+	}
+	if len(plans) != fulfilmentMaxAlternatives {
+		t.Errorf("PlanPrescriptionFulfilment() returned %d plans, want %d", len(plans), fulfilmentMaxAlternatives)
+	}
+
+	origins := map[int64]bool{}
+	for _, request := range shippingRateProvider.requests {
+		origins[request.Origin] = true
+	}
+	if len(origins) > fulfilmentMaxPharmacies {
+		t.Errorf("quoted %d pharmacies, want at most %d", len(origins), fulfilmentMaxPharmacies)
+	}
+
+	maxRequests := fulfilmentMaxPharmacies + fulfilmentShortlistSize*len(drugIds)
+	if len(shippingRateProvider.requests) > maxRequests {
+		t.Errorf("made %d courier calls, want at most %d", len(shippingRateProvider.requests), maxRequests)
+	}
+}
+
+func TestPlanPrescriptionFulfilmentFallsBackToNearestPerDrug(t *testing.T) {
+	// Every drug is nearest at its own pharmacy, so covering the prescription
+	// takes more pharmacies than the search allows. Pharmacy 100 sells all of
+	// them cheaply but is further away, so it must not be used.
+	drugIds := []int64{}
+	candidates := []entity.FulfilmentCandidate{}
+	for drugId := int64(1); drugId <= 25; drugId++ {
+		drugIds = append(drugIds, drugId)
+		candidates = append(candidates, newFulfilmentCandidate(drugId, float64(drugId), drugId, 5000))
+		candidates = append(candidates, newFulfilmentCandidate(100, 50, drugId, 1000))
+	}
+
+	shippingRateProvider := &fakeShippingRateProvider{flatFees: map[int64]float64{}}
+	optimizer := newTestFulfilmentOptimizer(candidates, shippingRateProvider)
+
+	plans, err := optimizer.PlanPrescriptionFulfilment(context.Background(), 7, testPrescriptionDrugs(drugIds...), appconstant.FulfilmentStrategyCheapest)
+	if err != nil {
+		t.Fatalf("PlanPrescriptionFulfilment() error = %v", err)
+	}
+	if len(plans) != 1 {
+		t.Fatalf("PlanPrescriptionFulfilment() returned %d plans, want 1", len(plans))
+	}
+
+	for _, item := range plans[0].Items {
+		if len(item.DrugQuantities) != 1 || item.DrugQuantities[0].PharmacyDrug.Drug.Id != item.PharmacyId {
+			t.Errorf("pharmacy %d supplies %v, want only its nearest drug", item.PharmacyId, item.DrugQuantities)
+		}
+	}
+	if len(plans[0].Items) != len(drugIds) {
+		t.Errorf("plan has %d shipments, want %d", len(plans[0].Items), len(drugIds))
+	}
+	if len(shippingRateProvider.requests) != len(drugIds) {
+		t.Errorf("made %d courier calls, want %d", len(shippingRateProvider.requests), len(drugIds))
+	}
+}
+
+func TestPickCourierOption(t *testing.T) {
+	deliveryOptions := []entity.AvailableCourier{
+		{PharmacyCourierId: 1, CourierOptions: []entity.CourierOption{{Price: 9000, Etd: "3-4 days"}, {Price: 20000, Etd: "1 day"}}},
+		{PharmacyCourierId: 2, CourierOptions: []entity.CourierOption{{Price: 15000, Etd: "4 hours"}, {Price: 8000, Etd: ""}}},
+	}
+
+	tests := []struct {
+		strategy      string
+		options       []entity.AvailableCourier
+		wantCourierId int64
+		wantPrice     float64
+		wantOk        bool
+	}{
+		{strategy: appconstant.FulfilmentStrategyCheapest, options: deliveryOptions, wantCourierId: 2, wantPrice: 8000, wantOk: true},
+		{strategy: appconstant.FulfilmentStrategyFastest, options: deliveryOptions, wantCourierId: 2, wantPrice: 15000, wantOk: true},
+		{strategy: appconstant.FulfilmentStrategyCheapest, options: []entity.AvailableCourier{{PharmacyCourierId: 3}}, wantOk: false},
+	}
+
+	for _, tt := range tests {
+		courierId, courierOption, ok := pickCourierOption(tt.strategy, tt.options)
+		if ok != tt.wantOk || courierId != tt.wantCourierId || courierOption.Price != tt.wantPrice {
+			t.Errorf("pickCourierOption(%q) = (%d, %v, %v), want (%d, %v, %v)", tt.strategy, courierId, courierOption.Price, ok, tt.wantCourierId, tt.wantPrice, tt.wantOk)
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package usecase
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/sidiqPratomo/max-health-backend/appconstant"
+	"github.com/sidiqPratomo/max-health-backend/apperror"
+	"github.com/sidiqPratomo/max-health-backend/dto"
+	"github.com/sidiqPratomo/max-health-backend/entity"
+	"github.com/sidiqPratomo/max-health-backend/repository"
+	"github.com/sidiqPratomo/max-health-backend/util"
+)
+
+const stockChangeAlertBatchSize = 100
+
+type LowStockAlertUsecase interface {
+	UpdateReorderThreshold(ctx context.Context, accountId, pharmacyDrugId int64, updateRequest dto.UpdateReorderThresholdRequest) error
+	GetLowStockAlerts(ctx context.Context, accountId int64, query dto.LowStockAlertQuery) (*dto.AllLowStockAlertsResponse, error)
+	MarkLowStockAlertRead(ctx context.Context, accountId, lowStockAlertId int64) error
+	GetReorderSuggestions(ctx context.Context, accountId int64, query dto.ReorderSuggestionQuery) (*dto.AllReorderSuggestionsResponse, error)
+	RaiseLowStockAlerts(ctx context.Context) (int, error)
+}
+
+type lowStockAlertUsecaseImpl struct {
+	lowStockAlertRepository   repository.LowStockAlertRepository
+	pharmacyDrugRepository    repository.PharmacyDrugRepository
+	pharmacyRepository        repository.PharmacyRepository
+	pharmacyManagerRepository repository.PharmacyManagerRepository
+	transaction               repository.Transaction
+	emailHelper               util.EmailHelper
+}
+
+func NewLowStockAlertUsecaseImpl(lowStockAlertRepository repository.LowStockAlertRepository, pharmacyDrugRepository repository.PharmacyDrugRepository, pharmacyRepository repository.PharmacyRepository, pharmacyManagerRepository repository.PharmacyManagerRepository, transaction repository.Transaction, emailHelper util.EmailHelper) lowStockAlertUsecaseImpl {
+	return lowStockAlertUsecaseImpl{
+		lowStockAlertRepository:   lowStockAlertRepository,
+		pharmacyDrugRepository:    pharmacyDrugRepository,
+		pharmacyRepository:        pharmacyRepository,
+		pharmacyManagerRepository: pharmacyManagerRepository,
+		transaction:               transaction,
+		emailHelper:               emailHelper,
+	}
+}
+
+func (u *lowStockAlertUsecaseImpl) findManager(ctx context.Context, accountId int64) (*entity.PharmacyManager, error) {
+	manager, err := u.pharmacyManagerRepository.FindOneByAccountId(ctx, accountId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+	if manager == nil {
+		return nil, apperror.PharmacyManagerNotFoundError()
+	}
+
+	return manager, nil
+}
+
+func (u *lowStockAlertUsecaseImpl) UpdateReorderThreshold(ctx context.Context, accountId, pharmacyDrugId int64, updateRequest dto.UpdateReorderThresholdRequest) error {
+	manager, err := u.findManager(ctx, accountId)
+	if err != nil {
+		return err
+	}
+
+	err = checkPharmacyDrugManager(ctx, u.pharmacyDrugRepository, u.pharmacyRepository, manager.Id, pharmacyDrugId)
+	if err != nil {
+		return err
+	}
+
+	err = u.pharmacyDrugRepository.UpdateReorderThreshold(ctx, pharmacyDrugId, updateRequest.ReorderThreshold)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	return nil
+}
+
+func (u *lowStockAlertUsecaseImpl) GetLowStockAlerts(ctx context.Context, accountId int64, query dto.LowStockAlertQuery) (*dto.AllLowStockAlertsResponse, error) {
+	manager, err := u.findManager(ctx, accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	params, err := util.SetDefaultQueryParams(util.QueryParam{Page: query.Page, Limit: query.Limit})
+	if err != nil {
+		return nil, err
+	}
+
+	lowStockAlertFilter := entity.LowStockAlertFilter{
+		ManagerId: manager.Id,
+		IsUnread:  query.IsUnread,
+	}
+
+	lowStockAlertFilter.Limit, _ = strconv.Atoi(params.Limit)
+	lowStockAlertFilter.Offset, _ = strconv.Atoi(params.Offset)
+
+	lowStockAlerts, pageInfo, err := u.lowStockAlertRepository.FindAll(ctx, lowStockAlertFilter)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	response := dto.ConvertToAllLowStockAlertsResponse(lowStockAlerts, *pageInfo)
+
+	return &response, nil
+}
+
+func (u *lowStockAlertUsecaseImpl) MarkLowStockAlertRead(ctx context.Context, accountId, lowStockAlertId int64) error {
+	manager, err := u.findManager(ctx, accountId)
+	if err != nil {
+		return err
+	}
+
+	updatedCount, err := u.lowStockAlertRepository.MarkReadOne(ctx, lowStockAlertId, manager.Id)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+	if updatedCount == 0 {
+		return apperror.LowStockAlertNotFoundError()
+	}
+
+	return nil
+}
+
+func (u *lowStockAlertUsecaseImpl) GetReorderSuggestions(ctx context.Context, accountId int64, query dto.ReorderSuggestionQuery) (*dto.AllReorderSuggestionsResponse, error) {
+	manager, err := u.findManager(ctx, accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	params, err := util.SetDefaultQueryParams(util.QueryParam{Page: query.Page, Limit: query.Limit})
+	if err != nil {
+		return nil, err
+	}
+
+	reorderSuggestionFilter := entity.ReorderSuggestionFilter{
+		ManagerId:       manager.Id,
+		PharmacyId:      query.PharmacyId,
+		SalesWindowDays: appconstant.DefaultReorderSalesWindowDays,
+		CoverDays:       appconstant.DefaultReorderCoverDays,
+	}
+	if query.Days != nil {
+		reorderSuggestionFilter.SalesWindowDays = *query.Days
+	}
+	if query.CoverDays != nil {
+		reorderSuggestionFilter.CoverDays = *query.CoverDays
+	}
+
+	reorderSuggestionFilter.Limit, _ = strconv.Atoi(params.Limit)
+	reorderSuggestionFilter.Offset, _ = strconv.Atoi(params.Offset)
+
+	reorderSuggestions, pageInfo, err := u.pharmacyDrugRepository.FindAllReorderSuggestions(ctx, reorderSuggestionFilter)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	response := dto.ConvertToAllReorderSuggestionsResponse(reorderSuggestions, *pageInfo)
+
+	return &response, nil
+}
+
+func (u *lowStockAlertUsecaseImpl) RaiseLowStockAlerts(ctx context.Context) (int, error) {
+	raisedCount := 0
+	var emailErr error
+
+	for {
+		lowStockAlerts, checkedCount, err := u.raiseLowStockAlertBatch(ctx)
+		if err != nil {
+			return raisedCount, err
+		}
+
+		raisedCount += len(lowStockAlerts)
+
+		for _, lowStockAlert := range lowStockAlerts {
+			if err := u.sendLowStockAlertEmail(lowStockAlert); err != nil && emailErr == nil {
+				emailErr = apperror.InternalServerError(err)
+			}
+		}
+
+		if checkedCount < stockChangeAlertBatchSize {
+			return raisedCount, emailErr
+		}
+	}
+}
+
+// Stock changes are marked as checked in the same transaction that raises
+// their alerts, so replicas never alert twice and a failed email is not
+// retried on every tick.
+func (u *lowStockAlertUsecaseImpl) raiseLowStockAlertBatch(ctx context.Context) ([]entity.LowStockAlert, int, error) {
+	tx, err := u.transaction.BeginTx(ctx)
+	if err != nil {
+		return nil, 0, apperror.InternalServerError(err)
+	}
+
+	stockChangeRepo := tx.StockChangeRepo()
+	lowStockAlertRepo := tx.LowStockAlertRepository()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+
+		tx.Commit()
+	}()
+
+	stockChanges, err := stockChangeRepo.FindAllAlertUncheckedForUpdate(ctx, stockChangeAlertBatchSize)
+	if err != nil {
+		return nil, 0, apperror.InternalServerError(err)
+	}
+
+	stockChangeIds := []int64{}
+	lowStockAlertIds := []int64{}
+
+	for _, stockChange := range stockChanges {
+		stockChangeIds = append(stockChangeIds, stockChange.Id)
+
+		if !isReorderThresholdCrossed(stockChange) {
+			continue
+		}
+
+		var lowStockAlertId *int64
+		lowStockAlertId, err = lowStockAlertRepo.CreateOne(ctx, stockChange)
+		if err != nil {
+			return nil, 0, apperror.InternalServerError(err)
+		}
+		if lowStockAlertId != nil {
+			lowStockAlertIds = append(lowStockAlertIds, *lowStockAlertId)
+		}
+	}
+
+	err = stockChangeRepo.MarkAlertChecked(ctx, stockChangeIds)
+	if err != nil {
+		return nil, 0, apperror.InternalServerError(err)
+	}
+
+	lowStockAlerts, err := lowStockAlertRepo.FindAllByIds(ctx, lowStockAlertIds)
+	if err != nil {
+		return nil, 0, apperror.InternalServerError(err)
+	}
+
+	return lowStockAlerts, len(stockChanges), nil
+}
+
+// Only the change that brings the stock down to the threshold raises an
+// alert, later sales while it stays below are not alerted again.
+func isReorderThresholdCrossed(stockChange entity.ReorderThresholdStockChange) bool {
+	if stockChange.ReorderThreshold == nil {
+		return false
+	}
+
+	reorderThreshold := *stockChange.ReorderThreshold
+	previousStock := stockChange.FinalStock - stockChange.Amount
+
+	return stockChange.FinalStock <= reorderThreshold && previousStock > reorderThreshold
+}
+
+func (u *lowStockAlertUsecaseImpl) sendLowStockAlertEmail(lowStockAlert entity.LowStockAlert) error {
+	u.emailHelper.AddRequest([]string{lowStockAlert.ManagerEmail}, appconstant.LowStockAlertEmailSubject)
+
+	err := u.emailHelper.CreateBody(appconstant.LowStockAlertEmailTemplate, struct {
+		Name             string
+		PharmacyName     string
+		DrugName         string
+		Stock            int
+		ReorderThreshold int
+	}{
+		Name:             lowStockAlert.ManagerName,
+		PharmacyName:     lowStockAlert.PharmacyName,
+		DrugName:         lowStockAlert.DrugName,
+		Stock:            lowStockAlert.Stock,
+		ReorderThreshold: lowStockAlert.ReorderThreshold,
+	})
+	if err != nil {
+		return err
+	}
+
+	return u.emailHelper.SendEmail()
+}
@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/sidiqPratomo/max-health-backend/appconstant"
+	"github.com/sidiqPratomo/max-health-backend/apperror"
+	"github.com/sidiqPratomo/max-health-backend/entity"
+	"github.com/sidiqPratomo/max-health-backend/repository"
+	"github.com/sidiqPratomo/max-health-backend/util"
+)
+
+const expiredOrderBatchSize = 50
+
+type OrderExpiryUsecase interface {
+	ExpireUnpaidOrders(ctx context.Context) (int, error)
+}
+
+type orderExpiryUsecaseImpl struct {
+	transaction repository.Transaction
+	emailHelper util.EmailHelper
+}
+
+func NewOrderExpiryUsecaseImpl(transaction repository.Transaction, emailHelper util.EmailHelper) orderExpiryUsecaseImpl {
+	return orderExpiryUsecaseImpl{
+		transaction: transaction,
+		emailHelper: emailHelper,
+	}
+}
+
+func (u *orderExpiryUsecaseImpl) ExpireUnpaidOrders(ctx context.Context) (int, error) {
+	expiredCount := 0
+	var emailErr error
+
+	for {
+		expiredOrders, lockedCount, err := u.expireUnpaidOrderBatch(ctx)
+		if err != nil {
+			return expiredCount, err
+		}
+
+		expiredCount += len(expiredOrders)
+
+		for _, expiredOrder := range expiredOrders {
+			if err := u.sendOrderExpiredEmail(expiredOrder); err != nil && emailErr == nil {
+				emailErr = apperror.InternalServerError(err)
+			}
+		}
+
+		if lockedCount < expiredOrderBatchSize {
+			return expiredCount, emailErr
+		}
+	}
+}
+
+// Rows are locked with SKIP LOCKED, so replicas running this concurrently
+// each pick up a different batch instead of restoring the same stock twice.
+func (u *orderExpiryUsecaseImpl) expireUnpaidOrderBatch(ctx context.Context) ([]entity.ExpiredOrder, int, error) {
+	tx, err := u.transaction.BeginTx(ctx)
+	if err != nil {
+		return nil, 0, apperror.InternalServerError(err)
+	}
+
+	orderRepo := tx.OrderRepository()
+	orderPharmacyRepo := tx.OrderPharmacyRepository()
+	pharmacyDrugRepo := tx.PharmacyDrugRepo()
+	stockChangeRepo := tx.StockChangeRepo()
+	prescriptionDrugRepo := tx.PrescriptionDrugRepository()
+	pharmacyDrugBatchRepo := tx.PharmacyDrugBatchRepository()
+	stockMutationRepo := tx.StockMutationRepo()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+
+		tx.Commit()
+	}()
+
+	lockedOrders, err := orderRepo.FindExpiredUnpaidOrdersForUpdate(ctx, expiredOrderBatchSize)
+	if err != nil {
+		return nil, 0, apperror.InternalServerError(err)
+	}
+
+	expiredOrders := []entity.ExpiredOrder{}
+
+	for _, lockedOrder := range lockedOrders {
+		var updatedCount int64
+		updatedCount, err = orderPharmacyRepo.UpdateStatusBulkByOrderIdAndStatusId(ctx, lockedOrder.Id, appconstant.OrderStatusWaitingForPayment, appconstant.OrderStatusCanceled)
+		if err != nil {
+			return nil, 0, apperror.InternalServerError(err)
+		}
+
+		if updatedCount == 0 {
+			continue
+		}
+
+		err = prescriptionDrugRepo.RestoreByOrderId(ctx, lockedOrder.Id)
+		if err != nil {
+			return nil, 0, apperror.InternalServerError(err)
+		}
+
+		var stockChanges []entity.StockChange
+		stockChanges, err = pharmacyDrugRepo.UpdatePharmacyDrugsByOrderId(ctx, lockedOrder.Id)
+		if err != nil {
+			return nil, 0, apperror.InternalServerError(err)
+		}
+
+		err = pharmacyDrugBatchRepo.ReleaseAllocationsByOrderId(ctx, lockedOrder.Id)
+		if err != nil {
+			return nil, 0, apperror.InternalServerError(err)
+		}
+
+		err = stockMutationRepo.CancelPendingByOrderId(ctx, lockedOrder.Id)
+		if err != nil {
+			return nil, 0, apperror.InternalServerError(err)
+		}
+
+		if len(stockChanges) > 0 {
+			for i := range stockChanges {
+				stockChanges[i].Description = "restored from expired order"
+				stockChanges[i].ChangeType = appconstant.StockChangeTypeCancellation
+			}
+
+			err = stockChangeRepo.PostStockChangesFromUpdate(ctx, stockChanges)
+			if err != nil {
+				return nil, 0, apperror.InternalServerError(err)
+			}
+		}
+
+		expiredOrders = append(expiredOrders, lockedOrder)
+	}
+
+	return expiredOrders, len(lockedOrders), nil
+}
+
+func (u *orderExpiryUsecaseImpl) sendOrderExpiredEmail(expiredOrder entity.ExpiredOrder) error {
+	u.emailHelper.AddRequest([]string{expiredOrder.UserEmail}, appconstant.OrderExpiredEmailSubject)
+
+	err := u.emailHelper.CreateBody(appconstant.OrderExpiredEmailTemplate, struct {
+		Name        string
+		OrderId     int64
+		TotalAmount string
+	}{
+		Name:        expiredOrder.UserName,
+		OrderId:     expiredOrder.Id,
+		TotalAmount: expiredOrder.TotalAmount.StringFixed(0),
+	})
+	if err != nil {
+		return err
+	}
+
+	return u.emailHelper.SendEmail()
+}
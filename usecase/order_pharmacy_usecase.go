@@ -254,6 +254,7 @@ func (u *orderPharmacyUsecaseImpl) UpdateStatusToCancelled(ctx context.Context,
 	pharmacyDrugRepo := tx.PharmacyDrugRepo()
 	orderPharmacyRepo := tx.OrderPharmacyRepository()
 	stockChangeRepo := tx.StockChangeRepo()
+	prescriptionDrugRepo := tx.PrescriptionDrugRepository()
 
 	defer func() {
 		if err != nil {
@@ -267,11 +268,25 @@ func (u *orderPharmacyUsecaseImpl) UpdateStatusToCancelled(ctx context.Context,
 		return apperror.InternalServerError(err)
 	}
 
+	err = prescriptionDrugRepo.RestoreByOrderPharmacyId(ctx, orderPharmacyId)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
 	stockChanges, err := pharmacyDrugRepo.UpdatePharmacyDrugsByOrderPharmacyId(ctx, orderPharmacyId)
 	if err != nil {
 		return apperror.InternalServerError(err)
 	}
 
+	err = tx.PharmacyDrugBatchRepository().ReleaseAllocationsByOrderPharmacyId(ctx, orderPharmacyId)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	for i := range stockChanges {
+		stockChanges[i].ActorAccountId = &accountId
+	}
+
 	err = stockChangeRepo.PostStockChanges(ctx, stockChanges)
 	if err != nil {
 		return apperror.InternalServerError(err)
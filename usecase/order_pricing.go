@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/shopspring/decimal"
+	"github.com/sidiqPratomo/max-health-backend/apperror"
+	"github.com/sidiqPratomo/max-health-backend/dto"
+	"github.com/sidiqPratomo/max-health-backend/entity"
+	"github.com/sidiqPratomo/max-health-backend/repository"
+	"github.com/sidiqPratomo/max-health-backend/util"
+)
+
+type PricingEngine interface {
+	PriceCartCheckout(ctx context.Context, userId, userAddressId int64, pharmacies []dto.PharmacyCheckoutRequest) (*entity.CheckoutPricing, error)
+	PriceCartCheckoutInTx(ctx context.Context, cartRepository repository.CartRepository, userId, userAddressId int64, pharmacies []dto.PharmacyCheckoutRequest) (*entity.CheckoutPricing, error)
+}
+
+type pricingEngineImpl struct {
+	cartRepository       repository.CartRepository
+	shippingRateProvider util.ShippingRateProvider
+}
+
+func NewPricingEngineImpl(cartRepository repository.CartRepository, shippingRateProvider util.ShippingRateProvider) pricingEngineImpl {
+	return pricingEngineImpl{
+		cartRepository:       cartRepository,
+		shippingRateProvider: shippingRateProvider,
+	}
+}
+
+func (e *pricingEngineImpl) PriceCartCheckout(ctx context.Context, userId, userAddressId int64, pharmacies []dto.PharmacyCheckoutRequest) (*entity.CheckoutPricing, error) {
+	return e.PriceCartCheckoutInTx(ctx, e.cartRepository, userId, userAddressId, pharmacies)
+}
+
+// PriceCartCheckoutInTx prices carts through the given repository, so carts
+// created earlier in the same transaction can be priced before it commits.
+func (e *pricingEngineImpl) PriceCartCheckoutInTx(ctx context.Context, cartRepository repository.CartRepository, userId, userAddressId int64, pharmacies []dto.PharmacyCheckoutRequest) (*entity.CheckoutPricing, error) {
+	cartItemIds := []int64{}
+	for _, pharmacy := range pharmacies {
+		cartItemIds = append(cartItemIds, pharmacy.CartItemIds...)
+	}
+
+	cartItems, err := cartRepository.GetAllCartDataByIds(ctx, cartItemIds)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+	if len(cartItems) != len(cartItemIds) {
+		return nil, apperror.CartItemNotFoundError()
+	}
+
+	cartItemsById := map[int64]entity.CartItemData{}
+	for _, cartItem := range cartItems {
+		cartItemsById[cartItem.CartItemId] = cartItem
+	}
+
+	deliveryFees, err := cartRepository.GetPharmacyDeliveryFeeForCart(ctx, cartItemIds, userAddressId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	for i := range deliveryFees {
+		if err := fillCourierOptions(ctx, e.shippingRateProvider, deliveryFees[i].Couriers); err != nil {
+			return nil, err
+		}
+	}
+
+	pricing := entity.CheckoutPricing{TotalAmount: decimal.Zero}
+
+	for _, pharmacy := range pharmacies {
+		subtotal := decimal.Zero
+
+		for _, cartItemId := range pharmacy.CartItemIds {
+			cartItem := cartItemsById[cartItemId]
+			if cartItem.UserId != userId {
+				return nil, apperror.UnauthorizedUserCartAccessError()
+			}
+			if cartItem.PharmacyId != pharmacy.PharmacyId {
+				return nil, apperror.InvalidCartItemError()
+			}
+
+			subtotal = subtotal.Add(cartItem.Price.Mul(decimal.NewFromInt(int64(cartItem.Quantity))))
+		}
+
+		deliveryFee, err := quotedDeliveryFee(deliveryFees, pharmacy)
+		if err != nil {
+			return nil, err
+		}
+
+		pricing.Pharmacies = append(pricing.Pharmacies, entity.PharmacyCheckoutPricing{
+			PharmacyId:        pharmacy.PharmacyId,
+			PharmacyCourierId: pharmacy.PharmacyCourierId,
+			Subtotal:          subtotal,
+			DeliveryFee:       deliveryFee,
+		})
+		pricing.TotalAmount = pricing.TotalAmount.Add(subtotal).Add(deliveryFee)
+	}
+
+	return &pricing, nil
+}
+
+// quotedDeliveryFee picks the courier option the client chose, when none of the quotes match
+// the cheapest one is returned so the mismatch error can show what the server expects.
+func quotedDeliveryFee(deliveryFees []entity.PharmacyDeliveryFee, pharmacy dto.PharmacyCheckoutRequest) (decimal.Decimal, error) {
+	submitted := decimal.NewFromInt(int64(pharmacy.DeliveryFee))
+
+	for _, deliveryFee := range deliveryFees {
+		if deliveryFee.Id != pharmacy.PharmacyId {
+			continue
+		}
+
+		for _, courier := range deliveryFee.Couriers {
+			if courier.PharmacyCourierId != pharmacy.PharmacyCourierId || len(courier.CourierOptions) == 0 {
+				continue
+			}
+
+			cheapest := decimal.NewFromFloat(courier.CourierOptions[0].Price).Round(0)
+			for _, option := range courier.CourierOptions {
+				price := decimal.NewFromFloat(option.Price).Round(0)
+				if price.Equal(submitted) {
+					return price, nil
+				}
+				if price.LessThan(cheapest) {
+					cheapest = price
+				}
+			}
+
+			return cheapest, nil
+		}
+	}
+
+	return decimal.Zero, apperror.CourierNotAvailableError()
+}
+
+func checkSubmittedAmounts(orderCheckoutRequest dto.OrderCheckoutRequest, pricing entity.CheckoutPricing) error {
+	for i, pharmacy := range orderCheckoutRequest.Pharmacies {
+		field := "pharmacies[" + strconv.Itoa(i) + "]."
+
+		submittedSubtotal := decimal.NewFromInt(int64(pharmacy.Subtotal))
+		if !submittedSubtotal.Equal(pricing.Pharmacies[i].Subtotal) {
+			return apperror.CheckoutAmountMismatchError(field+"subtotal_amount", submittedSubtotal, pricing.Pharmacies[i].Subtotal)
+		}
+
+		submittedDeliveryFee := decimal.NewFromInt(int64(pharmacy.DeliveryFee))
+		if !submittedDeliveryFee.Equal(pricing.Pharmacies[i].DeliveryFee) {
+			return apperror.CheckoutAmountMismatchError(field+"delivery_fee", submittedDeliveryFee, pricing.Pharmacies[i].DeliveryFee)
+		}
+	}
+
+	submittedTotalAmount := decimal.NewFromInt(int64(orderCheckoutRequest.TotalAmount))
+	if !submittedTotalAmount.Equal(pricing.TotalAmount) {
+		return apperror.CheckoutAmountMismatchError("total_amount", submittedTotalAmount, pricing.TotalAmount)
+	}
+
+	return nil
+}
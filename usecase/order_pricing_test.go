@@ -0,0 +1,250 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/sidiqPratomo/max-health-backend/apperror"
+	"github.com/sidiqPratomo/max-health-backend/dto"
+	"github.com/sidiqPratomo/max-health-backend/entity"
+	"github.com/sidiqPratomo/max-health-backend/repository"
+)
+
+type fakePricingCartRepository struct {
+	repository.CartRepository
+	cartItems    []entity.CartItemData
+	deliveryFees []entity.PharmacyDeliveryFee
+}
+
+func (r *fakePricingCartRepository) GetAllCartDataByIds(ctx context.Context, cartItemIds []int64) ([]entity.CartItemData, error) {
+	cartItems := []entity.CartItemData{}
+	for _, cartItem := range r.cartItems {
+		for _, cartItemId := range cartItemIds {
+			if cartItem.CartItemId == cartItemId {
+				cartItems = append(cartItems, cartItem)
+			}
+		}
+	}
+	return cartItems, nil
+}
+
+func (r *fakePricingCartRepository) GetPharmacyDeliveryFeeForCart(ctx context.Context, cartItemsId []int64, userAddressId int64) ([]entity.PharmacyDeliveryFee, error) {
+	deliveryFees := []entity.PharmacyDeliveryFee{}
+	for _, deliveryFee := range r.deliveryFees {
+		couriers := append([]entity.AvailableCourier{}, deliveryFee.Couriers...)
+		deliveryFee.Couriers = couriers
+		deliveryFees = append(deliveryFees, deliveryFee)
+	}
+	return deliveryFees, nil
+}
+
+func newPricingDeliveryFee(pharmacyId int64, prices ...float64) entity.PharmacyDeliveryFee {
+	courierOptions := []entity.CourierOption{}
+	for _, price := range prices {
+		courierOptions = append(courierOptions, entity.CourierOption{Price: price})
+	}
+
+	return entity.PharmacyDeliveryFee{
+		Id:       pharmacyId,
+		Couriers: []entity.AvailableCourier{{PharmacyCourierId: pharmacyId * 10, CourierOptions: courierOptions}},
+	}
+}
+
+func assertAppError(t *testing.T, err error, want *apperror.AppError) {
+	t.Helper()
+
+	if want == nil {
+		if err != nil {
+			t.Fatalf("error = %v, want nil", err)
+		}
+		return
+	}
+
+	appErr, ok := err.(*apperror.AppError)
+	if !ok {
+		t.Fatalf("error = %v, want %q", err, want.Message)
+	}
+	if appErr.Code != want.Code || appErr.Message != want.Message {
+		t.Errorf("error = (%d, %q), want (%d, %q)", appErr.Code, appErr.Message, want.Code, want.Message)
+	}
+}
+
+func TestQuotedDeliveryFee(t *testing.T) {
+	deliveryFees := []entity.PharmacyDeliveryFee{
+		newPricingDeliveryFee(1, 15000.4, 9000.6, 12000),
+		{Id: 2, Couriers: []entity.AvailableCourier{{PharmacyCourierId: 20}}},
+	}
+
+	tests := []struct {
+		name    string
+		request dto.PharmacyCheckoutRequest
+		want    int64
+		wantErr *apperror.AppError
+	}{
+		{
+			name:    "submitted option is quoted",
+			request: dto.PharmacyCheckoutRequest{PharmacyId: 1, PharmacyCourierId: 10, DeliveryFee: 12000},
+			want:    12000,
+		},
+		{
+			name:    "quotes are rounded before comparing",
+			request: dto.PharmacyCheckoutRequest{PharmacyId: 1, PharmacyCourierId: 10, DeliveryFee: 15000},
+			want:    15000,
+		},
+		{
+			name:    "unknown fee falls back to the cheapest quote",
+			request: dto.PharmacyCheckoutRequest{PharmacyId: 1, PharmacyCourierId: 10, DeliveryFee: 1},
+			want:    9001,
+		},
+		{
+			name:    "courier without quotes",
+			request: dto.PharmacyCheckoutRequest{PharmacyId: 2, PharmacyCourierId: 20, DeliveryFee: 9000},
+			wantErr: apperror.CourierNotAvailableError(),
+		},
+		{
+			name:    "courier of another pharmacy",
+			request: dto.PharmacyCheckoutRequest{PharmacyId: 1, PharmacyCourierId: 20, DeliveryFee: 9000},
+			wantErr: apperror.CourierNotAvailableError(),
+		},
+		{
+			name:    "pharmacy without quotes",
+			request: dto.PharmacyCheckoutRequest{PharmacyId: 3, PharmacyCourierId: 30, DeliveryFee: 9000},
+			wantErr: apperror.CourierNotAvailableError(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := quotedDeliveryFee(deliveryFees, tt.request)
+
+			assertAppError(t, err, tt.wantErr)
+			if tt.wantErr == nil && !got.Equal(decimal.NewFromInt(tt.want)) {
+				t.Errorf("quotedDeliveryFee() = %s, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckSubmittedAmounts(t *testing.T) {
+	pricing := entity.CheckoutPricing{
+		Pharmacies: []entity.PharmacyCheckoutPricing{
+			{PharmacyId: 1, Subtotal: decimal.NewFromInt(20000), DeliveryFee: decimal.NewFromInt(9000)},
+			{PharmacyId: 2, Subtotal: decimal.NewFromInt(5000), DeliveryFee: decimal.NewFromInt(8000)},
+		},
+		TotalAmount: decimal.NewFromInt(42000),
+	}
+
+	newRequest := func(edit func(*dto.OrderCheckoutRequest)) dto.OrderCheckoutRequest {
+		request := dto.OrderCheckoutRequest{
+			TotalAmount: 42000,
+			Pharmacies: []dto.PharmacyCheckoutRequest{
+				{PharmacyId: 1, Subtotal: 20000, DeliveryFee: 9000},
+				{PharmacyId: 2, Subtotal: 5000, DeliveryFee: 8000},
+			},
+		}
+		edit(&request)
+		return request
+	}
+
+	tests := []struct {
+		name    string
+		request dto.OrderCheckoutRequest
+		wantErr *apperror.AppError
+	}{
+		{
+			name:    "amounts match",
+			request: newRequest(func(r *dto.OrderCheckoutRequest) {}),
+		},
+		{
+			name:    "subtotal mismatch",
+			request: newRequest(func(r *dto.OrderCheckoutRequest) { r.Pharmacies[1].Subtotal = 4000 }),
+			wantErr: apperror.CheckoutAmountMismatchError("pharmacies[1].subtotal_amount", decimal.NewFromInt(4000), decimal.NewFromInt(5000)),
+		},
+		{
+			name:    "delivery fee mismatch",
+			request: newRequest(func(r *dto.OrderCheckoutRequest) { r.Pharmacies[0].DeliveryFee = 1 }),
+			wantErr: apperror.CheckoutAmountMismatchError("pharmacies[0].delivery_fee", decimal.NewFromInt(1), decimal.NewFromInt(9000)),
+		},
+		{
+			name:    "total mismatch",
+			request: newRequest(func(r *dto.OrderCheckoutRequest) { r.TotalAmount = 40000 }),
+			wantErr: apperror.CheckoutAmountMismatchError("total_amount", decimal.NewFromInt(40000), decimal.NewFromInt(42000)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkSubmittedAmounts(tt.request, pricing)
+
+			assertAppError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestPriceCartCheckout(t *testing.T) {
+	cartRepository := &fakePricingCartRepository{
+		cartItems: []entity.CartItemData{
+			{CartItemId: 1, UserId: 7, PharmacyId: 1, Quantity: 2, Price: decimal.NewFromInt(5000)},
+			{CartItemId: 2, UserId: 7, PharmacyId: 1, Quantity: 1, Price: decimal.NewFromInt(3000)},
+			{CartItemId: 3, UserId: 7, PharmacyId: 2, Quantity: 3, Price: decimal.NewFromInt(1000)},
+			{CartItemId: 4, UserId: 8, PharmacyId: 2, Quantity: 1, Price: decimal.NewFromInt(1000)},
+		},
+		deliveryFees: []entity.PharmacyDeliveryFee{
+			newPricingDeliveryFee(1, 9000),
+			{Id: 2, Couriers: []entity.AvailableCourier{{
+				PharmacyCourierId:   20,
+				ShippingRateRequest: &entity.ShippingRateRequest{Origin: 2, Weight: 500},
+			}}},
+		},
+	}
+	shippingRateProvider := &fakeShippingRateProvider{flatFees: map[int64]float64{2: 7000}}
+	pricingEngine := NewPricingEngineImpl(cartRepository, shippingRateProvider)
+
+	tests := []struct {
+		name       string
+		pharmacies []dto.PharmacyCheckoutRequest
+		wantTotal  int64
+		wantErr    *apperror.AppError
+	}{
+		{
+			name: "subtotals and quoted fees add up",
+			pharmacies: []dto.PharmacyCheckoutRequest{
+				{PharmacyId: 1, PharmacyCourierId: 10, DeliveryFee: 9000, CartItemIds: []int64{1, 2}},
+				{PharmacyId: 2, PharmacyCourierId: 20, DeliveryFee: 7500, CartItemIds: []int64{3}},
+			},
+			wantTotal: 13000 + 9000 + 3000 + 7500,
+		},
+		{
+			name:       "cart item of another user",
+			pharmacies: []dto.PharmacyCheckoutRequest{{PharmacyId: 2, PharmacyCourierId: 20, DeliveryFee: 7500, CartItemIds: []int64{4}}},
+			wantErr:    apperror.UnauthorizedUserCartAccessError(),
+		},
+		{
+			name:       "cart item of another pharmacy",
+			pharmacies: []dto.PharmacyCheckoutRequest{{PharmacyId: 1, PharmacyCourierId: 10, DeliveryFee: 9000, CartItemIds: []int64{3}}},
+			wantErr:    apperror.InvalidCartItemError(),
+		},
+		{
+			name:       "missing cart item",
+			pharmacies: []dto.PharmacyCheckoutRequest{{PharmacyId: 1, PharmacyCourierId: 10, DeliveryFee: 9000, CartItemIds: []int64{1, 99}}},
+			wantErr:    apperror.CartItemNotFoundError(),
+		},
+		{
+			name:       "courier without a quote",
+			pharmacies: []dto.PharmacyCheckoutRequest{{PharmacyId: 1, PharmacyCourierId: 20, DeliveryFee: 9000, CartItemIds: []int64{1}}},
+			wantErr:    apperror.CourierNotAvailableError(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pricing, err := pricingEngine.PriceCartCheckout(context.Background(), 7, 3, tt.pharmacies)
+
+			assertAppError(t, err, tt.wantErr)
+			if tt.wantErr == nil && !pricing.TotalAmount.Equal(decimal.NewFromInt(tt.wantTotal)) {
+				t.Errorf("PriceCartCheckout() total = %s, want %d", pricing.TotalAmount, tt.wantTotal)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"mime/multipart"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/sidiqPratomo/max-health-backend/appconstant"
 	"github.com/sidiqPratomo/max-health-backend/apperror"
@@ -27,16 +28,24 @@ type OrderUsecase interface {
 type orderUsecaseImpl struct {
 	transaction             repository.Transaction
 	userRepository          repository.UserRepository
+	userAddressRepository   repository.UserAddressRepository
 	orderRepository         repository.OrderRepository
 	orderPharmacyRepository repository.OrderPharmacyRepository
+	pricingEngine           PricingEngine
+	pharmacyHours           PharmacyHours
+	prescriptionCompliance  PrescriptionCompliance
 }
 
-func NewOrderUsecaseImpl(transaction repository.Transaction, userRepository repository.UserRepository, orderRepository repository.OrderRepository, orderPharmacyRepository repository.OrderPharmacyRepository) orderUsecaseImpl {
+func NewOrderUsecaseImpl(transaction repository.Transaction, userRepository repository.UserRepository, userAddressRepository repository.UserAddressRepository, orderRepository repository.OrderRepository, orderPharmacyRepository repository.OrderPharmacyRepository, pricingEngine PricingEngine, pharmacyHours PharmacyHours, prescriptionCompliance PrescriptionCompliance) orderUsecaseImpl {
 	return orderUsecaseImpl{
 		transaction:             transaction,
 		userRepository:          userRepository,
+		userAddressRepository:   userAddressRepository,
 		orderRepository:         orderRepository,
 		orderPharmacyRepository: orderPharmacyRepository,
+		pricingEngine:           pricingEngine,
+		pharmacyHours:           pharmacyHours,
+		prescriptionCompliance:  prescriptionCompliance,
 	}
 }
 
@@ -55,6 +64,36 @@ func (u *orderUsecaseImpl) CheckoutOrder(ctx context.Context, orderCheckoutReque
 		}
 	}
 
+	address, err := u.userAddressRepository.GetOneUserAddressByAddressId(ctx, orderCheckoutRequest.UserAddressId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+	if address == nil || address.UserId != user.Id {
+		return nil, apperror.UserAddressNotFoundError()
+	}
+
+	now := time.Now()
+	for _, pharmacy := range orderCheckoutRequest.Pharmacies {
+		if err = u.pharmacyHours.CheckPharmacyIsOpen(ctx, pharmacy.PharmacyId, now); err != nil {
+			return nil, err
+		}
+	}
+
+	pricing, err := u.pricingEngine.PriceCartCheckout(ctx, user.Id, orderCheckoutRequest.UserAddressId, orderCheckoutRequest.Pharmacies)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkSubmittedAmounts(orderCheckoutRequest, *pricing); err != nil {
+		return nil, err
+	}
+
+	for i, pharmacyPricing := range pricing.Pharmacies {
+		orderCheckoutRequest.Pharmacies[i].Subtotal = int(pharmacyPricing.Subtotal.IntPart())
+		orderCheckoutRequest.Pharmacies[i].DeliveryFee = int(pharmacyPricing.DeliveryFee.IntPart())
+	}
+	orderCheckoutRequest.TotalAmount = int(pricing.TotalAmount.IntPart())
+
 	tx, err := u.transaction.BeginTx(ctx)
 	if err != nil {
 		return nil, apperror.InternalServerError(err)
@@ -93,16 +132,21 @@ func (u *orderUsecaseImpl) CheckoutOrder(ctx context.Context, orderCheckoutReque
 		}
 	}
 
-	err = orderItemRepo.PostOrderItems(ctx, orderPharmacies)
-	if err != nil {
-		return nil, apperror.InternalServerError(err)
-	}
-
 	allCartItems := []entity.CartItemForCheckout{}
 	for _, pharmacy := range orderPharmacies {
 		allCartItems = append(allCartItems, pharmacy.CartItems...)
 	}
 
+	err = u.prescriptionCompliance.RedeemCartItems(ctx, tx, orderCheckoutRequest.AccountId, allCartItems)
+	if err != nil {
+		return nil, err
+	}
+
+	err = orderItemRepo.PostOrderItems(ctx, orderPharmacies)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
 	err = pharmacyDrugRepo.GetPharmacyDrugsByCartForUpdate(ctx, allCartItems)
 	if err != nil {
 		return nil, apperror.InternalServerError(err)
@@ -113,7 +157,7 @@ func (u *orderUsecaseImpl) CheckoutOrder(ctx context.Context, orderCheckoutReque
 		return nil, apperror.InternalServerError(err)
 	}
 
-	err = stockChangeRepo.PostStockChangesByCartIds(ctx, carts)
+	err = stockChangeRepo.PostStockChangesByCartIds(ctx, carts, orderCheckoutRequest.AccountId)
 	if err != nil {
 		return nil, apperror.InternalServerError(err)
 	}
@@ -143,26 +187,21 @@ func (u *orderUsecaseImpl) CheckoutOrder(ctx context.Context, orderCheckoutReque
 			if stock+alternative.AlternativeStock < 0 {
 				stock += alternative.AlternativeStock
 				stockMutationList = append(stockMutationList, alternative)
-				stockChangesList = append(stockChangesList, entity.StockChange{PharmacyDrugId: alternative.OriginalPharmacyDrug,
-					FinalStock: stock, Amount: alternative.AlternativeStock})
-				stockChangesList = append(stockChangesList, entity.StockChange{PharmacyDrugId: alternative.AlternativePharmacy,
-					FinalStock: 0, Amount: -1 * alternative.AlternativeStock})
 			} else {
 				partialAlternative := alternative
 				partialAlternative.AlternativeStock = stock * -1
 				stockMutationList = append(stockMutationList, partialAlternative)
-				stockChangesList = append(stockChangesList, entity.StockChange{PharmacyDrugId: alternative.OriginalPharmacyDrug,
-					FinalStock: 0, Amount: partialAlternative.AlternativeStock})
-				stockChangesList = append(stockChangesList, entity.StockChange{PharmacyDrugId: alternative.AlternativePharmacy,
-					FinalStock: alternative.AlternativeStock - partialAlternative.AlternativeStock,
-					Amount:     -1 * partialAlternative.AlternativeStock})
 				stock = 0
 				break
 			}
 		}
 		if stock < 0 {
 			insufficientCartItems = append(insufficientCartItems, pharmacyDrug.CartId)
+			continue
 		}
+		stockChangesList = append(stockChangesList, entity.StockChange{PharmacyDrugId: pharmacyDrug.PharmacyDrugId,
+			FinalStock: pharmacyDrug.Stock, Amount: 0, Description: appconstant.StockMutationRequestedDescription,
+			ActorAccountId: &orderCheckoutRequest.AccountId})
 	}
 
 	if len(insufficientCartItems) > 0 {
@@ -170,8 +209,10 @@ func (u *orderUsecaseImpl) CheckoutOrder(ctx context.Context, orderCheckoutReque
 		return nil, err
 	}
 
+	// Sibling pharmacies only hand over stock once their manager approves the
+	// request, until then the ordered pharmacy carries the shortfall.
 	if len(stockMutationList) > 0 {
-		err = stockMutationRepo.PostStockMutations(ctx, stockMutationList)
+		err = stockMutationRepo.PostStockMutations(ctx, stockMutationList, &orderId)
 		if err != nil {
 			return nil, apperror.InternalServerError(err)
 		}
@@ -180,11 +221,11 @@ func (u *orderUsecaseImpl) CheckoutOrder(ctx context.Context, orderCheckoutReque
 		if err != nil {
 			return nil, apperror.InternalServerError(err)
 		}
+	}
 
-		err = pharmacyDrugRepo.UpdatePharmacyDrugsForStockMutation(ctx, stockChangesList)
-		if err != nil {
-			return nil, apperror.InternalServerError(err)
-		}
+	err = allocateOrderPharmacyBatches(ctx, tx.PharmacyDrugBatchRepository(), orderPharmacies)
+	if err != nil {
+		return nil, err
 	}
 
 	err = cartRepo.DeleteCarts(ctx, allCartItems)
@@ -305,14 +346,23 @@ func (u *orderUsecaseImpl) UploadPaymentProofOrder(ctx context.Context, accountI
 		tx.Commit()
 	}()
 
-	if err := orderRepo.UpdatePaymentProofOne(ctx, &entity.Order{
-		Id:           orderId,
-		PaymentProof: paymentProofUrl,
-	}); err != nil {
+	// The order may have been cancelled or expired since it was read, so it
+	// only moves on while it is still waiting for payment.
+	updatedCount, err := orderPharmacyRepo.UpdateStatusBulkByOrderIdAndStatusId(ctx, orderId, appconstant.OrderStatusWaitingForPayment, appconstant.OrderStatusWaitingForPaymentConfirmation)
+	if err != nil {
 		return apperror.InternalServerError(err)
 	}
+	if updatedCount != int64(len(orderPharmacies)) {
+		util.DeleteInCloudinary(paymentProofUrl)
+		err = apperror.OrderStatusChangedError()
+		return err
+	}
 
-	if err := orderPharmacyRepo.UpdateStatusBulkByOrderId(ctx, orderId, 2); err != nil {
+	err = orderRepo.UpdatePaymentProofOne(ctx, &entity.Order{
+		Id:           orderId,
+		PaymentProof: paymentProofUrl,
+	})
+	if err != nil {
 		return apperror.InternalServerError(err)
 	}
 
@@ -406,6 +456,7 @@ func (u *orderUsecaseImpl) CancelOrder(ctx context.Context, accountId int64, ord
 	orderPharmacyRepo := tx.OrderPharmacyRepository()
 	pharmacyDrugRepo := tx.PharmacyDrugRepo()
 	stockChangeRepo := tx.StockChangeRepo()
+	prescriptionDrugRepo := tx.PrescriptionDrugRepository()
 
 	defer func() {
 		if err != nil {
@@ -414,14 +465,40 @@ func (u *orderUsecaseImpl) CancelOrder(ctx context.Context, accountId int64, ord
 
 		tx.Commit()
 	}()
-	if err := orderPharmacyRepo.UpdateStatusBulkByOrderId(ctx, orderId, 6); err != nil {
+
+	updatedCount, err := orderPharmacyRepo.UpdateStatusBulkByOrderIdAndStatusId(ctx, orderId, appconstant.OrderStatusWaitingForPayment, appconstant.OrderStatusCanceled)
+	if err != nil {
 		return apperror.InternalServerError(err)
 	}
+	if updatedCount != int64(len(orderPharmacies)) {
+		err = apperror.OrderStatusChangedError()
+		return err
+	}
+
+	err = prescriptionDrugRepo.RestoreByOrderId(ctx, orderId)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
 	stockChanges, err := pharmacyDrugRepo.UpdatePharmacyDrugsByOrderId(ctx, orderId)
 	if err != nil {
 		return apperror.InternalServerError(err)
 	}
 
+	err = tx.PharmacyDrugBatchRepository().ReleaseAllocationsByOrderId(ctx, orderId)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	err = tx.StockMutationRepo().CancelPendingByOrderId(ctx, orderId)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	for i := range stockChanges {
+		stockChanges[i].ActorAccountId = &accountId
+	}
+
 	err = stockChangeRepo.PostStockChanges(ctx, stockChanges)
 	if err != nil {
 		return apperror.InternalServerError(err)
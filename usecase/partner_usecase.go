@@ -10,6 +10,7 @@ import (
 	"github.com/sidiqPratomo/max-health-backend/appconstant"
 	"github.com/sidiqPratomo/max-health-backend/apperror"
 	"github.com/sidiqPratomo/max-health-backend/dto"
+	"github.com/sidiqPratomo/max-health-backend/entity"
 	"github.com/sidiqPratomo/max-health-backend/repository"
 	"github.com/sidiqPratomo/max-health-backend/util"
 )
@@ -28,6 +29,7 @@ type partnerUsecaseImpl struct {
 	transaction               repository.Transaction
 	hashHelper                util.HashHelperIntf
 	emailHelper               util.EmailHelper
+	accountTokenVersion       AccountTokenVersion
 }
 
 type PartnerUsecaseImplOpts struct {
@@ -36,6 +38,7 @@ type PartnerUsecaseImplOpts struct {
 	Transaction               repository.Transaction
 	HashHelper                util.HashHelperIntf
 	EmailHelper               util.EmailHelper
+	AccountTokenVersion       AccountTokenVersion
 }
 
 func NewPartnerUsecaseImpl(opts PartnerUsecaseImplOpts) partnerUsecaseImpl {
@@ -45,6 +48,7 @@ func NewPartnerUsecaseImpl(opts PartnerUsecaseImplOpts) partnerUsecaseImpl {
 		transaction:               opts.Transaction,
 		hashHelper:                opts.HashHelper,
 		emailHelper:               opts.EmailHelper,
+		accountTokenVersion:       opts.AccountTokenVersion,
 	}
 }
 
@@ -188,6 +192,17 @@ func (u *partnerUsecaseImpl) DeleteOnePartner(ctx context.Context, pharmacyManag
 		util.DeleteInCloudinary(pharmacyManager.Account.ProfilePicture)
 	}
 
+	err = u.deletePartner(ctx, pharmacyManager)
+	if err != nil {
+		return err
+	}
+
+	u.accountTokenVersion.Forget(pharmacyManager.Account.Id)
+
+	return nil
+}
+
+func (u *partnerUsecaseImpl) deletePartner(ctx context.Context, pharmacyManager *entity.PharmacyManager) error {
 	tx, err := u.transaction.BeginTx(ctx)
 	if err != nil {
 		return apperror.InternalServerError(err)
@@ -195,6 +210,7 @@ func (u *partnerUsecaseImpl) DeleteOnePartner(ctx context.Context, pharmacyManag
 
 	accountRepo := tx.AccountRepository()
 	pharmacyManagerRepo := tx.PharmacyManagerRepository()
+	accountSessionRepo := tx.AccountSessionRepository()
 
 	defer func() {
 		if err != nil {
@@ -204,7 +220,7 @@ func (u *partnerUsecaseImpl) DeleteOnePartner(ctx context.Context, pharmacyManag
 		tx.Commit()
 	}()
 
-	if err = pharmacyManagerRepo.DeleteOneById(ctx, pharmacyManagerId); err != nil {
+	if err = pharmacyManagerRepo.DeleteOneById(ctx, pharmacyManager.Id); err != nil {
 		return apperror.InternalServerError(err)
 	}
 
@@ -212,6 +228,14 @@ func (u *partnerUsecaseImpl) DeleteOnePartner(ctx context.Context, pharmacyManag
 		return apperror.InternalServerError(err)
 	}
 
+	if err = accountRepo.IncrementTokenVersionById(ctx, pharmacyManager.Account.Id); err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	if err = accountSessionRepo.RevokeAllByAccountId(ctx, pharmacyManager.Account.Id); err != nil {
+		return apperror.InternalServerError(err)
+	}
+
 	return nil
 }
 
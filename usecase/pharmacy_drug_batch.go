@@ -0,0 +1,170 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/sidiqPratomo/max-health-backend/appconstant"
+	"github.com/sidiqPratomo/max-health-backend/apperror"
+	"github.com/sidiqPratomo/max-health-backend/dto"
+	"github.com/sidiqPratomo/max-health-backend/entity"
+	"github.com/sidiqPratomo/max-health-backend/repository"
+)
+
+func newReceivedBatch(pharmacyDrugId int64, batchRequest dto.PharmacyDrugBatchRequest) (*entity.PharmacyDrugBatch, error) {
+	expiryDate, err := time.Parse(appconstant.BatchExpiryDateFormat, batchRequest.ExpiryDate)
+	if err != nil {
+		return nil, apperror.BadRequestError(err)
+	}
+
+	if expiryDate.Before(time.Now().UTC().Truncate(24 * time.Hour)) {
+		return nil, apperror.BatchExpiredError()
+	}
+
+	return &entity.PharmacyDrugBatch{
+		PharmacyDrugId: pharmacyDrugId,
+		BatchNumber:    strings.TrimSpace(batchRequest.BatchNumber),
+		ExpiryDate:     &expiryDate,
+	}, nil
+}
+
+// A pharmacy drug's stock is the stock of its batches minus the backorders
+// still waiting for one. Stock is taken first-expiry-first-out, whatever the
+// batches cannot cover is returned to the caller.
+func allocateBatches(ctx context.Context, pharmacyDrugBatchRepo repository.PharmacyDrugBatchRepository, pharmacyDrugId int64, quantity int, allocation entity.PharmacyDrugBatchAllocation) ([]entity.PharmacyDrugBatchAllocation, int, error) {
+	pharmacyDrugBatches, err := pharmacyDrugBatchRepo.FindAllAvailableForUpdate(ctx, pharmacyDrugId)
+	if err != nil {
+		return nil, 0, apperror.InternalServerError(err)
+	}
+
+	allocations := []entity.PharmacyDrugBatchAllocation{}
+	remaining := quantity
+
+	for _, pharmacyDrugBatch := range pharmacyDrugBatches {
+		if remaining == 0 {
+			break
+		}
+
+		taken := pharmacyDrugBatch.Stock
+		if taken > remaining {
+			taken = remaining
+		}
+
+		_, err = pharmacyDrugBatchRepo.DeductStock(ctx, pharmacyDrugBatch.Id, taken)
+		if err != nil {
+			return nil, 0, apperror.InternalServerError(err)
+		}
+
+		pharmacyDrugBatchId := pharmacyDrugBatch.Id
+		batchAllocation := allocation
+		batchAllocation.PharmacyDrugId = pharmacyDrugId
+		batchAllocation.PharmacyDrugBatchId = &pharmacyDrugBatchId
+		batchAllocation.BatchNumber = pharmacyDrugBatch.BatchNumber
+		batchAllocation.ExpiryDate = pharmacyDrugBatch.ExpiryDate
+		batchAllocation.Quantity = taken
+
+		allocations = append(allocations, batchAllocation)
+		remaining -= taken
+	}
+
+	err = pharmacyDrugBatchRepo.PostAllocations(ctx, allocations)
+	if err != nil {
+		return nil, 0, apperror.InternalServerError(err)
+	}
+
+	return allocations, remaining, nil
+}
+
+func deductBatches(ctx context.Context, pharmacyDrugBatchRepo repository.PharmacyDrugBatchRepository, pharmacyDrugId int64, quantity int, allocation entity.PharmacyDrugBatchAllocation) ([]entity.PharmacyDrugBatchAllocation, error) {
+	allocations, remaining, err := allocateBatches(ctx, pharmacyDrugBatchRepo, pharmacyDrugId, quantity, allocation)
+	if err != nil {
+		return nil, err
+	}
+	if remaining > 0 {
+		return nil, apperror.InsufficientStockError()
+	}
+
+	return allocations, nil
+}
+
+// Items the pharmacy sold beyond its shelf stock are kept as backorders and
+// get their batches once stock comes in.
+func allocateOrderPharmacyBatches(ctx context.Context, pharmacyDrugBatchRepo repository.PharmacyDrugBatchRepository, orderPharmacies []entity.OrderPharmacyForCheckout) error {
+	for _, orderPharmacy := range orderPharmacies {
+		orderPharmacyId := orderPharmacy.Id
+
+		for _, cartItem := range orderPharmacy.CartItems {
+			_, remaining, err := allocateBatches(ctx, pharmacyDrugBatchRepo, cartItem.PharmacyDrugId, cartItem.Quantity,
+				entity.PharmacyDrugBatchAllocation{OrderPharmacyId: &orderPharmacyId})
+			if err != nil {
+				return err
+			}
+
+			if remaining == 0 {
+				continue
+			}
+
+			backorder := entity.PharmacyDrugBatchAllocation{PharmacyDrugId: cartItem.PharmacyDrugId, OrderPharmacyId: &orderPharmacyId, Quantity: remaining}
+			err = pharmacyDrugBatchRepo.PostAllocations(ctx, []entity.PharmacyDrugBatchAllocation{backorder})
+			if err != nil {
+				return apperror.InternalServerError(err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Received stock goes on the shelf first and is then handed to the oldest
+// backorders of the pharmacy drug.
+func restockBatch(ctx context.Context, pharmacyDrugBatchRepo repository.PharmacyDrugBatchRepository, pharmacyDrugBatch entity.PharmacyDrugBatch) error {
+	expiryDate := pharmacyDrugBatch.ExpiryDate
+
+	err := pharmacyDrugBatchRepo.UpsertStock(ctx, &pharmacyDrugBatch)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	if !isSameExpiryDate(expiryDate, pharmacyDrugBatch.ExpiryDate) {
+		return apperror.BatchExpiryMismatchError()
+	}
+
+	return fillBackorders(ctx, pharmacyDrugBatchRepo, pharmacyDrugBatch.PharmacyDrugId)
+}
+
+func fillBackorders(ctx context.Context, pharmacyDrugBatchRepo repository.PharmacyDrugBatchRepository, pharmacyDrugId int64) error {
+	backorders, err := pharmacyDrugBatchRepo.FindAllPendingAllocationsForUpdate(ctx, pharmacyDrugId)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	for _, backorder := range backorders {
+		_, remaining, err := allocateBatches(ctx, pharmacyDrugBatchRepo, pharmacyDrugId, backorder.Quantity,
+			entity.PharmacyDrugBatchAllocation{OrderPharmacyId: backorder.OrderPharmacyId, StockMutationRequestId: backorder.StockMutationRequestId})
+		if err != nil {
+			return err
+		}
+
+		if filled := backorder.Quantity - remaining; filled > 0 {
+			_, err = pharmacyDrugBatchRepo.ReducePendingAllocationOne(ctx, backorder.Id, filled)
+			if err != nil {
+				return apperror.InternalServerError(err)
+			}
+		}
+
+		if remaining > 0 {
+			break
+		}
+	}
+
+	return nil
+}
+
+func isSameExpiryDate(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	return a.Format(appconstant.BatchExpiryDateFormat) == b.Format(appconstant.BatchExpiryDateFormat)
+}
@@ -0,0 +1,471 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/sidiqPratomo/max-health-backend/apperror"
+	"github.com/sidiqPratomo/max-health-backend/dto"
+	"github.com/sidiqPratomo/max-health-backend/entity"
+	"github.com/sidiqPratomo/max-health-backend/repository"
+)
+
+// fakePharmacyDrugBatchRepository keeps batches and allocations in memory and
+// answers the same way the batch queries do: usable batches are the ones
+// with stock that have not expired, first-expiry-first-out and batches without
+// an expiry date last.
+type fakePharmacyDrugBatchRepository struct {
+	repository.PharmacyDrugBatchRepository
+	batches          []entity.PharmacyDrugBatch
+	allocations      []entity.PharmacyDrugBatchAllocation
+	nextId           int64
+	nextAllocationId int64
+}
+
+func testToday() time.Time {
+	return time.Now().UTC().Truncate(24 * time.Hour)
+}
+
+func testExpiryDate(days int) *time.Time {
+	expiryDate := testToday().AddDate(0, 0, days)
+	return &expiryDate
+}
+
+func isBatchExpired(pharmacyDrugBatch entity.PharmacyDrugBatch) bool {
+	return pharmacyDrugBatch.ExpiryDate != nil && pharmacyDrugBatch.ExpiryDate.Before(testToday())
+}
+
+func (r *fakePharmacyDrugBatchRepository) find(pharmacyDrugBatchId int64) *entity.PharmacyDrugBatch {
+	for i := range r.batches {
+		if r.batches[i].Id == pharmacyDrugBatchId {
+			return &r.batches[i]
+		}
+	}
+	return nil
+}
+
+func (r *fakePharmacyDrugBatchRepository) FindAllByPharmacyDrugId(ctx context.Context, pharmacyDrugId int64) ([]entity.PharmacyDrugBatch, error) {
+	pharmacyDrugBatches := []entity.PharmacyDrugBatch{}
+	for _, pharmacyDrugBatch := range r.batches {
+		if pharmacyDrugBatch.PharmacyDrugId == pharmacyDrugId {
+			pharmacyDrugBatches = append(pharmacyDrugBatches, pharmacyDrugBatch)
+		}
+	}
+
+	sort.SliceStable(pharmacyDrugBatches, func(i, j int) bool {
+		a, b := pharmacyDrugBatches[i], pharmacyDrugBatches[j]
+		if a.ExpiryDate == nil || b.ExpiryDate == nil {
+			return a.ExpiryDate != nil && b.ExpiryDate == nil
+		}
+		if !a.ExpiryDate.Equal(*b.ExpiryDate) {
+			return a.ExpiryDate.Before(*b.ExpiryDate)
+		}
+		return a.Id < b.Id
+	})
+
+	return pharmacyDrugBatches, nil
+}
+
+func (r *fakePharmacyDrugBatchRepository) FindAllAvailableForUpdate(ctx context.Context, pharmacyDrugId int64) ([]entity.PharmacyDrugBatch, error) {
+	pharmacyDrugBatches, _ := r.FindAllByPharmacyDrugId(ctx, pharmacyDrugId)
+
+	availableBatches := []entity.PharmacyDrugBatch{}
+	for _, pharmacyDrugBatch := range pharmacyDrugBatches {
+		if pharmacyDrugBatch.Stock > 0 && !isBatchExpired(pharmacyDrugBatch) {
+			availableBatches = append(availableBatches, pharmacyDrugBatch)
+		}
+	}
+
+	return availableBatches, nil
+}
+
+func (r *fakePharmacyDrugBatchRepository) FindAllExpired(ctx context.Context, limit int) ([]entity.PharmacyDrugBatch, error) {
+	expiredBatches := []entity.PharmacyDrugBatch{}
+	for _, pharmacyDrugBatch := range r.batches {
+		if pharmacyDrugBatch.Stock > 0 && isBatchExpired(pharmacyDrugBatch) && len(expiredBatches) < limit {
+			expiredBatches = append(expiredBatches, pharmacyDrugBatch)
+		}
+	}
+
+	return expiredBatches, nil
+}
+
+func (r *fakePharmacyDrugBatchRepository) UpsertStock(ctx context.Context, pharmacyDrugBatch *entity.PharmacyDrugBatch) error {
+	for i := range r.batches {
+		existingBatch := &r.batches[i]
+		if existingBatch.PharmacyDrugId == pharmacyDrugBatch.PharmacyDrugId && existingBatch.BatchNumber == pharmacyDrugBatch.BatchNumber {
+			existingBatch.Stock += pharmacyDrugBatch.Stock
+			pharmacyDrugBatch.Id = existingBatch.Id
+			pharmacyDrugBatch.ExpiryDate = existingBatch.ExpiryDate
+			return nil
+		}
+	}
+
+	r.nextId++
+	pharmacyDrugBatch.Id = 100 + r.nextId
+	r.batches = append(r.batches, *pharmacyDrugBatch)
+
+	return nil
+}
+
+func (r *fakePharmacyDrugBatchRepository) DeductStock(ctx context.Context, pharmacyDrugBatchId int64, quantity int) (int64, error) {
+	r.find(pharmacyDrugBatchId).Stock -= quantity
+	return 1, nil
+}
+
+func (r *fakePharmacyDrugBatchRepository) WriteOffOne(ctx context.Context, pharmacyDrugBatchId int64) (int, error) {
+	pharmacyDrugBatch := r.find(pharmacyDrugBatchId)
+	if pharmacyDrugBatch.Stock == 0 || !isBatchExpired(*pharmacyDrugBatch) {
+		return 0, nil
+	}
+
+	writtenOffStock := pharmacyDrugBatch.Stock
+	pharmacyDrugBatch.Stock = 0
+
+	return writtenOffStock, nil
+}
+
+func (r *fakePharmacyDrugBatchRepository) PostAllocations(ctx context.Context, allocations []entity.PharmacyDrugBatchAllocation) error {
+	for _, allocation := range allocations {
+		r.nextAllocationId++
+		allocation.Id = r.nextAllocationId
+		r.allocations = append(r.allocations, allocation)
+	}
+
+	return nil
+}
+
+func (r *fakePharmacyDrugBatchRepository) FindAllPendingAllocationsForUpdate(ctx context.Context, pharmacyDrugId int64) ([]entity.PharmacyDrugBatchAllocation, error) {
+	backorders := []entity.PharmacyDrugBatchAllocation{}
+	for _, allocation := range r.allocations {
+		if allocation.PharmacyDrugId == pharmacyDrugId && allocation.PharmacyDrugBatchId == nil && allocation.Quantity > 0 {
+			backorders = append(backorders, allocation)
+		}
+	}
+
+	return backorders, nil
+}
+
+func (r *fakePharmacyDrugBatchRepository) ReducePendingAllocationOne(ctx context.Context, allocationId int64, quantity int) (int64, error) {
+	for i := range r.allocations {
+		if r.allocations[i].Id == allocationId {
+			r.allocations[i].Quantity -= quantity
+			return 1, nil
+		}
+	}
+
+	return 0, nil
+}
+
+type batchQuantity struct {
+	batchId  int64
+	quantity int
+}
+
+func allocationQuantities(allocations []entity.PharmacyDrugBatchAllocation) []batchQuantity {
+	quantities := []batchQuantity{}
+	for _, allocation := range allocations {
+		batchId := int64(0)
+		if allocation.PharmacyDrugBatchId != nil {
+			batchId = *allocation.PharmacyDrugBatchId
+		}
+		quantities = append(quantities, batchQuantity{batchId: batchId, quantity: allocation.Quantity})
+	}
+	return quantities
+}
+
+func batchStocks(pharmacyDrugBatches []entity.PharmacyDrugBatch) []batchQuantity {
+	stocks := []batchQuantity{}
+	for _, pharmacyDrugBatch := range pharmacyDrugBatches {
+		stocks = append(stocks, batchQuantity{batchId: pharmacyDrugBatch.Id, quantity: pharmacyDrugBatch.Stock})
+	}
+	return stocks
+}
+
+func equalBatchQuantities(a, b []batchQuantity) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func isAppError(err error, want *apperror.AppError) bool {
+	appErr, ok := err.(*apperror.AppError)
+	return ok && appErr.Code == want.Code && appErr.Message == want.Message
+}
+
+func TestDeductBatches(t *testing.T) {
+	tests := []struct {
+		name       string
+		batches    []entity.PharmacyDrugBatch
+		quantity   int
+		wantTaken  []batchQuantity
+		wantStocks []batchQuantity
+		wantErr    *apperror.AppError
+	}{
+		{
+			name: "takes the batch expiring first",
+			batches: []entity.PharmacyDrugBatch{
+				{Id: 1, PharmacyDrugId: 7, BatchNumber: "B-01", ExpiryDate: testExpiryDate(30), Stock: 5},
+				{Id: 2, PharmacyDrugId: 7, BatchNumber: "B-02", ExpiryDate: testExpiryDate(10), Stock: 5},
+			},
+			quantity:   3,
+			wantTaken:  []batchQuantity{{2, 3}},
+			wantStocks: []batchQuantity{{2, 2}, {1, 5}},
+		},
+		{
+			name: "spreads across batches and takes undated stock last",
+			batches: []entity.PharmacyDrugBatch{
+				{Id: 1, PharmacyDrugId: 7, BatchNumber: "B-01", ExpiryDate: testExpiryDate(30), Stock: 2},
+				{Id: 2, PharmacyDrugId: 7, BatchNumber: "B-02", ExpiryDate: testExpiryDate(10), Stock: 3},
+				{Id: 3, PharmacyDrugId: 7, BatchNumber: "LEGACY", Stock: 5},
+			},
+			quantity:   6,
+			wantTaken:  []batchQuantity{{2, 3}, {1, 2}, {3, 1}},
+			wantStocks: []batchQuantity{{2, 0}, {1, 0}, {3, 4}},
+		},
+		{
+			name: "batches expiring on the same day go by batch",
+			batches: []entity.PharmacyDrugBatch{
+				{Id: 2, PharmacyDrugId: 7, BatchNumber: "B-02", ExpiryDate: testExpiryDate(10), Stock: 3},
+				{Id: 1, PharmacyDrugId: 7, BatchNumber: "B-01", ExpiryDate: testExpiryDate(10), Stock: 3},
+			},
+			quantity:   4,
+			wantTaken:  []batchQuantity{{1, 3}, {2, 1}},
+			wantStocks: []batchQuantity{{1, 0}, {2, 2}},
+		},
+		{
+			name: "skips expired batches",
+			batches: []entity.PharmacyDrugBatch{
+				{Id: 1, PharmacyDrugId: 7, BatchNumber: "B-01", ExpiryDate: testExpiryDate(-1), Stock: 10},
+				{Id: 2, PharmacyDrugId: 7, BatchNumber: "B-02", ExpiryDate: testExpiryDate(0), Stock: 2},
+			},
+			quantity:   2,
+			wantTaken:  []batchQuantity{{2, 2}},
+			wantStocks: []batchQuantity{{1, 10}, {2, 0}},
+		},
+		{
+			name: "expired stock does not cover a deduction",
+			batches: []entity.PharmacyDrugBatch{
+				{Id: 1, PharmacyDrugId: 7, BatchNumber: "B-01", ExpiryDate: testExpiryDate(-1), Stock: 10},
+				{Id: 2, PharmacyDrugId: 7, BatchNumber: "B-02", ExpiryDate: testExpiryDate(5), Stock: 2},
+			},
+			quantity: 3,
+			wantErr:  apperror.InsufficientStockError(),
+		},
+		{
+			name: "ignores other pharmacy drugs",
+			batches: []entity.PharmacyDrugBatch{
+				{Id: 1, PharmacyDrugId: 8, BatchNumber: "B-01", ExpiryDate: testExpiryDate(1), Stock: 10},
+				{Id: 2, PharmacyDrugId: 7, BatchNumber: "B-02", ExpiryDate: testExpiryDate(5), Stock: 2},
+			},
+			quantity:   2,
+			wantTaken:  []batchQuantity{{2, 2}},
+			wantStocks: []batchQuantity{{2, 0}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pharmacyDrugBatchRepo := &fakePharmacyDrugBatchRepository{batches: tt.batches}
+			orderPharmacyId := int64(11)
+
+			allocations, err := deductBatches(context.Background(), pharmacyDrugBatchRepo, 7, tt.quantity, entity.PharmacyDrugBatchAllocation{OrderPharmacyId: &orderPharmacyId})
+			if tt.wantErr != nil {
+				if !isAppError(err, tt.wantErr) {
+					t.Fatalf("deductBatches() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("deductBatches() error = %v", err)
+			}
+
+			if got := allocationQuantities(allocations); !equalBatchQuantities(got, tt.wantTaken) {
+				t.Errorf("deductBatches() took %v, want %v", got, tt.wantTaken)
+			}
+			if got := allocationQuantities(pharmacyDrugBatchRepo.allocations); !equalBatchQuantities(got, tt.wantTaken) {
+				t.Errorf("deductBatches() posted %v, want %v", got, tt.wantTaken)
+			}
+			for _, allocation := range allocations {
+				if allocation.PharmacyDrugId != 7 || allocation.OrderPharmacyId == nil || *allocation.OrderPharmacyId != orderPharmacyId {
+					t.Errorf("deductBatches() allocation = %+v, want it linked to pharmacy drug 7 and order pharmacy %d", allocation, orderPharmacyId)
+				}
+				if allocation.BatchNumber != pharmacyDrugBatchRepo.find(*allocation.PharmacyDrugBatchId).BatchNumber {
+					t.Errorf("deductBatches() allocation batch number = %q, want the number of batch %d", allocation.BatchNumber, *allocation.PharmacyDrugBatchId)
+				}
+			}
+
+			pharmacyDrugBatches, _ := pharmacyDrugBatchRepo.FindAllByPharmacyDrugId(context.Background(), 7)
+			if got := batchStocks(pharmacyDrugBatches); !equalBatchQuantities(got, tt.wantStocks) {
+				t.Errorf("batch stocks = %v, want %v", got, tt.wantStocks)
+			}
+		})
+	}
+}
+
+func TestAllocateOrderPharmacyBatchesBackorders(t *testing.T) {
+	pharmacyDrugBatchRepo := &fakePharmacyDrugBatchRepository{batches: []entity.PharmacyDrugBatch{
+		{Id: 1, PharmacyDrugId: 7, BatchNumber: "B-01", ExpiryDate: testExpiryDate(-2), Stock: 4},
+		{Id: 2, PharmacyDrugId: 7, BatchNumber: "B-02", ExpiryDate: testExpiryDate(20), Stock: 2},
+	}}
+	orderPharmacies := []entity.OrderPharmacyForCheckout{
+		{Id: 11, CartItems: []entity.CartItemForCheckout{{PharmacyDrugId: 7, Quantity: 5}}},
+	}
+
+	err := allocateOrderPharmacyBatches(context.Background(), pharmacyDrugBatchRepo, orderPharmacies)
+	if err != nil {
+		t.Fatalf("allocateOrderPharmacyBatches() error = %v", err)
+	}
+
+	want := []batchQuantity{{2, 2}, {0, 3}}
+	if got := allocationQuantities(pharmacyDrugBatchRepo.allocations); !equalBatchQuantities(got, want) {
+		t.Errorf("allocateOrderPharmacyBatches() posted %v, want %v", got, want)
+	}
+	for _, allocation := range pharmacyDrugBatchRepo.allocations {
+		if allocation.OrderPharmacyId == nil || *allocation.OrderPharmacyId != 11 {
+			t.Errorf("allocation = %+v, want it linked to order pharmacy 11", allocation)
+		}
+	}
+}
+
+func TestRestockBatch(t *testing.T) {
+	firstOrderPharmacyId, secondOrderPharmacyId := int64(11), int64(12)
+
+	tests := []struct {
+		name            string
+		batches         []entity.PharmacyDrugBatch
+		backorders      []entity.PharmacyDrugBatchAllocation
+		received        entity.PharmacyDrugBatch
+		wantAllocations []batchQuantity
+		wantPending     []batchQuantity
+		wantStocks      []batchQuantity
+		wantErr         *apperror.AppError
+	}{
+		{
+			name: "partially fills the backorders oldest first",
+			backorders: []entity.PharmacyDrugBatchAllocation{
+				{PharmacyDrugId: 7, OrderPharmacyId: &firstOrderPharmacyId, Quantity: 4},
+				{PharmacyDrugId: 7, OrderPharmacyId: &secondOrderPharmacyId, Quantity: 3},
+			},
+			received:        entity.PharmacyDrugBatch{PharmacyDrugId: 7, BatchNumber: "B-01", ExpiryDate: testExpiryDate(30), Stock: 5},
+			wantAllocations: []batchQuantity{{101, 4}, {101, 1}},
+			wantPending:     []batchQuantity{{0, 2}},
+			wantStocks:      []batchQuantity{{101, 0}},
+		},
+		{
+			name: "keeps what is left on the shelf",
+			backorders: []entity.PharmacyDrugBatchAllocation{
+				{PharmacyDrugId: 7, OrderPharmacyId: &firstOrderPharmacyId, Quantity: 2},
+			},
+			received:        entity.PharmacyDrugBatch{PharmacyDrugId: 7, BatchNumber: "B-01", ExpiryDate: testExpiryDate(30), Stock: 5},
+			wantAllocations: []batchQuantity{{101, 2}},
+			wantPending:     []batchQuantity{},
+			wantStocks:      []batchQuantity{{101, 3}},
+		},
+		{
+			name: "backorders take older stock before the received batch",
+			batches: []entity.PharmacyDrugBatch{
+				{Id: 1, PharmacyDrugId: 7, BatchNumber: "B-00", ExpiryDate: testExpiryDate(10), Stock: 1},
+			},
+			backorders: []entity.PharmacyDrugBatchAllocation{
+				{PharmacyDrugId: 7, OrderPharmacyId: &firstOrderPharmacyId, Quantity: 3},
+			},
+			received:        entity.PharmacyDrugBatch{PharmacyDrugId: 7, BatchNumber: "B-01", ExpiryDate: testExpiryDate(30), Stock: 5},
+			wantAllocations: []batchQuantity{{1, 1}, {101, 2}},
+			wantPending:     []batchQuantity{},
+			wantStocks:      []batchQuantity{{1, 0}, {101, 3}},
+		},
+		{
+			name: "adds to a batch already on the shelf",
+			batches: []entity.PharmacyDrugBatch{
+				{Id: 1, PharmacyDrugId: 7, BatchNumber: "B-01", ExpiryDate: testExpiryDate(30), Stock: 1},
+			},
+			received:        entity.PharmacyDrugBatch{PharmacyDrugId: 7, BatchNumber: "B-01", ExpiryDate: testExpiryDate(30), Stock: 2},
+			wantAllocations: []batchQuantity{},
+			wantPending:     []batchQuantity{},
+			wantStocks:      []batchQuantity{{1, 3}},
+		},
+		{
+			name: "rejects another expiry date for the same batch",
+			batches: []entity.PharmacyDrugBatch{
+				{Id: 1, PharmacyDrugId: 7, BatchNumber: "B-01", ExpiryDate: testExpiryDate(30), Stock: 1},
+			},
+			received: entity.PharmacyDrugBatch{PharmacyDrugId: 7, BatchNumber: "B-01", ExpiryDate: testExpiryDate(60), Stock: 2},
+			wantErr:  apperror.BatchExpiryMismatchError(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pharmacyDrugBatchRepo := &fakePharmacyDrugBatchRepository{batches: tt.batches}
+			_ = pharmacyDrugBatchRepo.PostAllocations(context.Background(), tt.backorders)
+
+			err := restockBatch(context.Background(), pharmacyDrugBatchRepo, tt.received)
+			if tt.wantErr != nil {
+				if !isAppError(err, tt.wantErr) {
+					t.Fatalf("restockBatch() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("restockBatch() error = %v", err)
+			}
+
+			newAllocations := pharmacyDrugBatchRepo.allocations[len(tt.backorders):]
+			if got := allocationQuantities(newAllocations); !equalBatchQuantities(got, tt.wantAllocations) {
+				t.Errorf("restockBatch() allocated %v, want %v", got, tt.wantAllocations)
+			}
+
+			pending, _ := pharmacyDrugBatchRepo.FindAllPendingAllocationsForUpdate(context.Background(), 7)
+			if got := allocationQuantities(pending); !equalBatchQuantities(got, tt.wantPending) {
+				t.Errorf("pending backorders = %v, want %v", got, tt.wantPending)
+			}
+
+			pharmacyDrugBatches, _ := pharmacyDrugBatchRepo.FindAllByPharmacyDrugId(context.Background(), 7)
+			if got := batchStocks(pharmacyDrugBatches); !equalBatchQuantities(got, tt.wantStocks) {
+				t.Errorf("batch stocks = %v, want %v", got, tt.wantStocks)
+			}
+		})
+	}
+}
+
+func TestNewReceivedBatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		expiryDate string
+		wantCode   int
+	}{
+		{name: "expires today", expiryDate: testToday().Format("2006-01-02")},
+		{name: "expires next year", expiryDate: testToday().AddDate(1, 0, 0).Format("2006-01-02")},
+		{name: "already expired", expiryDate: testToday().AddDate(0, 0, -1).Format("2006-01-02"), wantCode: apperror.BatchExpiredError().Code},
+		{name: "not a date", expiryDate: "17/05/2030", wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pharmacyDrugBatch, err := newReceivedBatch(7, dto.PharmacyDrugBatchRequest{BatchNumber: " B-01 ", ExpiryDate: tt.expiryDate})
+			if tt.wantCode != 0 {
+				appErr, ok := err.(*apperror.AppError)
+				if !ok || appErr.Code != tt.wantCode {
+					t.Fatalf("newReceivedBatch() error = %v, want code %d", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("newReceivedBatch() error = %v", err)
+			}
+			if pharmacyDrugBatch.BatchNumber != "B-01" || pharmacyDrugBatch.PharmacyDrugId != 7 {
+				t.Errorf("newReceivedBatch() = %+v, want batch B-01 of pharmacy drug 7", pharmacyDrugBatch)
+			}
+		})
+	}
+}
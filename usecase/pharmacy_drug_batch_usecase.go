@@ -0,0 +1,267 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/sidiqPratomo/max-health-backend/appconstant"
+	"github.com/sidiqPratomo/max-health-backend/apperror"
+	"github.com/sidiqPratomo/max-health-backend/dto"
+	"github.com/sidiqPratomo/max-health-backend/entity"
+	"github.com/sidiqPratomo/max-health-backend/repository"
+	"github.com/sidiqPratomo/max-health-backend/util"
+)
+
+const expiredPharmacyDrugBatchSize = 50
+
+type PharmacyDrugBatchUsecase interface {
+	GetPharmacyDrugBatches(ctx context.Context, accountId, pharmacyDrugId int64) ([]dto.PharmacyDrugBatchResponse, error)
+	ReceivePharmacyDrugBatch(ctx context.Context, accountId, pharmacyDrugId int64, receiveRequest dto.ReceivePharmacyDrugBatchRequest) ([]dto.PharmacyDrugBatchResponse, error)
+	GetExpiringBatches(ctx context.Context, accountId int64, query dto.ExpiringBatchQuery) (*dto.AllExpiringBatchesResponse, error)
+	WriteOffExpiredBatches(ctx context.Context) (int, error)
+}
+
+type pharmacyDrugBatchUsecaseImpl struct {
+	pharmacyDrugBatchRepository repository.PharmacyDrugBatchRepository
+	pharmacyDrugRepository      repository.PharmacyDrugRepository
+	pharmacyRepository          repository.PharmacyRepository
+	pharmacyManagerRepository   repository.PharmacyManagerRepository
+	transaction                 repository.Transaction
+}
+
+func NewPharmacyDrugBatchUsecaseImpl(pharmacyDrugBatchRepository repository.PharmacyDrugBatchRepository, pharmacyDrugRepository repository.PharmacyDrugRepository, pharmacyRepository repository.PharmacyRepository, pharmacyManagerRepository repository.PharmacyManagerRepository, transaction repository.Transaction) pharmacyDrugBatchUsecaseImpl {
+	return pharmacyDrugBatchUsecaseImpl{
+		pharmacyDrugBatchRepository: pharmacyDrugBatchRepository,
+		pharmacyDrugRepository:      pharmacyDrugRepository,
+		pharmacyRepository:          pharmacyRepository,
+		pharmacyManagerRepository:   pharmacyManagerRepository,
+		transaction:                 transaction,
+	}
+}
+
+func (u *pharmacyDrugBatchUsecaseImpl) findManager(ctx context.Context, accountId int64) (*entity.PharmacyManager, error) {
+	manager, err := u.pharmacyManagerRepository.FindOneByAccountId(ctx, accountId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+	if manager == nil {
+		return nil, apperror.PharmacyManagerNotFoundError()
+	}
+
+	return manager, nil
+}
+
+func (u *pharmacyDrugBatchUsecaseImpl) checkPharmacyDrugOwnership(ctx context.Context, accountId, pharmacyDrugId int64) error {
+	manager, err := u.findManager(ctx, accountId)
+	if err != nil {
+		return err
+	}
+
+	return checkPharmacyDrugManager(ctx, u.pharmacyDrugRepository, u.pharmacyRepository, manager.Id, pharmacyDrugId)
+}
+
+func checkPharmacyDrugManager(ctx context.Context, pharmacyDrugRepository repository.PharmacyDrugRepository, pharmacyRepository repository.PharmacyRepository, managerId, pharmacyDrugId int64) error {
+	pharmacyDrug, err := pharmacyDrugRepository.GetPharmacyDrugById(ctx, pharmacyDrugId)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+	if pharmacyDrug == nil {
+		return apperror.DrugNotFoundError()
+	}
+
+	pharmacy, err := pharmacyRepository.GetOnePharmacyByPharmacyId(ctx, pharmacyDrug.PharmacyId)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+	if pharmacy == nil || pharmacy.PharmacyManagerId != managerId {
+		return apperror.ForbiddenAction()
+	}
+
+	return nil
+}
+
+func (u *pharmacyDrugBatchUsecaseImpl) GetPharmacyDrugBatches(ctx context.Context, accountId, pharmacyDrugId int64) ([]dto.PharmacyDrugBatchResponse, error) {
+	err := u.checkPharmacyDrugOwnership(ctx, accountId, pharmacyDrugId)
+	if err != nil {
+		return nil, err
+	}
+
+	pharmacyDrugBatches, err := u.pharmacyDrugBatchRepository.FindAllByPharmacyDrugId(ctx, pharmacyDrugId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	return dto.ConvertToPharmacyDrugBatchesResponse(pharmacyDrugBatches), nil
+}
+
+func (u *pharmacyDrugBatchUsecaseImpl) ReceivePharmacyDrugBatch(ctx context.Context, accountId, pharmacyDrugId int64, receiveRequest dto.ReceivePharmacyDrugBatchRequest) ([]dto.PharmacyDrugBatchResponse, error) {
+	err := u.checkPharmacyDrugOwnership(ctx, accountId, pharmacyDrugId)
+	if err != nil {
+		return nil, err
+	}
+
+	receivedBatch, err := newReceivedBatch(pharmacyDrugId, receiveRequest.PharmacyDrugBatchRequest)
+	if err != nil {
+		return nil, err
+	}
+	receivedBatch.Stock = receiveRequest.Stock
+
+	tx, err := u.transaction.BeginTx(ctx)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	pharmacyDrugRepo := tx.PharmacyDrugRepo()
+	stockChangeRepo := tx.StockChangeRepo()
+	pharmacyDrugBatchRepo := tx.PharmacyDrugBatchRepository()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+
+		tx.Commit()
+	}()
+
+	pharmacyDrug, err := pharmacyDrugRepo.GetPharmacyDrugByIdForUpdate(ctx, pharmacyDrugId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+	if pharmacyDrug == nil {
+		err = apperror.DrugNotFoundError()
+		return nil, err
+	}
+
+	err = restockBatch(ctx, pharmacyDrugBatchRepo, *receivedBatch)
+	if err != nil {
+		return nil, err
+	}
+
+	finalStock, err := pharmacyDrugRepo.UpdateStockByAmount(ctx, pharmacyDrugId, receivedBatch.Stock)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	stockChange := entity.StockChange{PharmacyDrugId: pharmacyDrugId, FinalStock: finalStock, Amount: receivedBatch.Stock,
+		Description: fmt.Sprintf(appconstant.BatchReceivedDescription, receivedBatch.BatchNumber),
+		ChangeType:  appconstant.StockChangeTypeManualAdjustment, ActorAccountId: &accountId}
+	err = stockChangeRepo.PostStockChangesFromUpdate(ctx, []entity.StockChange{stockChange})
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	pharmacyDrugBatches, err := pharmacyDrugBatchRepo.FindAllByPharmacyDrugId(ctx, pharmacyDrugId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	return dto.ConvertToPharmacyDrugBatchesResponse(pharmacyDrugBatches), nil
+}
+
+func (u *pharmacyDrugBatchUsecaseImpl) GetExpiringBatches(ctx context.Context, accountId int64, query dto.ExpiringBatchQuery) (*dto.AllExpiringBatchesResponse, error) {
+	manager, err := u.findManager(ctx, accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	params, err := util.SetDefaultQueryParams(util.QueryParam{Page: query.Page, Limit: query.Limit})
+	if err != nil {
+		return nil, err
+	}
+
+	expiringBatchFilter := entity.ExpiringBatchFilter{
+		ManagerId:  manager.Id,
+		PharmacyId: query.PharmacyId,
+		Days:       appconstant.DefaultExpiringBatchDays,
+	}
+	if query.Days != nil {
+		expiringBatchFilter.Days = *query.Days
+	}
+
+	expiringBatchFilter.Limit, _ = strconv.Atoi(params.Limit)
+	expiringBatchFilter.Offset, _ = strconv.Atoi(params.Offset)
+
+	expiringBatches, pageInfo, err := u.pharmacyDrugBatchRepository.FindAllExpiring(ctx, expiringBatchFilter)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	response := dto.ConvertToAllExpiringBatchesResponse(expiringBatches, *pageInfo)
+
+	return &response, nil
+}
+
+func (u *pharmacyDrugBatchUsecaseImpl) WriteOffExpiredBatches(ctx context.Context) (int, error) {
+	writtenOffCount := 0
+
+	for {
+		expiredBatches, err := u.pharmacyDrugBatchRepository.FindAllExpired(ctx, expiredPharmacyDrugBatchSize)
+		if err != nil {
+			return writtenOffCount, apperror.InternalServerError(err)
+		}
+
+		for _, expiredBatch := range expiredBatches {
+			isWrittenOff, err := u.writeOffExpiredBatch(ctx, expiredBatch)
+			if err != nil {
+				return writtenOffCount, err
+			}
+			if isWrittenOff {
+				writtenOffCount++
+			}
+		}
+
+		if len(expiredBatches) < expiredPharmacyDrugBatchSize {
+			return writtenOffCount, nil
+		}
+	}
+}
+
+// The pharmacy drug is locked before its batch, the same order checkout and
+// restocking take them in.
+func (u *pharmacyDrugBatchUsecaseImpl) writeOffExpiredBatch(ctx context.Context, expiredBatch entity.PharmacyDrugBatch) (bool, error) {
+	tx, err := u.transaction.BeginTx(ctx)
+	if err != nil {
+		return false, apperror.InternalServerError(err)
+	}
+
+	pharmacyDrugRepo := tx.PharmacyDrugRepo()
+	stockChangeRepo := tx.StockChangeRepo()
+	pharmacyDrugBatchRepo := tx.PharmacyDrugBatchRepository()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+
+		tx.Commit()
+	}()
+
+	_, err = pharmacyDrugRepo.GetPharmacyDrugByIdForUpdate(ctx, expiredBatch.PharmacyDrugId)
+	if err != nil {
+		return false, apperror.InternalServerError(err)
+	}
+
+	writtenOffStock, err := pharmacyDrugBatchRepo.WriteOffOne(ctx, expiredBatch.Id)
+	if err != nil {
+		return false, apperror.InternalServerError(err)
+	}
+	if writtenOffStock == 0 {
+		return false, nil
+	}
+
+	finalStock, err := pharmacyDrugRepo.UpdateStockByAmount(ctx, expiredBatch.PharmacyDrugId, -writtenOffStock)
+	if err != nil {
+		return false, apperror.InternalServerError(err)
+	}
+
+	stockChange := entity.StockChange{PharmacyDrugId: expiredBatch.PharmacyDrugId, FinalStock: finalStock, Amount: -writtenOffStock,
+		Description: fmt.Sprintf(appconstant.BatchWrittenOffDescription, expiredBatch.BatchNumber),
+		ChangeType:  appconstant.StockChangeTypeManualAdjustment}
+	err = stockChangeRepo.PostStockChangesFromUpdate(ctx, []entity.StockChange{stockChange})
+	if err != nil {
+		return false, apperror.InternalServerError(err)
+	}
+
+	return true, nil
+}
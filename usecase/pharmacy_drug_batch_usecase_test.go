@@ -0,0 +1,220 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sidiqPratomo/max-health-backend/apperror"
+	"github.com/sidiqPratomo/max-health-backend/dto"
+	"github.com/sidiqPratomo/max-health-backend/entity"
+	"github.com/sidiqPratomo/max-health-backend/repository"
+)
+
+type fakeBatchTransaction struct {
+	repository.Transaction
+	pharmacyDrugRepository      *fakeBatchPharmacyDrugRepository
+	stockChangeRepository       *fakeStockChangeRepository
+	pharmacyDrugBatchRepository *fakePharmacyDrugBatchRepository
+	rolledBack                  bool
+}
+
+func (tx *fakeBatchTransaction) BeginTx(ctx context.Context) (repository.Transaction, error) {
+	return tx, nil
+}
+
+func (tx *fakeBatchTransaction) Rollback() error {
+	tx.rolledBack = true
+	return nil
+}
+
+func (tx *fakeBatchTransaction) Commit() error {
+	return nil
+}
+
+func (tx *fakeBatchTransaction) PharmacyDrugRepo() repository.PharmacyDrugRepository {
+	return tx.pharmacyDrugRepository
+}
+
+func (tx *fakeBatchTransaction) StockChangeRepo() repository.StockChangeRepository {
+	return tx.stockChangeRepository
+}
+
+func (tx *fakeBatchTransaction) PharmacyDrugBatchRepository() repository.PharmacyDrugBatchRepository {
+	return tx.pharmacyDrugBatchRepository
+}
+
+type fakeBatchPharmacyDrugRepository struct {
+	repository.PharmacyDrugRepository
+	pharmacyDrugs map[int64]*entity.PharmacyDrugDetail
+}
+
+func (r *fakeBatchPharmacyDrugRepository) GetPharmacyDrugById(ctx context.Context, pharmacyDrugId int64) (*entity.PharmacyDrugDetail, error) {
+	return r.pharmacyDrugs[pharmacyDrugId], nil
+}
+
+func (r *fakeBatchPharmacyDrugRepository) GetPharmacyDrugByIdForUpdate(ctx context.Context, pharmacyDrugId int64) (*entity.PharmacyDrugDetail, error) {
+	return r.pharmacyDrugs[pharmacyDrugId], nil
+}
+
+func (r *fakeBatchPharmacyDrugRepository) UpdateStockByAmount(ctx context.Context, pharmacyDrugId int64, amount int) (int, error) {
+	r.pharmacyDrugs[pharmacyDrugId].Stock += amount
+	return r.pharmacyDrugs[pharmacyDrugId].Stock, nil
+}
+
+type fakeStockChangeRepository struct {
+	repository.StockChangeRepository
+	stockChanges []entity.StockChange
+}
+
+func (r *fakeStockChangeRepository) PostStockChangesFromUpdate(ctx context.Context, stockChanges []entity.StockChange) error {
+	r.stockChanges = append(r.stockChanges, stockChanges...)
+	return nil
+}
+
+type fakeBatchPharmacyRepository struct {
+	repository.PharmacyRepository
+}
+
+func (r *fakeBatchPharmacyRepository) GetOnePharmacyByPharmacyId(ctx context.Context, pharmacyId int64) (*entity.Pharmacy, error) {
+	return &entity.Pharmacy{Id: pharmacyId, PharmacyManagerId: 3}, nil
+}
+
+type fakeBatchPharmacyManagerRepository struct {
+	repository.PharmacyManagerRepository
+}
+
+func (r *fakeBatchPharmacyManagerRepository) FindOneByAccountId(ctx context.Context, accountId int64) (*entity.PharmacyManager, error) {
+	return &entity.PharmacyManager{Id: accountId - 100}, nil
+}
+
+func newTestPharmacyDrugBatchUsecase(pharmacyDrugBatchRepo *fakePharmacyDrugBatchRepository, stock int) (pharmacyDrugBatchUsecaseImpl, *fakeBatchTransaction) {
+	tx := &fakeBatchTransaction{
+		pharmacyDrugRepository: &fakeBatchPharmacyDrugRepository{pharmacyDrugs: map[int64]*entity.PharmacyDrugDetail{
+			7: {Id: 7, PharmacyId: 2, Stock: stock},
+		}},
+		stockChangeRepository:       &fakeStockChangeRepository{},
+		pharmacyDrugBatchRepository: pharmacyDrugBatchRepo,
+	}
+
+	pharmacyDrugBatchUsecase := NewPharmacyDrugBatchUsecaseImpl(pharmacyDrugBatchRepo, tx.pharmacyDrugRepository, &fakeBatchPharmacyRepository{},
+		&fakeBatchPharmacyManagerRepository{}, tx)
+
+	return pharmacyDrugBatchUsecase, tx
+}
+
+func TestReceivePharmacyDrugBatch(t *testing.T) {
+	orderPharmacyId := int64(11)
+	pharmacyDrugBatchRepo := &fakePharmacyDrugBatchRepository{}
+	_ = pharmacyDrugBatchRepo.PostAllocations(context.Background(), []entity.PharmacyDrugBatchAllocation{
+		{PharmacyDrugId: 7, OrderPharmacyId: &orderPharmacyId, Quantity: 2},
+	})
+
+	// Two items were sold as a backorder, the pharmacy drug stock is short.
+	pharmacyDrugBatchUsecase, tx := newTestPharmacyDrugBatchUsecase(pharmacyDrugBatchRepo, -2)
+
+	receiveRequest := dto.ReceivePharmacyDrugBatchRequest{
+		PharmacyDrugBatchRequest: dto.PharmacyDrugBatchRequest{BatchNumber: "B-01", ExpiryDate: testToday().AddDate(0, 6, 0).Format("2006-01-02")},
+		Stock:                    5,
+	}
+
+	pharmacyDrugBatches, err := pharmacyDrugBatchUsecase.ReceivePharmacyDrugBatch(context.Background(), 103, 7, receiveRequest)
+	if err != nil {
+		t.Fatalf("ReceivePharmacyDrugBatch() error = %v", err)
+	}
+
+	if len(pharmacyDrugBatches) != 1 || pharmacyDrugBatches[0].BatchNumber != "B-01" || pharmacyDrugBatches[0].Stock != 3 {
+		t.Errorf("ReceivePharmacyDrugBatch() = %+v, want batch B-01 with 3 left after the backorder", pharmacyDrugBatches)
+	}
+
+	if stock := tx.pharmacyDrugRepository.pharmacyDrugs[7].Stock; stock != 3 {
+		t.Errorf("pharmacy drug stock = %d, want 3", stock)
+	}
+
+	stockChanges := tx.stockChangeRepository.stockChanges
+	if len(stockChanges) != 1 || stockChanges[0].Amount != 5 || stockChanges[0].FinalStock != 3 || stockChanges[0].ActorAccountId == nil || *stockChanges[0].ActorAccountId != 103 {
+		t.Errorf("stock changes = %+v, want one change of 5 to 3 by account 103", stockChanges)
+	}
+
+	pending, _ := pharmacyDrugBatchRepo.FindAllPendingAllocationsForUpdate(context.Background(), 7)
+	if len(pending) != 0 {
+		t.Errorf("pending backorders = %+v, want none", pending)
+	}
+}
+
+func TestReceivePharmacyDrugBatchRejected(t *testing.T) {
+	expiryDate := testToday().AddDate(0, 6, 0).Format("2006-01-02")
+
+	tests := []struct {
+		name           string
+		accountId      int64
+		receiveRequest dto.ReceivePharmacyDrugBatchRequest
+		wantErr        *apperror.AppError
+	}{
+		{
+			name:      "another manager's pharmacy",
+			accountId: 104,
+			receiveRequest: dto.ReceivePharmacyDrugBatchRequest{
+				PharmacyDrugBatchRequest: dto.PharmacyDrugBatchRequest{BatchNumber: "B-01", ExpiryDate: expiryDate},
+				Stock:                    5,
+			},
+			wantErr: apperror.ForbiddenAction(),
+		},
+		{
+			name:      "expired batch",
+			accountId: 103,
+			receiveRequest: dto.ReceivePharmacyDrugBatchRequest{
+				PharmacyDrugBatchRequest: dto.PharmacyDrugBatchRequest{BatchNumber: "B-01", ExpiryDate: testToday().AddDate(0, 0, -1).Format("2006-01-02")},
+				Stock:                    5,
+			},
+			wantErr: apperror.BatchExpiredError(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pharmacyDrugBatchRepo := &fakePharmacyDrugBatchRepository{}
+			pharmacyDrugBatchUsecase, tx := newTestPharmacyDrugBatchUsecase(pharmacyDrugBatchRepo, 0)
+
+			_, err := pharmacyDrugBatchUsecase.ReceivePharmacyDrugBatch(context.Background(), tt.accountId, 7, tt.receiveRequest)
+			if !isAppError(err, tt.wantErr) {
+				t.Fatalf("ReceivePharmacyDrugBatch() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(pharmacyDrugBatchRepo.batches) != 0 || len(tx.stockChangeRepository.stockChanges) != 0 {
+				t.Errorf("ReceivePharmacyDrugBatch() stored batches %+v and stock changes %+v, want none", pharmacyDrugBatchRepo.batches, tx.stockChangeRepository.stockChanges)
+			}
+		})
+	}
+}
+
+func TestWriteOffExpiredBatches(t *testing.T) {
+	pharmacyDrugBatchRepo := &fakePharmacyDrugBatchRepository{batches: []entity.PharmacyDrugBatch{
+		{Id: 1, PharmacyDrugId: 7, BatchNumber: "B-01", ExpiryDate: testExpiryDate(-10), Stock: 4},
+		{Id: 2, PharmacyDrugId: 7, BatchNumber: "B-02", ExpiryDate: testExpiryDate(-1), Stock: 3},
+		{Id: 3, PharmacyDrugId: 7, BatchNumber: "B-03", ExpiryDate: testExpiryDate(0), Stock: 5},
+		{Id: 4, PharmacyDrugId: 7, BatchNumber: "B-04", ExpiryDate: testExpiryDate(-5), Stock: 0},
+	}}
+	pharmacyDrugBatchUsecase, tx := newTestPharmacyDrugBatchUsecase(pharmacyDrugBatchRepo, 12)
+
+	writtenOffCount, err := pharmacyDrugBatchUsecase.WriteOffExpiredBatches(context.Background())
+	if err != nil {
+		t.Fatalf("WriteOffExpiredBatches() error = %v", err)
+	}
+	if writtenOffCount != 2 {
+		t.Errorf("WriteOffExpiredBatches() = %d, want 2", writtenOffCount)
+	}
+
+	wantStocks := []batchQuantity{{1, 0}, {4, 0}, {2, 0}, {3, 5}}
+	pharmacyDrugBatches, _ := pharmacyDrugBatchRepo.FindAllByPharmacyDrugId(context.Background(), 7)
+	if got := batchStocks(pharmacyDrugBatches); !equalBatchQuantities(got, wantStocks) {
+		t.Errorf("batch stocks = %v, want %v", got, wantStocks)
+	}
+
+	if stock := tx.pharmacyDrugRepository.pharmacyDrugs[7].Stock; stock != 5 {
+		t.Errorf("pharmacy drug stock = %d, want 5", stock)
+	}
+
+	stockChanges := tx.stockChangeRepository.stockChanges
+	if len(stockChanges) != 2 || stockChanges[0].Amount != -4 || stockChanges[1].Amount != -3 || stockChanges[1].FinalStock != 5 {
+		t.Errorf("stock changes = %+v, want write-offs of 4 and 3 down to 5", stockChanges)
+	}
+}
@@ -0,0 +1,477 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/sidiqPratomo/max-health-backend/appconstant"
+	"github.com/sidiqPratomo/max-health-backend/apperror"
+	"github.com/sidiqPratomo/max-health-backend/dto"
+	"github.com/sidiqPratomo/max-health-backend/entity"
+	"github.com/sidiqPratomo/max-health-backend/repository"
+	"github.com/sidiqPratomo/max-health-backend/util"
+)
+
+type PharmacyDrugImportUsecase interface {
+	ImportPharmacyDrugs(ctx context.Context, accountId, pharmacyId int64, query dto.PharmacyDrugImportQuery, file multipart.File, fileHeader *multipart.FileHeader) (*dto.PharmacyDrugImportResponse, error)
+}
+
+type pharmacyDrugImportUsecaseImpl struct {
+	drugRepository            repository.DrugRepository
+	pharmacyDrugRepository    repository.PharmacyDrugRepository
+	pharmacyRepository        repository.PharmacyRepository
+	pharmacyManagerRepository repository.PharmacyManagerRepository
+	transaction               repository.Transaction
+}
+
+func NewPharmacyDrugImportUsecaseImpl(drugRepository repository.DrugRepository, pharmacyDrugRepository repository.PharmacyDrugRepository, pharmacyRepository repository.PharmacyRepository, pharmacyManagerRepository repository.PharmacyManagerRepository, transaction repository.Transaction) pharmacyDrugImportUsecaseImpl {
+	return pharmacyDrugImportUsecaseImpl{
+		drugRepository:            drugRepository,
+		pharmacyDrugRepository:    pharmacyDrugRepository,
+		pharmacyRepository:        pharmacyRepository,
+		pharmacyManagerRepository: pharmacyManagerRepository,
+		transaction:               transaction,
+	}
+}
+
+func (u *pharmacyDrugImportUsecaseImpl) ImportPharmacyDrugs(ctx context.Context, accountId, pharmacyId int64, query dto.PharmacyDrugImportQuery, file multipart.File, fileHeader *multipart.FileHeader) (*dto.PharmacyDrugImportResponse, error) {
+	manager, err := u.pharmacyManagerRepository.FindOneByAccountId(ctx, accountId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+	if manager == nil {
+		return nil, apperror.PharmacyManagerNotFoundError()
+	}
+
+	pharmacy, err := u.pharmacyRepository.GetOnePharmacyByPharmacyId(ctx, pharmacyId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+	if pharmacy == nil {
+		return nil, apperror.PharmacyNotFoundError()
+	}
+	if pharmacy.PharmacyManagerId != manager.Id {
+		return nil, apperror.ForbiddenAction()
+	}
+
+	_, format, err := util.ValidateFile(*fileHeader, "", []string{util.SpreadsheetFormatCsv, util.SpreadsheetFormatXlsx}, appconstant.PharmacyDrugImportMaxFileSize)
+	if err != nil {
+		return nil, apperror.NewAppError(http.StatusBadRequest, err, err.Error())
+	}
+
+	// The header row comes on top of the data rows.
+	rows, err := util.ReadSpreadsheet(file, fileHeader.Size, *format, appconstant.PharmacyDrugImportMaxRows+1)
+	if err != nil {
+		return nil, apperror.NewAppError(http.StatusBadRequest, err, err.Error())
+	}
+
+	importRows, err := parsePharmacyDrugImportRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.resolveImportDrugs(ctx, importRows)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.matchImportPharmacyDrugs(ctx, pharmacyId, importRows)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := false
+	if !hasPharmacyDrugImportErrors(importRows) {
+		applied, err = u.applyImportRows(ctx, accountId, pharmacyId, importRows, query.DryRun)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	response := dto.ConvertToPharmacyDrugImportResponse(importRows, query.DryRun, applied)
+
+	return &response, nil
+}
+
+func parsePharmacyDrugImportRows(rows [][]string) ([]entity.PharmacyDrugImportRow, error) {
+	if len(rows) == 0 {
+		return nil, apperror.EmptyDrugImportError()
+	}
+
+	columnReplacer := strings.NewReplacer(" ", "_", "-", "_")
+	columns := map[string]int{}
+	for i, header := range rows[0] {
+		column := columnReplacer.Replace(strings.ToLower(strings.TrimSpace(header)))
+		if _, ok := columns[column]; !ok {
+			columns[column] = i
+		}
+	}
+
+	_, hasDrugId := columns[appconstant.PharmacyDrugImportColumnDrugId]
+	_, hasDrugName := columns[appconstant.PharmacyDrugImportColumnDrugName]
+	_, hasStock := columns[appconstant.PharmacyDrugImportColumnStock]
+	_, hasPrice := columns[appconstant.PharmacyDrugImportColumnPrice]
+	if !hasStock || !hasPrice || (!hasDrugId && !hasDrugName) {
+		return nil, apperror.InvalidDrugImportColumnsError()
+	}
+
+	importRows := []entity.PharmacyDrugImportRow{}
+	for i, row := range rows[1:] {
+		if isBlankImportRow(row) {
+			continue
+		}
+
+		importRows = append(importRows, parsePharmacyDrugImportRow(i+2, row, columns))
+	}
+
+	if len(importRows) == 0 {
+		return nil, apperror.EmptyDrugImportError()
+	}
+
+	return importRows, nil
+}
+
+func isBlankImportRow(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func importCell(row []string, columns map[string]int, column string) string {
+	index, ok := columns[column]
+	if !ok || index >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[index])
+}
+
+func parsePharmacyDrugImportRow(rowNumber int, row []string, columns map[string]int) entity.PharmacyDrugImportRow {
+	importRow := entity.PharmacyDrugImportRow{
+		RowNumber:   rowNumber,
+		DrugName:    importCell(row, columns, appconstant.PharmacyDrugImportColumnDrugName),
+		BatchNumber: importCell(row, columns, appconstant.PharmacyDrugImportColumnBatchNumber),
+		ExpiryDate:  importCell(row, columns, appconstant.PharmacyDrugImportColumnExpiryDate),
+	}
+
+	drugIdCell := importCell(row, columns, appconstant.PharmacyDrugImportColumnDrugId)
+	if drugIdCell != "" {
+		drugId, err := strconv.ParseInt(drugIdCell, 10, 64)
+		if err != nil || drugId <= 0 {
+			importRow.Errors = append(importRow.Errors, appconstant.PharmacyDrugImportInvalidDrugId)
+		} else {
+			importRow.DrugId = &drugId
+		}
+	} else if importRow.DrugName == "" {
+		importRow.Errors = append(importRow.Errors, appconstant.PharmacyDrugImportDrugRequired)
+	}
+
+	stock, err := strconv.Atoi(importCell(row, columns, appconstant.PharmacyDrugImportColumnStock))
+	if err != nil || stock < 0 {
+		importRow.Errors = append(importRow.Errors, appconstant.PharmacyDrugImportInvalidStock)
+	} else {
+		importRow.Stock = stock
+	}
+
+	price, err := decimal.NewFromString(importCell(row, columns, appconstant.PharmacyDrugImportColumnPrice))
+	if err != nil || price.LessThan(decimal.NewFromInt(appconstant.PharmacyDrugImportMinPrice)) {
+		importRow.Errors = append(importRow.Errors, appconstant.PharmacyDrugImportInvalidPrice)
+	} else {
+		importRow.Price = price
+	}
+
+	if (importRow.BatchNumber == "") != (importRow.ExpiryDate == "") {
+		importRow.Errors = append(importRow.Errors, appconstant.PharmacyDrugImportIncompleteBatch)
+	} else if importRow.ExpiryDate != "" {
+		expiryDate, ok := parseImportExpiryDate(importRow.ExpiryDate)
+		if !ok {
+			importRow.Errors = append(importRow.Errors, appconstant.PharmacyDrugImportInvalidExpiryDate)
+		} else {
+			importRow.ExpiryDate = expiryDate
+			_, err := newReceivedBatch(0, dto.PharmacyDrugBatchRequest{BatchNumber: importRow.BatchNumber, ExpiryDate: importRow.ExpiryDate})
+			if err != nil {
+				importRow.Errors = append(importRow.Errors, importErrorMessage(err))
+			}
+		}
+	}
+
+	return importRow
+}
+
+// XLSX date cells hold the number of days since the spreadsheet epoch
+// instead of a formatted date.
+func parseImportExpiryDate(value string) (string, bool) {
+	_, err := time.Parse(appconstant.BatchExpiryDateFormat, value)
+	if err == nil {
+		return value, true
+	}
+
+	serial, err := strconv.Atoi(value)
+	if err != nil || serial <= 0 {
+		return "", false
+	}
+
+	spreadsheetEpoch := time.Date(1899, time.December, 30, 0, 0, 0, 0, time.UTC)
+	return spreadsheetEpoch.AddDate(0, 0, serial).Format(appconstant.BatchExpiryDateFormat), true
+}
+
+func importErrorMessage(err error) string {
+	if appErr, ok := err.(*apperror.AppError); ok {
+		return appErr.Message
+	}
+	return err.Error()
+}
+
+func hasPharmacyDrugImportErrors(importRows []entity.PharmacyDrugImportRow) bool {
+	for _, importRow := range importRows {
+		if len(importRow.Errors) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (u *pharmacyDrugImportUsecaseImpl) resolveImportDrugs(ctx context.Context, importRows []entity.PharmacyDrugImportRow) error {
+	drugIds := []int64{}
+	drugNames := []string{}
+	for _, importRow := range importRows {
+		if importRow.DrugId != nil {
+			drugIds = append(drugIds, *importRow.DrugId)
+		} else if importRow.DrugName != "" {
+			drugNames = append(drugNames, strings.ToLower(importRow.DrugName))
+		}
+	}
+
+	drugs, err := u.drugRepository.GetDrugsByIdsOrNames(ctx, drugIds, drugNames)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	drugsById := map[int64]entity.Drug{}
+	drugsByName := map[string][]entity.Drug{}
+	for _, drug := range drugs {
+		drugsById[drug.Id] = drug
+		drugsByName[strings.ToLower(drug.Name)] = append(drugsByName[strings.ToLower(drug.Name)], drug)
+	}
+
+	firstRowNumbers := map[int64]int{}
+	for i := range importRows {
+		importRow := &importRows[i]
+
+		var drug entity.Drug
+		if importRow.DrugId != nil {
+			foundDrug, ok := drugsById[*importRow.DrugId]
+			if !ok {
+				importRow.Errors = append(importRow.Errors, appconstant.PharmacyDrugImportDrugNotFound)
+				continue
+			}
+			if importRow.DrugName != "" && !strings.EqualFold(importRow.DrugName, foundDrug.Name) {
+				importRow.Errors = append(importRow.Errors, appconstant.PharmacyDrugImportDrugNameMismatch)
+				continue
+			}
+			drug = foundDrug
+		} else if importRow.DrugName != "" {
+			foundDrugs := drugsByName[strings.ToLower(importRow.DrugName)]
+			if len(foundDrugs) == 0 {
+				importRow.Errors = append(importRow.Errors, appconstant.PharmacyDrugImportDrugNotFound)
+				continue
+			}
+			if len(foundDrugs) > 1 {
+				importRow.Errors = append(importRow.Errors, appconstant.PharmacyDrugImportAmbiguousDrugName)
+				continue
+			}
+			drug = foundDrugs[0]
+		} else {
+			continue
+		}
+
+		drugId := drug.Id
+		importRow.DrugId = &drugId
+		importRow.DrugName = drug.Name
+
+		if firstRowNumber, ok := firstRowNumbers[drugId]; ok {
+			importRow.Errors = append(importRow.Errors, fmt.Sprintf(appconstant.PharmacyDrugImportDuplicateDrug, firstRowNumber))
+			continue
+		}
+		firstRowNumbers[drugId] = importRow.RowNumber
+	}
+
+	return nil
+}
+
+func (u *pharmacyDrugImportUsecaseImpl) matchImportPharmacyDrugs(ctx context.Context, pharmacyId int64, importRows []entity.PharmacyDrugImportRow) error {
+	pharmacyDrugs, err := u.pharmacyDrugRepository.GetPharmacyDrugByPharmacyId(ctx, pharmacyId)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	pharmacyDrugsByDrugId := map[int64]entity.PharmacyDrugDetail{}
+	for _, pharmacyDrug := range pharmacyDrugs {
+		pharmacyDrugsByDrugId[pharmacyDrug.DrugId] = pharmacyDrug
+	}
+
+	for i := range importRows {
+		importRow := &importRows[i]
+		if importRow.DrugId == nil {
+			continue
+		}
+
+		previousStock := 0
+		importRow.Action = appconstant.PharmacyDrugImportActionCreate
+
+		if pharmacyDrug, ok := pharmacyDrugsByDrugId[*importRow.DrugId]; ok {
+			pharmacyDrugId := pharmacyDrug.Id
+			previousStock = pharmacyDrug.Stock
+
+			importRow.Action = appconstant.PharmacyDrugImportActionUpdate
+			importRow.PharmacyDrugId = &pharmacyDrugId
+			importRow.PreviousStock = &previousStock
+		}
+
+		if importRow.Stock > previousStock && importRow.BatchNumber == "" {
+			importRow.Errors = append(importRow.Errors, appconstant.MsgBatchRequired)
+		}
+	}
+
+	return nil
+}
+
+// A dry run goes through the same writes and is rolled back at the end, so
+// stock the batches cannot cover is reported before anything is applied.
+func (u *pharmacyDrugImportUsecaseImpl) applyImportRows(ctx context.Context, accountId, pharmacyId int64, importRows []entity.PharmacyDrugImportRow, dryRun bool) (bool, error) {
+	tx, err := u.transaction.BeginTx(ctx)
+	if err != nil {
+		return false, apperror.InternalServerError(err)
+	}
+
+	pharmacyDrugRepo := tx.PharmacyDrugRepo()
+	stockChangeRepo := tx.StockChangeRepo()
+	pharmacyDrugBatchRepo := tx.PharmacyDrugBatchRepository()
+
+	hasRowErrors := false
+
+	defer func() {
+		if err != nil || dryRun || hasRowErrors {
+			tx.Rollback()
+		}
+
+		tx.Commit()
+	}()
+
+	stockChanges := []entity.StockChange{}
+	for i := range importRows {
+		importRow := &importRows[i]
+
+		stockChange, rowErr := applyImportRow(ctx, pharmacyDrugRepo, pharmacyDrugBatchRepo, pharmacyId, importRow)
+		if rowErr != nil {
+			appErr, ok := rowErr.(*apperror.AppError)
+			if !ok || appErr.Code >= http.StatusInternalServerError {
+				err = rowErr
+				return false, err
+			}
+
+			// The remaining rows are still applied so every failing row is
+			// reported, the transaction is rolled back afterwards.
+			importRow.Errors = append(importRow.Errors, appErr.Message)
+			hasRowErrors = true
+			continue
+		}
+
+		stockChange.Description = appconstant.PharmacyDrugImportDescription
+		stockChange.ChangeType = appconstant.StockChangeTypeManualAdjustment
+		stockChange.ActorAccountId = &accountId
+		stockChanges = append(stockChanges, *stockChange)
+	}
+
+	if hasRowErrors {
+		return false, nil
+	}
+
+	err = stockChangeRepo.PostStockChangesFromUpdate(ctx, stockChanges)
+	if err != nil {
+		return false, apperror.InternalServerError(err)
+	}
+
+	return !dryRun, nil
+}
+
+func applyImportRow(ctx context.Context, pharmacyDrugRepo repository.PharmacyDrugRepository, pharmacyDrugBatchRepo repository.PharmacyDrugBatchRepository, pharmacyId int64, importRow *entity.PharmacyDrugImportRow) (*entity.StockChange, error) {
+	var receivedBatch *entity.PharmacyDrugBatch
+	if importRow.BatchNumber != "" {
+		var err error
+		receivedBatch, err = newReceivedBatch(0, dto.PharmacyDrugBatchRequest{BatchNumber: importRow.BatchNumber, ExpiryDate: importRow.ExpiryDate})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if importRow.PharmacyDrugId == nil {
+		pharmacyDrugId, err := pharmacyDrugRepo.AddPharmacyDrug(ctx, pharmacyId, *importRow.DrugId, importRow.Stock, importRow.Price)
+		if err != nil {
+			return nil, apperror.InternalServerError(err)
+		}
+		importRow.PharmacyDrugId = &pharmacyDrugId
+
+		err = restockImportBatch(ctx, pharmacyDrugBatchRepo, receivedBatch, pharmacyDrugId, importRow.Stock)
+		if err != nil {
+			return nil, err
+		}
+
+		return &entity.StockChange{PharmacyDrugId: pharmacyDrugId, FinalStock: importRow.Stock, Amount: importRow.Stock}, nil
+	}
+
+	pharmacyDrug, err := pharmacyDrugRepo.GetPharmacyDrugByIdForUpdate(ctx, *importRow.PharmacyDrugId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+	if pharmacyDrug == nil {
+		return nil, apperror.DrugNotFoundError()
+	}
+
+	previousStock := pharmacyDrug.Stock
+	importRow.PreviousStock = &previousStock
+
+	amount := importRow.Stock - previousStock
+	if amount < 0 {
+		_, err = deductBatches(ctx, pharmacyDrugBatchRepo, pharmacyDrug.Id, -amount, entity.PharmacyDrugBatchAllocation{})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if amount > 0 {
+		err = restockImportBatch(ctx, pharmacyDrugBatchRepo, receivedBatch, pharmacyDrug.Id, amount)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = pharmacyDrugRepo.UpdatePharmacyDrugStockPrice(ctx, pharmacyDrug.Id, importRow.Stock, importRow.Price)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	return &entity.StockChange{PharmacyDrugId: pharmacyDrug.Id, FinalStock: importRow.Stock, Amount: amount}, nil
+}
+
+func restockImportBatch(ctx context.Context, pharmacyDrugBatchRepo repository.PharmacyDrugBatchRepository, receivedBatch *entity.PharmacyDrugBatch, pharmacyDrugId int64, stock int) error {
+	if stock == 0 {
+		return nil
+	}
+	if receivedBatch == nil {
+		return apperror.BatchRequiredError()
+	}
+
+	pharmacyDrugBatch := *receivedBatch
+	pharmacyDrugBatch.PharmacyDrugId = pharmacyDrugId
+	pharmacyDrugBatch.Stock = stock
+
+	return restockBatch(ctx, pharmacyDrugBatchRepo, pharmacyDrugBatch)
+}
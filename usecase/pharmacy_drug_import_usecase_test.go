@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/sidiqPratomo/max-health-backend/appconstant"
+	"github.com/sidiqPratomo/max-health-backend/apperror"
+)
+
+func TestParseImportExpiryDate(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   string
+		wantOk bool
+	}{
+		{value: "2030-05-17", want: "2030-05-17", wantOk: true},
+		{value: "45658", want: "2025-01-01", wantOk: true},
+		{value: "1", want: "1899-12-31", wantOk: true},
+		{value: "0", wantOk: false},
+		{value: "-3", wantOk: false},
+		{value: "17/05/2030", wantOk: false},
+		{value: "45658.5", wantOk: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseImportExpiryDate(tt.value)
+		if ok != tt.wantOk || got != tt.want {
+			t.Errorf("parseImportExpiryDate(%q) = (%q, %v), want (%q, %v)", tt.value, got, ok, tt.want, tt.wantOk)
+		}
+	}
+}
+
+func TestParsePharmacyDrugImportRowsColumns(t *testing.T) {
+	tests := []struct {
+		name    string
+		rows    [][]string
+		wantErr string
+	}{
+		{name: "no rows", rows: [][]string{}, wantErr: appconstant.MsgEmptyDrugImport},
+		{name: "missing price", rows: [][]string{{"drug_id", "stock"}, {"1", "2"}}, wantErr: appconstant.MsgInvalidDrugImportColumns},
+		{name: "missing drug", rows: [][]string{{"stock", "price"}, {"2", "1000"}}, wantErr: appconstant.MsgInvalidDrugImportColumns},
+		{name: "only blank rows", rows: [][]string{{"drug_id", "stock", "price"}, {"", " "}, {}}, wantErr: appconstant.MsgEmptyDrugImport},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parsePharmacyDrugImportRows(tt.rows)
+			appErr, ok := err.(*apperror.AppError)
+			if !ok || appErr.Code != http.StatusBadRequest || appErr.Message != tt.wantErr {
+				t.Errorf("parsePharmacyDrugImportRows() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParsePharmacyDrugImportRows(t *testing.T) {
+	nextYear := time.Now().AddDate(1, 0, 0).Format(appconstant.BatchExpiryDateFormat)
+
+	rows := [][]string{
+		{" Drug ID ", "drug-name", "Stock", "PRICE", "batch number", "expiry_date"},
+		{"12", "", "10", "1500", "B-01", nextYear},
+		{},
+		{"", " Paracetamol ", "0", "500.50"},
+		{"abc", "", "-1", "499", "B-02", ""},
+		{"", "", "3", "2000", "B-03", "2000-01-01"},
+		{"", "Amoxicillin", "3", "2000", "B-04", "tomorrow"},
+	}
+
+	importRows, err := parsePharmacyDrugImportRows(rows)
+	if err != nil {
+		t.Fatalf("parsePharmacyDrugImportRows() error = %v", err)
+	}
+
+	drugId := int64(12)
+	tests := []struct {
+		rowNumber   int
+		drugId      *int64
+		drugName    string
+		stock       int
+		price       string
+		batchNumber string
+		expiryDate  string
+		errors      []string
+	}{
+		{rowNumber: 2, drugId: &drugId, stock: 10, price: "1500", batchNumber: "B-01", expiryDate: nextYear},
+		{rowNumber: 4, drugName: "Paracetamol", stock: 0, price: "500.5"},
+		{rowNumber: 5, price: "0", batchNumber: "B-02", errors: []string{
+			appconstant.PharmacyDrugImportInvalidDrugId,
+			appconstant.PharmacyDrugImportInvalidStock,
+			appconstant.PharmacyDrugImportInvalidPrice,
+			appconstant.PharmacyDrugImportIncompleteBatch,
+		}},
+		{rowNumber: 6, stock: 3, price: "2000", batchNumber: "B-03", expiryDate: "2000-01-01", errors: []string{
+			appconstant.PharmacyDrugImportDrugRequired,
+			appconstant.MsgBatchExpired,
+		}},
+		{rowNumber: 7, drugName: "Amoxicillin", stock: 3, price: "2000", batchNumber: "B-04", expiryDate: "tomorrow", errors: []string{
+			appconstant.PharmacyDrugImportInvalidExpiryDate,
+		}},
+	}
+
+	if len(importRows) != len(tests) {
+		t.Fatalf("parsePharmacyDrugImportRows() returned %d rows, want %d", len(importRows), len(tests))
+	}
+
+	for i, tt := range tests {
+		importRow := importRows[i]
+
+		if importRow.RowNumber != tt.rowNumber {
+			t.Errorf("row %d: RowNumber = %d, want %d", i, importRow.RowNumber, tt.rowNumber)
+		}
+		if !reflect.DeepEqual(importRow.DrugId, tt.drugId) {
+			t.Errorf("row %d: DrugId = %v, want %v", tt.rowNumber, importRow.DrugId, tt.drugId)
+		}
+		if importRow.DrugName != tt.drugName {
+			t.Errorf("row %d: DrugName = %q, want %q", tt.rowNumber, importRow.DrugName, tt.drugName)
+		}
+		if importRow.Stock != tt.stock {
+			t.Errorf("row %d: Stock = %d, want %d", tt.rowNumber, importRow.Stock, tt.stock)
+		}
+		if !importRow.Price.Equal(decimal.RequireFromString(tt.price)) {
+			t.Errorf("row %d: Price = %s, want %s", tt.rowNumber, importRow.Price, tt.price)
+		}
+		if importRow.BatchNumber != tt.batchNumber || importRow.ExpiryDate != tt.expiryDate {
+			t.Errorf("row %d: batch = (%q, %q), want (%q, %q)", tt.rowNumber, importRow.BatchNumber, importRow.ExpiryDate, tt.batchNumber, tt.expiryDate)
+		}
+		if !reflect.DeepEqual(importRow.Errors, tt.errors) {
+			t.Errorf("row %d: Errors = %q, want %q", tt.rowNumber, importRow.Errors, tt.errors)
+		}
+	}
+
+	if !hasPharmacyDrugImportErrors(importRows) {
+		t.Error("hasPharmacyDrugImportErrors() = false, want true")
+	}
+	if hasPharmacyDrugImportErrors(importRows[:2]) {
+		t.Error("hasPharmacyDrugImportErrors() = true for valid rows, want false")
+	}
+}
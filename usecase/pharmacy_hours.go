@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/sidiqPratomo/max-health-backend/apperror"
+	"github.com/sidiqPratomo/max-health-backend/entity"
+	"github.com/sidiqPratomo/max-health-backend/repository"
+	"github.com/sidiqPratomo/max-health-backend/util"
+)
+
+type PharmacyHours interface {
+	GetPharmacySchedule(ctx context.Context, pharmacyId int64) (*entity.PharmacySchedule, error)
+	GetOpeningStatus(ctx context.Context, schedule entity.PharmacySchedule, now time.Time) (*entity.PharmacyOpeningStatus, error)
+	CheckPharmacyIsOpen(ctx context.Context, pharmacyId int64, now time.Time) error
+}
+
+type pharmacyHoursImpl struct {
+	pharmacyRepository            repository.PharmacyRepository
+	pharmacyOperationalRepository repository.PharmacyOperationalRepository
+	pharmacyHolidayRepository     repository.PharmacyHolidayRepository
+}
+
+func NewPharmacyHoursImpl(pharmacyRepository repository.PharmacyRepository, pharmacyOperationalRepository repository.PharmacyOperationalRepository, pharmacyHolidayRepository repository.PharmacyHolidayRepository) pharmacyHoursImpl {
+	return pharmacyHoursImpl{
+		pharmacyRepository:            pharmacyRepository,
+		pharmacyOperationalRepository: pharmacyOperationalRepository,
+		pharmacyHolidayRepository:     pharmacyHolidayRepository,
+	}
+}
+
+func (h *pharmacyHoursImpl) GetPharmacySchedule(ctx context.Context, pharmacyId int64) (*entity.PharmacySchedule, error) {
+	pharmacy, err := h.pharmacyRepository.FindOneById(ctx, pharmacyId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+	if pharmacy == nil {
+		return nil, apperror.PharmacyNotFoundError()
+	}
+
+	location, err := time.LoadLocation(pharmacy.Timezone)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	pharmacyOperationals, err := h.pharmacyOperationalRepository.FindAllByPharmacyId(ctx, pharmacyId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	pharmacyHolidays, err := h.pharmacyHolidayRepository.FindAllUpcomingByPharmacyId(ctx, pharmacyId, time.Now().In(location))
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	return &entity.PharmacySchedule{
+		PharmacyId:        pharmacy.Id,
+		PharmacyName:      pharmacy.Name,
+		PharmacyManagerId: pharmacy.PharmacyManagerId,
+		Timezone:          pharmacy.Timezone,
+		Operationals:      pharmacyOperationals,
+		Holidays:          pharmacyHolidays,
+	}, nil
+}
+
+func (h *pharmacyHoursImpl) GetOpeningStatus(ctx context.Context, schedule entity.PharmacySchedule, now time.Time) (*entity.PharmacyOpeningStatus, error) {
+	openingStatus, err := util.GetPharmacyOpeningStatus(schedule, now)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	return openingStatus, nil
+}
+
+func (h *pharmacyHoursImpl) CheckPharmacyIsOpen(ctx context.Context, pharmacyId int64, now time.Time) error {
+	schedule, err := h.GetPharmacySchedule(ctx, pharmacyId)
+	if err != nil {
+		return err
+	}
+
+	openingStatus, err := h.GetOpeningStatus(ctx, *schedule, now)
+	if err != nil {
+		return err
+	}
+
+	if !openingStatus.IsOpen {
+		return apperror.PharmacyClosedError(schedule.PharmacyName, openingStatus.OpensAt)
+	}
+
+	return nil
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/sidiqPratomo/max-health-backend/apperror"
 	"github.com/sidiqPratomo/max-health-backend/dto"
+	"github.com/sidiqPratomo/max-health-backend/entity"
 	"github.com/sidiqPratomo/max-health-backend/repository"
+	"github.com/sidiqPratomo/max-health-backend/util"
 )
 
 type PharmacyUsecase interface {
@@ -16,6 +19,10 @@ type PharmacyUsecase interface {
 	UpdateOnePharmacy(ctx context.Context, accountId int64, updatePharmacyRequest dto.UpdatePharmacyRequest) error
 	DeleteOnePharmacyById(ctx context.Context, accountId int64, pharmacyId int64) error
 	AdminGetAllPharmacyByManagerId(ctx context.Context, managerId int64, limit string, page string, search string) (*dto.GetAllPharmacyResponse, error)
+	GetPharmacySchedule(ctx context.Context, accountId int64, pharmacyId int64) (*dto.PharmacyScheduleResponse, error)
+	UpdatePharmacySchedule(ctx context.Context, accountId int64, pharmacyId int64, pharmacyScheduleRequest dto.PharmacyScheduleRequest) error
+	AddPharmacyHoliday(ctx context.Context, accountId int64, pharmacyId int64, pharmacyHolidayRequest dto.PharmacyHolidayRequest) error
+	DeletePharmacyHoliday(ctx context.Context, accountId int64, pharmacyId int64, pharmacyHolidayId int64) error
 }
 
 type pharmacyUsecaseImpl struct {
@@ -25,10 +32,12 @@ type pharmacyUsecaseImpl struct {
 	addressRepository         repository.AddressRepository
 	courierRepository         repository.CourierRepository
 	orderPharmacyRepository   repository.OrderPharmacyRepository
+	pharmacyHolidayRepository repository.PharmacyHolidayRepository
+	pharmacyHours             PharmacyHours
 	transaction               repository.Transaction
 }
 
-func NewPharmacyUsecaseImpl(pharmacyManagerRepository repository.PharmacyManagerRepository, pharmacyRepository repository.PharmacyRepository, pharmacyDrugRepository repository.PharmacyDrugRepository, addressRepository repository.AddressRepository, courierRepository repository.CourierRepository, orderPharmacyRepository repository.OrderPharmacyRepository, transaction repository.Transaction) pharmacyUsecaseImpl {
+func NewPharmacyUsecaseImpl(pharmacyManagerRepository repository.PharmacyManagerRepository, pharmacyRepository repository.PharmacyRepository, pharmacyDrugRepository repository.PharmacyDrugRepository, addressRepository repository.AddressRepository, courierRepository repository.CourierRepository, orderPharmacyRepository repository.OrderPharmacyRepository, pharmacyHolidayRepository repository.PharmacyHolidayRepository, pharmacyHours PharmacyHours, transaction repository.Transaction) pharmacyUsecaseImpl {
 	return pharmacyUsecaseImpl{
 		pharmacyManagerRepository: pharmacyManagerRepository,
 		pharmacyRepository:        pharmacyRepository,
@@ -36,6 +45,8 @@ func NewPharmacyUsecaseImpl(pharmacyManagerRepository repository.PharmacyManager
 		addressRepository:         addressRepository,
 		courierRepository:         courierRepository,
 		orderPharmacyRepository:   orderPharmacyRepository,
+		pharmacyHolidayRepository: pharmacyHolidayRepository,
+		pharmacyHours:             pharmacyHours,
 		transaction:               transaction,
 	}
 }
@@ -365,3 +376,176 @@ func (u *pharmacyUsecaseImpl) AdminGetAllPharmacyByManagerId(ctx context.Context
 
 	return &pharmacyResponse, nil
 }
+
+func (u *pharmacyUsecaseImpl) findManagedPharmacySchedule(ctx context.Context, accountId int64, pharmacyId int64) (*entity.PharmacySchedule, error) {
+	pharmacyManager, err := u.pharmacyManagerRepository.FindOneByAccountId(ctx, accountId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+	if pharmacyManager == nil {
+		return nil, apperror.PharmacyManagerNotFoundError()
+	}
+
+	schedule, err := u.pharmacyHours.GetPharmacySchedule(ctx, pharmacyId)
+	if err != nil {
+		return nil, err
+	}
+
+	if schedule.PharmacyManagerId != pharmacyManager.Id {
+		return nil, apperror.ForbiddenAction()
+	}
+
+	return schedule, nil
+}
+
+func validPharmacyHours(openHour string, closeHour string) bool {
+	openDuration, err := util.ParsePharmacyHour(openHour)
+	if err != nil {
+		return false
+	}
+
+	closeDuration, err := util.ParsePharmacyHour(closeHour)
+	if err != nil {
+		return false
+	}
+
+	return closeDuration >= openDuration
+}
+
+func (u *pharmacyUsecaseImpl) GetPharmacySchedule(ctx context.Context, accountId int64, pharmacyId int64) (*dto.PharmacyScheduleResponse, error) {
+	schedule, err := u.findManagedPharmacySchedule(ctx, accountId, pharmacyId)
+	if err != nil {
+		return nil, err
+	}
+
+	openingStatus, err := u.pharmacyHours.GetOpeningStatus(ctx, *schedule, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.ConvertToPharmacyScheduleResponse(*schedule, *openingStatus)
+
+	return &response, nil
+}
+
+func (u *pharmacyUsecaseImpl) UpdatePharmacySchedule(ctx context.Context, accountId int64, pharmacyId int64, pharmacyScheduleRequest dto.PharmacyScheduleRequest) error {
+	operationalDays := map[string]bool{}
+	for _, operational := range pharmacyScheduleRequest.Operationals {
+		if operationalDays[operational.OperationalDay] {
+			return apperror.InvalidPharmacyOperationalError()
+		}
+		operationalDays[operational.OperationalDay] = true
+
+		if !validPharmacyHours(operational.OpenHour, operational.CloseHour) {
+			return apperror.InvalidPharmacyOperationalError()
+		}
+	}
+
+	if _, err := u.findManagedPharmacySchedule(ctx, accountId, pharmacyId); err != nil {
+		return err
+	}
+
+	tx, err := u.transaction.BeginTx(ctx)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	pharmacyRepo := tx.PharmacyRepository()
+	pharmacyOperationalRepo := tx.PharmacyOperationalRepository()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+
+		tx.Commit()
+	}()
+
+	if err = pharmacyRepo.UpdateTimezoneById(ctx, pharmacyId, pharmacyScheduleRequest.Timezone); err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	pharmacyOperationals := dto.ConvertPharmacyScheduleRequestToPharmacyOperationals(pharmacyId, pharmacyScheduleRequest)
+
+	for i := 0; i < len(pharmacyOperationals); i++ {
+		var rowsAffected int64
+		rowsAffected, err = pharmacyOperationalRepo.UpdateOneByPharmacyIdAndDay(ctx, pharmacyOperationals[i])
+		if err != nil {
+			return apperror.InternalServerError(err)
+		}
+
+		if rowsAffected == 0 {
+			err = apperror.PharmacyOperationalNotFoundError()
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (u *pharmacyUsecaseImpl) AddPharmacyHoliday(ctx context.Context, accountId int64, pharmacyId int64, pharmacyHolidayRequest dto.PharmacyHolidayRequest) error {
+	date, err := time.Parse("2006-01-02", pharmacyHolidayRequest.Date)
+	if err != nil {
+		return apperror.InvalidPharmacyHolidayError()
+	}
+
+	pharmacyHoliday := entity.PharmacyHoliday{
+		PharmacyId:  pharmacyId,
+		Date:        date,
+		IsOpen:      pharmacyHolidayRequest.IsOpen,
+		Description: strings.TrimSpace(pharmacyHolidayRequest.Description),
+	}
+
+	if pharmacyHoliday.IsOpen {
+		if pharmacyHolidayRequest.OpenHour == nil || pharmacyHolidayRequest.CloseHour == nil {
+			return apperror.InvalidPharmacyHolidayError()
+		}
+
+		if !validPharmacyHours(*pharmacyHolidayRequest.OpenHour, *pharmacyHolidayRequest.CloseHour) {
+			return apperror.InvalidPharmacyHolidayError()
+		}
+
+		pharmacyHoliday.OpenHour = pharmacyHolidayRequest.OpenHour
+		pharmacyHoliday.CloseHour = pharmacyHolidayRequest.CloseHour
+	}
+
+	schedule, err := u.findManagedPharmacySchedule(ctx, accountId, pharmacyId)
+	if err != nil {
+		return err
+	}
+
+	location, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	now := time.Now().In(location)
+	if date.Before(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)) {
+		return apperror.InvalidPharmacyHolidayError()
+	}
+
+	if _, err = u.pharmacyHolidayRepository.CreateOne(ctx, pharmacyHoliday); err != nil {
+		if repository.IsUniqueViolation(err) {
+			return apperror.PharmacyHolidayExistsError()
+		}
+		return apperror.InternalServerError(err)
+	}
+
+	return nil
+}
+
+func (u *pharmacyUsecaseImpl) DeletePharmacyHoliday(ctx context.Context, accountId int64, pharmacyId int64, pharmacyHolidayId int64) error {
+	if _, err := u.findManagedPharmacySchedule(ctx, accountId, pharmacyId); err != nil {
+		return err
+	}
+
+	deletedCount, err := u.pharmacyHolidayRepository.DeleteOneById(ctx, pharmacyId, pharmacyHolidayId)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+	if deletedCount == 0 {
+		return apperror.PharmacyHolidayNotFoundError()
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/sidiqPratomo/max-health-backend/apperror"
+	"github.com/sidiqPratomo/max-health-backend/entity"
+	"github.com/sidiqPratomo/max-health-backend/repository"
+)
+
+type PrescriptionCompliance interface {
+	FindPrescriptionDrugForCart(ctx context.Context, userAccountId int64, pharmacyDrug entity.PharmacyDrugDetail, prescriptionId *int64, quantity int) (*int64, error)
+	CheckCartQuantity(ctx context.Context, cartItemId int64, quantity int) error
+	LinkPrescriptionDrugs(cartItems []entity.CartItemForCheckout, prescriptionDrugs []entity.PrescriptionDrug)
+	RedeemCartItems(ctx context.Context, tx repository.Transaction, userAccountId int64, cartItems []entity.CartItemForCheckout) error
+}
+
+type prescriptionComplianceImpl struct {
+	prescriptionDrugRepository repository.PrescriptionDrugRepository
+}
+
+func NewPrescriptionComplianceImpl(prescriptionDrugRepository repository.PrescriptionDrugRepository) prescriptionComplianceImpl {
+	return prescriptionComplianceImpl{
+		prescriptionDrugRepository: prescriptionDrugRepository,
+	}
+}
+
+// A prescription can be redeemed while it belongs to the user, is still
+// within its validity window and has not been fully ordered yet.
+func (c *prescriptionComplianceImpl) FindPrescriptionDrugForCart(ctx context.Context, userAccountId int64, pharmacyDrug entity.PharmacyDrugDetail, prescriptionId *int64, quantity int) (*int64, error) {
+	if !pharmacyDrug.IsPrescriptionRequired {
+		return nil, nil
+	}
+
+	if prescriptionId == nil {
+		return nil, apperror.PrescriptionRequiredError(pharmacyDrug.DrugName)
+	}
+
+	prescriptionDrug, err := c.prescriptionDrugRepository.FindOneRedeemable(ctx, userAccountId, *prescriptionId, pharmacyDrug.DrugId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+	if prescriptionDrug == nil {
+		return nil, apperror.PrescriptionRequiredError(pharmacyDrug.DrugName)
+	}
+
+	if quantity > prescriptionDrug.RemainingQuantity {
+		return nil, apperror.PrescriptionQuantityExceededError(pharmacyDrug.DrugName)
+	}
+
+	return &prescriptionDrug.Id, nil
+}
+
+func (c *prescriptionComplianceImpl) CheckCartQuantity(ctx context.Context, cartItemId int64, quantity int) error {
+	prescriptionDrug, err := c.prescriptionDrugRepository.GetPrescriptionDrugByCartItemId(ctx, cartItemId)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+	if prescriptionDrug == nil {
+		return nil
+	}
+
+	if quantity > prescriptionDrug.RemainingQuantity {
+		return apperror.PrescriptionQuantityExceededError(prescriptionDrug.Drug.Name)
+	}
+
+	return nil
+}
+
+func (c *prescriptionComplianceImpl) LinkPrescriptionDrugs(cartItems []entity.CartItemForCheckout, prescriptionDrugs []entity.PrescriptionDrug) {
+	for i := range cartItems {
+		for _, prescriptionDrug := range prescriptionDrugs {
+			if prescriptionDrug.Drug.Id == cartItems[i].DrugId {
+				prescriptionDrugId := prescriptionDrug.Id
+				cartItems[i].PrescriptionDrugId = &prescriptionDrugId
+				break
+			}
+		}
+	}
+}
+
+// The prescription lines are locked for the rest of the transaction, so two
+// checkouts cannot both spend the same remaining quantity. Once every line is
+// used up the next refill is started, or the prescription is closed when no
+// refills are left.
+func (c *prescriptionComplianceImpl) RedeemCartItems(ctx context.Context, tx repository.Transaction, userAccountId int64, cartItems []entity.CartItemForCheckout) error {
+	prescriptionDrugRepo := tx.PrescriptionDrugRepository()
+	prescriptionRepo := tx.PrescriptionRepository()
+
+	prescriptionDrugIds := []int64{}
+	quantities := map[int64]int{}
+	drugNames := map[int64]string{}
+	drugIds := map[int64]int64{}
+
+	for _, cartItem := range cartItems {
+		if cartItem.PrescriptionDrugId == nil {
+			if cartItem.IsPrescriptionRequired {
+				return apperror.PrescriptionRequiredError(cartItem.DrugName)
+			}
+			continue
+		}
+
+		prescriptionDrugId := *cartItem.PrescriptionDrugId
+		if _, ok := quantities[prescriptionDrugId]; !ok {
+			prescriptionDrugIds = append(prescriptionDrugIds, prescriptionDrugId)
+		}
+		quantities[prescriptionDrugId] += cartItem.Quantity
+		drugNames[prescriptionDrugId] = cartItem.DrugName
+		drugIds[prescriptionDrugId] = cartItem.DrugId
+	}
+
+	if len(prescriptionDrugIds) == 0 {
+		return nil
+	}
+
+	prescriptionDrugs, err := prescriptionDrugRepo.FindAllRedeemableByIdsForUpdate(ctx, userAccountId, prescriptionDrugIds)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	redeemable := map[int64]entity.PrescriptionDrug{}
+	for _, prescriptionDrug := range prescriptionDrugs {
+		redeemable[prescriptionDrug.Id] = prescriptionDrug
+	}
+
+	prescriptionIds := []int64{}
+	seenPrescriptionIds := map[int64]bool{}
+
+	for _, prescriptionDrugId := range prescriptionDrugIds {
+		prescriptionDrug, ok := redeemable[prescriptionDrugId]
+		if !ok || prescriptionDrug.Drug.Id != drugIds[prescriptionDrugId] {
+			return apperror.PrescriptionRequiredError(drugNames[prescriptionDrugId])
+		}
+
+		if quantities[prescriptionDrugId] > prescriptionDrug.RemainingQuantity {
+			return apperror.PrescriptionQuantityExceededError(drugNames[prescriptionDrugId])
+		}
+
+		updatedCount, err := prescriptionDrugRepo.DecreaseRemainingQuantity(ctx, prescriptionDrugId, quantities[prescriptionDrugId])
+		if err != nil {
+			return apperror.InternalServerError(err)
+		}
+		if updatedCount == 0 {
+			return apperror.PrescriptionQuantityExceededError(drugNames[prescriptionDrugId])
+		}
+
+		if !seenPrescriptionIds[prescriptionDrug.PrescriptionId] {
+			seenPrescriptionIds[prescriptionDrug.PrescriptionId] = true
+			prescriptionIds = append(prescriptionIds, prescriptionDrug.PrescriptionId)
+		}
+	}
+
+	for _, prescriptionId := range prescriptionIds {
+		if err := prescriptionRepo.RefillOneIfExhausted(ctx, prescriptionId); err != nil {
+			return apperror.InternalServerError(err)
+		}
+
+		if err := prescriptionRepo.SetPrescriptionOrderedAtNowIfExhausted(ctx, prescriptionId); err != nil {
+			return apperror.InternalServerError(err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/sidiqPratomo/max-health-backend/appconstant"
+	"github.com/sidiqPratomo/max-health-backend/apperror"
+	"github.com/sidiqPratomo/max-health-backend/dto"
+	"github.com/sidiqPratomo/max-health-backend/entity"
+	"github.com/sidiqPratomo/max-health-backend/repository"
+	"github.com/sidiqPratomo/max-health-backend/util"
+)
+
+type PrescriptionDocumentUsecase interface {
+	GetPrescriptionDocument(ctx context.Context, accountId, prescriptionId int64) ([]byte, error)
+	VerifyPrescription(ctx context.Context, prescriptionId int64, signature string) (*dto.PrescriptionVerificationResponse, error)
+}
+
+type prescriptionDocumentUsecaseImpl struct {
+	prescriptionRepository     repository.PrescriptionRepository
+	prescriptionDrugRepository repository.PrescriptionDrugRepository
+	prescriptionSigner         util.PrescriptionSigner
+	apiBaseUrl                 string
+}
+
+func NewPrescriptionDocumentUsecaseImpl(prescriptionRepository repository.PrescriptionRepository, prescriptionDrugRepository repository.PrescriptionDrugRepository, prescriptionSigner util.PrescriptionSigner, apiBaseUrl string) prescriptionDocumentUsecaseImpl {
+	return prescriptionDocumentUsecaseImpl{
+		prescriptionRepository:     prescriptionRepository,
+		prescriptionDrugRepository: prescriptionDrugRepository,
+		prescriptionSigner:         prescriptionSigner,
+		apiBaseUrl:                 apiBaseUrl,
+	}
+}
+
+func (u *prescriptionDocumentUsecaseImpl) GetPrescriptionDocument(ctx context.Context, accountId, prescriptionId int64) ([]byte, error) {
+	prescription, err := u.getPrescriptionWithDrugs(ctx, prescriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	if prescription.UserAccountId != accountId && prescription.DoctorAccountId != accountId {
+		return nil, apperror.InvalidPrescriptionIdError()
+	}
+
+	signature, err := u.prescriptionSigner.Sign(*prescription)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	verificationUrl := fmt.Sprintf(appconstant.PrescriptionVerificationUrl, u.apiBaseUrl, prescriptionId, url.QueryEscape(signature))
+
+	document, err := util.GeneratePrescriptionDocument(*prescription, verificationUrl)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	return document, nil
+}
+
+func (u *prescriptionDocumentUsecaseImpl) VerifyPrescription(ctx context.Context, prescriptionId int64, signature string) (*dto.PrescriptionVerificationResponse, error) {
+	prescription, err := u.getPrescriptionWithDrugs(ctx, prescriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	isValid, err := u.prescriptionSigner.Verify(*prescription, signature)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+	if !isValid {
+		return nil, apperror.PrescriptionSignatureInvalidError()
+	}
+
+	response := dto.ConvertToPrescriptionVerificationResponse(*prescription)
+
+	return &response, nil
+}
+
+func (u *prescriptionDocumentUsecaseImpl) getPrescriptionWithDrugs(ctx context.Context, prescriptionId int64) (*entity.Prescription, error) {
+	prescription, err := u.prescriptionRepository.GetPrescriptionDocumentById(ctx, prescriptionId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+	if prescription == nil {
+		return nil, apperror.InvalidPrescriptionIdError()
+	}
+
+	prescription.PrescriptionDrugs, err = u.prescriptionDrugRepository.GetAllPrescriptionDrug(ctx, prescriptionId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	return prescription, nil
+}
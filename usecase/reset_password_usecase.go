@@ -123,6 +123,17 @@ func (u *authenticationUsecaseImpl) ResetPassword(ctx context.Context, resetPass
 		return apperror.InternalServerError(err)
 	}
 
+	err = u.resetPassword(ctx, resetPasswordToken, newPassword)
+	if err != nil {
+		return err
+	}
+
+	u.accountTokenVersion.Forget(resetPasswordToken.AccountId)
+
+	return nil
+}
+
+func (u *authenticationUsecaseImpl) resetPassword(ctx context.Context, resetPasswordToken *entity.ResetPasswordToken, newPassword string) error {
 	tx, err := u.transaction.BeginTx(ctx)
 	if err != nil {
 		return apperror.InternalServerError(err)
@@ -130,6 +141,8 @@ func (u *authenticationUsecaseImpl) ResetPassword(ctx context.Context, resetPass
 
 	accountRepo := tx.AccountRepository()
 	resetPasswordTokenRepo := tx.ResetPasswordTokenRepository()
+	accountSessionRepo := tx.AccountSessionRepository()
+	refreshTokenRepo := tx.RefreshTokenRepository()
 
 	defer func() {
 		if err != nil {
@@ -147,5 +160,17 @@ func (u *authenticationUsecaseImpl) ResetPassword(ctx context.Context, resetPass
 		return apperror.InternalServerError(err)
 	}
 
+	if err = accountRepo.IncrementTokenVersionById(ctx, resetPasswordToken.AccountId); err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	if err = accountSessionRepo.RevokeAllByAccountId(ctx, resetPasswordToken.AccountId); err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	if err = refreshTokenRepo.InvalidateCodes(ctx, resetPasswordToken.AccountId); err != nil {
+		return apperror.InternalServerError(err)
+	}
+
 	return nil
 }
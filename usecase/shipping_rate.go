@@ -0,0 +1,26 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/sidiqPratomo/max-health-backend/apperror"
+	"github.com/sidiqPratomo/max-health-backend/entity"
+	"github.com/sidiqPratomo/max-health-backend/util"
+)
+
+func fillCourierOptions(ctx context.Context, shippingRateProvider util.ShippingRateProvider, availableCouriers []entity.AvailableCourier) error {
+	for i := range availableCouriers {
+		if availableCouriers[i].ShippingRateRequest == nil {
+			continue
+		}
+
+		courierOptions, err := shippingRateProvider.GetShippingRates(ctx, *availableCouriers[i].ShippingRateRequest)
+		if err != nil {
+			return apperror.ShippingRateUnavailableError(err)
+		}
+
+		availableCouriers[i].CourierOptions = courierOptions
+	}
+
+	return nil
+}
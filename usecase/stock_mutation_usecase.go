@@ -0,0 +1,283 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/sidiqPratomo/max-health-backend/appconstant"
+	"github.com/sidiqPratomo/max-health-backend/apperror"
+	"github.com/sidiqPratomo/max-health-backend/dto"
+	"github.com/sidiqPratomo/max-health-backend/entity"
+	"github.com/sidiqPratomo/max-health-backend/repository"
+	"github.com/sidiqPratomo/max-health-backend/util"
+)
+
+type stockMutationTransition struct {
+	fromStatus      string
+	isTargetManaged bool
+}
+
+// The target pharmacy answers and ships a request, the requester confirms it
+// arrived.
+var stockMutationTransitions = map[string]stockMutationTransition{
+	appconstant.StockMutationApproved: {fromStatus: appconstant.StockMutationPending, isTargetManaged: true},
+	appconstant.StockMutationRejected: {fromStatus: appconstant.StockMutationPending, isTargetManaged: true},
+	appconstant.StockMutationShipped:  {fromStatus: appconstant.StockMutationApproved, isTargetManaged: true},
+	appconstant.StockMutationReceived: {fromStatus: appconstant.StockMutationShipped, isTargetManaged: false},
+}
+
+type StockMutationUsecase interface {
+	GetStockMutationRequests(ctx context.Context, accountId int64, query dto.StockMutationRequestQuery) (*dto.AllStockMutationRequestsResponse, error)
+	UpdateStockMutationRequestStatus(ctx context.Context, accountId, stockMutationRequestId int64, status string) (*dto.StockMutationRequestResponse, error)
+}
+
+type stockMutationUsecaseImpl struct {
+	stockMutationRepository   repository.StockMutationRepository
+	pharmacyManagerRepository repository.PharmacyManagerRepository
+	transaction               repository.Transaction
+}
+
+func NewStockMutationUsecaseImpl(stockMutationRepository repository.StockMutationRepository, pharmacyManagerRepository repository.PharmacyManagerRepository, transaction repository.Transaction) stockMutationUsecaseImpl {
+	return stockMutationUsecaseImpl{
+		stockMutationRepository:   stockMutationRepository,
+		pharmacyManagerRepository: pharmacyManagerRepository,
+		transaction:               transaction,
+	}
+}
+
+func (u *stockMutationUsecaseImpl) GetStockMutationRequests(ctx context.Context, accountId int64, query dto.StockMutationRequestQuery) (*dto.AllStockMutationRequestsResponse, error) {
+	manager, err := u.pharmacyManagerRepository.FindOneByAccountId(ctx, accountId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+	if manager == nil {
+		return nil, apperror.PharmacyManagerNotFoundError()
+	}
+
+	params, err := util.SetDefaultQueryParams(util.QueryParam{Page: query.Page, Limit: query.Limit})
+	if err != nil {
+		return nil, err
+	}
+
+	stockMutationRequestFilter := entity.StockMutationRequestFilter{
+		ManagerId: manager.Id,
+		Direction: query.Direction,
+	}
+	if query.Status != "" {
+		stockMutationRequestFilter.Status = &query.Status
+	}
+
+	stockMutationRequestFilter.Limit, _ = strconv.Atoi(params.Limit)
+	stockMutationRequestFilter.Offset, _ = strconv.Atoi(params.Offset)
+
+	stockMutationRequests, pageInfo, err := u.stockMutationRepository.FindAll(ctx, stockMutationRequestFilter)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	response := dto.ConvertToAllStockMutationRequestsResponse(stockMutationRequests, *pageInfo)
+
+	return &response, nil
+}
+
+func (u *stockMutationUsecaseImpl) UpdateStockMutationRequestStatus(ctx context.Context, accountId, stockMutationRequestId int64, status string) (*dto.StockMutationRequestResponse, error) {
+	transition, ok := stockMutationTransitions[status]
+	if !ok {
+		return nil, apperror.InvalidStockMutationStatusError()
+	}
+
+	manager, err := u.pharmacyManagerRepository.FindOneByAccountId(ctx, accountId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+	if manager == nil {
+		return nil, apperror.PharmacyManagerNotFoundError()
+	}
+
+	tx, err := u.transaction.BeginTx(ctx)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	stockMutationRepo := tx.StockMutationRepo()
+	pharmacyDrugRepo := tx.PharmacyDrugRepo()
+	stockChangeRepo := tx.StockChangeRepo()
+	pharmacyDrugBatchRepo := tx.PharmacyDrugBatchRepository()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+
+		tx.Commit()
+	}()
+
+	stockMutationRequest, err := stockMutationRepo.FindOneByIdForUpdate(ctx, stockMutationRequestId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+	if stockMutationRequest == nil {
+		return nil, apperror.StockMutationNotFoundError()
+	}
+
+	managerId := stockMutationRequest.RequesterManagerId
+	if transition.isTargetManaged {
+		managerId = stockMutationRequest.TargetManagerId
+	}
+	if managerId != manager.Id {
+		return nil, apperror.ForbiddenAction()
+	}
+	if stockMutationRequest.Status != transition.fromStatus {
+		return nil, apperror.InvalidStockMutationStatusError()
+	}
+	if stockMutationRequest.RequesterPharmacyDrugId == nil || stockMutationRequest.TargetPharmacyDrugId == nil {
+		return nil, apperror.DrugNotFoundError()
+	}
+
+	requesterDrug, err := pharmacyDrugRepo.GetPharmacyDrugByIdForUpdate(ctx, *stockMutationRequest.RequesterPharmacyDrugId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+	targetDrug, err := pharmacyDrugRepo.GetPharmacyDrugByIdForUpdate(ctx, *stockMutationRequest.TargetPharmacyDrugId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+	if requesterDrug == nil || targetDrug == nil {
+		return nil, apperror.DrugNotFoundError()
+	}
+
+	description := fmt.Sprintf(appconstant.StockMutationTransitionDescription, stockMutationRequestId, status)
+	stockChanges := []entity.StockChange{}
+
+	switch status {
+	case appconstant.StockMutationApproved:
+		if targetDrug.Stock < stockMutationRequest.Stock {
+			return nil, apperror.InsufficientStockError()
+		}
+
+		stockChanges = append(stockChanges,
+			entity.StockChange{PharmacyDrugId: targetDrug.Id, FinalStock: targetDrug.Stock - stockMutationRequest.Stock,
+				Amount: -1 * stockMutationRequest.Stock, Description: description},
+			entity.StockChange{PharmacyDrugId: requesterDrug.Id, FinalStock: requesterDrug.Stock + stockMutationRequest.Stock,
+				Amount: stockMutationRequest.Stock, Description: description},
+		)
+
+		err = pharmacyDrugRepo.UpdatePharmacyDrugsForStockMutation(ctx, stockChanges)
+		if err != nil {
+			return nil, apperror.InternalServerError(err)
+		}
+
+		var allocations []entity.PharmacyDrugBatchAllocation
+		allocations, err = deductBatches(ctx, pharmacyDrugBatchRepo, targetDrug.Id, stockMutationRequest.Stock,
+			entity.PharmacyDrugBatchAllocation{StockMutationRequestId: &stockMutationRequestId})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, allocation := range allocations {
+			err = restockBatch(ctx, pharmacyDrugBatchRepo, entity.PharmacyDrugBatch{PharmacyDrugId: requesterDrug.Id,
+				BatchNumber: allocation.BatchNumber, ExpiryDate: allocation.ExpiryDate, Stock: allocation.Quantity})
+			if err != nil {
+				return nil, err
+			}
+		}
+	case appconstant.StockMutationShipped:
+		stockChanges = append(stockChanges, entity.StockChange{PharmacyDrugId: targetDrug.Id, FinalStock: targetDrug.Stock,
+			Amount: 0, Description: description})
+	default:
+		stockChanges = append(stockChanges, entity.StockChange{PharmacyDrugId: requesterDrug.Id, FinalStock: requesterDrug.Stock,
+			Amount: 0, Description: description})
+	}
+
+	for i := range stockChanges {
+		stockChanges[i].ActorAccountId = &accountId
+	}
+
+	err = stockChangeRepo.PostStockChangesFromMutation(ctx, stockChanges)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	if status == appconstant.StockMutationRejected && stockMutationRequest.OrderId != nil {
+		err = cancelOrderOfRejectedStockMutation(ctx, tx, *stockMutationRequest.OrderId, accountId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = stockMutationRepo.UpdateStatusOne(ctx, stockMutationRequestId, status)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	updatedStockMutationRequest, err := stockMutationRepo.FindOneById(ctx, stockMutationRequestId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	response := dto.ConvertToStockMutationRequestResponse(*updatedStockMutationRequest)
+
+	return &response, nil
+}
+
+// cancelOrderOfRejectedStockMutation cancels the order a rejected request was
+// raised for, since the ordered pharmacy cannot cover it without the stock.
+// Orders a pharmacy has already started processing cannot be rolled back.
+func cancelOrderOfRejectedStockMutation(ctx context.Context, tx repository.Transaction, orderId int64, accountId int64) error {
+	orderPharmacies, err := tx.OrderPharmacyRepository().FindAllByOrderIdForUpdate(ctx, orderId)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	isCancelled := true
+	for _, orderPharmacy := range orderPharmacies {
+		switch orderPharmacy.OrderStatusId {
+		case appconstant.OrderStatusCanceled:
+			continue
+		case appconstant.OrderStatusWaitingForPayment, appconstant.OrderStatusWaitingForPaymentConfirmation:
+			isCancelled = false
+		default:
+			return apperror.StockMutationOrderStartedError()
+		}
+	}
+	if isCancelled {
+		return nil
+	}
+
+	err = tx.OrderPharmacyRepository().UpdateStatusBulkByOrderId(ctx, orderId, appconstant.OrderStatusCanceled)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	err = tx.PrescriptionDrugRepository().RestoreByOrderId(ctx, orderId)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	stockChanges, err := tx.PharmacyDrugRepo().UpdatePharmacyDrugsByOrderId(ctx, orderId)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	err = tx.PharmacyDrugBatchRepository().ReleaseAllocationsByOrderId(ctx, orderId)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	err = tx.StockMutationRepo().CancelPendingByOrderId(ctx, orderId)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	for i := range stockChanges {
+		stockChanges[i].ActorAccountId = &accountId
+	}
+
+	err = tx.StockChangeRepo().PostStockChanges(ctx, stockChanges)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	return nil
+}
@@ -2,14 +2,29 @@ package usecase
 
 import (
 	"context"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
 
+	"github.com/sidiqPratomo/max-health-backend/appconstant"
 	"github.com/sidiqPratomo/max-health-backend/apperror"
 	"github.com/sidiqPratomo/max-health-backend/dto"
+	"github.com/sidiqPratomo/max-health-backend/entity"
 	"github.com/sidiqPratomo/max-health-backend/repository"
+	"github.com/sidiqPratomo/max-health-backend/util"
 )
 
+const stockChangeExportFlushSize = 500
+
+var stockChangeExportHeader = []string{
+	"id", "created_at", "pharmacy_id", "pharmacy_name", "drug_id", "drug_name", "pharmacy_drug_id",
+	"change_type", "amount", "final_stock", "description", "actor_account_id", "actor_name",
+}
+
 type StockUsecase interface {
-	GetAllStockChanges(ctx context.Context, accountId int64, pharmacyId *int64) ([]dto.StockChangeResponse, error)
+	GetAllStockChanges(ctx context.Context, accountId int64, query dto.StockChangeQuery) (*dto.AllStockChangesResponse, error)
+	ExportStockChanges(ctx context.Context, accountId int64, query dto.StockChangeQuery, w io.Writer) error
 }
 
 type stockUsecaseImpl struct {
@@ -24,7 +39,7 @@ func NewStockUsecaseImpl(stockRepository repository.StockChangeRepository, manag
 	}
 }
 
-func (u *stockUsecaseImpl) GetAllStockChanges(ctx context.Context, accountId int64, pharmacyId *int64) ([]dto.StockChangeResponse, error) {
+func (u *stockUsecaseImpl) stockChangeFilter(ctx context.Context, accountId int64, query dto.StockChangeQuery) (*entity.StockChangeFilter, error) {
 	manager, err := u.managerRepository.FindOneByAccountId(ctx, accountId)
 	if err != nil {
 		return nil, apperror.InternalServerError(err)
@@ -33,9 +48,128 @@ func (u *stockUsecaseImpl) GetAllStockChanges(ctx context.Context, accountId int
 		return nil, apperror.PharmacyManagerNotFoundError()
 	}
 
-	stockChanges, err := u.stockRepository.GetStockChanges(ctx, manager.Id, pharmacyId)
+	stockChangeFilter := entity.StockChangeFilter{
+		ManagerId:  manager.Id,
+		PharmacyId: query.PharmacyId,
+		DrugId:     query.DrugId,
+		ChangeType: query.ChangeType,
+		AmountSign: query.Sign,
+	}
+
+	if query.StartDate != nil {
+		startDate, err := time.Parse(appconstant.StockChangeDateFormat, *query.StartDate)
+		if err != nil {
+			return nil, apperror.BadRequestError(err)
+		}
+		stockChangeFilter.StartDate = &startDate
+	}
+
+	// The end date is inclusive, so the filter stops at the start of the
+	// following day.
+	if query.EndDate != nil {
+		endDate, err := time.Parse(appconstant.StockChangeDateFormat, *query.EndDate)
+		if err != nil {
+			return nil, apperror.BadRequestError(err)
+		}
+		if stockChangeFilter.StartDate != nil && endDate.Before(*stockChangeFilter.StartDate) {
+			return nil, apperror.InvalidStockChangeDateRangeError()
+		}
+		endDate = endDate.AddDate(0, 0, 1)
+		stockChangeFilter.EndDate = &endDate
+	}
+
+	return &stockChangeFilter, nil
+}
+
+func (u *stockUsecaseImpl) GetAllStockChanges(ctx context.Context, accountId int64, query dto.StockChangeQuery) (*dto.AllStockChangesResponse, error) {
+	stockChangeFilter, err := u.stockChangeFilter(ctx, accountId, query)
+	if err != nil {
+		return nil, err
+	}
+
+	params, err := util.SetDefaultQueryParams(util.QueryParam{Page: query.Page, Limit: query.Limit})
+	if err != nil {
+		return nil, err
+	}
+
+	stockChangeFilter.Limit, _ = strconv.Atoi(params.Limit)
+	stockChangeFilter.Offset, _ = strconv.Atoi(params.Offset)
+
+	stockChanges, pageInfo, err := u.stockRepository.GetStockChanges(ctx, *stockChangeFilter)
 	if err != nil {
 		return nil, apperror.InternalServerError(err)
 	}
-	return stockChanges, nil
+
+	response := dto.ConvertToAllStockChangesResponse(stockChanges, *pageInfo)
+
+	return &response, nil
+}
+
+func (u *stockUsecaseImpl) ExportStockChanges(ctx context.Context, accountId int64, query dto.StockChangeQuery, w io.Writer) error {
+	stockChangeFilter, err := u.stockChangeFilter(ctx, accountId, query)
+	if err != nil {
+		return err
+	}
+
+	csvWriter := csv.NewWriter(w)
+
+	err = csvWriter.Write(stockChangeExportHeader)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	writtenCount := 0
+
+	err = u.stockRepository.StreamStockChanges(ctx, *stockChangeFilter, func(stockChange entity.StockChangeDetail) error {
+		err := csvWriter.Write(stockChangeExportRecord(stockChange))
+		if err != nil {
+			return err
+		}
+
+		writtenCount++
+		if writtenCount%stockChangeExportFlushSize == 0 {
+			csvWriter.Flush()
+			return csvWriter.Error()
+		}
+
+		return nil
+	})
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	csvWriter.Flush()
+	if err := csvWriter.Error(); err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	return nil
+}
+
+func stockChangeExportRecord(stockChange entity.StockChangeDetail) []string {
+	actorAccountId := ""
+	if stockChange.ActorAccountId != nil {
+		actorAccountId = strconv.FormatInt(*stockChange.ActorAccountId, 10)
+	}
+
+	actorName := ""
+	if stockChange.ActorName != nil {
+		actorName = *stockChange.ActorName
+	}
+
+	return []string{
+		strconv.FormatInt(stockChange.Id, 10),
+		stockChange.CreatedAt.Format(time.RFC3339),
+		strconv.FormatInt(stockChange.PharmacyId, 10),
+		util.EscapeSpreadsheetCell(stockChange.PharmacyName),
+		strconv.FormatInt(stockChange.DrugId, 10),
+		util.EscapeSpreadsheetCell(stockChange.DrugName),
+		strconv.FormatInt(stockChange.PharmacyDrugId, 10),
+		stockChange.ChangeType,
+		strconv.Itoa(stockChange.Amount),
+		strconv.Itoa(stockChange.FinalStock),
+		util.EscapeSpreadsheetCell(stockChange.Description),
+		actorAccountId,
+		util.EscapeSpreadsheetCell(actorName),
+	}
 }
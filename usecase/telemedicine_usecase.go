@@ -6,7 +6,6 @@ import (
 	"mime/multipart"
 	"net/http"
 	"strconv"
-	"sync"
 	"time"
 
 	"github.com/sidiqPratomo/max-health-backend/appconstant"
@@ -15,25 +14,25 @@ import (
 	"github.com/sidiqPratomo/max-health-backend/entity"
 	"github.com/sidiqPratomo/max-health-backend/repository"
 	"github.com/sidiqPratomo/max-health-backend/util"
-	"github.com/shopspring/decimal"
 )
 
 type TelemedicineUsecase interface {
-	UserCreateRoom(ctx context.Context, userAccountId, doctorAccountId int64) (*int64, error)
+	UserCreateRoom(ctx context.Context, userAccountId, doctorAccountId int64, allowFallback bool) (*int64, error)
 	DoctorJoinRoom(ctx context.Context, doctorAccountId, roomId int64) error
+	DoctorDeclineRoom(ctx context.Context, doctorAccountId, roomId int64) error
+	GetQueueStatus(ctx context.Context, accountId, roomId int64) (*dto.ConsultationQueueResponse, error)
 	PostOneMessage(ctx context.Context, accountId int64, postOneMessageRequest dto.PostOneMessageRequest, file multipart.File, fileHeader *multipart.FileHeader) (*dto.Chat, error)
-	Listen(ctx context.Context, accountId int64, roomId int64) (*dto.Chat, error)
-	findListenerBySender(sender entity.Participant) bool
-	findListener(listener entity.Participant) bool
-	addListener(listener entity.Participant)
-	removeListener(listener entity.Participant)
+	SubscribeChatRoom(ctx context.Context, accountId, roomId int64) (*entity.ChatSubscription, error)
+	UnsubscribeChatRoom(subscription *entity.ChatSubscription)
+	PublishChatEvent(ctx context.Context, accountId, roomId int64, chatEventRequest dto.ChatEventRequest) error
 	GetAllChat(ctx context.Context, accountId, roomId int64) (*dto.ChatRoom, error)
 	GetAllChatRoomPreview(ctx context.Context, accountId int64, role string) ([]dto.ChatRoomPreview, error)
 	DoctorGetChatRequest(ctx context.Context, accountId int64) ([]dto.ChatRoomPreview, error)
 	SavePrescription(ctx context.Context, accountId, prescriptionId int64) error
 	GetAllPrescriptions(ctx context.Context, accountId int64, limit, page string) (*dto.PrescriptionResponseList, error)
-	PrepareForCheckout(ctx context.Context, accountId, prescriptionId int64, addressIdString string) (*dto.PreapareForCheckoutResponse, error)
+	PrepareForCheckout(ctx context.Context, accountId, prescriptionId int64, addressIdString string, strategy string) (*dto.PreapareForCheckoutResponse, error)
 	CheckoutFromPrescription(ctx context.Context, checkoutFromPrescriptionRequest dto.CheckoutFromPrescriptionRequest) (*int64, error)
+	GetPrescriptionRedemptions(ctx context.Context, accountId, prescriptionId int64) (*dto.PrescriptionRedemptionsResponse, error)
 	CloseChatRoom(ctx context.Context, userAccountId, roomId int64) error
 }
 
@@ -49,14 +48,15 @@ type telemedicineUsecaseImpl struct {
 	orderRepository            repository.OrderRepository
 	userAddressRepository      repository.UserAddressRepository
 	pharmacyRepository         repository.PharmacyRepository
-	chatChannel                map[int64]chan entity.Chat
-	listeners                  []entity.Participant
-	listenersLock              sync.Mutex
-	abortChannel               chan entity.Participant
+	chatBroker                 ChatBroker
+	prescriptionCompliance     PrescriptionCompliance
+	fulfilmentOptimizer        FulfilmentOptimizer
+	consultationQueue          ConsultationQueue
+	pricingEngine              PricingEngine
 	transaction                repository.Transaction
 }
 
-func NewTelemedicineUsecaseImpl(chatRoomRepository repository.ChatRoomRepository, chatRepository repository.ChatRepository, userRepository repository.UserRepository, doctorRepository repository.DoctorRepository, pharmacyDrugRepository repository.PharmacyDrugRepository, prescriptionDrugRepository repository.PrescriptionDrugRepository, prescriptionRepository repository.PrescriptionRepository, cartRepository repository.CartRepository, orderRepository repository.OrderRepository, userAddressRepository repository.UserAddressRepository, pharmacyRepository repository.PharmacyRepository, transaction repository.Transaction) telemedicineUsecaseImpl {
+func NewTelemedicineUsecaseImpl(chatRoomRepository repository.ChatRoomRepository, chatRepository repository.ChatRepository, userRepository repository.UserRepository, doctorRepository repository.DoctorRepository, pharmacyDrugRepository repository.PharmacyDrugRepository, prescriptionDrugRepository repository.PrescriptionDrugRepository, prescriptionRepository repository.PrescriptionRepository, cartRepository repository.CartRepository, orderRepository repository.OrderRepository, userAddressRepository repository.UserAddressRepository, pharmacyRepository repository.PharmacyRepository, chatBroker ChatBroker, prescriptionCompliance PrescriptionCompliance, fulfilmentOptimizer FulfilmentOptimizer, consultationQueue ConsultationQueue, pricingEngine PricingEngine, transaction repository.Transaction) telemedicineUsecaseImpl {
 	return telemedicineUsecaseImpl{
 		chatRoomRepository:         chatRoomRepository,
 		chatRepository:             chatRepository,
@@ -69,15 +69,16 @@ func NewTelemedicineUsecaseImpl(chatRoomRepository repository.ChatRoomRepository
 		orderRepository:            orderRepository,
 		userAddressRepository:      userAddressRepository,
 		pharmacyRepository:         pharmacyRepository,
-		chatChannel:                make(map[int64]chan entity.Chat),
-		listeners:                  make([]entity.Participant, 0),
-		listenersLock:              sync.Mutex{},
-		abortChannel:               make(chan entity.Participant),
+		chatBroker:                 chatBroker,
+		prescriptionCompliance:     prescriptionCompliance,
+		fulfilmentOptimizer:        fulfilmentOptimizer,
+		consultationQueue:          consultationQueue,
+		pricingEngine:              pricingEngine,
 		transaction:                transaction,
 	}
 }
 
-func (u *telemedicineUsecaseImpl) UserCreateRoom(ctx context.Context, userAccountId, doctorAccountId int64) (*int64, error) {
+func (u *telemedicineUsecaseImpl) UserCreateRoom(ctx context.Context, userAccountId, doctorAccountId int64, allowFallback bool) (*int64, error) {
 	user, err := u.userRepository.FindUserByAccountId(ctx, userAccountId)
 	if err != nil {
 		return nil, apperror.InternalServerError(err)
@@ -102,12 +103,23 @@ func (u *telemedicineUsecaseImpl) UserCreateRoom(ctx context.Context, userAccoun
 		return nil, apperror.OnGoingChatExistError()
 	}
 
-	roomId, err := u.chatRoomRepository.CreateOneRoom(ctx, userAccountId, doctorAccountId)
+	tx, err := u.transaction.BeginTx(ctx)
 	if err != nil {
 		return nil, apperror.InternalServerError(err)
 	}
 
-	u.chatChannel[*roomId] = make(chan entity.Chat)
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+
+		tx.Commit()
+	}()
+
+	roomId, err := u.consultationQueue.OpenRoom(ctx, tx, userAccountId, doctorAccountId, doctor.FeePerPatient, allowFallback, nil)
+	if err != nil {
+		return nil, err
+	}
 
 	return roomId, nil
 }
@@ -123,8 +135,33 @@ func (u *telemedicineUsecaseImpl) DoctorJoinRoom(ctx context.Context, doctorAcco
 	if room.DoctorAccountId != doctorAccountId {
 		return apperror.ForbiddenAction()
 	}
+	if room.ScheduledAt != nil && room.ScheduledAt.After(time.Now()) {
+		return apperror.AppointmentNotStartedError()
+	}
 
-	err = u.chatRoomRepository.StartChat(ctx, roomId, doctorAccountId)
+	doctor, err := u.doctorRepository.FindDoctorByAccountId(ctx, doctorAccountId)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+	if doctor == nil {
+		return apperror.ForbiddenAction()
+	}
+
+	expiredAt, err := u.startChatRoom(ctx, doctorAccountId, roomId, consultationDurationMinutes(doctor.ConsultationDuration))
+	if err != nil {
+		return err
+	}
+
+	if expiredAt == nil {
+		return nil
+	}
+
+	err = u.chatBroker.Publish(ctx, entity.ChatEvent{
+		Type:            appconstant.ChatEventStarted,
+		RoomId:          roomId,
+		SenderAccountId: doctorAccountId,
+		ExpiredAt:       expiredAt,
+	})
 	if err != nil {
 		return apperror.InternalServerError(err)
 	}
@@ -132,29 +169,162 @@ func (u *telemedicineUsecaseImpl) DoctorJoinRoom(ctx context.Context, doctorAcco
 	return nil
 }
 
-func (u *telemedicineUsecaseImpl) PostOneMessage(ctx context.Context, accountId int64, postOneMessageRequest dto.PostOneMessageRequest, file multipart.File, fileHeader *multipart.FileHeader) (*dto.Chat, error) {
-	chat := dto.ConvertPostMessageRequestToChatEntity(postOneMessageRequest)
-	chat.SenderAccountId = accountId
+func (u *telemedicineUsecaseImpl) startChatRoom(ctx context.Context, doctorAccountId, roomId int64, durationMinutes int) (*time.Time, error) {
+	tx, err := u.transaction.BeginTx(ctx)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	chatRoomRepo := tx.ChatRoomRepository()
+	consultationPaymentRepo := tx.ConsultationPaymentRepository()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+
+		tx.Commit()
+	}()
+
+	// The payment row stays locked until the chat has started, so the expiry
+	// scheduler cannot refund a consultation the doctor is joining.
+	consultationPayment, err := consultationPaymentRepo.FindOneByChatRoomIdForUpdate(ctx, roomId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+	if consultationPayment == nil || consultationPayment.Status != appconstant.ConsultationPaymentPaid {
+		return nil, apperror.ConsultationNotPaidError()
+	}
 
-	chatRoom, err := u.chatRoomRepository.FindChatRoomById(ctx, chat.RoomId)
+	expiredAt, err := chatRoomRepo.StartChat(ctx, roomId, doctorAccountId, durationMinutes)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	return expiredAt, nil
+}
+
+func (u *telemedicineUsecaseImpl) DoctorDeclineRoom(ctx context.Context, doctorAccountId, roomId int64) error {
+	reassignedDoctorAccountId, err := u.declineChatRoom(ctx, doctorAccountId, roomId)
+	if err != nil {
+		return err
+	}
+
+	if reassignedDoctorAccountId != nil {
+		return nil
+	}
+
+	err = u.chatBroker.Publish(ctx, entity.ChatEvent{
+		Type:            appconstant.ChatEventClosed,
+		RoomId:          roomId,
+		SenderAccountId: doctorAccountId,
+	})
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	return nil
+}
+
+func (u *telemedicineUsecaseImpl) declineChatRoom(ctx context.Context, doctorAccountId, roomId int64) (*int64, error) {
+	tx, err := u.transaction.BeginTx(ctx)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	chatRoomRepo := tx.ChatRoomRepository()
+	consultationPaymentRepo := tx.ConsultationPaymentRepository()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+
+		tx.Commit()
+	}()
+
+	consultationPayment, err := consultationPaymentRepo.FindOneByChatRoomIdForUpdate(ctx, roomId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+	if consultationPayment == nil {
+		return nil, apperror.ChatRoomNotFoundError()
+	}
+
+	chatRoom, err := chatRoomRepo.FindChatRoomById(ctx, roomId)
 	if err != nil {
 		return nil, apperror.InternalServerError(err)
 	}
 	if chatRoom == nil {
 		return nil, apperror.ChatRoomNotFoundError()
 	}
-	if chatRoom.DoctorAccountId != accountId && chatRoom.UserAccountId != accountId {
+	if chatRoom.DoctorAccountId != doctorAccountId {
 		return nil, apperror.ForbiddenAction()
 	}
+	if chatRoom.ExpiredAt != nil || chatRoom.QueuedAt == nil || consultationPayment.Status != appconstant.ConsultationPaymentPaid {
+		return nil, apperror.ChatRoomNotQueuedError()
+	}
 
-	if chatRoom.ExpiredAt != nil {
-		if chatRoom.ExpiredAt.Before(time.Now()) {
-			return nil, apperror.RoomIsNowExpiredError()
-		}
+	reassignedDoctorAccountId, err := u.consultationQueue.ReleaseRoom(ctx, tx, *chatRoom, *consultationPayment, appconstant.ChatRoomDeclineReasonDeclined)
+	if err != nil {
+		return nil, err
+	}
+
+	return reassignedDoctorAccountId, nil
+}
+
+func (u *telemedicineUsecaseImpl) GetQueueStatus(ctx context.Context, accountId, roomId int64) (*dto.ConsultationQueueResponse, error) {
+	chatRoom, err := u.chatRoomRepository.FindChatRoomById(ctx, roomId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+	if chatRoom == nil {
+		return nil, apperror.ChatRoomNotFoundError()
+	}
+	if chatRoom.UserAccountId != accountId && chatRoom.DoctorAccountId != accountId {
+		return nil, apperror.ForbiddenAction()
+	}
+
+	queueStatus, err := u.consultationQueue.GetQueueStatus(ctx, *chatRoom)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.ConvertToConsultationQueueResponse(*queueStatus)
+
+	return &response, nil
+}
+
+func (u *telemedicineUsecaseImpl) PostOneMessage(ctx context.Context, accountId int64, postOneMessageRequest dto.PostOneMessageRequest, file multipart.File, fileHeader *multipart.FileHeader) (*dto.Chat, error) {
+	chat := dto.ConvertPostMessageRequestToChatEntity(postOneMessageRequest)
+	chat.SenderAccountId = accountId
+
+	chatRoom, err := u.findActiveChatRoomForParticipant(ctx, accountId, chat.RoomId)
+	if err != nil {
+		return nil, err
 	}
 
 	chat.RoomId = chatRoom.Id
 
+	savedChat, err := u.saveChat(ctx, *chatRoom, chat, file, fileHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	// the chat is already stored, a participant that misses the event reloads it via GetAllChat
+	_ = u.chatBroker.Publish(ctx, entity.ChatEvent{
+		Type:            appconstant.ChatEventMessage,
+		RoomId:          savedChat.RoomId,
+		SenderAccountId: savedChat.SenderAccountId,
+		Chat:            savedChat,
+	})
+
+	postMessageResponse := dto.ConvertToChatDTO(*savedChat)
+
+	return &postMessageResponse, nil
+}
+
+func (u *telemedicineUsecaseImpl) saveChat(ctx context.Context, chatRoom entity.ChatRoom, chat entity.Chat, file multipart.File, fileHeader *multipart.FileHeader) (*entity.Chat, error) {
 	tx, err := u.transaction.BeginTx(ctx)
 	if err != nil {
 		return nil, apperror.InternalServerError(err)
@@ -172,8 +342,11 @@ func (u *telemedicineUsecaseImpl) PostOneMessage(ctx context.Context, accountId
 	prescriptionDrugRepo := tx.PrescriptionDrugRepository()
 	chatRepo := tx.ChatRepository()
 
-	if len(postOneMessageRequest.PrescriptionDrugs) > 0 {
-		prescriptionId, err := prescriptionRepo.CreateOnePrescription(ctx, chatRoom.UserAccountId, chatRoom.DoctorAccountId)
+	if len(chat.Prescription.PrescriptionDrugs) > 0 {
+		chat.Prescription.UserAccountId = chatRoom.UserAccountId
+		chat.Prescription.DoctorAccountId = chatRoom.DoctorAccountId
+
+		prescriptionId, err := prescriptionRepo.CreateOnePrescription(ctx, chat.Prescription)
 		if err != nil {
 			return nil, apperror.InternalServerError(err)
 		}
@@ -211,146 +384,69 @@ func (u *telemedicineUsecaseImpl) PostOneMessage(ctx context.Context, accountId
 	chat.CreatedAt = &createdAt
 	chat.Id = *chatId
 
-	isListenerReady := u.findListenerBySender(entity.Participant{AccountId: chat.SenderAccountId, RoomId: chat.RoomId})
-	if isListenerReady {
-		u.chatChannel[chat.RoomId] <- chat
-	}
-
-	postMessageResponse := dto.ConvertToChatDTO(chat)
-
-	return &postMessageResponse, nil
+	return &chat, nil
 }
 
-func (u *telemedicineUsecaseImpl) findListenerBySender(sender entity.Participant) bool {
-	u.listenersLock.Lock()
-	defer u.listenersLock.Unlock()
-
-	for _, listener := range u.listeners {
-		if sender.AccountId != listener.AccountId && sender.RoomId == listener.RoomId {
-			return true
-		}
+func (u *telemedicineUsecaseImpl) findActiveChatRoomForParticipant(ctx context.Context, accountId, roomId int64) (*entity.ChatRoom, error) {
+	chatRoom, err := u.chatRoomRepository.FindChatRoomById(ctx, roomId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
 	}
-
-	return false
-}
-
-func (u *telemedicineUsecaseImpl) findListener(listener entity.Participant) bool {
-	for _, l := range u.listeners {
-		if l.AccountId == listener.AccountId && l.RoomId == listener.RoomId {
-			return true
-		}
+	if chatRoom == nil {
+		return nil, apperror.ChatRoomNotFoundError()
 	}
-
-	return false
-}
-
-func (u *telemedicineUsecaseImpl) findListenerRoomIdByAccountId(listenerAccountId int64) *int64 {
-	for _, l := range u.listeners {
-		if l.AccountId == listenerAccountId {
-			return &l.RoomId
-		}
+	if chatRoom.DoctorAccountId != accountId && chatRoom.UserAccountId != accountId {
+		return nil, apperror.ForbiddenAction()
 	}
 
-	return nil
-}
-
-func (u *telemedicineUsecaseImpl) addListener(listener entity.Participant) {
-	u.listenersLock.Lock()
-	defer u.listenersLock.Unlock()
-
-	u.listeners = append(u.listeners, listener)
-}
-
-func (u *telemedicineUsecaseImpl) removeListener(listener entity.Participant) {
-	u.listenersLock.Lock()
-	defer u.listenersLock.Unlock()
-
-	for i, l := range u.listeners {
-		if l.AccountId == listener.AccountId && l.RoomId == listener.RoomId {
-			u.listeners = append(u.listeners[:i], u.listeners[i+1:]...)
-			return
+	if chatRoom.ExpiredAt != nil {
+		if chatRoom.ExpiredAt.Before(time.Now()) {
+			return nil, apperror.RoomIsNowExpiredError()
 		}
 	}
+
+	return chatRoom, nil
 }
 
-func (u *telemedicineUsecaseImpl) Listen(ctx context.Context, accountId int64, roomId int64) (*dto.Chat, error) {
-	roomChat, err := u.chatRoomRepository.FindChatRoomById(ctx, roomId)
+func (u *telemedicineUsecaseImpl) SubscribeChatRoom(ctx context.Context, accountId, roomId int64) (*entity.ChatSubscription, error) {
+	chatRoom, err := u.findActiveChatRoomForParticipant(ctx, accountId, roomId)
 	if err != nil {
-		return nil, apperror.InternalServerError(err)
-	}
-	if roomChat.DoctorAccountId != accountId && roomChat.UserAccountId != accountId {
-		return nil, apperror.ForbiddenAction()
+		return nil, err
 	}
 
-	listener := entity.Participant{
+	subscription := entity.ChatSubscription{
 		AccountId: accountId,
-		RoomId:    roomChat.Id,
+		RoomId:    chatRoom.Id,
+		ExpiredAt: chatRoom.ExpiredAt,
 	}
 
-	isAlreadyListen := u.findListener(listener)
-	if isAlreadyListen {
-		u.abortChannel <- listener
-	}
+	u.chatBroker.Subscribe(&subscription)
 
-	if u.chatChannel[listener.RoomId] == nil {
-		u.chatChannel[listener.RoomId] = make(chan entity.Chat)
-	}
-
-	u.addListener(listener)
-
-	resultChan := make(chan entity.Chat)
-	errorChan := make(chan error)
+	return &subscription, nil
+}
 
-	expiredAt := time.Now().Add(10 * time.Minute)
+func (u *telemedicineUsecaseImpl) UnsubscribeChatRoom(subscription *entity.ChatSubscription) {
+	u.chatBroker.Unsubscribe(subscription)
+}
 
-	if roomChat.ExpiredAt != nil {
-		expiredAt = *roomChat.ExpiredAt
+func (u *telemedicineUsecaseImpl) PublishChatEvent(ctx context.Context, accountId, roomId int64, chatEventRequest dto.ChatEventRequest) error {
+	chatRoom, err := u.findActiveChatRoomForParticipant(ctx, accountId, roomId)
+	if err != nil {
+		return err
 	}
 
-	go func() {
-		for {
-			select {
-			case chat := <-u.chatChannel[listener.RoomId]:
-				if chat.Id == 0 {
-
-					continue
-				}
-
-				if chat.SenderAccountId != listener.AccountId {
-					resultChan <- chat
-
-					return
-				}
-
-				u.chatChannel[chat.RoomId] <- chat
-
-			case l := <-u.abortChannel:
-				if listener.AccountId == l.AccountId && listener.RoomId == l.RoomId {
-					errorChan <- apperror.AbortPreviousListenRequestError()
-
-					return
-				}
-
-				u.abortChannel <- l
-
-			case <-time.After(time.Until(expiredAt)):
-				errorChan <- apperror.AbortPreviousListenRequestError()
-				return
-			}
-		}
-	}()
-
-	select {
-	case chatReceived := <-resultChan:
-		chatResponse := dto.ConvertToChatDTO(chatReceived)
-		u.removeListener(listener)
-
-		return &chatResponse, nil
-	case err := <-errorChan:
-		u.removeListener(listener)
-
-		return nil, err
+	err = u.chatBroker.Publish(ctx, entity.ChatEvent{
+		Type:            chatEventRequest.Type,
+		RoomId:          chatRoom.Id,
+		SenderAccountId: accountId,
+		IsTyping:        chatEventRequest.IsTyping,
+		LastReadChatId:  chatEventRequest.LastReadChatId,
+	})
+	if err != nil {
+		return apperror.InternalServerError(err)
 	}
+
+	return nil
 }
 
 func (u *telemedicineUsecaseImpl) GetAllChat(ctx context.Context, accountId, roomId int64) (*dto.ChatRoom, error) {
@@ -363,11 +459,6 @@ func (u *telemedicineUsecaseImpl) GetAllChat(ctx context.Context, accountId, roo
 		return nil, apperror.ChatRoomNotFoundError()
 	}
 
-	previousRoomId := u.findListenerRoomIdByAccountId(accountId)
-	if previousRoomId != nil && *previousRoomId != roomId {
-		u.abortChannel <- entity.Participant{RoomId: *previousRoomId, AccountId: accountId}
-	}
-
 	if chatRoom.DoctorAccountId != accountId && chatRoom.UserAccountId != accountId {
 		return nil, apperror.ChatRoomNotFoundError()
 	}
@@ -486,21 +577,36 @@ func (u *telemedicineUsecaseImpl) GetAllPrescriptions(ctx context.Context, accou
 	return &response, nil
 }
 
-func (u *telemedicineUsecaseImpl) PrepareForCheckout(ctx context.Context, accountId, prescriptionId int64, addressIdString string) (*dto.PreapareForCheckoutResponse, error) {
+func (u *telemedicineUsecaseImpl) PrepareForCheckout(ctx context.Context, accountId, prescriptionId int64, addressIdString string, strategy string) (*dto.PreapareForCheckoutResponse, error) {
 	if prescriptionId < 1 {
 		return nil, apperror.PrescriptionIdNotANumberError()
 	}
 
+	if strategy == "" {
+		strategy = appconstant.FulfilmentStrategyCheapest
+	}
+	if strategy != appconstant.FulfilmentStrategyCheapest && strategy != appconstant.FulfilmentStrategyFewestShipments && strategy != appconstant.FulfilmentStrategyFastest {
+		return nil, apperror.InvalidFulfilmentStrategyError()
+	}
+
 	prescription, err := u.prescriptionRepository.GetPrescriptionById(ctx, prescriptionId)
 	if err != nil {
 		return nil, apperror.InternalServerError(err)
 	}
 
+	if prescription == nil || prescription.UserAccountId != accountId {
+		return nil, apperror.InvalidPrescriptionIdError()
+	}
+
 	addressId, err := strconv.Atoi(addressIdString)
 	if err != nil {
 		return nil, apperror.AddressIdInvalidError()
 	}
 
+	if prescription.IsExpired {
+		return nil, apperror.PrescriptionExpiredError()
+	}
+
 	if prescription.OrderedAt != nil {
 		return nil, apperror.PrescriptionHasBeenUsedError()
 	}
@@ -531,59 +637,25 @@ func (u *telemedicineUsecaseImpl) PrepareForCheckout(ctx context.Context, accoun
 		return nil, apperror.InternalServerError(err)
 	}
 
-	var checkoutPreparation entity.PrepareForCheckout
-
-	checkoutPreparation.UserAddress = *userAddress
-
-out:
+	prescriptionDrugsToFulfil := []entity.PrescriptionDrug{}
 	for _, prescriptionDrug := range prescriptionDrugList {
-		if !prescriptionDrug.Drug.IsActive {
-			return nil, apperror.DrugIsInactiveError()
-		}
-
-		pharmacy, drugQuantity, err := u.pharmacyDrugRepository.GetNearestAvailablePharmacyDrugByDrugId(ctx, prescriptionDrug.Drug.Id, userAddress.Id)
-		if err != nil {
-			return nil, apperror.InternalServerError(err)
-		}
-
-		if pharmacy == nil || drugQuantity == nil {
-			return nil, apperror.NoDrugNearby()
-		}
-
-		for i, pharmacyDrug := range checkoutPreparation.Items {
-			if pharmacyDrug.PharmacyId == pharmacy.Id {
-				drugQuantity.Quantity = prescriptionDrug.Quantity
-				checkoutPreparation.Items[i].DrugQuantities = append(checkoutPreparation.Items[i].DrugQuantities, *drugQuantity)
-				checkoutPreparation.Items[i].Subtotal = checkoutPreparation.Items[i].Subtotal.Add(decimal.NewFromInt(int64(drugQuantity.Quantity)).Mul(drugQuantity.PharmacyDrug.Price))
-				checkoutPreparation.Items[i].Weight = checkoutPreparation.Items[i].Weight.Add(decimal.NewFromInt(int64(prescriptionDrug.Quantity)).Mul(drugQuantity.PharmacyDrug.Drug.Weight))
-
-				continue out
-			}
+		if prescriptionDrug.RemainingQuantity == 0 {
+			continue
 		}
 
-		weight := decimal.NewFromInt(int64(prescriptionDrug.Quantity)).Mul(drugQuantity.PharmacyDrug.Drug.Weight)
-
-		avaiableCourierList, err := u.pharmacyRepository.GetAllCourierOptionsByPharmacyId(ctx, userAddress.Id, pharmacy.Id, weight.InexactFloat64())
-		if err != nil {
-			return nil, apperror.InternalServerError(err)
+		if !prescriptionDrug.Drug.IsActive {
+			return nil, apperror.DrugIsInactiveError()
 		}
 
-		drugQuantity.Quantity = prescriptionDrug.Quantity
-		nearestPharmacyDrug := entity.PrepareForCheckoutItem{
-			PharmacyId:      pharmacy.Id,
-			PharmacyName:    pharmacy.Name,
-			PharmacyAddress: pharmacy.Address,
-			Distance:        pharmacy.Distance,
-			DeliveryOptions: avaiableCourierList,
-			Subtotal:        decimal.NewFromInt(int64(prescriptionDrug.Quantity)).Mul(drugQuantity.PharmacyDrug.Price),
-			Weight:          weight,
-			DrugQuantities:  []entity.DrugQuantity{*drugQuantity},
-		}
+		prescriptionDrugsToFulfil = append(prescriptionDrugsToFulfil, prescriptionDrug)
+	}
 
-		checkoutPreparation.Items = append(checkoutPreparation.Items, nearestPharmacyDrug)
+	plans, err := u.fulfilmentOptimizer.PlanPrescriptionFulfilment(ctx, userAddress.Id, prescriptionDrugsToFulfil, strategy)
+	if err != nil {
+		return nil, err
 	}
 
-	response := dto.ConvertPrepareForCheckoutToResponse(checkoutPreparation.Items)
+	response := dto.ConvertFulfilmentPlansToPrepareForCheckoutResponse(strategy, plans)
 
 	return &response, nil
 }
@@ -602,10 +674,31 @@ func (u *telemedicineUsecaseImpl) CheckoutFromPrescription(ctx context.Context,
 		return nil, apperror.InternalServerError(err)
 	}
 
+	if prescription == nil || prescription.UserAccountId != checkoutFromPrescriptionRequest.AccountId {
+		return nil, apperror.InvalidPrescriptionIdError()
+	}
+
+	if prescription.IsExpired {
+		return nil, apperror.PrescriptionExpiredError()
+	}
+
 	if prescription.OrderedAt != nil {
 		return nil, apperror.PrescriptionHasBeenUsedError()
 	}
 
+	address, err := u.userAddressRepository.GetOneUserAddressByAddressId(ctx, checkoutFromPrescriptionRequest.UserAddressId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+	if address == nil || address.UserId != user.Id {
+		return nil, apperror.UserAddressNotFoundError()
+	}
+
+	prescriptionDrugs, err := u.prescriptionDrugRepository.GetAllPrescriptionDrug(ctx, checkoutFromPrescriptionRequest.PrescriptionId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
 	tx, err := u.transaction.BeginTx(ctx)
 	if err != nil {
 		return nil, apperror.InternalServerError(err)
@@ -618,7 +711,6 @@ func (u *telemedicineUsecaseImpl) CheckoutFromPrescription(ctx context.Context,
 	pharmacyDrugRepo := tx.PharmacyDrugRepo()
 	stockChangeRepo := tx.StockChangeRepo()
 	stockMutationRepo := tx.StockMutationRepo()
-	prescriptionRepo := tx.PrescriptionRepository()
 
 	defer func() {
 		if err != nil {
@@ -634,7 +726,8 @@ func (u *telemedicineUsecaseImpl) CheckoutFromPrescription(ctx context.Context,
 		var cartItemIds []int64
 
 		for _, phamacyDrugQuantity := range pharmacy.PharmacyDrugs {
-			cartItemId, err := cartRepo.PostOneCart(ctx, checkoutFromPrescriptionRequest.AccountId, phamacyDrugQuantity.PharmacyDrugId, phamacyDrugQuantity.Quantity)
+			var cartItemId *int64
+			cartItemId, err = cartRepo.PostOneCart(ctx, checkoutFromPrescriptionRequest.AccountId, phamacyDrugQuantity.PharmacyDrugId, phamacyDrugQuantity.Quantity, nil)
 			if err != nil {
 				return nil, apperror.InternalServerError(err)
 			}
@@ -642,10 +735,30 @@ func (u *telemedicineUsecaseImpl) CheckoutFromPrescription(ctx context.Context,
 			cartItemIds = append(cartItemIds, *cartItemId)
 		}
 
+		if len(cartItemIds) < 1 {
+			err = apperror.EmptyCartSelectionError()
+			return nil, err
+		}
+
 		orderCheckoutRequest.Pharmacies[i].CartItemIds = cartItemIds
 	}
 
-	orderId, err := orderRepo.PostOneOrder(ctx, user.Id, checkoutFromPrescriptionRequest.Address, checkoutFromPrescriptionRequest.TotalAmount)
+	pricing, err := u.pricingEngine.PriceCartCheckoutInTx(ctx, cartRepo, user.Id, orderCheckoutRequest.UserAddressId, orderCheckoutRequest.Pharmacies)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkSubmittedAmounts(orderCheckoutRequest, *pricing); err != nil {
+		return nil, err
+	}
+
+	for i, pharmacyPricing := range pricing.Pharmacies {
+		orderCheckoutRequest.Pharmacies[i].Subtotal = int(pharmacyPricing.Subtotal.IntPart())
+		orderCheckoutRequest.Pharmacies[i].DeliveryFee = int(pharmacyPricing.DeliveryFee.IntPart())
+	}
+	orderCheckoutRequest.TotalAmount = int(pricing.TotalAmount.IntPart())
+
+	orderId, err := orderRepo.PostOneOrder(ctx, user.Id, orderCheckoutRequest.Address, orderCheckoutRequest.TotalAmount)
 	if err != nil {
 		return nil, apperror.InternalServerError(err)
 	}
@@ -660,11 +773,8 @@ func (u *telemedicineUsecaseImpl) CheckoutFromPrescription(ctx context.Context,
 		if err != nil {
 			return nil, apperror.InternalServerError(err)
 		}
-	}
 
-	err = orderItemRepo.PostOrderItems(ctx, orderPharmacies)
-	if err != nil {
-		return nil, apperror.InternalServerError(err)
+		u.prescriptionCompliance.LinkPrescriptionDrugs(orderPharmacies[i].CartItems, prescriptionDrugs)
 	}
 
 	allCartItems := []entity.CartItemForCheckout{}
@@ -672,6 +782,16 @@ func (u *telemedicineUsecaseImpl) CheckoutFromPrescription(ctx context.Context,
 		allCartItems = append(allCartItems, pharmacy.CartItems...)
 	}
 
+	err = u.prescriptionCompliance.RedeemCartItems(ctx, tx, checkoutFromPrescriptionRequest.AccountId, allCartItems)
+	if err != nil {
+		return nil, err
+	}
+
+	err = orderItemRepo.PostOrderItems(ctx, orderPharmacies)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
 	err = pharmacyDrugRepo.GetPharmacyDrugsByCartForUpdate(ctx, allCartItems)
 	if err != nil {
 		return nil, apperror.InternalServerError(err)
@@ -682,7 +802,7 @@ func (u *telemedicineUsecaseImpl) CheckoutFromPrescription(ctx context.Context,
 		return nil, apperror.InternalServerError(err)
 	}
 
-	err = stockChangeRepo.PostStockChangesByCartIds(ctx, carts)
+	err = stockChangeRepo.PostStockChangesByCartIds(ctx, carts, checkoutFromPrescriptionRequest.AccountId)
 	if err != nil {
 		return nil, apperror.InternalServerError(err)
 	}
@@ -712,26 +832,21 @@ func (u *telemedicineUsecaseImpl) CheckoutFromPrescription(ctx context.Context,
 			if stock+alternative.AlternativeStock < 0 {
 				stock += alternative.AlternativeStock
 				stockMutationList = append(stockMutationList, alternative)
-				stockChangesList = append(stockChangesList, entity.StockChange{PharmacyDrugId: alternative.OriginalPharmacyDrug,
-					FinalStock: stock, Amount: alternative.AlternativeStock})
-				stockChangesList = append(stockChangesList, entity.StockChange{PharmacyDrugId: alternative.AlternativePharmacy,
-					FinalStock: 0, Amount: -1 * alternative.AlternativeStock})
 			} else {
 				partialAlternative := alternative
 				partialAlternative.AlternativeStock = stock * -1
 				stockMutationList = append(stockMutationList, partialAlternative)
-				stockChangesList = append(stockChangesList, entity.StockChange{PharmacyDrugId: alternative.OriginalPharmacyDrug,
-					FinalStock: 0, Amount: partialAlternative.AlternativeStock})
-				stockChangesList = append(stockChangesList, entity.StockChange{PharmacyDrugId: alternative.AlternativePharmacy,
-					FinalStock: alternative.AlternativeStock - partialAlternative.AlternativeStock,
-					Amount:     -1 * partialAlternative.AlternativeStock})
 				stock = 0
 				break
 			}
 		}
 		if stock < 0 {
 			insufficientCartItems = append(insufficientCartItems, pharmacyDrug.CartId)
+			continue
 		}
+		stockChangesList = append(stockChangesList, entity.StockChange{PharmacyDrugId: pharmacyDrug.PharmacyDrugId,
+			FinalStock: pharmacyDrug.Stock, Amount: 0, Description: appconstant.StockMutationRequestedDescription,
+			ActorAccountId: &checkoutFromPrescriptionRequest.AccountId})
 	}
 
 	if len(insufficientCartItems) > 0 {
@@ -740,7 +855,7 @@ func (u *telemedicineUsecaseImpl) CheckoutFromPrescription(ctx context.Context,
 	}
 
 	if len(stockMutationList) > 0 {
-		err = stockMutationRepo.PostStockMutations(ctx, stockMutationList)
+		err = stockMutationRepo.PostStockMutations(ctx, stockMutationList, &orderId)
 		if err != nil {
 			return nil, apperror.InternalServerError(err)
 		}
@@ -749,11 +864,11 @@ func (u *telemedicineUsecaseImpl) CheckoutFromPrescription(ctx context.Context,
 		if err != nil {
 			return nil, apperror.InternalServerError(err)
 		}
+	}
 
-		err = pharmacyDrugRepo.UpdatePharmacyDrugsForStockMutation(ctx, stockChangesList)
-		if err != nil {
-			return nil, apperror.InternalServerError(err)
-		}
+	err = allocateOrderPharmacyBatches(ctx, tx.PharmacyDrugBatchRepository(), orderPharmacies)
+	if err != nil {
+		return nil, err
 	}
 
 	err = cartRepo.DeleteCarts(ctx, allCartItems)
@@ -761,12 +876,32 @@ func (u *telemedicineUsecaseImpl) CheckoutFromPrescription(ctx context.Context,
 		return nil, apperror.InternalServerError(err)
 	}
 
-	err = prescriptionRepo.SetPrescriptionOrderedAtNow(ctx, *prescription.Id)
+	return &orderId, nil
+}
+
+func (u *telemedicineUsecaseImpl) GetPrescriptionRedemptions(ctx context.Context, accountId, prescriptionId int64) (*dto.PrescriptionRedemptionsResponse, error) {
+	prescription, err := u.prescriptionRepository.GetPrescriptionById(ctx, prescriptionId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	if prescription == nil || (prescription.UserAccountId != accountId && prescription.DoctorAccountId != accountId) {
+		return nil, apperror.InvalidPrescriptionIdError()
+	}
+
+	prescription.PrescriptionDrugs, err = u.prescriptionDrugRepository.GetAllPrescriptionDrug(ctx, prescriptionId)
 	if err != nil {
 		return nil, apperror.InternalServerError(err)
 	}
 
-	return &orderId, nil
+	redemptions, err := u.prescriptionRepository.GetAllRedemptions(ctx, prescriptionId)
+	if err != nil {
+		return nil, apperror.InternalServerError(err)
+	}
+
+	response := dto.ConvertToPrescriptionRedemptionsResponse(*prescription, redemptions)
+
+	return &response, nil
 }
 
 func (u *telemedicineUsecaseImpl) CloseChatRoom(ctx context.Context, userAccountId, roomId int64) error {
@@ -787,16 +922,69 @@ func (u *telemedicineUsecaseImpl) CloseChatRoom(ctx context.Context, userAccount
 		return apperror.ChatRoomAlreadyClosedError()
 	}
 
-	err = u.chatRoomRepository.CloseChatRoom(ctx, chatRoom.Id)
+	err = u.closeChatRoom(ctx, chatRoom.Id)
+	if err != nil {
+		return err
+	}
+
+	err = u.chatBroker.Publish(ctx, entity.ChatEvent{
+		Type:            appconstant.ChatEventClosed,
+		RoomId:          chatRoom.Id,
+		SenderAccountId: userAccountId,
+	})
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	return nil
+}
+
+// A room closed before the doctor joined never started the consultation, so
+// its fee is cancelled, or refunded when it has already been paid.
+func (u *telemedicineUsecaseImpl) closeChatRoom(ctx context.Context, roomId int64) error {
+	tx, err := u.transaction.BeginTx(ctx)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	chatRoomRepo := tx.ChatRoomRepository()
+	consultationPaymentRepo := tx.ConsultationPaymentRepository()
+	appointmentRepo := tx.AppointmentRepository()
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+
+		tx.Commit()
+	}()
+
+	_, err = consultationPaymentRepo.FindOneByChatRoomIdForUpdate(ctx, roomId)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
+	chatRoom, err := chatRoomRepo.FindChatRoomById(ctx, roomId)
 	if err != nil {
 		return apperror.InternalServerError(err)
 	}
 
-	for _, l := range u.listeners {
-		if l.RoomId == chatRoom.Id {
-			u.abortChannel <- l
+	if chatRoom.ExpiredAt == nil {
+		_, err = consultationPaymentRepo.CancelOne(ctx, roomId)
+		if err != nil {
+			return apperror.InternalServerError(err)
+		}
+
+		_, err = appointmentRepo.CancelOneByChatRoomId(ctx, roomId)
+		if err != nil {
+			return apperror.InternalServerError(err)
 		}
 	}
 
+	err = chatRoomRepo.CloseChatRoom(ctx, roomId)
+	if err != nil {
+		return apperror.InternalServerError(err)
+	}
+
 	return nil
 }
@@ -0,0 +1,36 @@
+package util
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var courierEtdNumberRegex = regexp.MustCompile(`\d+`)
+
+// Couriers report delivery estimates as free text such as "2-3", "1 day" or
+// "2-4 hours". Plain numbers are days, and the upper bound is used.
+func CourierEtdHours(etd string) (int, bool) {
+	numbers := courierEtdNumberRegex.FindAllString(etd, -1)
+	if len(numbers) == 0 {
+		return 0, false
+	}
+
+	upperBound := 0
+	for _, number := range numbers {
+		value, err := strconv.Atoi(number)
+		if err != nil {
+			return 0, false
+		}
+		if value > upperBound {
+			upperBound = value
+		}
+	}
+
+	lowerEtd := strings.ToLower(etd)
+	if strings.Contains(lowerEtd, "hour") || strings.Contains(lowerEtd, "jam") {
+		return upperBound, true
+	}
+
+	return upperBound * 24, true
+}
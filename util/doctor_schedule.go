@@ -0,0 +1,95 @@
+package util
+
+import (
+	"time"
+
+	"github.com/sidiqPratomo/max-health-backend/entity"
+)
+
+type doctorScheduleWindow struct {
+	start time.Duration
+	end   time.Duration
+}
+
+// An exception on the date replaces the weekly schedule for that whole day.
+// Every window is split into back to back consultations of the given length.
+func GetDoctorAppointmentSlots(schedule entity.DoctorSchedule, date time.Time, duration time.Duration) ([]entity.AppointmentSlot, error) {
+	location, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	localDate := date.In(location)
+	day := time.Date(localDate.Year(), localDate.Month(), localDate.Day(), 0, 0, 0, 0, location)
+
+	windows, err := doctorScheduleWindowsOn(schedule, day)
+	if err != nil {
+		return nil, err
+	}
+
+	slots := []entity.AppointmentSlot{}
+	if duration <= 0 {
+		return slots, nil
+	}
+
+	for _, window := range windows {
+		for start := window.start; start+duration <= window.end; start += duration {
+			slots = append(slots, entity.AppointmentSlot{
+				StartAt: day.Add(start),
+				EndAt:   day.Add(start + duration),
+			})
+		}
+	}
+
+	return slots, nil
+}
+
+func doctorScheduleWindowsOn(schedule entity.DoctorSchedule, date time.Time) ([]doctorScheduleWindow, error) {
+	for _, exception := range schedule.Exceptions {
+		if exception.Date.Year() != date.Year() || exception.Date.YearDay() != date.YearDay() {
+			continue
+		}
+
+		if !exception.IsAvailable || exception.StartHour == nil || exception.EndHour == nil {
+			return nil, nil
+		}
+
+		window, err := parseDoctorScheduleWindow(*exception.StartHour, *exception.EndHour)
+		if err != nil {
+			return nil, err
+		}
+
+		return []doctorScheduleWindow{*window}, nil
+	}
+
+	windows := []doctorScheduleWindow{}
+
+	for _, slot := range schedule.Slots {
+		if slot.Day != date.Weekday().String() {
+			continue
+		}
+
+		window, err := parseDoctorScheduleWindow(slot.StartHour, slot.EndHour)
+		if err != nil {
+			return nil, err
+		}
+
+		windows = append(windows, *window)
+	}
+
+	return windows, nil
+}
+
+func parseDoctorScheduleWindow(startHour string, endHour string) (*doctorScheduleWindow, error) {
+	start, err := ParsePharmacyHour(startHour)
+	if err != nil {
+		return nil, err
+	}
+
+	end, err := ParsePharmacyHour(endHour)
+	if err != nil {
+		return nil, err
+	}
+
+	return &doctorScheduleWindow{start: start, end: end}, nil
+}
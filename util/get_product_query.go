@@ -194,6 +194,7 @@ type GetOrderQuery struct {
 type ValidatedGetOrderQuery struct {
 	StatusId     *int64
 	PharmacyName *string
+	UserId       *int64
 	Limit        int
 	Page         int
 }
@@ -0,0 +1,104 @@
+package util
+
+import (
+	"errors"
+	"time"
+
+	"github.com/sidiqPratomo/max-health-backend/entity"
+)
+
+const pharmacyOpeningLookahead = 14
+
+var pharmacyHourLayouts = []string{"15:04:05", "15:04"}
+
+func ParsePharmacyHour(hour string) (time.Duration, error) {
+	for _, layout := range pharmacyHourLayouts {
+		parsed, err := time.Parse(layout, hour)
+		if err == nil {
+			return time.Duration(parsed.Hour())*time.Hour + time.Duration(parsed.Minute())*time.Minute + time.Duration(parsed.Second())*time.Second, nil
+		}
+	}
+
+	return 0, errors.New("invalid hour format " + hour)
+}
+
+// An equal open and close hour means the pharmacy is open for the whole day.
+func GetPharmacyOpeningStatus(schedule entity.PharmacySchedule, now time.Time) (*entity.PharmacyOpeningStatus, error) {
+	location, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	localNow := now.In(location)
+	today := time.Date(localNow.Year(), localNow.Month(), localNow.Day(), 0, 0, 0, 0, location)
+
+	for offset := 0; offset <= pharmacyOpeningLookahead; offset++ {
+		date := today.AddDate(0, 0, offset)
+
+		isOpen, openHour, closeHour, err := pharmacyHoursOn(schedule, date)
+		if err != nil {
+			return nil, err
+		}
+		if !isOpen {
+			continue
+		}
+
+		opensAt := date.Add(openHour)
+		closesAt := date.Add(closeHour)
+		if openHour == closeHour {
+			closesAt = date.AddDate(0, 0, 1)
+		}
+
+		if !localNow.Before(opensAt) && localNow.Before(closesAt) {
+			return &entity.PharmacyOpeningStatus{IsOpen: true}, nil
+		}
+
+		if localNow.Before(opensAt) {
+			return &entity.PharmacyOpeningStatus{IsOpen: false, OpensAt: &opensAt}, nil
+		}
+	}
+
+	return &entity.PharmacyOpeningStatus{IsOpen: false}, nil
+}
+
+func pharmacyHoursOn(schedule entity.PharmacySchedule, date time.Time) (bool, time.Duration, time.Duration, error) {
+	for _, holiday := range schedule.Holidays {
+		if holiday.Date.Year() != date.Year() || holiday.Date.YearDay() != date.YearDay() {
+			continue
+		}
+
+		if !holiday.IsOpen || holiday.OpenHour == nil || holiday.CloseHour == nil {
+			return false, 0, 0, nil
+		}
+
+		return parsePharmacyWindow(*holiday.OpenHour, *holiday.CloseHour)
+	}
+
+	for _, operational := range schedule.Operationals {
+		if operational.OperationalDay != date.Weekday().String() {
+			continue
+		}
+
+		if !operational.IsOpen {
+			return false, 0, 0, nil
+		}
+
+		return parsePharmacyWindow(operational.OpenHour, operational.CloseHour)
+	}
+
+	return false, 0, 0, nil
+}
+
+func parsePharmacyWindow(openHour string, closeHour string) (bool, time.Duration, time.Duration, error) {
+	openDuration, err := ParsePharmacyHour(openHour)
+	if err != nil {
+		return false, 0, 0, err
+	}
+
+	closeDuration, err := ParsePharmacyHour(closeHour)
+	if err != nil {
+		return false, 0, 0, err
+	}
+
+	return true, openDuration, closeDuration, nil
+}
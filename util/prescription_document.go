@@ -0,0 +1,115 @@
+package util
+
+import (
+	"bytes"
+	"strconv"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/sidiqPratomo/max-health-backend/entity"
+	"github.com/skip2/go-qrcode"
+)
+
+const (
+	prescriptionDocumentDateLayout = "02 January 2006"
+	prescriptionDocumentQrName     = "verification"
+	prescriptionDocumentQrSize     = 256
+	prescriptionDocumentQrWidth    = 40
+)
+
+func GeneratePrescriptionDocument(prescription entity.Prescription, verificationUrl string) ([]byte, error) {
+	qrCode, err := qrcode.Encode(verificationUrl, qrcode.Medium, prescriptionDocumentQrSize)
+	if err != nil {
+		return nil, err
+	}
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pageWidth, _ := pdf.GetPageSize()
+	left, _, right, _ := pdf.GetMargins()
+	contentWidth := pageWidth - left - right
+
+	pdf.AddPage()
+
+	pdf.SetFont("Arial", "B", 18)
+	pdf.CellFormat(contentWidth, 10, "Max Health", "", 1, "L", false, 0, "")
+	pdf.SetFont("Arial", "", 12)
+	pdf.CellFormat(contentWidth, 7, "Medical Prescription", "", 1, "L", false, 0, "")
+	pdf.Line(left, pdf.GetY()+2, pageWidth-right, pdf.GetY()+2)
+	pdf.Ln(6)
+
+	prescriptionNumber := ""
+	if prescription.Id != nil {
+		prescriptionNumber = strconv.FormatInt(*prescription.Id, 10)
+	}
+
+	issuedAt := ""
+	if prescription.CreatedAt != nil {
+		issuedAt = prescription.CreatedAt.Format(prescriptionDocumentDateLayout)
+	}
+
+	validUntil := "-"
+	if prescription.ValidUntil != nil {
+		validUntil = prescription.ValidUntil.Format(prescriptionDocumentDateLayout)
+	}
+
+	doctor := prescription.DoctorName
+	if prescription.DoctorSpecialization != "" {
+		doctor += " (" + prescription.DoctorSpecialization + ")"
+	}
+
+	details := [][]string{
+		{"Prescription No.", prescriptionNumber},
+		{"Issue Date", issuedAt},
+		{"Valid Until", validUntil},
+		{"Doctor", doctor},
+		{"Patient", prescription.UserName},
+		{"Refills", strconv.Itoa(prescription.RefillCount)},
+	}
+
+	for _, detail := range details {
+		pdf.SetFont("Arial", "B", 11)
+		pdf.CellFormat(40, 7, detail[0], "", 0, "L", false, 0, "")
+		pdf.SetFont("Arial", "", 11)
+		pdf.CellFormat(contentWidth-40, 7, tr(detail[1]), "", 1, "L", false, 0, "")
+	}
+
+	pdf.Ln(6)
+
+	columnWidths := []float64{10, 70, 20, contentWidth - 100}
+	pdf.SetFont("Arial", "B", 11)
+	pdf.SetFillColor(230, 230, 230)
+	for i, header := range []string{"No", "Drug", "Qty", "Note"} {
+		pdf.CellFormat(columnWidths[i], 8, header, "1", 0, "C", true, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont("Arial", "", 11)
+	for i, prescriptionDrug := range prescription.PrescriptionDrugs {
+		note := tr(prescriptionDrug.Note)
+		lines := len(pdf.SplitLines([]byte(note), columnWidths[3]-2))
+		if lines < 1 {
+			lines = 1
+		}
+		height := float64(lines) * 7
+
+		pdf.CellFormat(columnWidths[0], height, strconv.Itoa(i+1), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(columnWidths[1], height, tr(prescriptionDrug.Drug.Name), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(columnWidths[2], height, strconv.Itoa(prescriptionDrug.Quantity), "1", 0, "C", false, 0, "")
+		pdf.MultiCell(columnWidths[3], height/float64(lines), note, "1", "L", false)
+	}
+
+	pdf.Ln(10)
+
+	pdf.RegisterImageOptionsReader(prescriptionDocumentQrName, gofpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(qrCode))
+	pdf.ImageOptions(prescriptionDocumentQrName, left, pdf.GetY(), prescriptionDocumentQrWidth, prescriptionDocumentQrWidth, true, gofpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+
+	pdf.SetFont("Arial", "", 9)
+	pdf.MultiCell(contentWidth, 5, "Scan the QR code to verify this prescription.", "", "L", false)
+
+	var document bytes.Buffer
+	if err := pdf.Output(&document); err != nil {
+		return nil, err
+	}
+
+	return document.Bytes(), nil
+}
@@ -0,0 +1,106 @@
+package util
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/sidiqPratomo/max-health-backend/entity"
+)
+
+type PrescriptionSigner interface {
+	Sign(prescription entity.Prescription) (string, error)
+	Verify(prescription entity.Prescription, signature string) (bool, error)
+}
+
+type HmacPrescriptionSigner struct {
+	Secret string
+}
+
+type signedPrescriptionDrug struct {
+	DrugId   int64  `json:"drug_id"`
+	Quantity int    `json:"quantity"`
+	Note     string `json:"note"`
+}
+
+type signedPrescription struct {
+	Id              int64                    `json:"id"`
+	UserAccountId   int64                    `json:"user_account_id"`
+	DoctorAccountId int64                    `json:"doctor_account_id"`
+	RefillCount     int                      `json:"refill_count"`
+	IssuedAt        int64                    `json:"issued_at"`
+	ValidUntil      *int64                   `json:"valid_until"`
+	Drugs           []signedPrescriptionDrug `json:"drugs"`
+}
+
+func NewHmacPrescriptionSigner(secret string) HmacPrescriptionSigner {
+	return HmacPrescriptionSigner{
+		Secret: secret,
+	}
+}
+
+func (s HmacPrescriptionSigner) Sign(prescription entity.Prescription) (string, error) {
+	payload, err := prescriptionSignaturePayload(prescription)
+	if err != nil {
+		return "", err
+	}
+
+	mac := hmac.New(sha256.New, []byte(s.Secret))
+	mac.Write(payload)
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+func (s HmacPrescriptionSigner) Verify(prescription entity.Prescription, signature string) (bool, error) {
+	decodedSignature, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return false, nil
+	}
+
+	expectedSignature, err := s.Sign(prescription)
+	if err != nil {
+		return false, err
+	}
+
+	decodedExpectedSignature, err := base64.RawURLEncoding.DecodeString(expectedSignature)
+	if err != nil {
+		return false, err
+	}
+
+	return hmac.Equal(decodedSignature, decodedExpectedSignature), nil
+}
+
+// Only what the doctor issued is signed. Remaining quantities and refills
+// used change with every order and must not invalidate the document.
+func prescriptionSignaturePayload(prescription entity.Prescription) ([]byte, error) {
+	signed := signedPrescription{
+		UserAccountId:   prescription.UserAccountId,
+		DoctorAccountId: prescription.DoctorAccountId,
+		RefillCount:     prescription.RefillCount,
+		Drugs:           []signedPrescriptionDrug{},
+	}
+
+	if prescription.Id != nil {
+		signed.Id = *prescription.Id
+	}
+
+	if prescription.CreatedAt != nil {
+		signed.IssuedAt = prescription.CreatedAt.Unix()
+	}
+
+	if prescription.ValidUntil != nil {
+		validUntil := prescription.ValidUntil.Unix()
+		signed.ValidUntil = &validUntil
+	}
+
+	for _, prescriptionDrug := range prescription.PrescriptionDrugs {
+		signed.Drugs = append(signed.Drugs, signedPrescriptionDrug{
+			DrugId:   prescriptionDrug.Drug.Id,
+			Quantity: prescriptionDrug.Quantity,
+			Note:     prescriptionDrug.Note,
+		})
+	}
+
+	return json.Marshal(signed)
+}
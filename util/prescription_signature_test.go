@@ -0,0 +1,128 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sidiqPratomo/max-health-backend/entity"
+)
+
+func newSignedTestPrescription() entity.Prescription {
+	prescriptionId := int64(12)
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	validUntil := createdAt.AddDate(0, 0, 30)
+
+	return entity.Prescription{
+		Id:              &prescriptionId,
+		UserAccountId:   3,
+		DoctorAccountId: 4,
+		RefillCount:     1,
+		CreatedAt:       &createdAt,
+		ValidUntil:      &validUntil,
+		PrescriptionDrugs: []entity.PrescriptionDrug{
+			{Drug: entity.Drug{Id: 5}, Quantity: 2, RemainingQuantity: 2, Note: "after meals"},
+		},
+	}
+}
+
+func TestHmacPrescriptionSignerVerify(t *testing.T) {
+	signer := NewHmacPrescriptionSigner("secret")
+
+	signature, err := signer.Sign(newSignedTestPrescription())
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	tamperedSignature := []byte(signature)
+	if tamperedSignature[0] == 'A' {
+		tamperedSignature[0] = 'B'
+	} else {
+		tamperedSignature[0] = 'A'
+	}
+
+	tests := []struct {
+		name      string
+		signer    HmacPrescriptionSigner
+		edit      func(*entity.Prescription)
+		signature string
+		want      bool
+	}{
+		{
+			name:      "untouched prescription",
+			signer:    signer,
+			edit:      func(p *entity.Prescription) {},
+			signature: signature,
+			want:      true,
+		},
+		{
+			name:      "remaining quantity and refills used are not signed",
+			signer:    signer,
+			edit:      func(p *entity.Prescription) { p.PrescriptionDrugs[0].RemainingQuantity = 0; p.RefillsUsed = 1 },
+			signature: signature,
+			want:      true,
+		},
+		{
+			name:      "tampered quantity",
+			signer:    signer,
+			edit:      func(p *entity.Prescription) { p.PrescriptionDrugs[0].Quantity = 20 },
+			signature: signature,
+		},
+		{
+			name:      "tampered refill count",
+			signer:    signer,
+			edit:      func(p *entity.Prescription) { p.RefillCount = 5 },
+			signature: signature,
+		},
+		{
+			name:      "tampered validity",
+			signer:    signer,
+			edit:      func(p *entity.Prescription) { validUntil := p.ValidUntil.AddDate(1, 0, 0); p.ValidUntil = &validUntil },
+			signature: signature,
+		},
+		{
+			name:      "tampered signature",
+			signer:    signer,
+			edit:      func(p *entity.Prescription) {},
+			signature: string(tamperedSignature),
+		},
+		{
+			name:      "truncated signature",
+			signer:    signer,
+			edit:      func(p *entity.Prescription) {},
+			signature: signature[:len(signature)-4],
+		},
+		{
+			name:      "empty signature",
+			signer:    signer,
+			edit:      func(p *entity.Prescription) {},
+			signature: "",
+		},
+		{
+			name:      "signature that is not base64",
+			signer:    signer,
+			edit:      func(p *entity.Prescription) {},
+			signature: "not a signature!",
+		},
+		{
+			name:      "another secret",
+			signer:    NewHmacPrescriptionSigner("another secret"),
+			edit:      func(p *entity.Prescription) {},
+			signature: signature,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prescription := newSignedTestPrescription()
+			tt.edit(&prescription)
+
+			got, err := tt.signer.Verify(prescription, tt.signature)
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package util
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sidiqPratomo/max-health-backend/entity"
+)
+
+type cachedShippingRates struct {
+	courierOptions []entity.CourierOption
+	expiredAt      time.Time
+}
+
+type cachingShippingRateProvider struct {
+	next    ShippingRateProvider
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[entity.ShippingRateRequest]cachedShippingRates
+}
+
+func NewCachingShippingRateProvider(next ShippingRateProvider, ttl time.Duration) *cachingShippingRateProvider {
+	return &cachingShippingRateProvider{
+		next:    next,
+		ttl:     ttl,
+		entries: map[entity.ShippingRateRequest]cachedShippingRates{},
+	}
+}
+
+func (p *cachingShippingRateProvider) GetShippingRates(ctx context.Context, shippingRateRequest entity.ShippingRateRequest) ([]entity.CourierOption, error) {
+	now := time.Now()
+
+	p.mu.Lock()
+	cached, ok := p.entries[shippingRateRequest]
+	if ok && now.Before(cached.expiredAt) {
+		p.mu.Unlock()
+		return append([]entity.CourierOption{}, cached.courierOptions...), nil
+	}
+	p.mu.Unlock()
+
+	courierOptions, err := p.next.GetShippingRates(ctx, shippingRateRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	for request, entry := range p.entries {
+		if now.After(entry.expiredAt) {
+			delete(p.entries, request)
+		}
+	}
+	p.entries[shippingRateRequest] = cachedShippingRates{
+		courierOptions: courierOptions,
+		expiredAt:      now.Add(p.ttl),
+	}
+	p.mu.Unlock()
+
+	return append([]entity.CourierOption{}, courierOptions...), nil
+}
@@ -0,0 +1,61 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sidiqPratomo/max-health-backend/entity"
+)
+
+type LocalShippingTariff struct {
+	Etd        string
+	BasePrice  float64
+	PricePerKg float64
+}
+
+var DefaultLocalShippingTariffs = map[string][]LocalShippingTariff{
+	"jne": {
+		{Etd: "2-3", BasePrice: 9000, PricePerKg: 9000},
+		{Etd: "1-2", BasePrice: 12000, PricePerKg: 12000},
+	},
+	"pos": {
+		{Etd: "2-4", BasePrice: 8000, PricePerKg: 8500},
+	},
+	"tiki": {
+		{Etd: "3-4", BasePrice: 8500, PricePerKg: 9000},
+		{Etd: "1-2", BasePrice: 13000, PricePerKg: 11000},
+	},
+}
+
+type localShippingRateProvider struct {
+	tariffs map[string][]LocalShippingTariff
+}
+
+func NewLocalShippingRateProvider(tariffs map[string][]LocalShippingTariff) *localShippingRateProvider {
+	return &localShippingRateProvider{
+		tariffs: tariffs,
+	}
+}
+
+func (p *localShippingRateProvider) GetShippingRates(ctx context.Context, shippingRateRequest entity.ShippingRateRequest) ([]entity.CourierOption, error) {
+	tariffs, ok := p.tariffs[strings.ToLower(shippingRateRequest.Courier)]
+	if !ok {
+		return nil, fmt.Errorf("no local tariff for courier %s", shippingRateRequest.Courier)
+	}
+
+	weightInKg := (shippingRateRequest.Weight + 999) / 1000
+	if weightInKg < 1 {
+		weightInKg = 1
+	}
+
+	courierOptions := []entity.CourierOption{}
+	for _, tariff := range tariffs {
+		courierOptions = append(courierOptions, entity.CourierOption{
+			Price: tariff.BasePrice + tariff.PricePerKg*float64(weightInKg-1),
+			Etd:   tariff.Etd,
+		})
+	}
+
+	return courierOptions, nil
+}
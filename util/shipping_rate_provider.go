@@ -0,0 +1,11 @@
+package util
+
+import (
+	"context"
+
+	"github.com/sidiqPratomo/max-health-backend/entity"
+)
+
+type ShippingRateProvider interface {
+	GetShippingRates(ctx context.Context, shippingRateRequest entity.ShippingRateRequest) ([]entity.CourierOption, error)
+}
@@ -0,0 +1,95 @@
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sidiqPratomo/max-health-backend/entity"
+)
+
+const rajaOngkirCostUrl = "https://api.rajaongkir.com/starter/cost"
+
+type rajaOngkirCostResponse struct {
+	RajaOngkir struct {
+		Status struct {
+			Code        int    `json:"code"`
+			Description string `json:"description"`
+		} `json:"status"`
+		Results []struct {
+			Costs []struct {
+				Service string `json:"service"`
+				Cost    []struct {
+					Value float64 `json:"value"`
+					Etd   string  `json:"etd"`
+				} `json:"cost"`
+			} `json:"costs"`
+		} `json:"results"`
+	} `json:"rajaongkir"`
+}
+
+type rajaOngkirShippingRateProvider struct {
+	client  *http.Client
+	costUrl string
+	apiKey  string
+}
+
+func NewRajaOngkirShippingRateProvider(apiKey string) *rajaOngkirShippingRateProvider {
+	return &rajaOngkirShippingRateProvider{
+		client:  &http.Client{Timeout: 10 * time.Second},
+		costUrl: rajaOngkirCostUrl,
+		apiKey:  apiKey,
+	}
+}
+
+func (p *rajaOngkirShippingRateProvider) GetShippingRates(ctx context.Context, shippingRateRequest entity.ShippingRateRequest) ([]entity.CourierOption, error) {
+	form := url.Values{}
+	form.Set("origin", strconv.FormatInt(shippingRateRequest.Origin, 10))
+	form.Set("destination", strconv.FormatInt(shippingRateRequest.Destination, 10))
+	form.Set("weight", strconv.FormatInt(shippingRateRequest.Weight, 10))
+	form.Set("courier", shippingRateRequest.Courier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.costUrl, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("key", p.apiKey)
+	req.Header.Add("content-type", "application/x-www-form-urlencoded")
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var costResponse rajaOngkirCostResponse
+	if err := json.NewDecoder(res.Body).Decode(&costResponse); err != nil {
+		return nil, fmt.Errorf("rajaongkir: invalid response with status %d: %w", res.StatusCode, err)
+	}
+
+	if res.StatusCode != http.StatusOK || costResponse.RajaOngkir.Status.Code != http.StatusOK {
+		return nil, fmt.Errorf("rajaongkir: status %d: %s", res.StatusCode, costResponse.RajaOngkir.Status.Description)
+	}
+
+	courierOptions := []entity.CourierOption{}
+	for _, result := range costResponse.RajaOngkir.Results {
+		for _, cost := range result.Costs {
+			if len(cost.Cost) == 0 {
+				continue
+			}
+
+			courierOptions = append(courierOptions, entity.CourierOption{
+				Price: cost.Cost[0].Value,
+				Etd:   cost.Cost[0].Etd,
+			})
+		}
+	}
+
+	return courierOptions, nil
+}
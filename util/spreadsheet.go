@@ -0,0 +1,395 @@
+package util
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/sidiqPratomo/max-health-backend/appconstant"
+)
+
+const (
+	SpreadsheetFormatCsv  = "csv"
+	SpreadsheetFormatXlsx = "xlsx"
+
+	xlsxWorkbookPath      = "xl/workbook.xml"
+	xlsxWorkbookRelsPath  = "xl/_rels/workbook.xml.rels"
+	xlsxSharedStringsPath = "xl/sharedStrings.xml"
+	xlsxRootPath          = "xl"
+
+	// Excel sheets end at column XFD.
+	xlsxMaxColumns = 16384
+	// Parts are read from a small upload, a larger decompressed part is
+	// most likely a zip bomb.
+	xlsxMaxPartSize = 32 << 20
+)
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		RelationshipId string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		Id     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxRichText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxRichText) String() string {
+	text := t.Text
+	for _, run := range t.Runs {
+		text += run.Text
+	}
+	return text
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxRichText `xml:"si"`
+}
+
+// ReadSpreadsheet returns the cells of a CSV file or of the first sheet of an
+// XLSX workbook, one slice per row. Empty rows are kept so row numbers match
+// the ones the uploader sees. XLSX cells right of the first row are dropped.
+func ReadSpreadsheet(file io.ReaderAt, size int64, format string, maxRows int) ([][]string, error) {
+	switch strings.ToLower(format) {
+	case SpreadsheetFormatCsv:
+		return readCsv(io.NewSectionReader(file, 0, size), maxRows)
+	case SpreadsheetFormatXlsx:
+		return readXlsx(file, size, maxRows)
+	default:
+		return nil, errors.New(appconstant.MsgInvalidFileType)
+	}
+}
+
+func readCsv(file io.Reader, maxRows int) ([][]string, error) {
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	rows := [][]string{}
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if len(rows) == maxRows {
+			return nil, errors.New(appconstant.MsgTooManySpreadsheetRows)
+		}
+		rows = append(rows, row)
+	}
+
+	// Spreadsheet programs often save CSV files with a byte order mark.
+	if len(rows) > 0 && len(rows[0]) > 0 {
+		rows[0][0] = strings.TrimPrefix(rows[0][0], "\ufeff")
+	}
+
+	return rows, nil
+}
+
+func readXlsx(file io.ReaderAt, size int64, maxRows int) ([][]string, error) {
+	zipReader, err := zip.NewReader(file, size)
+	if err != nil {
+		return nil, errors.New(appconstant.MsgInvalidSpreadsheet)
+	}
+
+	files := map[string]*zip.File{}
+	for _, zipFile := range zipReader.File {
+		files[zipFile.Name] = zipFile
+	}
+
+	sheetPath, err := xlsxFirstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+
+	sharedStrings := xlsxSharedStrings{}
+	if sharedStringsFile, ok := files[xlsxSharedStringsPath]; ok {
+		err = decodeXlsxFile(sharedStringsFile, &sharedStrings)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sheetFile, ok := files[sheetPath]
+	if !ok {
+		return nil, errors.New(appconstant.MsgInvalidSpreadsheet)
+	}
+
+	sheetReader, err := openXlsxFile(sheetFile)
+	if err != nil {
+		return nil, err
+	}
+	defer sheetReader.Close()
+
+	sharedStringValues := make([]string, len(sharedStrings.Items))
+	for i, item := range sharedStrings.Items {
+		sharedStringValues[i] = item.String()
+	}
+
+	return readXlsxSheet(sheetReader, sharedStringValues, maxRows)
+}
+
+// xlsxCell collects the parts of a <c> element while the sheet is streamed.
+type xlsxCell struct {
+	column int
+	kind   string
+	value  string
+	inline string
+}
+
+// readXlsxSheet walks the sheet token by token, so a sheet with too many rows
+// is rejected without decoding the rest of it.
+func readXlsxSheet(reader io.Reader, sharedStrings []string, maxRows int) ([][]string, error) {
+	decoder := xml.NewDecoder(reader)
+
+	rows := [][]string{}
+	var row []string
+	var cell *xlsxCell
+	rowNumber := 0
+	width := xlsxMaxColumns
+	text := ""
+	isText := false
+	phoneticDepth := 0
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.New(appconstant.MsgInvalidSpreadsheet)
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			switch element.Name.Local {
+			case "row":
+				rowNumber = len(rows) + 1
+				if reference := xlsxAttr(element, "r"); reference != "" {
+					rowNumber, err = strconv.Atoi(reference)
+					if err != nil || rowNumber < 1 {
+						return nil, errors.New(appconstant.MsgInvalidSpreadsheet)
+					}
+				}
+				if rowNumber > maxRows {
+					return nil, errors.New(appconstant.MsgTooManySpreadsheetRows)
+				}
+
+				if len(rows) > 0 {
+					width = len(rows[0])
+				}
+				row = []string{}
+			case "c":
+				if row == nil {
+					return nil, errors.New(appconstant.MsgInvalidSpreadsheet)
+				}
+
+				cell = &xlsxCell{column: len(row), kind: xlsxAttr(element, "t")}
+				if reference := xlsxAttr(element, "r"); reference != "" {
+					cell.column, err = xlsxColumnIndex(reference)
+					if err != nil {
+						return nil, err
+					}
+				}
+			case "rPh":
+				phoneticDepth++
+			case "v", "t":
+				text = ""
+				isText = cell != nil && phoneticDepth == 0
+			}
+		case xml.CharData:
+			if isText {
+				text += string(element)
+			}
+		case xml.EndElement:
+			switch element.Name.Local {
+			case "v":
+				if isText {
+					cell.value = text
+				}
+				isText = false
+			case "t":
+				if isText {
+					cell.inline += text
+				}
+				isText = false
+			case "rPh":
+				phoneticDepth--
+			case "c":
+				if cell == nil || row == nil {
+					return nil, errors.New(appconstant.MsgInvalidSpreadsheet)
+				}
+
+				if cell.column < width {
+					value, err := xlsxCellValue(*cell, sharedStrings)
+					if err != nil {
+						return nil, err
+					}
+
+					for len(row) < cell.column {
+						row = append(row, "")
+					}
+					row = append(row, value)
+				}
+				cell = nil
+			case "row":
+				for len(rows) < rowNumber {
+					rows = append(rows, []string{})
+				}
+				rows[rowNumber-1] = row
+				row = nil
+			}
+		}
+	}
+
+	return rows, nil
+}
+
+func xlsxCellValue(cell xlsxCell, sharedStrings []string) (string, error) {
+	switch cell.kind {
+	case "s":
+		index, err := strconv.Atoi(cell.value)
+		if err != nil || index < 0 || index >= len(sharedStrings) {
+			return "", errors.New(appconstant.MsgInvalidSpreadsheet)
+		}
+		return sharedStrings[index], nil
+	case "inlineStr":
+		return cell.inline, nil
+	default:
+		return cell.value, nil
+	}
+}
+
+func xlsxAttr(element xml.StartElement, name string) string {
+	for _, attr := range element.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+func xlsxFirstSheetPath(files map[string]*zip.File) (string, error) {
+	workbookFile, ok := files[xlsxWorkbookPath]
+	if !ok {
+		return "", errors.New(appconstant.MsgInvalidSpreadsheet)
+	}
+
+	workbook := xlsxWorkbook{}
+	err := decodeXlsxFile(workbookFile, &workbook)
+	if err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", errors.New(appconstant.MsgInvalidSpreadsheet)
+	}
+
+	relsFile, ok := files[xlsxWorkbookRelsPath]
+	if !ok {
+		return "", errors.New(appconstant.MsgInvalidSpreadsheet)
+	}
+
+	relationships := xlsxRelationships{}
+	err = decodeXlsxFile(relsFile, &relationships)
+	if err != nil {
+		return "", err
+	}
+
+	for _, relationship := range relationships.Relationships {
+		if relationship.Id != workbook.Sheets[0].RelationshipId {
+			continue
+		}
+
+		if strings.HasPrefix(relationship.Target, "/") {
+			return strings.TrimPrefix(relationship.Target, "/"), nil
+		}
+		return path.Join(xlsxRootPath, relationship.Target), nil
+	}
+
+	return "", errors.New(appconstant.MsgInvalidSpreadsheet)
+}
+
+type xlsxFileReader struct {
+	io.Reader
+	io.Closer
+}
+
+// openXlsxFile opens a part of the workbook and stops reading it once it goes
+// past xlsxMaxPartSize, whatever size the zip header claims.
+func openXlsxFile(zipFile *zip.File) (io.ReadCloser, error) {
+	if zipFile.UncompressedSize64 > xlsxMaxPartSize {
+		return nil, errors.New(appconstant.MsgInvalidSpreadsheet)
+	}
+
+	reader, err := zipFile.Open()
+	if err != nil {
+		return nil, errors.New(appconstant.MsgInvalidSpreadsheet)
+	}
+
+	return xlsxFileReader{Reader: io.LimitReader(reader, xlsxMaxPartSize), Closer: reader}, nil
+}
+
+func decodeXlsxFile(zipFile *zip.File, v interface{}) error {
+	reader, err := openXlsxFile(zipFile)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	err = xml.NewDecoder(reader).Decode(v)
+	if err != nil {
+		return errors.New(appconstant.MsgInvalidSpreadsheet)
+	}
+
+	return nil
+}
+
+// xlsxColumnIndex turns a cell reference such as "AB12" into the zero based
+// index of its column.
+func xlsxColumnIndex(reference string) (int, error) {
+	column := 0
+	for _, r := range reference {
+		if r >= 'A' && r <= 'Z' {
+			column = column*26 + int(r-'A'+1)
+			if column > xlsxMaxColumns {
+				return 0, errors.New(appconstant.MsgInvalidSpreadsheet)
+			}
+			continue
+		}
+		break
+	}
+
+	if column == 0 {
+		return 0, errors.New(appconstant.MsgInvalidSpreadsheet)
+	}
+
+	return column - 1, nil
+}
+
+// EscapeSpreadsheetCell keeps a spreadsheet from evaluating a user supplied
+// value as a formula when an export is opened.
+func EscapeSpreadsheetCell(value string) string {
+	if value == "" || !strings.ContainsAny(value[:1], "=+-@\t\r") {
+		return value
+	}
+
+	return "'" + value
+}
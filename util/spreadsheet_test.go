@@ -0,0 +1,223 @@
+package util
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sidiqPratomo/max-health-backend/appconstant"
+)
+
+const (
+	testXlsxWorkbook = `<?xml version="1.0" encoding="UTF-8"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+	<sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets>
+</workbook>`
+
+	testXlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+	<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+</Relationships>`
+
+	testXlsxSharedStrings = `<?xml version="1.0" encoding="UTF-8"?>
+<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+	<si><t>drug_name</t></si>
+	<si><t>stock</t></si>
+	<si><r><t>Para</t></r><r><t>cetamol</t></r></si>
+</sst>`
+)
+
+func newTestXlsx(t *testing.T, sheetData string, extraFiles map[string]string) *bytes.Reader {
+	t.Helper()
+
+	files := map[string]string{
+		xlsxWorkbookPath:          testXlsxWorkbook,
+		xlsxWorkbookRelsPath:      testXlsxWorkbookRels,
+		xlsxSharedStringsPath:     testXlsxSharedStrings,
+		"xl/worksheets/sheet1.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` + sheetData + `</sheetData></worksheet>`,
+	}
+	for name, content := range extraFiles {
+		files[name] = content
+	}
+
+	buffer := bytes.Buffer{}
+	zipWriter := zip.NewWriter(&buffer)
+	for name, content := range files {
+		writer, err := zipWriter.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := writer.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return bytes.NewReader(buffer.Bytes())
+}
+
+func TestReadSpreadsheetXlsx(t *testing.T) {
+	tests := []struct {
+		name      string
+		sheetData string
+		maxRows   int
+		want      [][]string
+		wantErr   string
+	}{
+		{
+			name: "shared, inline and number cells",
+			sheetData: `<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c></row>` +
+				`<row r="2"><c r="A2" t="s"><v>2</v></c><c r="B2"><v>10</v></c></row>` +
+				`<row r="3"><c r="A3" t="inlineStr"><is><t>Amoxicillin</t><rPh><t>x</t></rPh></is></c><c r="B3"><v>4</v></c></row>`,
+			maxRows: 10,
+			want:    [][]string{{"drug_name", "stock"}, {"Paracetamol", "10"}, {"Amoxicillin", "4"}},
+		},
+		{
+			name: "gaps keep their row and column",
+			sheetData: `<row r="1"><c r="A1" t="s"><v>0</v></c><c r="C1" t="s"><v>1</v></c></row>` +
+				`<row r="3"><c r="C3"><v>5</v></c></row>`,
+			maxRows: 10,
+			want:    [][]string{{"drug_name", "", "stock"}, {}, {"", "", "5"}},
+		},
+		{
+			name: "cells without a reference follow the previous one",
+			sheetData: `<row><c t="s"><v>0</v></c><c t="s"><v>1</v></c></row>` +
+				`<row><c t="inlineStr"><is><t>Paracetamol</t></is></c><c><v>3</v></c></row>`,
+			maxRows: 10,
+			want:    [][]string{{"drug_name", "stock"}, {"Paracetamol", "3"}},
+		},
+		{
+			name: "cells right of the header are dropped",
+			sheetData: `<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c></row>` +
+				`<row r="2"><c r="A2"><v>1</v></c><c r="XFD2"><v>2</v></c></row>`,
+			maxRows: 10,
+			want:    [][]string{{"drug_name", "stock"}, {"1"}},
+		},
+		{
+			name:      "column past the last excel column",
+			sheetData: `<row r="1"><c r="ZZZZZZZ1"><v>1</v></c></row>`,
+			maxRows:   10,
+			wantErr:   appconstant.MsgInvalidSpreadsheet,
+		},
+		{
+			name:      "shared string out of range",
+			sheetData: `<row r="1"><c r="A1" t="s"><v>9</v></c></row>`,
+			maxRows:   10,
+			wantErr:   appconstant.MsgInvalidSpreadsheet,
+		},
+		{
+			name:      "row past the limit",
+			sheetData: `<row r="1"><c r="A1"><v>1</v></c></row><row r="4"><c r="A4"><v>1</v></c></row>`,
+			maxRows:   3,
+			wantErr:   appconstant.MsgTooManySpreadsheetRows,
+		},
+		{
+			name:      "rows without a number past the limit",
+			sheetData: `<row><c><v>1</v></c></row><row><c><v>2</v></c></row>`,
+			maxRows:   1,
+			wantErr:   appconstant.MsgTooManySpreadsheetRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := newTestXlsx(t, tt.sheetData, nil)
+
+			got, err := ReadSpreadsheet(file, file.Size(), SpreadsheetFormatXlsx, tt.maxRows)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("ReadSpreadsheet() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadSpreadsheet() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadSpreadsheet() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadSpreadsheetXlsxRejectsOversizedPart(t *testing.T) {
+	sharedStrings := `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><si><t>` +
+		strings.Repeat("a", xlsxMaxPartSize) + `</t></si></sst>`
+	file := newTestXlsx(t, `<row r="1"><c r="A1" t="s"><v>0</v></c></row>`, map[string]string{xlsxSharedStringsPath: sharedStrings})
+
+	_, err := ReadSpreadsheet(file, file.Size(), SpreadsheetFormatXlsx, 10)
+	if err == nil || err.Error() != appconstant.MsgInvalidSpreadsheet {
+		t.Fatalf("ReadSpreadsheet() error = %v, want %q", err, appconstant.MsgInvalidSpreadsheet)
+	}
+}
+
+func TestReadSpreadsheetCsv(t *testing.T) {
+	file := strings.NewReader("\ufeffdrug_name,stock\nParacetamol, 10\n\nAmoxicillin,4,extra\n")
+
+	got, err := ReadSpreadsheet(file, file.Size(), SpreadsheetFormatCsv, 10)
+	if err != nil {
+		t.Fatalf("ReadSpreadsheet() error = %v", err)
+	}
+
+	want := [][]string{{"drug_name", "stock"}, {"Paracetamol", "10"}, {"Amoxicillin", "4", "extra"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadSpreadsheet() = %q, want %q", got, want)
+	}
+
+	_, err = ReadSpreadsheet(file, file.Size(), SpreadsheetFormatCsv, 2)
+	if err == nil || err.Error() != appconstant.MsgTooManySpreadsheetRows {
+		t.Errorf("ReadSpreadsheet() error = %v, want %q", err, appconstant.MsgTooManySpreadsheetRows)
+	}
+}
+
+func TestXlsxColumnIndex(t *testing.T) {
+	tests := []struct {
+		reference string
+		want      int
+		wantErr   bool
+	}{
+		{reference: "A1", want: 0},
+		{reference: "Z9", want: 25},
+		{reference: "AB12", want: 27},
+		{reference: "XFD1048576", want: xlsxMaxColumns - 1},
+		{reference: "XFE1", wantErr: true},
+		{reference: "12", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := xlsxColumnIndex(tt.reference)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("xlsxColumnIndex(%q) error = %v, wantErr %v", tt.reference, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("xlsxColumnIndex(%q) = %d, want %d", tt.reference, got, tt.want)
+		}
+	}
+}
+
+func TestEscapeSpreadsheetCell(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "", want: ""},
+		{value: "Apotek Sehat", want: "Apotek Sehat"},
+		{value: "=HYPERLINK(\"http://x\")", want: "'=HYPERLINK(\"http://x\")"},
+		{value: "+62 811", want: "'+62 811"},
+		{value: "-1+1", want: "'-1+1"},
+		{value: "@SUM(A1)", want: "'@SUM(A1)"},
+		{value: "\t=1", want: "'\t=1"},
+		{value: "a=1", want: "a=1"},
+	}
+
+	for _, tt := range tests {
+		if got := EscapeSpreadsheetCell(tt.value); got != tt.want {
+			t.Errorf("EscapeSpreadsheetCell(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
@@ -13,6 +13,8 @@ type JwtCustomClaims struct {
 	Email         string `json:"email"`
 	Role          string `json:"role"`
 	TokenDuration int    `json:"token_duration"`
+	SessionId     int64  `json:"session_id,omitempty"`
+	TokenVersion  int64  `json:"token_version"`
 }
 
 type TokenAuthentication interface {